@@ -28,6 +28,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -102,6 +103,10 @@ func Start(opt *agentOption) error {
 
 // getAgentConfig returns Configuration that agent needs
 func getAgentConfig(opt *agentOption) (*common.Configuration, error) {
+	benchmarkSize, err := resource.ParseQuantity(opt.BenchmarkSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid benchmark size %q: %s", opt.BenchmarkSize, err.Error())
+	}
 	configuration := &common.Configuration{
 		Nodename:                opt.NodeName,
 		SysPath:                 opt.SysPath,
@@ -109,6 +114,10 @@ func getAgentConfig(opt *agentOption) (*common.Configuration, error) {
 		DiscoverInterval:        opt.Interval,
 		LogicalVolumeNamePrefix: opt.LVNamePrefix,
 		RegExp:                  opt.RegExp,
+		BenchmarkInterval:       opt.BenchmarkInterval,
+		BenchmarkDuration:       opt.BenchmarkDuration,
+		BenchmarkSize:           benchmarkSize.Value(),
+		HealthInterval:          opt.HealthInterval,
 	}
 	return configuration, nil
 }
@@ -17,6 +17,8 @@ limitations under the License.
 package agent
 
 import (
+	"time"
+
 	"github.com/alibaba/open-local/pkg/agent/common"
 	"github.com/spf13/pflag"
 )
@@ -30,6 +32,11 @@ type agentOption struct {
 	Interval     int
 	LVNamePrefix string
 	RegExp       string
+
+	BenchmarkInterval time.Duration
+	BenchmarkDuration time.Duration
+	BenchmarkSize     string
+	HealthInterval    time.Duration
 }
 
 func (option *agentOption) addFlags(fs *pflag.FlagSet) {
@@ -41,4 +48,8 @@ func (option *agentOption) addFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&option.Interval, "interval", common.DefaultInterval, "The interval that the agent checks the local storage at one time")
 	fs.StringVar(&option.LVNamePrefix, "lvname", "local", "The prefix of Logical Volume Name created by open-local")
 	fs.StringVar(&option.RegExp, "regexp", "^(s|v|xv)d[a-z]+$", "regexp is used to filter device names")
+	fs.DurationVar(&option.BenchmarkInterval, "benchmark.interval", 0, "The interval to benchmark VGs and unused devices, benchmark is disabled if zero. Write probes destroy the data on unused devices")
+	fs.DurationVar(&option.BenchmarkDuration, "benchmark.duration", 5*time.Second, "The duration of each probe of benchmark")
+	fs.StringVar(&option.BenchmarkSize, "benchmark.size", "1Gi", "The size of the scratch LV, or of the region of device to benchmark")
+	fs.DurationVar(&option.HealthInterval, "health.interval", 5*time.Minute, "The interval to check SMART or NVMe health log of disks, health check is disabled if zero")
 }
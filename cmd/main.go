@@ -30,6 +30,7 @@ import (
 	"github.com/alibaba/open-local/cmd/csi"
 	"github.com/alibaba/open-local/cmd/doc"
 	"github.com/alibaba/open-local/cmd/scheduler"
+	"github.com/alibaba/open-local/cmd/simulate"
 	"github.com/alibaba/open-local/cmd/version"
 	localtype "github.com/alibaba/open-local/pkg"
 )
@@ -56,6 +57,7 @@ func addCommands() {
 		scheduler.Cmd,
 		csi.Cmd,
 		controller.Cmd,
+		simulate.Cmd,
 		version.Cmd,
 		doc.Cmd.Cmd,
 	)
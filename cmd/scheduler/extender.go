@@ -88,7 +88,7 @@ func Run(opt *extenderOption) error {
 	localStorageInformerFactory := informers.NewSharedInformerFactory(localClient, 0)
 	snapshotInformerFactory := volumesnapshotinformers.NewSharedInformerFactory(snapClient, 0)
 
-	extenderServer := server.NewExtenderServer(kubeClient, localClient, snapClient, kubeInformerFactory, localStorageInformerFactory, snapshotInformerFactory, opt.Port, weights)
+	extenderServer := server.NewExtenderServer(kubeClient, localClient, snapClient, kubeInformerFactory, localStorageInformerFactory, snapshotInformerFactory, opt.Port, weights, opt.ReservationTTL)
 
 	log.Info("starting open-local scheduler extender")
 	kubeInformerFactory.Start(stopCh)
@@ -813,7 +813,7 @@ func (f *fixture) newExtender() (*server.ExtenderServer, kubeinformers.SharedInf
 	localInformer := localinformers.NewSharedInformerFactory(f.localclient, noResyncPeriodFunc())
 	snapInforer := volumesnapshotinformers.NewSharedInformerFactory(f.snapclient, noResyncPeriodFunc())
 
-	extenderServer := server.NewExtenderServer(f.kubeclient, f.localclient, f.snapclient, k8sInformer, localInformer, snapInforer, TestPort, localtype.NewNodeAntiAffinityWeight())
+	extenderServer := server.NewExtenderServer(f.kubeclient, f.localclient, f.snapclient, k8sInformer, localInformer, snapInforer, TestPort, localtype.NewNodeAntiAffinityWeight(), localtype.DefaultReservationTTL)
 
 	return extenderServer, k8sInformer, localInformer, snapInforer
 }
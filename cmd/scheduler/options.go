@@ -17,13 +17,11 @@ limitations under the License.
 package scheduler
 
 import (
-	"fmt"
-	"strconv"
-	"strings"
+	"time"
 
 	"github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/algo"
 	"github.com/alibaba/open-local/pkg/scheduler/algorithm/priorities"
-	log "github.com/sirupsen/logrus"
 	"github.com/spf13/pflag"
 )
 
@@ -34,10 +32,11 @@ type extenderOption struct {
 	Port                    int32
 	EnabledNodeAntiAffinity string
 	Strategy                string
+	ReservationTTL          time.Duration
 }
 
 const (
-	DefaultNodeAffinityWeight int = 5
+	DefaultNodeAffinityWeight int = priorities.DefaultNodeAntiAffinityWeight
 )
 
 func (option *extenderOption) AddFlags(fs *pflag.FlagSet) {
@@ -45,59 +44,19 @@ func (option *extenderOption) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&option.Master, "master", option.Master, "URL/IP for master.")
 	fs.Int32Var(&option.Port, "port", option.Port, "Port for receiving scheduler callback, set to '0' to disable http server")
 	fs.StringVar(&option.EnabledNodeAntiAffinity, "enabled-node-anti-affinity", option.EnabledNodeAntiAffinity, "whether enable node anti-affinity for open-local storage backend, example format: 'MountPoint=5,LVM=3'")
-	fs.StringVar(&option.Strategy, "scheduler-strategy", "binpack", "Scheduler Strategy: binpack or spread")
+	fs.StringVar(&option.Strategy, "scheduler-strategy", "binpack", "Default scheduler strategy of volumes whose storage class does not set schedulerStrategy: binpack, spread, best-fit, worst-fit, media-aware, least-fragmentation or round-robin")
+	fs.DurationVar(&option.ReservationTTL, "reservation-ttl", pkg.DefaultReservationTTL, "Time the local storage is reserved for a pod before its pvs are created")
 }
 
 func (option *extenderOption) ParseWeight() (weights *pkg.NodeAntiAffinityWeight, err error) {
-	weights = pkg.NewNodeAntiAffinityWeight()
-	if len(option.EnabledNodeAntiAffinity) > 0 {
-		// example format
-		sec := strings.Split(option.EnabledNodeAntiAffinity, ",")
-		for _, e := range sec {
-			var vt pkg.VolumeType
-			var weight int
-			if strings.Contains(e, "=") { // e is "<volumeType>=<weight>"
-				ex := strings.SplitN(e, "=", 2)
-				t := ex[0]
-				vt, err = pkg.VolumeTypeFromString(t)
-				if err != nil {
-					return nil, err
-				}
-				tmp, err := strconv.ParseInt(ex[1], 10, 32)
-				weight = int(tmp)
-				if err != nil {
-					return nil, err
-				}
-			} else { // e is "volumeType"
-				vt, err = pkg.VolumeTypeFromString(e)
-				if err != nil {
-					return
-				}
-				weight = DefaultNodeAffinityWeight
-			}
-
-			//
-			if weight < priorities.MinScore || weight > priorities.MaxScore {
-				err = fmt.Errorf("weight is out-of-range [%d, %d], current value is %d", priorities.MinScore, priorities.MaxScore, weight)
-				log.Errorf(err.Error())
-				return nil, err
-			}
-			log.Infof("node anti-affinity for %v added, weight is %d", vt, weight)
-			weights.Put(vt, weight)
-		}
-	}
-	return
+	return priorities.ParseNodeAntiAffinityWeight(option.EnabledNodeAntiAffinity)
 }
 
 func (option *extenderOption) ParseStrategy() error {
-	switch option.Strategy {
-	case "binpack":
-		pkg.SchedulerStrategy = pkg.StrategyBinpack
-	case "spread":
-		pkg.SchedulerStrategy = pkg.StrategySpread
-	default:
-		return fmt.Errorf("Scheduler strategy parameter may be wrong. You can set one of those: binpack or spread")
+	strategy, err := algo.ParseStrategy(option.Strategy)
+	if err != nil {
+		return err
 	}
-
+	pkg.SchedulerStrategy = strategy
 	return nil
 }
@@ -0,0 +1,133 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/simulate"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// readManifests decodes the pods and pvcs from yaml or json documents of r into args,
+// statefulsets are expanded into the pods and pvcs of their replicas
+func readManifests(r io.Reader, args *simulate.Args) error {
+	decoder := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		var raw runtime.RawExtension
+		if err := decoder.Decode(&raw); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if len(raw.Raw) == 0 {
+			continue
+		}
+		if err := addObject(raw.Raw, args); err != nil {
+			return err
+		}
+	}
+}
+
+func addObject(data []byte, args *simulate.Args) error {
+	var typeMeta metav1.TypeMeta
+	if err := json.Unmarshal(data, &typeMeta); err != nil {
+		return err
+	}
+	switch typeMeta.Kind {
+	case "Pod":
+		var pod corev1.Pod
+		if err := json.Unmarshal(data, &pod); err != nil {
+			return err
+		}
+		args.Pods = append(args.Pods, pod)
+	case "PersistentVolumeClaim":
+		var pvc corev1.PersistentVolumeClaim
+		if err := json.Unmarshal(data, &pvc); err != nil {
+			return err
+		}
+		args.PersistentVolumeClaims = append(args.PersistentVolumeClaims, pvc)
+	case "StatefulSet":
+		var sts appsv1.StatefulSet
+		if err := json.Unmarshal(data, &sts); err != nil {
+			return err
+		}
+		addStatefulSet(&sts, args)
+	case "List":
+		var list metav1.List
+		if err := json.Unmarshal(data, &list); err != nil {
+			return err
+		}
+		for _, item := range list.Items {
+			if err := addObject(item.Raw, args); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported kind %q, only Pod, PersistentVolumeClaim, StatefulSet and List are supported", typeMeta.Kind)
+	}
+	return nil
+}
+
+// addStatefulSet adds the pods of statefulset replicas and the pvcs of their volume claim templates
+func addStatefulSet(sts *appsv1.StatefulSet, args *simulate.Args) {
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	for i := int32(0); i < replicas; i++ {
+		pod := corev1.Pod{
+			ObjectMeta: *sts.Spec.Template.ObjectMeta.DeepCopy(),
+			Spec:       *sts.Spec.Template.Spec.DeepCopy(),
+		}
+		pod.Name = fmt.Sprintf("%s-%d", sts.Name, i)
+		pod.Namespace = sts.Namespace
+		for _, template := range sts.Spec.VolumeClaimTemplates {
+			pvc := corev1.PersistentVolumeClaim{
+				ObjectMeta: *template.ObjectMeta.DeepCopy(),
+				Spec:       *template.Spec.DeepCopy(),
+			}
+			pvc.Name = fmt.Sprintf("%s-%s", template.Name, pod.Name)
+			pvc.Namespace = sts.Namespace
+			args.PersistentVolumeClaims = append(args.PersistentVolumeClaims, pvc)
+
+			volume := corev1.Volume{
+				Name: template.Name,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.Name},
+				},
+			}
+			replaced := false
+			for j := range pod.Spec.Volumes {
+				if pod.Spec.Volumes[j].Name == template.Name {
+					pod.Spec.Volumes[j] = volume
+					replaced = true
+				}
+			}
+			if !replaced {
+				pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+			}
+		}
+		args.Pods = append(args.Pods, pod)
+	}
+}
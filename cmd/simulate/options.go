@@ -0,0 +1,40 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulate
+
+import (
+	"github.com/spf13/pflag"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+type simulateOption struct {
+	Server    string
+	Filenames []string
+	NodeNames []string
+	Output    string
+}
+
+func (option *simulateOption) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&option.Server, "server", "http://127.0.0.1:23000", "Address of open-local scheduler extender, e.g. forwarded by 'kubectl -n kube-system port-forward svc/open-local-scheduler-extender 23000'")
+	fs.StringSliceVarP(&option.Filenames, "filename", "f", option.Filenames, "Files of pod, pvc, statefulset or list manifests in yaml or json, '-' reads from stdin")
+	fs.StringSliceVar(&option.NodeNames, "node", option.NodeNames, "Nodes the pods can be scheduled to, all nodes if not set")
+	fs.StringVarP(&option.Output, "output", "o", outputTable, "Output format: table or json")
+}
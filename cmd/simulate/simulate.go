@@ -0,0 +1,176 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/simulate"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var (
+	opt = simulateOption{}
+)
+
+func init() {
+	opt.AddFlags(Cmd.Flags())
+}
+
+var Cmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "simulate the local storage scheduling of pods and pvcs without changing the cluster",
+	Long: `simulate sends the pods and pvcs to open-local scheduler extender, which schedules them one by one
+with its predicates and priorities against a copy of its cache, and reports the node and storage each pvc
+would be allocated from, or why the pods do not fit. Only local storage is simulated.`,
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return Run(&opt, os.Stdin, os.Stdout)
+	},
+}
+
+func Run(opt *simulateOption, stdin io.Reader, out io.Writer) error {
+	if opt.Output != outputTable && opt.Output != outputJSON {
+		return fmt.Errorf("invalid output format %q, valid values are table or json", opt.Output)
+	}
+	if len(opt.Filenames) == 0 {
+		return fmt.Errorf("no manifest is set by --filename")
+	}
+	args := simulate.Args{NodeNames: opt.NodeNames}
+	for _, filename := range opt.Filenames {
+		if err := readFile(filename, stdin, &args); err != nil {
+			return fmt.Errorf("failed to read %s: %s", filename, err.Error())
+		}
+	}
+	if len(args.Pods) == 0 {
+		return fmt.Errorf("no pod is found in manifests")
+	}
+
+	result, err := post(opt.Server, &args)
+	if err != nil {
+		return err
+	}
+	if opt.Output == outputJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+	return printTable(out, result)
+}
+
+func readFile(filename string, stdin io.Reader, args *simulate.Args) error {
+	if filename == "-" {
+		return readManifests(stdin, args)
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return readManifests(f, args)
+}
+
+func post(server string, args *simulate.Args) (*simulate.Result, error) {
+	body, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	url := strings.TrimSuffix(server, "/") + "/apis/simulate"
+	resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to request %s: %s", url, err.Error())
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to request %s: %s %s", url, resp.Status, string(data))
+	}
+	result := &simulate.Result{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("simulation failed: %s", result.Error)
+	}
+	return result, nil
+}
+
+// printTable prints a line for each pvc of the scheduled pods, and the reasons of the unscheduled pods
+func printTable(out io.Writer, result *simulate.Result) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "POD\tNODE\tPVC\tTYPE\tSIZE\tALLOCATED FROM")
+	var failed []simulate.PodResult
+	for _, pod := range result.Pods {
+		if pod.Error != "" {
+			failed = append(failed, pod)
+			continue
+		}
+		if len(pod.Claims) == 0 {
+			fmt.Fprintf(w, "%s\t%s\t-\t-\t-\t-\n", pod.Pod, pod.Node)
+		}
+		for _, claim := range pod.Claims {
+			size := resource.NewQuantity(claim.Requested, resource.BinarySI)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", pod.Pod, pod.Node, claim.PersistentVolumeClaim, claim.VolumeType, size.String(), allocatedFrom(claim))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(failed) > 0 {
+		fmt.Fprintf(out, "\n%d/%d pods can not be scheduled:\n", len(failed), len(result.Pods))
+	}
+	for _, pod := range failed {
+		fmt.Fprintf(out, "%s: %s\n", pod.Pod, pod.Error)
+		nodes := make([]string, 0, len(pod.FailedNodes))
+		for node := range pod.FailedNodes {
+			nodes = append(nodes, node)
+		}
+		sort.Strings(nodes)
+		for _, node := range nodes {
+			fmt.Fprintf(out, "  %s: %s\n", node, pod.FailedNodes[node])
+		}
+	}
+	return nil
+}
+
+func allocatedFrom(claim simulate.ClaimResult) string {
+	switch {
+	case claim.VgName != "" && claim.Thin:
+		return "thin pool of " + claim.VgName
+	case claim.VgName != "":
+		return claim.VgName
+	case claim.Device != "":
+		return claim.Device
+	}
+	return claim.MountPoint
+}
@@ -0,0 +1,138 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulate
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/simulate"
+)
+
+const manifests = `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: html
+spec:
+  storageClassName: open-local-lvm
+  resources:
+    requests:
+      storage: 5Gi
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+spec:
+  volumes:
+  - name: html
+    persistentVolumeClaim:
+      claimName: html
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+  namespace: prod
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: mysql
+        image: mysql
+  volumeClaimTemplates:
+  - metadata:
+      name: data
+    spec:
+      storageClassName: open-local-lvm
+      resources:
+        requests:
+          storage: 50Gi
+---
+{"apiVersion": "v1", "kind": "List", "items": [{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "web"}}]}
+`
+
+func TestReadManifests(t *testing.T) {
+	args := simulate.Args{}
+	if err := readManifests(strings.NewReader(manifests), &args); err != nil {
+		t.Fatalf("readManifests() error = %v", err)
+	}
+	var pods, pvcs []string
+	for _, pod := range args.Pods {
+		pods = append(pods, pod.Namespace+"/"+pod.Name)
+	}
+	for _, pvc := range args.PersistentVolumeClaims {
+		pvcs = append(pvcs, pvc.Namespace+"/"+pvc.Name)
+	}
+	if strings.Join(pods, ",") != "/nginx,prod/db-0,prod/db-1,/web" {
+		t.Errorf("pods are %v", pods)
+	}
+	if strings.Join(pvcs, ",") != "/html,prod/data-db-0,prod/data-db-1" {
+		t.Errorf("pvcs are %v", pvcs)
+	}
+	if claim := args.Pods[2].Spec.Volumes[0].PersistentVolumeClaim; claim == nil || claim.ClaimName != "data-db-1" {
+		t.Errorf("volumes of db-1 are %+v", args.Pods[2].Spec.Volumes)
+	}
+
+	if err := readManifests(strings.NewReader("apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n"), &args); err == nil {
+		t.Errorf("expected error of unsupported kind")
+	}
+}
+
+func TestRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var args simulate.Args
+		if r.URL.Path != "/apis/simulate" || json.NewDecoder(r.Body).Decode(&args) != nil || len(args.Pods) != 4 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(simulate.Result{Pods: []simulate.PodResult{
+			{Pod: "default/nginx", Node: "node-1", Claims: []simulate.ClaimResult{
+				{PersistentVolumeClaim: "default/html", VolumeType: localtype.VolumeTypeLVM, Requested: 5 << 30, VgName: "share"},
+			}},
+			{Pod: "prod/db-0", Error: "0/2 nodes are available", FailedNodes: map[string]string{"node-2": "not enough lv storage", "node-1": "not enough lv storage"}},
+		}})
+	}))
+	defer server.Close()
+
+	out := &bytes.Buffer{}
+	opt := &simulateOption{Server: server.URL, Filenames: []string{"-"}, Output: outputTable}
+	if err := Run(opt, strings.NewReader(manifests), out); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	for _, expected := range []string{
+		"default/nginx  node-1  default/html  LVM   5Gi   share",
+		"1/2 pods can not be scheduled",
+		"prod/db-0: 0/2 nodes are available\n  node-1: not enough lv storage\n  node-2:",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("output does not contain %q:\n%s", expected, out.String())
+		}
+	}
+
+	opt.Output = "yaml"
+	if err := Run(opt, strings.NewReader(manifests), out); err == nil {
+		t.Errorf("expected error of invalid output format")
+	}
+}
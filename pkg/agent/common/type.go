@@ -16,6 +16,8 @@ limitations under the License.
 
 package common
 
+import "time"
+
 // Configuration stores all the user-defined parameters to the controller
 type Configuration struct {
 	// Nodename is the kube node name
@@ -30,6 +32,14 @@ type Configuration struct {
 	LogicalVolumeNamePrefix string
 	// RegExp is used to filter device names
 	RegExp string
+	// BenchmarkInterval is the interval to benchmark VGs and devices, benchmark is disabled if zero
+	BenchmarkInterval time.Duration
+	// BenchmarkDuration is the duration of each probe of benchmark
+	BenchmarkDuration time.Duration
+	// BenchmarkSize is the size of scratch LV, or of the region of device to probe
+	BenchmarkSize int64
+	// HealthInterval is the interval to check SMART or NVMe health log of disks, health check is disabled if zero
+	HealthInterval time.Duration
 }
 
 const (
@@ -38,4 +48,6 @@ const (
 	// DefaultInterval is the duration(second) that the agent checks at one time
 	DefaultInterval int    = 60
 	DefaultEndpoint string = "unix://tmp/csi.sock"
+	// BenchmarkLVName is the name of the scratch LV to benchmark a VG
+	BenchmarkLVName string = "open-local-benchmark"
 )
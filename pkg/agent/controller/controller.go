@@ -25,11 +25,15 @@ import (
 	localtype "github.com/alibaba/open-local/pkg"
 	"github.com/alibaba/open-local/pkg/agent/common"
 	"github.com/alibaba/open-local/pkg/agent/discovery"
+	"github.com/alibaba/open-local/pkg/agent/throttle"
 	clientset "github.com/alibaba/open-local/pkg/generated/clientset/versioned"
 	snapshot "github.com/kubernetes-csi/external-snapshotter/client/v4/clientset/versioned"
 	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 )
@@ -82,6 +86,35 @@ func (c *Agent) Run(stopCh <-chan struct{}) error {
 	}
 	go wait.Until(discoverer.ExpandSnapshotLVIfNeeded, time.Duration(expandSnapInterval)*time.Second, stopCh)
 
+	// benchmark new VGs and devices at discover interval, and all of them at benchmark interval
+	if discoverer.BenchmarkInterval > 0 {
+		go wait.Until(discoverer.Benchmark, time.Duration(discoverer.DiscoverInterval)*time.Second, stopCh)
+	}
+
+	// health of disks is reported as conditions at next discover
+	if discoverer.HealthInterval > 0 {
+		go wait.Until(discoverer.CheckHealth, discoverer.HealthInterval, stopCh)
+	}
+
+	// update io limits of volumes on this node from the annotations of pvc
+	kubeInformerFactory := informers.NewSharedInformerFactory(c.kubeclientset, throttle.ResyncPeriod)
+	podInformerFactory := informers.NewSharedInformerFactoryWithOptions(c.kubeclientset, throttle.ResyncPeriod,
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", c.Nodename).String()
+		}))
+	throttler := throttle.NewController(c.Nodename, c.SysPath, c.kubeclientset,
+		kubeInformerFactory.Core().V1().PersistentVolumeClaims(),
+		kubeInformerFactory.Core().V1().PersistentVolumes(),
+		podInformerFactory.Core().V1().Pods(),
+		c.eventRecorder)
+	kubeInformerFactory.Start(stopCh)
+	podInformerFactory.Start(stopCh)
+	go func() {
+		if err := throttler.Run(1, stopCh); err != nil {
+			log.Errorf("failed to run io throttling controller: %s", err.Error())
+		}
+	}()
+
 	log.Info("Started open-local agent")
 	<-stopCh
 	log.Info("Shutting down agent")
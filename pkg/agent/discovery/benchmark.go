@@ -0,0 +1,238 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"syscall"
+	"time"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/agent/common"
+	localv1alpha1 "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
+	"github.com/alibaba/open-local/pkg/utils"
+	"github.com/alibaba/open-local/pkg/utils/benchmark"
+	"github.com/alibaba/open-local/pkg/utils/lvm"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// benchmarkConcurrency is the number of outstanding ios of benchmark
+var benchmarkConcurrency = runtime.NumCPU()
+
+// Benchmark measures the performance of filtered VGs and unused filtered devices whose profile
+// is older than BenchmarkInterval, and reports the results in status of NodeLocalStorage
+func (d *Discoverer) Benchmark() {
+	nls, err := d.localclientset.CsiV1alpha1().NodeLocalStorages().Get(context.Background(), d.Nodename, metav1.GetOptions{})
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			log.Errorf("get NodeLocalStorages failed: %s", err.Error())
+		}
+		return
+	}
+	existing := make(map[string]localv1alpha1.PerformanceProfile, len(nls.Status.PerformanceProfiles))
+	for _, profile := range nls.Status.PerformanceProfiles {
+		existing[profile.Name] = profile
+	}
+	usedDevices, err := d.usedDevices()
+	if err != nil {
+		log.Errorf("list devices used by pv failed: %s", err.Error())
+		return
+	}
+	readOnlyDevices := make(map[string]bool)
+	for _, device := range nls.Status.NodeStorageInfo.DeviceInfos {
+		readOnlyDevices[device.Name] = device.ReadOnly
+	}
+
+	var profiles []localv1alpha1.PerformanceProfile
+	benchmarkFunc := func(name string, run func() (*benchmark.Result, error)) {
+		profile, exist := existing[name]
+		stale := !exist || profile.LastBenchmarkTime == nil || time.Since(profile.LastBenchmarkTime.Time) >= d.BenchmarkInterval
+		// failed benchmarks are not retried until next interval either
+		if attempt, attempted := d.benchmarkAttempts[name]; stale && (!attempted || time.Since(attempt) >= d.BenchmarkInterval) {
+			log.Infof("benchmarking %s", name)
+			d.benchmarkAttempts[name] = time.Now()
+			result, err := run()
+			if errors.Is(err, syscall.EBUSY) {
+				log.Infof("skip benchmark of %s, which is in use", name)
+			} else if err != nil {
+				msg := fmt.Sprintf("benchmark %s failed: %s", name, err.Error())
+				log.Error(msg)
+				d.recorder.Event(nls, corev1.EventTypeWarning, localtype.EventBenchmarkFailed, msg)
+			} else if result != nil {
+				log.Infof("benchmark of %s: %+v", name, *result)
+				profile, exist = newPerformanceProfile(name, result), true
+			}
+		}
+		if exist {
+			// tiers may be changed since last benchmark
+			profile.Tier = performanceTier(nls.Spec.PerformanceTiers, profile)
+			profiles = append(profiles, profile)
+		}
+	}
+	for _, vg := range nls.Status.FilteredStorageInfo.VolumeGroups {
+		benchmarkFunc(vg, func() (*benchmark.Result, error) {
+			return d.benchmarkVG(vg)
+		})
+	}
+	for _, device := range nls.Status.FilteredStorageInfo.Devices {
+		if usedDevices[device] {
+			// keep the profile measured before the device is used
+			if profile, exist := existing[device]; exist {
+				profile.Tier = performanceTier(nls.Spec.PerformanceTiers, profile)
+				profiles = append(profiles, profile)
+			}
+			continue
+		}
+		benchmarkFunc(device, func() (*benchmark.Result, error) {
+			return benchmark.Run(device, d.benchmarkOptions(!readOnlyDevices[device]))
+		})
+	}
+
+	// status may be updated by Discover during benchmark
+	for i := 0; i < 3; i++ {
+		if reflect.DeepEqual(nls.Status.PerformanceProfiles, profiles) {
+			return
+		}
+		nlsCopy := nls.DeepCopy()
+		nlsCopy.Status.PerformanceProfiles = profiles
+		if _, err = d.localclientset.CsiV1alpha1().NodeLocalStorages().UpdateStatus(context.Background(), nlsCopy, metav1.UpdateOptions{}); err == nil {
+			log.Infof("update performance profiles of nls %s", nlsCopy.Name)
+			return
+		}
+		if !k8serr.IsConflict(err) {
+			break
+		}
+		if nls, err = d.localclientset.CsiV1alpha1().NodeLocalStorages().Get(context.Background(), d.Nodename, metav1.GetOptions{}); err != nil {
+			break
+		}
+	}
+	log.Errorf("update performance profiles of nls %s failed: %s", d.Nodename, err.Error())
+}
+
+// benchmarkVG probes a scratch LV in vg, which is removed afterwards
+func (d *Discoverer) benchmarkVG(vgName string) (*benchmark.Result, error) {
+	vg, err := lvm.LookupVolumeGroup(vgName)
+	if err != nil {
+		return nil, err
+	}
+	// scratch LV is left if agent crashed during benchmark
+	if lv, err := vg.LookupLogicalVolume(common.BenchmarkLVName); err == nil {
+		if err := lv.Remove(); err != nil {
+			return nil, err
+		}
+	}
+	free, err := vg.BytesFree()
+	if err != nil {
+		return nil, err
+	}
+	if free < uint64(d.BenchmarkSize) {
+		return nil, fmt.Errorf("free size %d of vg is less than benchmark size %d", free, d.BenchmarkSize)
+	}
+	lv, err := vg.CreateLogicalVolume(common.BenchmarkLVName, uint64(d.BenchmarkSize), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := lv.Remove(); err != nil {
+			log.Errorf("remove benchmark lv %s/%s failed: %s", vgName, common.BenchmarkLVName, err.Error())
+		}
+	}()
+	path, err := lv.Path()
+	if err != nil {
+		return nil, err
+	}
+	return benchmark.Run(path, d.benchmarkOptions(true))
+}
+
+func (d *Discoverer) benchmarkOptions(write bool) benchmark.Options {
+	return benchmark.Options{
+		Duration:    d.BenchmarkDuration,
+		Size:        d.BenchmarkSize,
+		Concurrency: benchmarkConcurrency,
+		Write:       write,
+	}
+}
+
+// usedDevices returns the devices of this node which are allocated to Device pvs
+func (d *Discoverer) usedDevices() (map[string]bool, error) {
+	pvs, err := d.kubeclientset.CoreV1().PersistentVolumes().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string]bool)
+	for i := range pvs.Items {
+		pv := &pvs.Items[i]
+		if pv.Spec.CSI == nil || !utils.ContainsProvisioner(pv.Spec.CSI.Driver) {
+			continue
+		}
+		if _, node := utils.IsLocalPV(pv); node != d.Nodename {
+			continue
+		}
+		if device := utils.GetDeviceNameFromCsiPV(pv); device != "" {
+			used[device] = true
+		}
+	}
+	return used, nil
+}
+
+func newPerformanceProfile(name string, result *benchmark.Result) localv1alpha1.PerformanceProfile {
+	now := metav1.Now()
+	return localv1alpha1.PerformanceProfile{
+		Name:              name,
+		ReadIOPS:          result.ReadIOPS,
+		WriteIOPS:         result.WriteIOPS,
+		ReadBPS:           result.ReadBPS,
+		WriteBPS:          result.WriteBPS,
+		ReadLatency:       latencyPercentiles(result.ReadLatency),
+		WriteLatency:      latencyPercentiles(result.WriteLatency),
+		LastBenchmarkTime: &now,
+	}
+}
+
+func latencyPercentiles(latency benchmark.Latency) localv1alpha1.LatencyPercentiles {
+	return localv1alpha1.LatencyPercentiles{
+		P50: metav1.Duration{Duration: latency.P50},
+		P90: metav1.Duration{Duration: latency.P90},
+		P99: metav1.Duration{Duration: latency.P99},
+	}
+}
+
+// performanceTier returns the first tier whose thresholds are all met by profile
+func performanceTier(tiers []localv1alpha1.PerformanceTier, profile localv1alpha1.PerformanceProfile) string {
+	for _, tier := range tiers {
+		if profile.ReadIOPS < tier.MinReadIOPS || profile.WriteIOPS < tier.MinWriteIOPS ||
+			profile.ReadBPS < tier.MinReadBPS || profile.WriteBPS < tier.MinWriteBPS {
+			continue
+		}
+		if tier.MaxReadLatency != nil && profile.ReadLatency.P99.Duration > tier.MaxReadLatency.Duration {
+			continue
+		}
+		// write latency is unknown if write probes are skipped
+		if tier.MaxWriteLatency != nil && (profile.WriteIOPS == 0 || profile.WriteLatency.P99.Duration > tier.MaxWriteLatency.Duration) {
+			continue
+		}
+		return tier.Name
+	}
+	return ""
+}
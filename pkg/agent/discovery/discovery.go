@@ -24,12 +24,15 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	localtype "github.com/alibaba/open-local/pkg"
 	"github.com/alibaba/open-local/pkg/agent/common"
 	localv1alpha1 "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
 	clientset "github.com/alibaba/open-local/pkg/generated/clientset/versioned"
 	"github.com/alibaba/open-local/pkg/utils"
+	"github.com/alibaba/open-local/pkg/utils/health"
 	"github.com/alibaba/open-local/pkg/utils/lvm"
 	units "github.com/docker/go-units"
 	snapshot "github.com/kubernetes-csi/external-snapshotter/client/v4/clientset/versioned"
@@ -52,6 +55,12 @@ type Discoverer struct {
 	// K8sMounter used to verify mountpoints
 	K8sMounter mount.Interface
 	recorder   record.EventRecorder
+	// benchmarkAttempts records the last time of benchmark of VGs and devices
+	benchmarkAttempts map[string]time.Time
+	// healthReports is the last health reports of disks, keyed by disk path
+	healthReports map[string]*health.Report
+	healthLock    sync.RWMutex
+	healthRunner  health.CommandRunner
 }
 
 type ReservedVGInfo struct {
@@ -74,6 +83,10 @@ func NewDiscoverer(config *common.Configuration, kubeclientset kubernetes.Interf
 		snapclient:     snapclient,
 		K8sMounter:     mount.New("" /* default mount path */),
 		recorder:       recorder,
+
+		benchmarkAttempts: make(map[string]time.Time),
+		healthReports:     make(map[string]*health.Report),
+		healthRunner:      health.HostRunner,
 	}
 }
 
@@ -112,11 +125,18 @@ func (d *Discoverer) Discover() {
 			log.Errorf("discover MountPoint error: %s", err.Error())
 			return
 		}
+		setIOCapacities(newStatus, nlsCopy.Spec.IOCapacities)
 		newStatus.NodeStorageInfo.Phase = localv1alpha1.NodeStorageRunning
 		newStatus.NodeStorageInfo.State.Status = localv1alpha1.ConditionTrue
-		newStatus.NodeStorageInfo.State.Type = localv1alpha1.StorageReady
+		applyHealth(newStatus, d.faultyDisks(), func(device string) []string {
+			return parentDisks(d.SysPath, device)
+		})
 		lastHeartbeatTime := metav1.Now()
 		newStatus.NodeStorageInfo.State.LastHeartbeatTime = &lastHeartbeatTime
+		newStatus.NodeStorageInfo.State.LastTransitionTime = nlsCopy.Status.NodeStorageInfo.State.LastTransitionTime
+		if newStatus.NodeStorageInfo.State.Type != nlsCopy.Status.NodeStorageInfo.State.Type || newStatus.NodeStorageInfo.State.LastTransitionTime == nil {
+			newStatus.NodeStorageInfo.State.LastTransitionTime = &lastHeartbeatTime
+		}
 		nlsCopy.Status.NodeStorageInfo = newStatus.NodeStorageInfo
 		nlsCopy.Status.FilteredStorageInfo.VolumeGroups = FilterVGInfo(nlsCopy)
 		nlsCopy.Status.FilteredStorageInfo.MountPoints = FilterMPInfo(nlsCopy)
@@ -153,6 +173,14 @@ func (d *Discoverer) InitResource() {
 				msg := fmt.Sprintf("create vg %s with device %v failed: %s. you can try command \"vgcreate %s %v --force\" manually on this node", vg.Name, vg.Devices, err.Error(), vg.Name, strings.Join(vg.Devices, " "))
 				log.Error(msg)
 				d.recorder.Event(nls, corev1.EventTypeWarning, localtype.EventCreateVGFailed, msg)
+				continue
+			}
+		}
+		if vg.ThinPool != nil {
+			if err := d.createThinPool(vg.Name, vg.ThinPool); err != nil {
+				msg := fmt.Sprintf("create thin pool %s in vg %s failed: %s", vg.ThinPool.Name, vg.Name, err.Error())
+				log.Error(msg)
+				d.recorder.Event(nls, corev1.EventTypeWarning, localtype.EventCreateThinPoolFailed, msg)
 			}
 		}
 	}
@@ -17,7 +17,16 @@ limitations under the License.
 package discovery
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
+
+	localv1alpha1 "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
+	"github.com/alibaba/open-local/pkg/utils/health"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestFilterInfo(t *testing.T) {
@@ -59,6 +68,73 @@ func TestFilterInfo(t *testing.T) {
 	}
 }
 
+func TestSetIOCapacities(t *testing.T) {
+	status := &localv1alpha1.NodeLocalStorageStatus{
+		NodeStorageInfo: localv1alpha1.NodeStorageInfo{
+			VolumeGroups: []localv1alpha1.VolumeGroup{{Name: "share"}, {Name: "other"}},
+			DeviceInfos:  []localv1alpha1.DeviceInfo{{Name: "/dev/vdc"}, {Name: "/dev/vdd"}},
+		},
+	}
+	setIOCapacities(status, []localv1alpha1.IOCapacityDeclaration{
+		{Name: "share", IOCapacity: localv1alpha1.IOCapacity{WriteIOPS: 1000}},
+		{Name: "/dev/vdc", IOCapacity: localv1alpha1.IOCapacity{ReadBPS: 1 << 20}},
+	})
+	vgs := status.NodeStorageInfo.VolumeGroups
+	if vgs[0].IOCapacity == nil || vgs[0].IOCapacity.WriteIOPS != 1000 || vgs[1].IOCapacity != nil {
+		t.Errorf("unexpected io capacity of vgs: %+v, %+v", vgs[0].IOCapacity, vgs[1].IOCapacity)
+	}
+	devices := status.NodeStorageInfo.DeviceInfos
+	if devices[0].IOCapacity == nil || devices[0].IOCapacity.ReadBPS != 1<<20 || devices[1].IOCapacity != nil {
+		t.Errorf("unexpected io capacity of devices: %+v, %+v", devices[0].IOCapacity, devices[1].IOCapacity)
+	}
+}
+
+func TestPerformanceTier(t *testing.T) {
+	tiers := []localv1alpha1.PerformanceTier{
+		{Name: "nvme-fast", MinReadIOPS: 100000, MaxWriteLatency: &metav1.Duration{Duration: time.Millisecond}},
+		{Name: "ssd", MinReadIOPS: 10000},
+		{Name: "hdd"},
+	}
+	tests := []struct {
+		name    string
+		profile localv1alpha1.PerformanceProfile
+		want    string
+	}{
+		{
+			name: "nvme",
+			profile: localv1alpha1.PerformanceProfile{ReadIOPS: 500000, WriteIOPS: 200000,
+				WriteLatency: localv1alpha1.LatencyPercentiles{P99: metav1.Duration{Duration: 200 * time.Microsecond}}},
+			want: "nvme-fast",
+		},
+		{
+			name: "slow writes",
+			profile: localv1alpha1.PerformanceProfile{ReadIOPS: 500000, WriteIOPS: 200000,
+				WriteLatency: localv1alpha1.LatencyPercentiles{P99: metav1.Duration{Duration: 5 * time.Millisecond}}},
+			want: "ssd",
+		},
+		{
+			name:    "write latency unknown",
+			profile: localv1alpha1.PerformanceProfile{ReadIOPS: 500000},
+			want:    "ssd",
+		},
+		{
+			name:    "first tier without thresholds",
+			profile: localv1alpha1.PerformanceProfile{ReadIOPS: 200},
+			want:    "hdd",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := performanceTier(tiers, test.profile); got != test.want {
+				t.Errorf("performanceTier() = %q, expected %q", got, test.want)
+			}
+		})
+	}
+	if got := performanceTier(nil, tests[0].profile); got != "" {
+		t.Errorf("performanceTier() without tiers = %q", got)
+	}
+}
+
 func sameStringSlice(x, y []string) bool {
 	if len(x) != len(y) {
 		return false
@@ -81,3 +157,99 @@ func sameStringSlice(x, y []string) bool {
 	}
 	return len(diff) == 0
 }
+
+func TestParentDisks(t *testing.T) {
+	sysPath, err := ioutil.TempDir("", "sysfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(sysPath)
+	// sdb1 is a partition of sdb, dm-0 is a linear lv on sdb1 and sdc
+	for _, dir := range []string{"devices/sdb/sdb1", "devices/sdc", "devices/dm-0/slaves", "class/block"} {
+		if err := os.MkdirAll(filepath.Join(sysPath, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(sysPath, "devices/sdb/sdb1/partition"), []byte("1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	links := map[string]string{
+		"class/block/sdb":          "devices/sdb",
+		"class/block/sdb1":         "devices/sdb/sdb1",
+		"class/block/sdc":          "devices/sdc",
+		"class/block/dm-0":         "devices/dm-0",
+		"devices/dm-0/slaves/sdb1": "devices/sdb/sdb1",
+		"devices/dm-0/slaves/sdc":  "devices/sdc",
+	}
+	for link, target := range links {
+		if err := os.Symlink(filepath.Join(sysPath, target), filepath.Join(sysPath, link)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[string][]string{
+		"/dev/sdb":  {"/dev/sdb"},
+		"/dev/sdb1": {"/dev/sdb"},
+		"/dev/dm-0": {"/dev/sdb", "/dev/sdc"},
+		"/dev/vdb":  {"/dev/vdb"},
+		"":          nil,
+	}
+	for device, want := range tests {
+		if got := parentDisks(sysPath, device); !reflect.DeepEqual(got, want) {
+			t.Errorf("parentDisks(%q) = %v, want %v", device, got, want)
+		}
+	}
+}
+
+func TestApplyHealth(t *testing.T) {
+	disksOf := func(device string) []string {
+		return map[string][]string{
+			"/dev/sdb":  {"/dev/sdb"},
+			"/dev/sdb1": {"/dev/sdb"},
+			"/dev/sdc":  {"/dev/sdc"},
+			"/dev/dm-0": {"/dev/sdc"},
+		}[device]
+	}
+	newStatus := func() *localv1alpha1.NodeLocalStorageStatus {
+		return &localv1alpha1.NodeLocalStorageStatus{
+			NodeStorageInfo: localv1alpha1.NodeStorageInfo{
+				VolumeGroups: []localv1alpha1.VolumeGroup{
+					{Name: "share", PhysicalVolumes: []string{"/dev/sdb1"}, Condition: localv1alpha1.StorageReady},
+					{Name: "other", PhysicalVolumes: []string{"/dev/sdc"}, Condition: localv1alpha1.StorageFull},
+				},
+				DeviceInfos: []localv1alpha1.DeviceInfo{
+					{Name: "/dev/sdb", Condition: localv1alpha1.StorageReady},
+					{Name: "/dev/sdb1", Condition: localv1alpha1.StorageReady},
+					{Name: "/dev/sdc", Condition: localv1alpha1.StorageReady},
+				},
+				MountPoints: []localv1alpha1.MountPoint{
+					{Name: "/mnt/open-local/disk-0", Device: "/dev/dm-0", Condition: localv1alpha1.StorageReady},
+				},
+			},
+		}
+	}
+
+	status := newStatus()
+	applyHealth(status, map[string]*health.Report{}, disksOf)
+	if status.NodeStorageInfo.State.Type != localv1alpha1.StorageReady || status.NodeStorageInfo.VolumeGroups[0].Condition != localv1alpha1.StorageReady {
+		t.Errorf("unexpected status without faulty disk: %+v", status.NodeStorageInfo)
+	}
+
+	status = newStatus()
+	applyHealth(status, map[string]*health.Report{
+		"/dev/sdb": {Device: "/dev/sdb", Faults: []string{"SMART overall-health self-assessment failed"}},
+	}, disksOf)
+	info := status.NodeStorageInfo
+	if info.VolumeGroups[0].Condition != localv1alpha1.StorageFault || info.VolumeGroups[1].Condition != localv1alpha1.StorageFull {
+		t.Errorf("unexpected condition of vgs: %s, %s", info.VolumeGroups[0].Condition, info.VolumeGroups[1].Condition)
+	}
+	if info.DeviceInfos[0].Condition != localv1alpha1.StorageFault || info.DeviceInfos[1].Condition != localv1alpha1.StorageFault || info.DeviceInfos[2].Condition != localv1alpha1.StorageReady {
+		t.Errorf("unexpected condition of devices: %+v", info.DeviceInfos)
+	}
+	if info.MountPoints[0].Condition != localv1alpha1.StorageReady {
+		t.Errorf("unexpected condition of mount point: %s", info.MountPoints[0].Condition)
+	}
+	if info.State.Type != localv1alpha1.StorageFault || info.State.Message != "/dev/sdb: SMART overall-health self-assessment failed" {
+		t.Errorf("unexpected state: %+v", info.State)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	localv1alpha1 "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
+	"github.com/alibaba/open-local/pkg/utils/health"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CheckHealth reads SMART or NVMe health log of the disks underlying devices, VGs and mount points
+// in status of NodeLocalStorage, and emits events when a disk fails, recovers or degrades.
+// The results are reported as conditions by Discover
+func (d *Discoverer) CheckHealth() {
+	nls, err := d.localclientset.CsiV1alpha1().NodeLocalStorages().Get(context.Background(), d.Nodename, metav1.GetOptions{})
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			log.Errorf("get NodeLocalStorages failed: %s", err.Error())
+		}
+		return
+	}
+
+	disks := make(map[string]bool)
+	addDisks := func(device string) {
+		for _, disk := range parentDisks(d.SysPath, device) {
+			disks[disk] = true
+		}
+	}
+	for _, device := range nls.Status.NodeStorageInfo.DeviceInfos {
+		addDisks(device.Name)
+	}
+	for _, vg := range nls.Status.NodeStorageInfo.VolumeGroups {
+		for _, pv := range vg.PhysicalVolumes {
+			addDisks(pv)
+		}
+	}
+	for _, mp := range nls.Status.NodeStorageInfo.MountPoints {
+		addDisks(mp.Device)
+	}
+
+	reports := make(map[string]*health.Report, len(disks))
+	for disk := range disks {
+		report, err := health.Check(disk, d.healthRunner)
+		if errors.Is(err, health.ErrNoHealthInfo) {
+			log.Debugf("disk %s does not report health information", disk)
+			continue
+		} else if err != nil {
+			// keep the last report, so that a faulty disk is not regarded as recovered
+			log.Warningf("check health of disk %s failed: %s", disk, err.Error())
+			if last := d.healthReport(disk); last != nil {
+				reports[disk] = last
+			}
+			continue
+		}
+		d.recordHealthEvent(nls, d.healthReport(disk), report)
+		reports[disk] = report
+	}
+
+	d.healthLock.Lock()
+	d.healthReports = reports
+	d.healthLock.Unlock()
+}
+
+func (d *Discoverer) healthReport(disk string) *health.Report {
+	d.healthLock.RLock()
+	defer d.healthLock.RUnlock()
+	return d.healthReports[disk]
+}
+
+func (d *Discoverer) faultyDisks() map[string]*health.Report {
+	d.healthLock.RLock()
+	defer d.healthLock.RUnlock()
+	faulty := make(map[string]*health.Report)
+	for disk, report := range d.healthReports {
+		if !report.Healthy() {
+			faulty[disk] = report
+		}
+	}
+	return faulty
+}
+
+func (d *Discoverer) recordHealthEvent(nls *localv1alpha1.NodeLocalStorage, last, report *health.Report) {
+	switch {
+	case !report.Healthy() && (last == nil || last.Healthy()):
+		msg := fmt.Sprintf("disk %s is faulty: %s", report.Device, strings.Join(report.Faults, "; "))
+		log.Error(msg)
+		d.recorder.Event(nls, corev1.EventTypeWarning, localtype.EventDiskFault, msg)
+	case report.Healthy() && last != nil && !last.Healthy():
+		msg := fmt.Sprintf("disk %s is recovered", report.Device)
+		log.Info(msg)
+		d.recorder.Event(nls, corev1.EventTypeNormal, localtype.EventDiskRecovered, msg)
+	}
+	if len(report.Warnings) > 0 && (last == nil || !reflect.DeepEqual(last.Warnings, report.Warnings)) {
+		msg := fmt.Sprintf("disk %s is degraded: %s", report.Device, strings.Join(report.Warnings, "; "))
+		log.Warning(msg)
+		d.recorder.Event(nls, corev1.EventTypeWarning, localtype.EventDiskDegraded, msg)
+	}
+}
+
+// applyHealth sets condition of devices, VGs and mount points on faulty disks to StorageFault,
+// and the state of node to StorageFault if any
+func applyHealth(status *localv1alpha1.NodeLocalStorageStatus, faulty map[string]*health.Report, disksOf func(device string) []string) {
+	isFaulty := func(devices ...string) bool {
+		for _, device := range devices {
+			for _, disk := range disksOf(device) {
+				if _, exist := faulty[disk]; exist {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for i, device := range status.NodeStorageInfo.DeviceInfos {
+		if isFaulty(device.Name) {
+			status.NodeStorageInfo.DeviceInfos[i].Condition = localv1alpha1.StorageFault
+		}
+	}
+	for i, vg := range status.NodeStorageInfo.VolumeGroups {
+		if isFaulty(vg.PhysicalVolumes...) {
+			status.NodeStorageInfo.VolumeGroups[i].Condition = localv1alpha1.StorageFault
+		}
+	}
+	for i, mp := range status.NodeStorageInfo.MountPoints {
+		if isFaulty(mp.Device) {
+			status.NodeStorageInfo.MountPoints[i].Condition = localv1alpha1.StorageFault
+		}
+	}
+
+	if len(faulty) == 0 {
+		status.NodeStorageInfo.State.Type = localv1alpha1.StorageReady
+		status.NodeStorageInfo.State.Reason = ""
+		status.NodeStorageInfo.State.Message = ""
+		return
+	}
+	disks := make([]string, 0, len(faulty))
+	for disk := range faulty {
+		disks = append(disks, disk)
+	}
+	sort.Strings(disks)
+	var messages []string
+	for _, disk := range disks {
+		messages = append(messages, fmt.Sprintf("%s: %s", disk, strings.Join(faulty[disk].Faults, ", ")))
+	}
+	status.NodeStorageInfo.State.Type = localv1alpha1.StorageFault
+	status.NodeStorageInfo.State.Reason = localtype.EventDiskFault
+	status.NodeStorageInfo.State.Message = strings.Join(messages, "; ")
+}
+
+// parentDisks returns the whole disks underlying device, e.g. /dev/sdb for partition /dev/sdb1,
+// and the disks of all slaves for device mapper /dev/mapper/vg-lv
+func parentDisks(sysPath, device string) []string {
+	if device == "" {
+		return nil
+	}
+	if resolved, err := filepath.EvalSymlinks(device); err == nil {
+		device = resolved
+	}
+	name := filepath.Base(device)
+	blockPath := filepath.Join(sysPath, "class/block", name)
+
+	if slaves, err := ioutil.ReadDir(filepath.Join(blockPath, "slaves")); err == nil && len(slaves) > 0 {
+		var disks []string
+		for _, slave := range slaves {
+			disks = append(disks, parentDisks(sysPath, filepath.Join("/dev", slave.Name()))...)
+		}
+		return disks
+	}
+	if _, err := os.Stat(filepath.Join(blockPath, "partition")); err == nil {
+		// /sys/class/block/sdb1 links to .../block/sdb/sdb1
+		if resolved, err := filepath.EvalSymlinks(blockPath); err == nil {
+			return []string{filepath.Join("/dev", filepath.Base(filepath.Dir(resolved)))}
+		}
+	}
+	return []string{filepath.Join("/dev", name)}
+}
@@ -0,0 +1,39 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	localv1alpha1 "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
+)
+
+// setIOCapacities reports the io budget declared in spec for VGs and devices in status
+func setIOCapacities(status *localv1alpha1.NodeLocalStorageStatus, declarations []localv1alpha1.IOCapacityDeclaration) {
+	capacities := make(map[string]localv1alpha1.IOCapacity, len(declarations))
+	for _, declaration := range declarations {
+		capacities[declaration.Name] = declaration.IOCapacity
+	}
+	for i, vg := range status.NodeStorageInfo.VolumeGroups {
+		if capacity, exist := capacities[vg.Name]; exist {
+			status.NodeStorageInfo.VolumeGroups[i].IOCapacity = &capacity
+		}
+	}
+	for i, device := range status.NodeStorageInfo.DeviceInfos {
+		if capacity, exist := capacities[device.Name]; exist {
+			status.NodeStorageInfo.DeviceInfos[i].IOCapacity = &capacity
+		}
+	}
+}
@@ -63,6 +63,10 @@ func (d *Discoverer) discoverVGs(newStatus *localv1alpha1.NodeLocalStorageStatus
 			log.Errorf("List volume group %s error: %s", vgname, err.Error())
 			continue
 		}
+		abnormalLVs, err := vg.ListAbnormalLogicalVolumes()
+		if err != nil {
+			log.Errorf("List abnormal logical volumes of volume group %s error: %s", vgname, err.Error())
+		}
 		vgCrd.Allocatable = vgCrd.Total
 		for _, lvname := range logicalVolumeNames {
 			var lv localv1alpha1.LogicalVolume
@@ -74,13 +78,30 @@ func (d *Discoverer) discoverVGs(newStatus *localv1alpha1.NodeLocalStorageStatus
 				continue
 			}
 			lv.Total = tmplv.SizeInBytes()
-			if !d.isLocalLV(lvname) {
+			// thin lv is allocated from thin pool, whose size is already excluded
+			if !d.isLocalLV(lvname) && !tmplv.IsThin() {
 				vgCrd.Allocatable -= lv.Total
 			}
 			lv.Condition = localv1alpha1.StorageReady
+			if msg, abnormal := abnormalLVs[lvname]; abnormal {
+				lv.Condition = localv1alpha1.StorageFault
+				lv.Message = msg
+			}
 			vgCrd.LogicalVolumes = append(vgCrd.LogicalVolumes, lv)
 		}
 
+		// ThinPool
+		if pool, err := vg.LookupThinPool(); err == nil {
+			vgCrd.ThinPool = &localv1alpha1.ThinPool{
+				Name:            pool.Name(),
+				Total:           pool.SizeInBytes(),
+				DataPercent:     pool.DataPercent(),
+				MetadataPercent: pool.MetadataPercent(),
+			}
+		} else if err != lvm.ErrThinPoolNotFound {
+			log.Errorf("Look up thin pool of volume group %s error: %s", vgname, err.Error())
+		}
+
 		// check if vgCrd.Allocatable is correct
 		if info, exist := reservedVGInfo[vg.Name()]; exist {
 			// reservedPercent
@@ -133,6 +154,26 @@ func (d *Discoverer) createVG(vgname string, devices []string) error {
 	return nil
 }
 
+func (d *Discoverer) createThinPool(vgname string, thinPool *localv1alpha1.ThinPoolToBeInited) error {
+	vg, err := lvm.LookupVolumeGroup(vgname)
+	if err != nil {
+		return err
+	}
+	if _, err := vg.LookupThinPool(); err != lvm.ErrThinPoolNotFound {
+		// thin pool already exists, or lookup failed
+		return err
+	}
+	percent := thinPool.Percent
+	if percent == 0 {
+		percent = localtype.DefaultThinPoolPercent
+	}
+	if _, err := vg.CreateThinPool(thinPool.Name, percent); err != nil {
+		log.Errorf("create thin pool %s/%s error: %s", vgname, thinPool.Name, err.Error())
+		return err
+	}
+	return nil
+}
+
 // isLocalLV check if lv is created by open-local according to the lv name
 func (d *Discoverer) isLocalLV(lvname string) bool {
 	prefixlen := len(d.Configuration.LogicalVolumeNamePrefix)
@@ -0,0 +1,87 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"reflect"
+	"testing"
+
+	localv1alpha1 "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
+	"github.com/alibaba/open-local/pkg/utils/lvm"
+)
+
+func TestCreateVG(t *testing.T) {
+	fake := lvm.NewFakeExecutor()
+	defer lvm.SetExecutor(lvm.SetExecutor(fake))
+	for _, cmdline := range []string{"pvcreate /dev/vdb", "pvcreate /dev/vdc", "vgcreate share /dev/vdb /dev/vdc", "pvscan --cache", "vgscan --cache"} {
+		fake.Record(cmdline, "")
+	}
+
+	d := &Discoverer{}
+	if err := d.createVG("share", []string{"/dev/vdb", "/dev/vdc"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"pvcreate /dev/vdb", "pvcreate /dev/vdc", "vgcreate share /dev/vdb /dev/vdc", "pvscan --cache", "vgscan --cache"}
+	if commands := fake.Commands(); !reflect.DeepEqual(commands, expected) {
+		t.Errorf("commands are %v, expected %v", commands, expected)
+	}
+}
+
+func TestCreateThinPool(t *testing.T) {
+	const (
+		vgs       = "vgs --reportformat=json --units=b --nosuffix --options=vg_name share"
+		lvs       = "lvs --reportformat=json --units=b --nosuffix --options=lv_name,lv_size,vg_name,lv_attr,data_percent,metadata_percent share"
+		create    = "lvcreate --type=thin-pool --extents=50%FREE --name=pool share"
+		noPool    = `{"report": [{"lv": [{"lv_name":"pv-1", "vg_name":"share", "lv_size":"1073741824", "lv_attr":"-wi-ao----"}]}]}`
+		existPool = `{"report": [{"lv": [{"lv_name":"pool", "vg_name":"share", "lv_size":"10737418240", "lv_attr":"twi-a-tz--"}]}]}`
+	)
+	tests := []struct {
+		name     string
+		lvs      []string
+		commands []string
+	}{
+		{
+			name:     "create thin pool",
+			lvs:      []string{noPool, existPool},
+			commands: []string{vgs, lvs, create, lvs},
+		},
+		{
+			name:     "thin pool exists",
+			lvs:      []string{existPool},
+			commands: []string{vgs, lvs},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := lvm.NewFakeExecutor()
+			defer lvm.SetExecutor(lvm.SetExecutor(fake))
+			fake.Record(vgs, `{"report": [{"vg": [{"vg_name":"share"}]}]}`)
+			for _, out := range test.lvs {
+				fake.Record(lvs, out)
+			}
+			fake.Record(create, "")
+
+			d := &Discoverer{}
+			if err := d.createThinPool("share", &localv1alpha1.ThinPoolToBeInited{Name: "pool", Percent: 50}); err != nil {
+				t.Fatal(err)
+			}
+			if commands := fake.Commands(); !reflect.DeepEqual(commands, test.commands) {
+				t.Errorf("commands are %v, expected %v", commands, test.commands)
+			}
+		})
+	}
+}
@@ -131,7 +131,8 @@ func getAllLocalSnapshotLV() (lvs []*lvm.LogicalVolume, err error) {
 				log.Errorf("[getAllLocalSnapshotLV]List logical volume %s error: %s", lvName, err.Error())
 				continue
 			}
-			if tmplv.IsSnapshot() {
+			// thin snapshot is allocated from thin pool on demand, no need to expand
+			if tmplv.IsSnapshot() && !tmplv.IsThin() {
 				lvs = append(lvs, tmplv)
 			}
 		}
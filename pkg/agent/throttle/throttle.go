@@ -0,0 +1,354 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/utils"
+	"github.com/alibaba/open-local/pkg/utils/cgroup"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// ConditionIOThrottling is the condition of pvc which reports the applied io limits
+	ConditionIOThrottling corev1.PersistentVolumeClaimConditionType = "IOThrottling"
+	// ReasonAnnotations means the io limits are from the annotations of pvc
+	ReasonAnnotations = "Annotations"
+	// ReasonStorageClass means the io limits are from the parameters of storageclass
+	ReasonStorageClass = "StorageClass"
+
+	EventIOThrottlingUpdated = "IOThrottlingUpdated"
+	EventIOThrottlingFailed  = "IOThrottlingFailed"
+
+	// ResyncPeriod is the period to re-apply io limits, which are lost when the cgroup of pod is recreated
+	ResyncPeriod = time.Minute
+)
+
+// Controller applies the io limits in the annotations of pvc to the cgroups of pods
+// using the volume on this node, so that the limits can be changed without restarting pods
+type Controller struct {
+	nodeName      string
+	cgroupRoot    string
+	kubeclientset kubernetes.Interface
+
+	pvcLister corelisters.PersistentVolumeClaimLister
+	pvcSynced cache.InformerSynced
+	pvLister  corelisters.PersistentVolumeLister
+	pvSynced  cache.InformerSynced
+	podLister corelisters.PodLister
+	podSynced cache.InformerSynced
+
+	manager      *cgroup.Manager
+	deviceNumber func(device string) (uint32, uint32, error)
+
+	workqueue workqueue.RateLimitingInterface
+	recorder  record.EventRecorder
+}
+
+// NewController returns a new io throttling controller. podInformer should only list the pods on this node.
+func NewController(
+	nodeName string,
+	sysPath string,
+	kubeclientset kubernetes.Interface,
+	pvcInformer coreinformers.PersistentVolumeClaimInformer,
+	pvInformer coreinformers.PersistentVolumeInformer,
+	podInformer coreinformers.PodInformer,
+	recorder record.EventRecorder) *Controller {
+
+	c := &Controller{
+		nodeName:      nodeName,
+		cgroupRoot:    filepath.Join(sysPath, "fs/cgroup"),
+		kubeclientset: kubeclientset,
+		pvcLister:     pvcInformer.Lister(),
+		pvcSynced:     pvcInformer.Informer().HasSynced,
+		pvLister:      pvInformer.Lister(),
+		pvSynced:      pvInformer.Informer().HasSynced,
+		podLister:     podInformer.Lister(),
+		podSynced:     podInformer.Informer().HasSynced,
+		deviceNumber:  cgroup.DeviceNumber,
+		workqueue:     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "IOThrottling"),
+		recorder:      recorder,
+	}
+
+	// update events are also triggered by resync, which re-applies the limits periodically
+	pvcInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueuePVC,
+		UpdateFunc: func(old, new interface{}) {
+			c.enqueuePVC(new)
+		},
+	})
+	podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueuePodPVCs,
+		UpdateFunc: func(old, new interface{}) {
+			c.enqueuePodPVCs(new)
+		},
+	})
+
+	return c
+}
+
+// Run waits for the caches to be synced and starts workers until stopCh is closed
+func (c *Controller) Run(workers int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+
+	if ok := cache.WaitForCacheSync(stopCh, c.pvcSynced, c.pvSynced, c.podSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+	for i := 0; i < workers; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+	log.Info("Started io throttling controller")
+	<-stopCh
+	log.Info("Shutting down io throttling controller")
+	return nil
+}
+
+func (c *Controller) enqueuePVC(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+func (c *Controller) enqueuePodPVCs(obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok || pod.Spec.NodeName != c.nodeName {
+		return
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			c.workqueue.Add(pod.Namespace + "/" + volume.PersistentVolumeClaim.ClaimName)
+		}
+	}
+}
+
+func (c *Controller) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	obj, shutdown := c.workqueue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.workqueue.Done(obj)
+	key := obj.(string)
+	if err := c.syncHandler(key); err != nil {
+		c.workqueue.AddRateLimited(key)
+		utilruntime.HandleError(fmt.Errorf("error syncing io throttling of pvc %s: %s, requeuing", key, err.Error()))
+		return true
+	}
+	c.workqueue.Forget(obj)
+	return true
+}
+
+func (c *Controller) syncHandler(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil
+	}
+	pvc, err := c.pvcLister.PersistentVolumeClaims(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if pvc.Status.Phase != corev1.ClaimBound || pvc.Spec.VolumeName == "" {
+		return nil
+	}
+	annotated := cgroup.HasLimits(pvc.Annotations, cgroup.AnnotationLimitKeys)
+	condition := getCondition(pvc)
+	// limits of storageclass are set by csi plugin, unless they are overridden by annotations before
+	if !annotated && condition == nil {
+		return nil
+	}
+	pv, err := c.pvLister.Get(pvc.Spec.VolumeName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if pv.Spec.CSI == nil || !utils.ContainsProvisioner(pv.Spec.CSI.Driver) {
+		return nil
+	}
+	pods, err := c.podsUsing(pvc)
+	if err != nil || len(pods) == 0 {
+		return err
+	}
+
+	device := volumeDevice(pv)
+	if device == "" {
+		log.Debugf("io throttling of %s volume %s is not supported", pv.Spec.CSI.VolumeAttributes[localtype.VolumeTypeKey], pv.Name)
+		return nil
+	}
+	limits := cgroup.Limits{}
+	if err := cgroup.ParseLimits(pv.Spec.CSI.VolumeAttributes, cgroup.VolumeLimitKeys, &limits); err != nil {
+		log.Warningf("ignore io limits of pv %s: %s", pv.Name, err.Error())
+	}
+	if err := cgroup.ParseLimits(pvc.Annotations, cgroup.AnnotationLimitKeys, &limits); err != nil {
+		// wait for the annotations to be fixed
+		c.recorder.Event(pvc, corev1.EventTypeWarning, EventIOThrottlingFailed, err.Error())
+		return nil
+	}
+
+	major, minor, err := c.deviceNumber(device)
+	if err != nil {
+		return err
+	}
+	manager, err := c.getManager()
+	if err != nil {
+		return err
+	}
+	var applied []string
+	for _, pod := range pods {
+		err := manager.SetLimits(string(pod.UID), major, minor, limits)
+		if errors.Is(err, os.ErrNotExist) {
+			// cgroup of pod is not created yet, the limits are applied by csi plugin when published
+			continue
+		}
+		if err != nil {
+			c.recorder.Eventf(pvc, corev1.EventTypeWarning, EventIOThrottlingFailed, "failed to set io limits of pod %s: %s", pod.Name, err.Error())
+			return err
+		}
+		applied = append(applied, pod.Name)
+	}
+	if len(applied) == 0 {
+		return nil
+	}
+
+	reason := ReasonStorageClass
+	if annotated {
+		reason = ReasonAnnotations
+	}
+	message := fmt.Sprintf("%s on node %s", limits.String(), c.nodeName)
+	if condition != nil && condition.Reason == reason && condition.Message == message {
+		return nil
+	}
+	log.Infof("io limits of pvc %s are updated to %s for pods %v", key, limits.String(), applied)
+	c.recorder.Eventf(pvc, corev1.EventTypeNormal, EventIOThrottlingUpdated, "io limits are updated to %s for pods %s", limits.String(), strings.Join(applied, ","))
+	return c.updateCondition(pvc, reason, message)
+}
+
+// podsUsing returns the running pods on this node which use pvc
+func (c *Controller) podsUsing(pvc *corev1.PersistentVolumeClaim) ([]*corev1.Pod, error) {
+	pods, err := c.podLister.Pods(pvc.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var using []*corev1.Pod
+	for _, pod := range pods {
+		if pod.Spec.NodeName != c.nodeName || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == pvc.Name {
+				using = append(using, pod)
+				break
+			}
+		}
+	}
+	sort.Slice(using, func(i, j int) bool { return using[i].Name < using[j].Name })
+	return using, nil
+}
+
+func (c *Controller) updateCondition(pvc *corev1.PersistentVolumeClaim, reason, message string) error {
+	pvc = pvc.DeepCopy()
+	condition := corev1.PersistentVolumeClaimCondition{
+		Type:               ConditionIOThrottling,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+	found := false
+	for i := range pvc.Status.Conditions {
+		if pvc.Status.Conditions[i].Type == ConditionIOThrottling {
+			pvc.Status.Conditions[i] = condition
+			found = true
+		}
+	}
+	if !found {
+		pvc.Status.Conditions = append(pvc.Status.Conditions, condition)
+	}
+	_, err := c.kubeclientset.CoreV1().PersistentVolumeClaims(pvc.Namespace).UpdateStatus(context.Background(), pvc, metav1.UpdateOptions{})
+	return err
+}
+
+// getManager detects the cgroup version and driver on first use
+func (c *Controller) getManager() (*cgroup.Manager, error) {
+	if c.manager != nil {
+		return c.manager, nil
+	}
+	manager, err := cgroup.Detect(c.cgroupRoot)
+	if err != nil {
+		return nil, err
+	}
+	c.manager = manager
+	return manager, nil
+}
+
+func getCondition(pvc *corev1.PersistentVolumeClaim) *corev1.PersistentVolumeClaimCondition {
+	for i := range pvc.Status.Conditions {
+		if pvc.Status.Conditions[i].Type == ConditionIOThrottling {
+			return &pvc.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// volumeDevice returns the block device of lvm or device volume
+func volumeDevice(pv *corev1.PersistentVolume) string {
+	attributes := pv.Spec.CSI.VolumeAttributes
+	switch localtype.VolumeType(attributes[localtype.VolumeTypeKey]) {
+	case localtype.VolumeTypeLVM:
+		lvName := pv.Name
+		if attributes[localtype.ParamSnapshotReadonly] == "true" && attributes[localtype.ParamSnapshotName] != "" {
+			lvName = attributes[localtype.ParamSnapshotName]
+		}
+		return filepath.Join("/dev", attributes[localtype.VGName], lvName)
+	case localtype.VolumeTypeDevice:
+		return attributes[localtype.DeviceName]
+	}
+	return ""
+}
@@ -0,0 +1,187 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	nodeName = "node1"
+	podUID   = "2a7bbb9c-c915-4006-84d7-0e3ac9d8d70f"
+)
+
+func newPVC(annotations map[string]string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default", Annotations: annotations},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pv1"},
+		Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+	}
+}
+
+func TestSyncHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		ioMax       string
+		reason      string
+		event       string
+	}{
+		{
+			name:        "annotations override storageclass",
+			annotations: map[string]string{localtype.AnnReadIOPS: "200", localtype.AnnBPS: "10Mi"},
+			ioMax:       "253:3 rbps=10485760 wbps=10485760 riops=200 wiops=100",
+			reason:      ReasonAnnotations,
+			event:       "Normal " + EventIOThrottlingUpdated,
+		},
+		{
+			name:        "invalid annotations",
+			annotations: map[string]string{localtype.AnnIOPS: "-1"},
+			event:       "Warning " + EventIOThrottlingFailed,
+		},
+		{
+			name: "no annotations",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sysPath, err := ioutil.TempDir("", "throttle")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(sysPath)
+			cgroupRoot := filepath.Join(sysPath, "fs/cgroup")
+			podPath := filepath.Join(cgroupRoot, "kubepods/pod"+podUID)
+			if err := os.MkdirAll(podPath, 0755); err != nil {
+				t.Fatal(err)
+			}
+			for _, file := range []string{filepath.Join(cgroupRoot, "cgroup.controllers"), filepath.Join(podPath, "io.max")} {
+				if err := ioutil.WriteFile(file, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			pvc := newPVC(test.annotations)
+			pv := &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "pv1"},
+				Spec: corev1.PersistentVolumeSpec{
+					PersistentVolumeSource: corev1.PersistentVolumeSource{
+						CSI: &corev1.CSIPersistentVolumeSource{
+							Driver: localtype.ProvisionerName,
+							VolumeAttributes: map[string]string{
+								localtype.VolumeTypeKey:   string(localtype.VolumeTypeLVM),
+								localtype.VGName:          "share",
+								localtype.VolumeWriteIOPS: "100",
+							},
+						},
+					},
+				},
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: types.UID(podUID)},
+				Spec: corev1.PodSpec{
+					NodeName: nodeName,
+					Volumes: []corev1.Volume{{
+						Name: "data",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"},
+						},
+					}},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning},
+			}
+
+			client := k8sfake.NewSimpleClientset(pvc, pv, pod)
+			factory := informers.NewSharedInformerFactory(client, 0)
+			recorder := record.NewFakeRecorder(10)
+			c := NewController(nodeName, sysPath, client,
+				factory.Core().V1().PersistentVolumeClaims(),
+				factory.Core().V1().PersistentVolumes(),
+				factory.Core().V1().Pods(),
+				recorder)
+			c.deviceNumber = func(device string) (uint32, uint32, error) {
+				if device != "/dev/share/pv1" {
+					t.Errorf("unexpected device %s", device)
+				}
+				return 253, 3, nil
+			}
+			factory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(pvc)
+			factory.Core().V1().PersistentVolumes().Informer().GetIndexer().Add(pv)
+			factory.Core().V1().Pods().Informer().GetIndexer().Add(pod)
+
+			if err := c.syncHandler("default/data"); err != nil {
+				t.Fatalf("syncHandler: %s", err.Error())
+			}
+
+			data, err := ioutil.ReadFile(filepath.Join(podPath, "io.max"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != test.ioMax {
+				t.Errorf("expected io.max %q, got %q", test.ioMax, string(data))
+			}
+
+			event := ""
+			select {
+			case event = <-recorder.Events:
+			default:
+			}
+			if !strings.HasPrefix(event, test.event) || test.event == "" && event != "" {
+				t.Errorf("expected event %q, got %q", test.event, event)
+			}
+
+			updated, err := client.CoreV1().PersistentVolumeClaims("default").Get(context.Background(), "data", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := getCondition(updated)
+			if test.reason == "" {
+				if condition != nil {
+					t.Errorf("unexpected condition %+v", condition)
+				}
+				return
+			}
+			if condition == nil || condition.Reason != test.reason || condition.Status != corev1.ConditionTrue {
+				t.Fatalf("unexpected condition %+v", condition)
+			}
+
+			// the condition is not updated again if the limits are unchanged
+			factory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Update(updated)
+			if err := c.syncHandler("default/data"); err != nil {
+				t.Fatalf("syncHandler: %s", err.Error())
+			}
+			select {
+			case event = <-recorder.Events:
+				t.Errorf("unexpected event %q", event)
+			default:
+			}
+		})
+	}
+}
@@ -46,15 +46,19 @@ type NodeLocalStorageInitConfigList struct {
 
 // GlobalConfig is configuration for agent to create default NodeLocalStorage
 type GlobalConfig struct {
-	ListConfig         ListConfig         `json:"listConfig,omitempty"`
-	ResourceToBeInited ResourceToBeInited `json:"resourceToBeInited,omitempty"`
+	ListConfig         ListConfig              `json:"listConfig,omitempty"`
+	ResourceToBeInited ResourceToBeInited      `json:"resourceToBeInited,omitempty"`
+	IOCapacities       []IOCapacityDeclaration `json:"ioCapacities,omitempty"`
+	// PerformanceTiers are shared by all nodes
+	PerformanceTiers []PerformanceTier `json:"performanceTiers,omitempty"`
 }
 
 // NodeConfig is configuration for agent to create NodeLocalStorage of specific node
 type NodeConfig struct {
-	Selector           *metav1.LabelSelector `json:"selector,omitempty"`
-	ListConfig         ListConfig            `json:"listConfig,omitempty"`
-	ResourceToBeInited ResourceToBeInited    `json:"resourceToBeInited,omitempty"`
+	Selector           *metav1.LabelSelector   `json:"selector,omitempty"`
+	ListConfig         ListConfig              `json:"listConfig,omitempty"`
+	ResourceToBeInited ResourceToBeInited      `json:"resourceToBeInited,omitempty"`
+	IOCapacities       []IOCapacityDeclaration `json:"ioCapacities,omitempty"`
 }
 
 // NodeLocalStorageInitConfigSpec is spec of NodeLocalStorageInitConfig
@@ -59,6 +59,15 @@ type NodeLocalStorageSpec struct {
 	NodeName           string             `json:"nodeName,omitempty"`
 	ListConfig         ListConfig         `json:"listConfig,omitempty"`
 	ResourceToBeInited ResourceToBeInited `json:"resourceToBeInited,omitempty"`
+	// IOCapacities declares the io budget of VGs and devices, which is used by scheduler
+	// +kubebuilder:validation:MaxItems=50
+	// +optional
+	IOCapacities []IOCapacityDeclaration `json:"ioCapacities,omitempty"`
+	// PerformanceTiers classifies VGs and devices by their benchmark results,
+	// the first matching tier in the list is reported
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	PerformanceTiers []PerformanceTier `json:"performanceTiers,omitempty"`
 }
 
 // NodeLocalStorageStatus defines the observed state of NodeLocalStorage
@@ -67,6 +76,10 @@ type NodeLocalStorageStatus struct {
 	// Important: Run "make" to regenerate code after modifying this file
 	NodeStorageInfo     NodeStorageInfo     `json:"nodeStorageInfo,omitempty"`
 	FilteredStorageInfo FilteredStorageInfo `json:"filteredStorageInfo,omitempty"`
+	// PerformanceProfiles are the benchmark results of filtered VGs and devices,
+	// which are measured by agent in benchmark mode
+	// +optional
+	PerformanceProfiles []PerformanceProfile `json:"performanceProfiles,omitempty"`
 }
 
 type ListConfig struct {
@@ -130,6 +143,106 @@ type VGToBeInited struct {
 	// +kubebuilder:validation:MaxItems=50
 	// +kubebuilder:validation:UniqueItems=false
 	Devices []string `json:"devices"`
+	// ThinPool defines the thin pool to be created in the volume group
+	// +optional
+	ThinPool *ThinPoolToBeInited `json:"thinPool,omitempty"`
+}
+
+type ThinPoolToBeInited struct {
+	// Name is the name of thin pool
+	// +kubebuilder:validation:MaxLength=128
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Percent is the percentage of free space of the volume group used by the thin pool, 100 by default
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Percent int32 `json:"percent,omitempty"`
+	// OvercommitRatio is the ratio of the total size of thin volumes to the size of thin pool
+	// which is allowed by scheduler, "1" by default
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	// +optional
+	OvercommitRatio string `json:"overcommitRatio,omitempty"`
+}
+
+// IOCapacity is the io budget of a VG or device, zero means unlimited
+type IOCapacity struct {
+	// ReadIOPS is the total read IOPS limit of volumes
+	// +optional
+	ReadIOPS uint64 `json:"readIOPS,omitempty"`
+	// WriteIOPS is the total write IOPS limit of volumes
+	// +optional
+	WriteIOPS uint64 `json:"writeIOPS,omitempty"`
+	// ReadBPS is the total read bytes per second limit of volumes
+	// +optional
+	ReadBPS uint64 `json:"readBPS,omitempty"`
+	// WriteBPS is the total write bytes per second limit of volumes
+	// +optional
+	WriteBPS uint64 `json:"writeBPS,omitempty"`
+}
+
+type IOCapacityDeclaration struct {
+	// Name is the VG name or the device name, e.g. /dev/sdb
+	// +kubebuilder:validation:MaxLength=128
+	// +kubebuilder:validation:MinLength=1
+	Name       string `json:"name"`
+	IOCapacity `json:",inline"`
+}
+
+// PerformanceTier is a named class of VGs and devices, zero thresholds are not checked
+type PerformanceTier struct {
+	// Name is the tier name referred by the performanceTier parameter of storage class, e.g. nvme-fast
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// MinReadIOPS is the minimum random read IOPS
+	// +optional
+	MinReadIOPS uint64 `json:"minReadIOPS,omitempty"`
+	// MinWriteIOPS is the minimum random write IOPS
+	// +optional
+	MinWriteIOPS uint64 `json:"minWriteIOPS,omitempty"`
+	// MinReadBPS is the minimum sequential read bytes per second
+	// +optional
+	MinReadBPS uint64 `json:"minReadBPS,omitempty"`
+	// MinWriteBPS is the minimum sequential write bytes per second
+	// +optional
+	MinWriteBPS uint64 `json:"minWriteBPS,omitempty"`
+	// MaxReadLatency is the maximum p99 latency of random reads
+	// +optional
+	MaxReadLatency *metav1.Duration `json:"maxReadLatency,omitempty"`
+	// MaxWriteLatency is the maximum p99 latency of random writes
+	// +optional
+	MaxWriteLatency *metav1.Duration `json:"maxWriteLatency,omitempty"`
+}
+
+// PerformanceProfile is the benchmark result of a VG or device
+type PerformanceProfile struct {
+	// Name is the VG name or the device name
+	Name string `json:"name"`
+	// Tier is the first performance tier in spec the result matches
+	// +optional
+	Tier string `json:"tier,omitempty"`
+	// ReadIOPS is the random read IOPS
+	ReadIOPS uint64 `json:"readIOPS"`
+	// WriteIOPS is the random write IOPS
+	WriteIOPS uint64 `json:"writeIOPS"`
+	// ReadBPS is the sequential read bytes per second
+	ReadBPS uint64 `json:"readBPS"`
+	// WriteBPS is the sequential write bytes per second
+	WriteBPS uint64 `json:"writeBPS"`
+	// ReadLatency is the latency of random reads
+	ReadLatency LatencyPercentiles `json:"readLatency"`
+	// WriteLatency is the latency of random writes
+	WriteLatency LatencyPercentiles `json:"writeLatency"`
+	// LastBenchmarkTime is the time when the benchmark finished
+	// +optional
+	LastBenchmarkTime *metav1.Time `json:"lastBenchmarkTime,omitempty"`
+}
+
+type LatencyPercentiles struct {
+	P50 metav1.Duration `json:"p50"`
+	P90 metav1.Duration `json:"p90"`
+	P99 metav1.Duration `json:"p99"`
 }
 
 type MountPointToBeInited struct {
@@ -222,6 +335,24 @@ type VolumeGroup struct {
 	Allocatable uint64 `json:"allocatable"`
 	// Condition is the condition for Volume group
 	Condition StorageConditionType `json:"condition,omitempty"`
+	// ThinPool is the thin pool in the VG
+	// +optional
+	ThinPool *ThinPool `json:"thinPool,omitempty"`
+	// IOCapacity is the io budget of the VG
+	// +optional
+	IOCapacity *IOCapacity `json:"ioCapacity,omitempty"`
+}
+
+// ThinPool is an alias for LVM thin pool
+type ThinPool struct {
+	// Name is the thin pool name
+	Name string `json:"name"`
+	// Total is the data size of thin pool
+	Total uint64 `json:"total"`
+	// DataPercent is the usage of data in percent, e.g. "12.50"
+	DataPercent string `json:"dataPercent,omitempty"`
+	// MetadataPercent is the usage of metadata in percent
+	MetadataPercent string `json:"metadataPercent,omitempty"`
 }
 
 // LogicalVolume is an alias for LVM LV
@@ -236,6 +367,9 @@ type LogicalVolume struct {
 	ReadOnly bool `json:"readOnly,omitempty"`
 	// Condition is the condition for LogicalVolume
 	Condition StorageConditionType `json:"condition,omitempty"`
+	// Message describes why the LogicalVolume is abnormal
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // MountPoint is the mount point on a node
@@ -272,6 +406,9 @@ type DeviceInfo struct {
 	ReadOnly bool `json:"readOnly"`
 	// Condition is the condition for mount point
 	Condition StorageConditionType `json:"condition,omitempty"`
+	// IOCapacity is the io budget of the device
+	// +optional
+	IOCapacity *IOCapacity `json:"ioCapacity,omitempty"`
 }
 
 type StorageConditionType string
@@ -28,6 +28,11 @@ import (
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceInfo) DeepCopyInto(out *DeviceInfo) {
 	*out = *in
+	if in.IOCapacity != nil {
+		in, out := &in.IOCapacity, &out.IOCapacity
+		*out = new(IOCapacity)
+		**out = **in
+	}
 	return
 }
 
@@ -104,6 +109,18 @@ func (in *GlobalConfig) DeepCopyInto(out *GlobalConfig) {
 	*out = *in
 	in.ListConfig.DeepCopyInto(&out.ListConfig)
 	in.ResourceToBeInited.DeepCopyInto(&out.ResourceToBeInited)
+	if in.IOCapacities != nil {
+		in, out := &in.IOCapacities, &out.IOCapacities
+		*out = make([]IOCapacityDeclaration, len(*in))
+		copy(*out, *in)
+	}
+	if in.PerformanceTiers != nil {
+		in, out := &in.PerformanceTiers, &out.PerformanceTiers
+		*out = make([]PerformanceTier, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -117,6 +134,58 @@ func (in *GlobalConfig) DeepCopy() *GlobalConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IOCapacity) DeepCopyInto(out *IOCapacity) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IOCapacity.
+func (in *IOCapacity) DeepCopy() *IOCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(IOCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IOCapacityDeclaration) DeepCopyInto(out *IOCapacityDeclaration) {
+	*out = *in
+	out.IOCapacity = in.IOCapacity
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IOCapacityDeclaration.
+func (in *IOCapacityDeclaration) DeepCopy() *IOCapacityDeclaration {
+	if in == nil {
+		return nil
+	}
+	out := new(IOCapacityDeclaration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatencyPercentiles) DeepCopyInto(out *LatencyPercentiles) {
+	*out = *in
+	out.P50 = in.P50
+	out.P90 = in.P90
+	out.P99 = in.P99
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatencyPercentiles.
+func (in *LatencyPercentiles) DeepCopy() *LatencyPercentiles {
+	if in == nil {
+		return nil
+	}
+	out := new(LatencyPercentiles)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ListConfig) DeepCopyInto(out *ListConfig) {
 	*out = *in
@@ -230,6 +299,11 @@ func (in *NodeConfig) DeepCopyInto(out *NodeConfig) {
 	}
 	in.ListConfig.DeepCopyInto(&out.ListConfig)
 	in.ResourceToBeInited.DeepCopyInto(&out.ResourceToBeInited)
+	if in.IOCapacities != nil {
+		in, out := &in.IOCapacities, &out.IOCapacities
+		*out = make([]IOCapacityDeclaration, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -393,6 +467,18 @@ func (in *NodeLocalStorageSpec) DeepCopyInto(out *NodeLocalStorageSpec) {
 	*out = *in
 	in.ListConfig.DeepCopyInto(&out.ListConfig)
 	in.ResourceToBeInited.DeepCopyInto(&out.ResourceToBeInited)
+	if in.IOCapacities != nil {
+		in, out := &in.IOCapacities, &out.IOCapacities
+		*out = make([]IOCapacityDeclaration, len(*in))
+		copy(*out, *in)
+	}
+	if in.PerformanceTiers != nil {
+		in, out := &in.PerformanceTiers, &out.PerformanceTiers
+		*out = make([]PerformanceTier, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -411,6 +497,13 @@ func (in *NodeLocalStorageStatus) DeepCopyInto(out *NodeLocalStorageStatus) {
 	*out = *in
 	in.NodeStorageInfo.DeepCopyInto(&out.NodeStorageInfo)
 	in.FilteredStorageInfo.DeepCopyInto(&out.FilteredStorageInfo)
+	if in.PerformanceProfiles != nil {
+		in, out := &in.PerformanceProfiles, &out.PerformanceProfiles
+		*out = make([]PerformanceProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -430,7 +523,9 @@ func (in *NodeStorageInfo) DeepCopyInto(out *NodeStorageInfo) {
 	if in.DeviceInfos != nil {
 		in, out := &in.DeviceInfos, &out.DeviceInfos
 		*out = make([]DeviceInfo, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.VolumeGroups != nil {
 		in, out := &in.VolumeGroups, &out.VolumeGroups
@@ -460,6 +555,54 @@ func (in *NodeStorageInfo) DeepCopy() *NodeStorageInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PerformanceProfile) DeepCopyInto(out *PerformanceProfile) {
+	*out = *in
+	out.ReadLatency = in.ReadLatency
+	out.WriteLatency = in.WriteLatency
+	if in.LastBenchmarkTime != nil {
+		in, out := &in.LastBenchmarkTime, &out.LastBenchmarkTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerformanceProfile.
+func (in *PerformanceProfile) DeepCopy() *PerformanceProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(PerformanceProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PerformanceTier) DeepCopyInto(out *PerformanceTier) {
+	*out = *in
+	if in.MaxReadLatency != nil {
+		in, out := &in.MaxReadLatency, &out.MaxReadLatency
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxWriteLatency != nil {
+		in, out := &in.MaxWriteLatency, &out.MaxWriteLatency
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerformanceTier.
+func (in *PerformanceTier) DeepCopy() *PerformanceTier {
+	if in == nil {
+		return nil
+	}
+	out := new(PerformanceTier)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceToBeInited) DeepCopyInto(out *ResourceToBeInited) {
 	*out = *in
@@ -514,6 +657,38 @@ func (in *StorageState) DeepCopy() *StorageState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThinPool) DeepCopyInto(out *ThinPool) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThinPool.
+func (in *ThinPool) DeepCopy() *ThinPool {
+	if in == nil {
+		return nil
+	}
+	out := new(ThinPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThinPoolToBeInited) DeepCopyInto(out *ThinPoolToBeInited) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThinPoolToBeInited.
+func (in *ThinPoolToBeInited) DeepCopy() *ThinPoolToBeInited {
+	if in == nil {
+		return nil
+	}
+	out := new(ThinPoolToBeInited)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UpdateStatusInfo) DeepCopyInto(out *UpdateStatusInfo) {
 	*out = *in
@@ -568,6 +743,11 @@ func (in *VGToBeInited) DeepCopyInto(out *VGToBeInited) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ThinPool != nil {
+		in, out := &in.ThinPool, &out.ThinPool
+		*out = new(ThinPoolToBeInited)
+		**out = **in
+	}
 	return
 }
 
@@ -594,6 +774,16 @@ func (in *VolumeGroup) DeepCopyInto(out *VolumeGroup) {
 		*out = make([]LogicalVolume, len(*in))
 		copy(*out, *in)
 	}
+	if in.ThinPool != nil {
+		in, out := &in.ThinPool, &out.ThinPool
+		*out = new(ThinPool)
+		**out = **in
+	}
+	if in.IOCapacity != nil {
+		in, out := &in.IOCapacity, &out.IOCapacity
+		*out = new(IOCapacity)
+		**out = **in
+	}
 	return
 }
 
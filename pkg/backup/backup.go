@@ -0,0 +1,277 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+const (
+	// DefaultChunkSize is the size of each chunk of a backup
+	DefaultChunkSize = 4 << 20
+	chunkPrefix      = "chunks/"
+	indexPrefix      = "indexes/"
+)
+
+// Index lists the chunks of a backup in order. Chunks are content addressed by
+// the sha256 of the uncompressed data, so the same chunk is uploaded only once
+// across all backups in the store. Empty hash means the chunk is all zero.
+type Index struct {
+	Size      int64    `json:"size"`
+	ChunkSize int64    `json:"chunkSize"`
+	Chunks    []string `json:"chunks"`
+	// Parent is the backup which the incremental backup is based on
+	Parent string `json:"parent,omitempty"`
+}
+
+// Extent is a range of device in bytes
+type Extent struct {
+	Offset int64
+	Length int64
+}
+
+// Progress receives the progress of backup and restore
+type Progress interface {
+	SetTotal(total int64)
+	Add(n int64)
+}
+
+// Backup streams the device to store in compressed chunks, and saves the index as name
+func Backup(ctx context.Context, store ObjectStore, device, name string, progress Progress) (*Index, error) {
+	return backup(ctx, store, device, name, "", nil, nil, progress)
+}
+
+// BackupIncremental backs up the device like Backup, but only the chunks changed since the
+// parent backup are uploaded. Chunks out of changed extents are not read at all, and if
+// changed is nil, changed chunks are found by comparing their hashes with the parent.
+// The index is complete, so restoring an incremental backup does not need its parent.
+func BackupIncremental(ctx context.Context, store ObjectStore, device, name, parent string, changed []Extent, progress Progress) (*Index, error) {
+	parentIndex, err := GetIndex(ctx, store, parent)
+	if err != nil {
+		return nil, fmt.Errorf("get parent backup %s: %s", parent, err.Error())
+	}
+	if parentIndex.ChunkSize != DefaultChunkSize {
+		return backup(ctx, store, device, name, "", nil, nil, progress)
+	}
+	return backup(ctx, store, device, name, parent, parentIndex, changed, progress)
+}
+
+func backup(ctx context.Context, store ObjectStore, device, name, parentName string, parent *Index, changed []Extent, progress Progress) (*Index, error) {
+	in, err := os.Open(device)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	size, err := in.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	progress.SetTotal(size)
+
+	index := &Index{Size: size, ChunkSize: DefaultChunkSize, Parent: parentName}
+	buf := make([]byte, DefaultChunkSize)
+	for i, offset := 0, int64(0); offset < size; i, offset = i+1, offset+DefaultChunkSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		n := int64(DefaultChunkSize)
+		if offset+n > size {
+			n = size - offset
+		}
+		// the chunk has the same length in parent, and is not changed since then
+		parentHash, inParent := "", parent != nil && offset+n <= parent.Size && i < len(parent.Chunks)
+		if inParent {
+			parentHash = parent.Chunks[i]
+		}
+		if inParent && changed != nil && !overlaps(changed, offset, n) {
+			index.Chunks = append(index.Chunks, parentHash)
+			progress.Add(n)
+			continue
+		}
+		if _, err := in.ReadAt(buf[:n], offset); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("read %s: %s", device, err.Error())
+		}
+		hash := ""
+		if !isZero(buf[:n]) {
+			hash = sha256Hex(buf[:n])
+			if !inParent || hash != parentHash {
+				if err := putChunk(ctx, store, hash, buf[:n]); err != nil {
+					return nil, err
+				}
+			}
+		}
+		index.Chunks = append(index.Chunks, hash)
+		progress.Add(n)
+	}
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.Put(ctx, indexKey(name), data); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// Restore writes the backup of name to the device, which must not be smaller than the backup.
+// Zero chunks are skipped if skipZero is set, which keeps thin volumes unallocated.
+func Restore(ctx context.Context, store ObjectStore, name, device string, skipZero bool, progress Progress) error {
+	index, err := GetIndex(ctx, store, name)
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(device, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	size, err := out.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if size < index.Size {
+		return fmt.Errorf("size of %s(%d) is smaller than backup %s(%d)", device, size, name, index.Size)
+	}
+	progress.SetTotal(index.Size)
+
+	zero := make([]byte, index.ChunkSize)
+	for i, hash := range index.Chunks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		offset := int64(i) * index.ChunkSize
+		length := index.ChunkSize
+		if offset+length > index.Size {
+			length = index.Size - offset
+		}
+		data := zero[:length]
+		if hash == "" && skipZero {
+			progress.Add(length)
+			continue
+		}
+		if hash != "" {
+			if data, err = getChunk(ctx, store, hash); err != nil {
+				return err
+			}
+			if int64(len(data)) != length {
+				return fmt.Errorf("chunk %s has %d bytes, expect %d", hash, len(data), length)
+			}
+		}
+		if _, err := out.WriteAt(data, offset); err != nil {
+			return fmt.Errorf("write %s: %s", device, err.Error())
+		}
+		progress.Add(length)
+	}
+	return out.Sync()
+}
+
+// GetIndex returns the index of backup name
+func GetIndex(ctx context.Context, store ObjectStore, name string) (*Index, error) {
+	data, err := store.Get(ctx, indexKey(name))
+	if err != nil {
+		return nil, err
+	}
+	index := &Index{}
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("invalid index of backup %s: %s", name, err.Error())
+	}
+	if index.ChunkSize <= 0 || int64(len(index.Chunks)) != (index.Size+index.ChunkSize-1)/index.ChunkSize {
+		return nil, fmt.Errorf("invalid index of backup %s: %d chunks for size %d", name, len(index.Chunks), index.Size)
+	}
+	return index, nil
+}
+
+// HasIndex checks whether backup name is finished
+func HasIndex(ctx context.Context, store ObjectStore, name string) (bool, error) {
+	return store.Exists(ctx, indexKey(name))
+}
+
+// DeleteIndex deletes the index of backup name. Chunks may be shared with other
+// backups, so they are left in the store.
+func DeleteIndex(ctx context.Context, store ObjectStore, name string) error {
+	return store.Delete(ctx, indexKey(name))
+}
+
+func putChunk(ctx context.Context, store ObjectStore, hash string, chunk []byte) error {
+	exists, err := store.Exists(ctx, chunkKey(hash))
+	if err != nil || exists {
+		return err
+	}
+	var buf bytes.Buffer
+	w, _ := gzip.NewWriterLevel(&buf, gzip.BestSpeed)
+	if _, err := w.Write(chunk); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return store.Put(ctx, chunkKey(hash), buf.Bytes())
+}
+
+func getChunk(ctx context.Context, store ObjectStore, hash string) ([]byte, error) {
+	data, err := store.Get(ctx, chunkKey(hash))
+	if err != nil {
+		return nil, fmt.Errorf("get chunk %s: %s", hash, err.Error())
+	}
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decompress chunk %s: %s", hash, err.Error())
+	}
+	defer r.Close()
+	if data, err = ioutil.ReadAll(r); err != nil {
+		return nil, fmt.Errorf("decompress chunk %s: %s", hash, err.Error())
+	}
+	if sha256Hex(data) != hash {
+		return nil, fmt.Errorf("chunk %s is corrupted", hash)
+	}
+	return data, nil
+}
+
+func chunkKey(hash string) string {
+	return chunkPrefix + hash[:2] + "/" + hash
+}
+
+func indexKey(name string) string {
+	return indexPrefix + name + ".json"
+}
+
+// overlaps checks whether [offset, offset+length) overlaps any of extents
+func overlaps(extents []Extent, offset, length int64) bool {
+	for _, e := range extents {
+		if e.Offset < offset+length && offset < e.Offset+e.Length {
+			return true
+		}
+	}
+	return false
+}
+
+func isZero(data []byte) bool {
+	for _, b := range data {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,247 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeS3 is a MinIO-style stand-in serving path style requests of one bucket
+type fakeS3 struct {
+	mu      sync.Mutex
+	bucket  string
+	objects map[string][]byte
+	puts    int
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=ak/") ||
+		r.Header.Get("x-amz-content-sha256") != sha256Hex(body) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/"+f.bucket+"/")
+	if key == r.URL.Path {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, ok := f.objects[key]
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[key] = body
+		f.puts++
+	case http.MethodGet, http.MethodHead:
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+type progress struct {
+	total, done int64
+}
+
+func (p *progress) SetTotal(total int64) { p.total = total }
+func (p *progress) Add(n int64)          { p.done += n }
+
+func TestBackupRestore(t *testing.T) {
+	fake := &fakeS3{bucket: "open-local", objects: map[string][]byte{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	store, err := NewS3Store(S3Config{Endpoint: server.URL, Bucket: "open-local", AccessKeyID: "ak", SecretAccessKey: "sk"})
+	if err != nil {
+		t.Fatalf("NewS3Store() error = %v", err)
+	}
+	ctx := context.Background()
+
+	// chunk 0 and 2 are the same, chunk 1 is zero, and the last chunk is partial
+	chunk := bytes.Repeat([]byte("open-local"), DefaultChunkSize/10+1)[:DefaultChunkSize]
+	data := append(append(append([]byte{}, chunk...), make([]byte, DefaultChunkSize)...), chunk...)
+	data = append(data, []byte("tail")...)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "snap-1")
+	if err := ioutil.WriteFile(src, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &progress{}
+	index, err := Backup(ctx, store, src, "snap-1", p)
+	if err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	if len(index.Chunks) != 4 || index.Chunks[1] != "" || index.Chunks[0] != index.Chunks[2] {
+		t.Errorf("Backup() chunks = %v", index.Chunks)
+	}
+	if p.total != int64(len(data)) || p.done != p.total {
+		t.Errorf("Backup() progress = %d/%d, want %d", p.done, p.total, len(data))
+	}
+	// 2 distinct chunks and the index
+	if fake.puts != 3 {
+		t.Errorf("Backup() puts = %d, want 3", fake.puts)
+	}
+	// chunks are shared by backups of the same data
+	if _, err := Backup(ctx, store, src, "snap-2", &progress{}); err != nil || fake.puts != 4 {
+		t.Errorf("Backup() error = %v, puts = %d, want 4", err, fake.puts)
+	}
+
+	dest := filepath.Join(dir, "pv-1")
+	if err := ioutil.WriteFile(dest, bytes.Repeat([]byte{0xff}, len(data)+1), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Restore(ctx, store, "snap-1", dest, false, &progress{}); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	restored, _ := ioutil.ReadFile(dest)
+	if !bytes.Equal(restored[:len(data)], data) || restored[len(data)] != 0xff {
+		t.Errorf("Restore() data mismatch")
+	}
+
+	small := filepath.Join(dir, "pv-2")
+	if err := ioutil.WriteFile(small, data[:DefaultChunkSize], 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Restore(ctx, store, "snap-1", small, false, &progress{}); err == nil {
+		t.Errorf("Restore() to smaller device should fail")
+	}
+
+	if err := DeleteIndex(ctx, store, "snap-1"); err != nil {
+		t.Fatalf("DeleteIndex() error = %v", err)
+	}
+	if ok, err := HasIndex(ctx, store, "snap-1"); err != nil || ok {
+		t.Errorf("HasIndex() = %v, %v, want false", ok, err)
+	}
+	if err := Restore(ctx, store, "snap-1", dest, false, &progress{}); err != ErrObjectNotFound {
+		t.Errorf("Restore() error = %v, want %v", err, ErrObjectNotFound)
+	}
+
+	// wrong credential is rejected
+	denied, _ := NewS3Store(S3Config{Endpoint: server.URL, Bucket: "open-local", AccessKeyID: "other"})
+	if _, err := Backup(ctx, denied, src, "snap-3", &progress{}); err == nil {
+		t.Errorf("Backup() with wrong credential should fail")
+	}
+}
+
+func TestBackupIncremental(t *testing.T) {
+	fake := &fakeS3{bucket: "open-local", objects: map[string][]byte{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	store, _ := NewS3Store(S3Config{Endpoint: server.URL, Bucket: "open-local", AccessKeyID: "ak", SecretAccessKey: "sk"})
+	ctx := context.Background()
+
+	data := make([]byte, 4*DefaultChunkSize)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	dir := t.TempDir()
+	src := filepath.Join(dir, "snap")
+	if err := ioutil.WriteFile(src, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Backup(ctx, store, src, "snap-1", &progress{}); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	// chunk 1 and 3 are changed, and the device grows by a partial chunk
+	data[DefaultChunkSize+1]++
+	data[3*DefaultChunkSize+1]++
+	data = append(data, []byte("tail")...)
+	if err := ioutil.WriteFile(src, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		changed []Extent
+		// chunks and the index
+		wantPuts int
+	}{
+		// only the changed chunks are read, so the unlisted change of chunk 3 is missed
+		{name: "snap-2", changed: []Extent{{Offset: DefaultChunkSize + 1, Length: 1}}, wantPuts: 3},
+		// nil changed means comparing hashes
+		{name: "snap-3", changed: nil, wantPuts: 2},
+	}
+	for _, tt := range tests {
+		puts := fake.puts
+		index, err := BackupIncremental(ctx, store, src, tt.name, "snap-1", tt.changed, &progress{})
+		if err != nil {
+			t.Fatalf("BackupIncremental(%s) error = %v", tt.name, err)
+		}
+		if index.Parent != "snap-1" || len(index.Chunks) != 5 {
+			t.Errorf("BackupIncremental(%s) index = %+v", tt.name, index)
+		}
+		if fake.puts-puts != tt.wantPuts {
+			t.Errorf("BackupIncremental(%s) puts = %d, want %d", tt.name, fake.puts-puts, tt.wantPuts)
+		}
+	}
+
+	// the incremental backup is restored without its parent
+	if err := DeleteIndex(ctx, store, "snap-1"); err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(dir, "pv")
+	if err := ioutil.WriteFile(dest, make([]byte, len(data)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Restore(ctx, store, "snap-3", dest, false, &progress{}); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if restored, _ := ioutil.ReadFile(dest); !bytes.Equal(restored, data) {
+		t.Errorf("Restore() data mismatch")
+	}
+	if _, err := BackupIncremental(ctx, store, src, "snap-4", "snap-1", nil, &progress{}); err == nil {
+		t.Errorf("BackupIncremental() without parent should fail")
+	}
+}
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		location string
+		want     Location
+		wantErr  bool
+	}{
+		{location: "http://minio:9000/open-local/snap-1", want: Location{Endpoint: "http://minio:9000", Bucket: "open-local", Name: "snap-1"}},
+		{location: "https://s3.example.com/path/open-local/snap-1", want: Location{Endpoint: "https://s3.example.com/path", Bucket: "open-local", Name: "snap-1"}},
+		{location: "open-local/snap-1", wantErr: true},
+		{location: "http://minio:9000/open-local/", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLocation(tt.location)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLocation(%q) = %v, %v", tt.location, got, err)
+		}
+		if err == nil && got.String() != tt.location {
+			t.Errorf("Location.String() = %q, want %q", got.String(), tt.location)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrObjectNotFound is returned by ObjectStore if the key does not exist
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectStore stores the chunks and indexes of backups
+type ObjectStore interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Location is where a backup is stored, it is formatted as <endpoint>/<bucket>/<name>
+type Location struct {
+	Endpoint string
+	Bucket   string
+	Name     string
+}
+
+func (l Location) String() string {
+	return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(l.Endpoint, "/"), l.Bucket, l.Name)
+}
+
+// ParseLocation parses the string formatted by Location.String
+func ParseLocation(s string) (Location, error) {
+	i := strings.LastIndex(s, "/")
+	if i <= 0 {
+		return Location{}, fmt.Errorf("invalid backup location %q", s)
+	}
+	j := strings.LastIndex(s[:i], "/")
+	if j <= 0 {
+		return Location{}, fmt.Errorf("invalid backup location %q", s)
+	}
+	l := Location{Endpoint: s[:j], Bucket: s[j+1 : i], Name: s[i+1:]}
+	if l.Bucket == "" || l.Name == "" || !strings.Contains(l.Endpoint, "://") {
+		return Location{}, fmt.Errorf("invalid backup location %q", s)
+	}
+	return l, nil
+}
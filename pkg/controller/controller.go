@@ -279,6 +279,8 @@ func (c *Controller) updateNLSSpec(nls *localv1alpha1.NodeLocalStorage) (*localv
 	nlsCopy := nls.DeepCopy()
 	nlsCopy.Spec.ListConfig = nlsc.Spec.GlobalConfig.ListConfig
 	nlsCopy.Spec.ResourceToBeInited = nlsc.Spec.GlobalConfig.ResourceToBeInited
+	nlsCopy.Spec.IOCapacities = nlsc.Spec.GlobalConfig.IOCapacities
+	nlsCopy.Spec.PerformanceTiers = nlsc.Spec.GlobalConfig.PerformanceTiers
 	node, err := c.nodeLister.Get(nlsCopy.Name)
 	if err != nil {
 		return nil, fmt.Errorf("get node %s failed", nlsCopy.Name)
@@ -294,6 +296,7 @@ func (c *Controller) updateNLSSpec(nls *localv1alpha1.NodeLocalStorage) (*localv
 		}
 		nlsCopy.Spec.ListConfig = nodeconfig.ListConfig
 		nlsCopy.Spec.ResourceToBeInited = nodeconfig.ResourceToBeInited
+		nlsCopy.Spec.IOCapacities = nodeconfig.IOCapacities
 	}
 
 	return nlsCopy, nil
@@ -0,0 +1,181 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/backup"
+	"github.com/alibaba/open-local/pkg/csi/client"
+	"github.com/alibaba/open-local/pkg/csi/lib"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	snapshotapi "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// restoreSource is a backed up snapshot which a volume is restored from
+type restoreSource struct {
+	snapshotID string
+	location   backup.Location
+	store      *lib.BackupStore
+}
+
+// getBackupStore returns the backup store configured in volumesnapshotclass, or nil if not configured
+func getBackupStore(params map[string]string, secrets map[string]string) *lib.BackupStore {
+	endpoint := params[localtype.ParamBackupEndpoint]
+	if endpoint == "" {
+		return nil
+	}
+	return &lib.BackupStore{
+		Endpoint:        endpoint,
+		Region:          params[localtype.ParamBackupRegion],
+		Bucket:          params[localtype.ParamBackupBucket],
+		AccessKeyId:     secrets[localtype.BackupAccessKeyID],
+		SecretAccessKey: secrets[localtype.BackupSecretAccessKey],
+	}
+}
+
+// backupSnapshot uploads the snapshot to store, the snapshot is not ready to use until the upload is done
+func (cs *controllerServer) backupSnapshot(ctx context.Context, conn client.Connection, req *csi.CreateSnapshotRequest, vgName string, store *lib.BackupStore, snapshotSize int64) (*csi.CreateSnapshotResponse, error) {
+	snapshotName := req.GetName()
+	progress, err := conn.BackupSnapshot(ctx, vgName, snapshotName, store)
+	if err != nil {
+		log.Errorf("CreateSnapshot: backup snapshot %s failed: %s", snapshotName, err.Error())
+		return nil, status.Errorf(status.Code(err), "CreateSnapshot: backup snapshot %s failed: %s", snapshotName, err.Error())
+	}
+	if progress.Error != "" {
+		log.Errorf("CreateSnapshot: backup snapshot %s failed: %s", snapshotName, progress.Error)
+		return nil, status.Errorf(codes.Internal, "CreateSnapshot: backup snapshot %s failed: %s", snapshotName, progress.Error)
+	}
+	response, err := cs.newCreateSnapshotResponse(req, snapshotSize)
+	if err != nil {
+		return nil, err
+	}
+	if !progress.Done {
+		log.Infof("CreateSnapshot: snapshot %s is being backed up, %d/%d bytes uploaded", snapshotName, progress.CopiedBytes, progress.TotalBytes)
+		response.Snapshot.ReadyToUse = false
+		return response, nil
+	}
+	location := backup.Location{Endpoint: store.Endpoint, Bucket: store.Bucket, Name: snapshotName}
+	if err := cs.setBackupLocation(snapshotName, location.String()); err != nil {
+		log.Errorf("CreateSnapshot: set backup location of snapshot %s failed: %s", snapshotName, err.Error())
+		return nil, status.Errorf(codes.Internal, "CreateSnapshot: set backup location of snapshot %s failed: %s", snapshotName, err.Error())
+	}
+	log.Infof("CreateSnapshot: snapshot %s is backed up to %s", snapshotName, location.String())
+	return response, nil
+}
+
+// setBackupLocation annotates the volumesnapshotcontent of snapshot with the backup location
+func (cs *controllerServer) setBackupLocation(snapshotName, location string) error {
+	content, err := getVolumeSnapshotContent(cs.snapclient, snapshotName)
+	if err != nil {
+		return err
+	}
+	if content.Annotations[localtype.AnnBackupLocation] == location {
+		return nil
+	}
+	metav1.SetMetaDataAnnotation(&content.ObjectMeta, localtype.AnnBackupLocation, location)
+	_, err = cs.snapclient.SnapshotV1().VolumeSnapshotContents().Update(context.Background(), content, metav1.UpdateOptions{})
+	return err
+}
+
+// deleteBackup deletes the backup index of snapshot content, the chunks are kept since they may be shared
+func deleteBackup(ctx context.Context, content *snapshotapi.VolumeSnapshotContent, params map[string]string, secrets map[string]string) error {
+	value, ok := content.Annotations[localtype.AnnBackupLocation]
+	if !ok {
+		return nil
+	}
+	location, err := backup.ParseLocation(value)
+	if err != nil {
+		return err
+	}
+	store, err := backup.NewS3Store(backup.S3Config{
+		Endpoint:        location.Endpoint,
+		Region:          params[localtype.ParamBackupRegion],
+		Bucket:          location.Bucket,
+		AccessKeyID:     secrets[localtype.BackupAccessKeyID],
+		SecretAccessKey: secrets[localtype.BackupSecretAccessKey],
+	})
+	if err != nil {
+		return err
+	}
+	if err := backup.DeleteIndex(ctx, store, location.Name); err != nil && err != backup.ErrObjectNotFound {
+		return err
+	}
+	log.Infof("deleteBackup: backup %s of snapshot content %s is deleted", value, content.Name)
+	return nil
+}
+
+// getRestoreSource returns the backup which volume is restored from. Volume is restored from
+// backup when the source snapshot is backed up, unless the snapshot is readonly.
+func (cs *controllerServer) getRestoreSource(req *csi.CreateVolumeRequest) (*restoreSource, error) {
+	sourceSnapshot := req.GetVolumeContentSource().GetSnapshot()
+	if sourceSnapshot == nil {
+		return nil, nil
+	}
+	snapshotID := sourceSnapshot.GetSnapshotId()
+	snapContent, err := getVolumeSnapshotContent(cs.snapclient, snapshotID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "get snapshot content of %s failed: %s", snapshotID, err.Error())
+	}
+	value, ok := snapContent.Annotations[localtype.AnnBackupLocation]
+	if !ok || snapContent.Spec.VolumeSnapshotClassName == nil {
+		return nil, nil
+	}
+	class, err := getVolumeSnapshotClass(cs.snapclient, *snapContent.Spec.VolumeSnapshotClassName)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "get snapshot class failed: %s", err.Error())
+	}
+	if class.Parameters[localtype.ParamSnapshotReadonly] == "true" {
+		return nil, nil
+	}
+	location, err := backup.ParseLocation(value)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "snapshot content %s: %s", snapContent.Name, err.Error())
+	}
+	secrets := map[string]string{}
+	if name := class.Parameters[localtype.ParamSnapshotterSecretName]; name != "" {
+		secret, err := cs.client.CoreV1().Secrets(class.Parameters[localtype.ParamSnapshotterSecretNamespace]).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "get backup secret %s failed: %s", name, err.Error())
+		}
+		for k, v := range secret.Data {
+			secrets[k] = string(v)
+		}
+	}
+	// the location where the snapshot was uploaded takes precedence over the current class
+	store := &lib.BackupStore{
+		Endpoint:        location.Endpoint,
+		Region:          class.Parameters[localtype.ParamBackupRegion],
+		Bucket:          location.Bucket,
+		AccessKeyId:     secrets[localtype.BackupAccessKeyID],
+		SecretAccessKey: secrets[localtype.BackupSecretAccessKey],
+	}
+	return &restoreSource{snapshotID: snapshotID, location: location, store: store}, nil
+}
+
+// restoreVolume creates the volume on node and writes the backup to it, and waits until the
+// restore is finished or ctx is done, like cloneVolume
+func (cs *controllerServer) restoreVolume(ctx context.Context, conn client.Connection, options *client.LVMOptions, source *restoreSource) error {
+	if _, err := conn.RestoreLvm(ctx, options, source.location.Name, source.store); err != nil {
+		return status.Errorf(status.Code(err), "restore lvm from %s error: %s", source.location.String(), err.Error())
+	}
+	return waitForCopy(ctx, conn, options.VolumeGroup, options.Name)
+}
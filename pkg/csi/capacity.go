@@ -0,0 +1,218 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	localtype "github.com/alibaba/open-local/pkg"
+	localv1alpha1 "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
+	"github.com/alibaba/open-local/pkg/utils"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// TopologyVGKeyPrefix is the prefix of topology keys of VGs on node, e.g. vg.csi.aliyun.com/open-local-pool-0: "true"
+	TopologyVGKeyPrefix = "vg.csi.aliyun.com/"
+	// TopologyMediaTypeKeyPrefix is the prefix of topology keys of media types of devices on node, e.g. mediatype.csi.aliyun.com/ssd: "true"
+	TopologyMediaTypeKeyPrefix = "mediatype.csi.aliyun.com/"
+	topologyValueTrue          = "true"
+)
+
+// nodeTopology returns the topology segments of VGs and media types in NodeLocalStorage,
+// which are reported in NodeGetInfo along with the node name, so that capacity is tracked
+// by external-provisioner in segments of node, VG and media type
+func nodeTopology(nls *localv1alpha1.NodeLocalStorage) map[string]string {
+	segments := make(map[string]string)
+	for _, vg := range nls.Status.FilteredStorageInfo.VolumeGroups {
+		// the key is also the label of node, skip vg whose name is not a valid label name
+		if len(validation.IsQualifiedName(TopologyVGKeyPrefix+vg)) > 0 {
+			log.Warningf("vg %s is not reported in topology, for it is not a valid label name", vg)
+			continue
+		}
+		segments[TopologyVGKeyPrefix+vg] = topologyValueTrue
+	}
+	for _, device := range nls.Status.NodeStorageInfo.DeviceInfos {
+		if device.MediaType != "" {
+			segments[TopologyMediaTypeKeyPrefix+device.MediaType] = topologyValueTrue
+		}
+	}
+	return segments
+}
+
+// GetCapacity returns the capacity available for new volumes of the storage class parameters
+// in the topology segment, which is calculated from the allocatable storage reported by agent
+// minus the size of local pvs, as the scheduler does. As a volume is allocated in a single VG,
+// device or mount point, the capacity is the largest one of them.
+func (cs *controllerServer) GetCapacity(ctx context.Context, req *csi.GetCapacityRequest) (*csi.GetCapacityResponse, error) {
+	params := req.GetParameters()
+	volumeType := params[VolumeTypeKey]
+	if volumeType == "" {
+		volumeType = LvmVolumeType
+	}
+	supported := false
+	for _, supportVolType := range supportVolumeTypes {
+		if supportVolType == volumeType {
+			supported = true
+		}
+	}
+	if !supported {
+		return nil, status.Errorf(codes.InvalidArgument, "GetCapacity: unsupported volume type %s", volumeType)
+	}
+	segments := req.GetAccessibleTopology().GetSegments()
+
+	var nlses []localv1alpha1.NodeLocalStorage
+	if nodeName, exist := segments[TopologyNodeKey]; exist {
+		nls, err := cs.localclient.CsiV1alpha1().NodeLocalStorages().Get(ctx, nodeName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return &csi.GetCapacityResponse{}, nil
+		} else if err != nil {
+			return nil, status.Errorf(codes.Internal, "GetCapacity: get nls %s error: %s", nodeName, err.Error())
+		}
+		nlses = append(nlses, *nls)
+	} else {
+		nlsList, err := cs.localclient.CsiV1alpha1().NodeLocalStorages().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "GetCapacity: list nls error: %s", err.Error())
+		}
+		nlses = nlsList.Items
+	}
+	// read from the cache of apiserver, for it is called periodically for every segment and storage class
+	pvList, err := cs.client.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{ResourceVersion: "0"})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "GetCapacity: list pv error: %s", err.Error())
+	}
+
+	var capacity int64
+	for i := range nlses {
+		nls := &nlses[i]
+		if !matchTopology(nls, segments) {
+			continue
+		}
+		if c := nodeCapacity(nls, localPVsOf(pvList.Items, nls.Name), volumeType, params); c > capacity {
+			capacity = c
+		}
+	}
+	log.Debugf("GetCapacity: capacity of %s volume with parameters %v in segment %v is %d", volumeType, params, segments, capacity)
+	return &csi.GetCapacityResponse{AvailableCapacity: capacity}, nil
+}
+
+// matchTopology returns true if the node of nls is in the segments
+func matchTopology(nls *localv1alpha1.NodeLocalStorage, segments map[string]string) bool {
+	topology := nodeTopology(nls)
+	topology[TopologyNodeKey] = nls.Name
+	for key, value := range segments {
+		if topology[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func localPVsOf(pvs []v1.PersistentVolume, nodeName string) map[string]v1.PersistentVolume {
+	localPVs := make(map[string]v1.PersistentVolume)
+	for _, pv := range pvs {
+		if pv.Spec.CSI == nil || !utils.ContainsProvisioner(pv.Spec.CSI.Driver) {
+			continue
+		}
+		if _, node := utils.IsLocalPV(&pv); node == nodeName {
+			localPVs[pv.Name] = pv
+		}
+	}
+	return localPVs
+}
+
+// nodeCapacity returns the largest VG, device or mount point on the node for the volume type,
+// which matches the vgName and mediaType in parameters
+func nodeCapacity(nls *localv1alpha1.NodeLocalStorage, localPVs map[string]v1.PersistentVolume, volumeType string, params map[string]string) int64 {
+	info := nls.Status.NodeStorageInfo
+	filtered := nls.Status.FilteredStorageInfo
+	mediaTypes := make(map[string]string)
+	for _, device := range info.DeviceInfos {
+		mediaTypes[device.Name] = device.MediaType
+	}
+	matchMediaType := func(device string) bool {
+		mediaType := params[localtype.VolumeMediaType]
+		return mediaType == "" || mediaTypes[device] == mediaType
+	}
+	matchVG := func(vgName string) bool {
+		name := params[VgNameTag]
+		return name == "" || name == vgName
+	}
+
+	var capacity int64
+	max := func(c int64) {
+		if c > capacity {
+			capacity = c
+		}
+	}
+	switch volumeType {
+	case LvmVolumeType:
+		for _, vg := range info.VolumeGroups {
+			if !utils.ContainsString(filtered.VolumeGroups, vg.Name) || vg.Condition == localv1alpha1.StorageFault || !matchVG(vg.Name) {
+				continue
+			}
+			if params[LvmTypeTag] == ThinType {
+				if vg.ThinPool != nil {
+					max(int64(float64(vg.ThinPool.Total)*utils.GetThinPoolOvercommitRatio(nls, vg.Name)) - utils.GetThinPoolRequested(localPVs, vg.Name))
+				}
+				continue
+			}
+			max(int64(vg.Allocatable) - utils.GetVGRequested(localPVs, vg.Name))
+		}
+	case DeviceVolumeType:
+		allocated := make(map[string]bool)
+		for _, pv := range localPVs {
+			allocated[utils.GetDeviceNameFromCsiPV(&pv)] = true
+		}
+		for _, device := range info.DeviceInfos {
+			if !utils.ContainsString(filtered.Devices, device.Name) || device.Condition == localv1alpha1.StorageFault ||
+				allocated[device.Name] || !matchMediaType(device.Name) {
+				continue
+			}
+			max(int64(device.Total))
+		}
+	case MountPointType, QuotaVolumeType:
+		allocated := make(map[string]bool)
+		for _, pv := range localPVs {
+			if pv.Spec.CSI.VolumeAttributes[VolumeTypeKey] == MountPointType {
+				allocated[utils.GetMountPointFromCsiPV(&pv)] = true
+			}
+		}
+		for _, mp := range info.MountPoints {
+			if !utils.ContainsString(filtered.MountPoints, mp.Name) || mp.Condition == localv1alpha1.StorageFault ||
+				allocated[mp.Name] || !matchMediaType(mp.Device) {
+				continue
+			}
+			if volumeType == MountPointType {
+				// a mount point is allocated to a single volume, and shared by quota volumes
+				if utils.GetQuotaRequested(localPVs, mp.Name) == 0 {
+					max(int64(mp.Total))
+				}
+				continue
+			}
+			max(int64(mp.Total) - utils.GetQuotaRequested(localPVs, mp.Name))
+		}
+	}
+	return capacity
+}
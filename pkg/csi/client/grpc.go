@@ -37,9 +37,15 @@ type Connection interface {
 	DeleteLvm(ctx context.Context, volGroup string, volumeID string) error
 	CreateSnapshot(ctx context.Context, volGroup string, snapVolumeID string, volumeID string, size uint64) (string, error)
 	DeleteSnapshot(ctx context.Context, volGroup string, snapVolumeID string) error
+	CloneLvm(ctx context.Context, volGroup string, srcVolumeID string, volumeID string, size uint64) (string, error)
+	GetCloneProgress(ctx context.Context, volGroup string, volumeID string) (*CloneProgress, error)
+	CancelClone(ctx context.Context, volGroup string, volumeID string) error
+	BackupSnapshot(ctx context.Context, volGroup string, snapVolumeID string, store *lib.BackupStore) (*CloneProgress, error)
+	RestoreLvm(ctx context.Context, opt *LVMOptions, backupName string, store *lib.BackupStore) (string, error)
 	ExpandLvm(ctx context.Context, volGroup string, volumeID string, size uint64) error
 	CleanPath(ctx context.Context, path string) error
 	CleanDevice(ctx context.Context, device string) error
+	RemoveQuota(ctx context.Context, quotaSubpath string) error
 	Close() error
 }
 
@@ -50,6 +56,15 @@ type LVMOptions struct {
 	Size        uint64   `json:"size,omitempty"`
 	Tags        []string `json:"tags,omitempty"`
 	Striping    bool     `json:"striping,omitempty"`
+	Thin        bool     `json:"thin,omitempty"`
+}
+
+// CloneProgress is the copy progress of cloning, restoring or backing up volume
+type CloneProgress struct {
+	CopiedBytes uint64 `json:"copiedBytes,omitempty"`
+	TotalBytes  uint64 `json:"totalBytes,omitempty"`
+	Done        bool   `json:"done,omitempty"`
+	Error       string `json:"error,omitempty"`
 }
 
 //
@@ -123,6 +138,7 @@ func (c *workerConnection) CreateLvm(ctx context.Context, opt *LVMOptions) (stri
 		Size:        opt.Size,
 		Tags:        opt.Tags,
 		Striping:    opt.Striping,
+		Thin:        opt.Thin,
 	}
 
 	rsp, err := client.CreateLV(ctx, &req)
@@ -152,6 +168,96 @@ func (c *workerConnection) CreateSnapshot(ctx context.Context, volGroup string,
 	return rsp.GetCommandOutput(), nil
 }
 
+func (c *workerConnection) CloneLvm(ctx context.Context, volGroup string, srcVolumeID string, volumeID string, size uint64) (string, error) {
+	client := lib.NewLVMClient(c.conn)
+	req := lib.CloneLVRequest{
+		VolumeGroup: volGroup,
+		SourceName:  srcVolumeID,
+		DestName:    volumeID,
+		Size:        size,
+	}
+	rsp, err := client.CloneLV(ctx, &req)
+	if err != nil {
+		log.Errorf("Clone Lvm with error: %s", err.Error())
+		return "", err
+	}
+	log.Debugf("Clone Lvm with result: %+v", rsp.CommandOutput)
+	return rsp.GetCommandOutput(), nil
+}
+
+func (c *workerConnection) GetCloneProgress(ctx context.Context, volGroup string, volumeID string) (*CloneProgress, error) {
+	client := lib.NewLVMClient(c.conn)
+	req := lib.GetCloneLVProgressRequest{
+		VolumeGroup: volGroup,
+		DestName:    volumeID,
+	}
+	rsp, err := client.GetCloneLVProgress(ctx, &req)
+	if err != nil {
+		log.Errorf("Get Lvm Clone Progress with error: %s", err.Error())
+		return nil, err
+	}
+	return &CloneProgress{
+		CopiedBytes: rsp.GetCopiedBytes(),
+		TotalBytes:  rsp.GetTotalBytes(),
+		Done:        rsp.GetDone(),
+		Error:       rsp.GetError(),
+	}, nil
+}
+
+func (c *workerConnection) BackupSnapshot(ctx context.Context, volGroup string, snapVolumeID string, store *lib.BackupStore) (*CloneProgress, error) {
+	client := lib.NewLVMClient(c.conn)
+	req := lib.BackupSnapshotRequest{
+		VolumeGroup:  volGroup,
+		SnapshotName: snapVolumeID,
+		Store:        store,
+	}
+	rsp, err := client.BackupSnapshot(ctx, &req)
+	if err != nil {
+		log.Errorf("Backup Snapshot with error: %s", err.Error())
+		return nil, err
+	}
+	return &CloneProgress{
+		CopiedBytes: rsp.GetCopiedBytes(),
+		TotalBytes:  rsp.GetTotalBytes(),
+		Done:        rsp.GetDone(),
+		Error:       rsp.GetError(),
+	}, nil
+}
+
+func (c *workerConnection) RestoreLvm(ctx context.Context, opt *LVMOptions, backupName string, store *lib.BackupStore) (string, error) {
+	client := lib.NewLVMClient(c.conn)
+	req := lib.RestoreLVRequest{
+		VolumeGroup: opt.VolumeGroup,
+		Name:        opt.Name,
+		Size:        opt.Size,
+		BackupName:  backupName,
+		Store:       store,
+		Thin:        opt.Thin,
+	}
+	rsp, err := client.RestoreLV(ctx, &req)
+	if err != nil {
+		log.Errorf("Restore Lvm with error: %s", err.Error())
+		return "", err
+	}
+	log.Debugf("Restore Lvm with result: %+v", rsp.CommandOutput)
+	return rsp.GetCommandOutput(), nil
+}
+
+func (c *workerConnection) CancelClone(ctx context.Context, volGroup string, volumeID string) error {
+	client := lib.NewLVMClient(c.conn)
+	req := lib.CancelCloneLVRequest{
+		VolumeGroup: volGroup,
+		DestName:    volumeID,
+	}
+	rsp, err := client.CancelCloneLV(ctx, &req)
+	if err != nil {
+		log.Errorf("Cancel Lvm Clone with error: %s", err.Error())
+		return err
+	}
+	log.Debugf("Cancel Lvm Clone with result: %+v", rsp.CommandOutput)
+	return nil
+}
+
 func (c *workerConnection) GetLvm(ctx context.Context, volGroup string, volumeID string) (string, error) {
 	client := lib.NewLVMClient(c.conn)
 	req := lib.ListLVRequest{
@@ -229,6 +335,20 @@ func (c *workerConnection) CleanDevice(ctx context.Context, device string) error
 	return err
 }
 
+func (c *workerConnection) RemoveQuota(ctx context.Context, quotaSubpath string) error {
+	client := lib.NewLVMClient(c.conn)
+	req := lib.RemoveQuotaRequest{
+		QuotaSubpath: quotaSubpath,
+	}
+	response, err := client.RemoveQuota(ctx, &req)
+	if err != nil {
+		log.Errorf("fail to remove quota subpath %s: %s", quotaSubpath, err.Error())
+		return err
+	}
+	log.Debugf("remove quota subpath %s successfully with result: %s", quotaSubpath, response.GetCommandOutput())
+	return err
+}
+
 func (c *workerConnection) ExpandLvm(ctx context.Context, volGroup string, volumeID string, size uint64) error {
 	client := lib.NewLVMClient(c.conn)
 	req := lib.ExpandLVRequest{
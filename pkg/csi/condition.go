@@ -0,0 +1,156 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	localv1alpha1 "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
+	"github.com/alibaba/open-local/pkg/utils/lvm"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	log "github.com/sirupsen/logrus"
+)
+
+const volumeHealthyMessage = "volume is healthy"
+
+func healthyCondition() *csi.VolumeCondition {
+	return &csi.VolumeCondition{Abnormal: false, Message: volumeHealthyMessage}
+}
+
+func abnormalCondition(format string, a ...interface{}) *csi.VolumeCondition {
+	return &csi.VolumeCondition{Abnormal: true, Message: fmt.Sprintf(format, a...)}
+}
+
+// volumeCondition checks the storage backing the volume on this node: the lv and its
+// device node for LVM volumes, the device for Device volumes, and whether the staging
+// path or mount point is remounted read-only, e.g. by filesystem errors
+func (ns *nodeServer) volumeCondition(volumeID, stagingPath string, attributes map[string]string) *csi.VolumeCondition {
+	switch attributes[VolumeTypeTag] {
+	case LvmVolumeType:
+		vgName := attributes[VgNameTag]
+		lvName := lvNameOf(volumeID, attributes)
+		if condition := lvCondition(vgName, lvName); condition != nil {
+			return condition
+		}
+		devicePath := filepath.Join("/dev", vgName, lvName)
+		if _, err := os.Stat(devicePath); err != nil {
+			return abnormalCondition("device %s of logical volume is missing: %s", devicePath, err.Error())
+		}
+	case DeviceVolumeType:
+		devicePath := attributes[DeviceVolumeType]
+		if _, err := os.Stat(devicePath); err != nil {
+			return abnormalCondition("device %s is missing: %s", devicePath, err.Error())
+		}
+	case MountPointType, QuotaVolumeType:
+		stagingPath = attributes[MountPointType]
+	}
+
+	if stagingPath != "" && !isReadonlySnapshot(attributes) && ns.isReadonlyMount(stagingPath) {
+		return abnormalCondition("filesystem of %s is mounted read-only", stagingPath)
+	}
+	return healthyCondition()
+}
+
+// lvNameOf returns the lv of the LVM volume, which is the snapshot lv for readonly snapshot volumes
+func lvNameOf(volumeID string, attributes map[string]string) string {
+	if isReadonlySnapshot(attributes) {
+		return attributes[localtype.ParamSnapshotName]
+	}
+	return volumeID
+}
+
+func lvCondition(vgName, lvName string) *csi.VolumeCondition {
+	vg, err := lvm.LookupVolumeGroup(vgName)
+	if err != nil {
+		return abnormalCondition("look up volume group %s failed: %s", vgName, err.Error())
+	}
+	if _, err := vg.LookupLogicalVolume(lvName); err == lvm.ErrLogicalVolumeNotFound {
+		return abnormalCondition("logical volume %s/%s is not found", vgName, lvName)
+	} else if err != nil {
+		return abnormalCondition("look up logical volume %s/%s failed: %s", vgName, lvName, err.Error())
+	}
+	abnormalLVs, err := vg.ListAbnormalLogicalVolumes()
+	if err != nil {
+		log.Warningf("list abnormal logical volumes of %s failed: %s", vgName, err.Error())
+		return nil
+	}
+	if msg, abnormal := abnormalLVs[lvName]; abnormal {
+		return abnormalCondition("logical volume %s/%s is abnormal: %s", vgName, lvName, msg)
+	}
+	return nil
+}
+
+func (ns *nodeServer) isReadonlyMount(path string) bool {
+	mounts, err := ns.k8smounter.List()
+	if err != nil {
+		log.Warningf("list mount points failed: %s", err.Error())
+		return false
+	}
+	readonly := false
+	// the last mount on the path is the one in use
+	for _, mount := range mounts {
+		if mount.Path != path {
+			continue
+		}
+		readonly = false
+		for _, opt := range mount.Opts {
+			if opt == "ro" {
+				readonly = true
+			}
+		}
+	}
+	return readonly
+}
+
+// nlsVolumeCondition returns the condition of the volume reported by agent in NodeLocalStorage
+func nlsVolumeCondition(nls *localv1alpha1.NodeLocalStorage, volumeID string, attributes map[string]string) *csi.VolumeCondition {
+	info := nls.Status.NodeStorageInfo
+	switch attributes[VolumeTypeTag] {
+	case LvmVolumeType:
+		vgName := attributes[VgNameTag]
+		lvName := lvNameOf(volumeID, attributes)
+		for _, vg := range info.VolumeGroups {
+			if vg.Name != vgName {
+				continue
+			}
+			if vg.Condition == localv1alpha1.StorageFault {
+				return abnormalCondition("volume group %s on node %s is faulty", vgName, nls.Name)
+			}
+			for _, lv := range vg.LogicalVolumes {
+				if lv.Name == lvName && lv.Condition == localv1alpha1.StorageFault {
+					return abnormalCondition("logical volume %s/%s on node %s is abnormal: %s", vgName, lvName, nls.Name, lv.Message)
+				}
+			}
+		}
+	case DeviceVolumeType:
+		for _, device := range info.DeviceInfos {
+			if device.Name == attributes[DeviceVolumeType] && device.Condition == localv1alpha1.StorageFault {
+				return abnormalCondition("device %s on node %s is faulty", device.Name, nls.Name)
+			}
+		}
+	case MountPointType, QuotaVolumeType:
+		for _, mp := range info.MountPoints {
+			if mp.Name == attributes[MountPointType] && mp.Condition == localv1alpha1.StorageFault {
+				return abnormalCondition("mount point %s on node %s is faulty", mp.Name, nls.Name)
+			}
+		}
+	}
+	return healthyCondition()
+}
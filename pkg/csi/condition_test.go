@@ -0,0 +1,182 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"path/filepath"
+	"testing"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	localv1alpha1 "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
+	"github.com/alibaba/open-local/pkg/utils/lvm"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8smount "k8s.io/utils/mount"
+)
+
+func TestVolumeCondition(t *testing.T) {
+	fake := lvm.NewFakeExecutor()
+	defer lvm.SetExecutor(lvm.SetExecutor(fake))
+	fake.Record("vgs --reportformat=json --units=b --nosuffix --options=vg_name share", `{"report": [{"vg": [{"vg_name":"share"}]}]}`)
+	fake.Record("lvs --reportformat=json --units=b --nosuffix --options=lv_name,lv_size,vg_name,origin,pool_lv share", `{"report": [{"lv": [
+		{"lv_name":"pv-1", "vg_name":"share", "lv_size":"1073741824", "origin":""},
+		{"lv_name":"snap-1", "vg_name":"share", "lv_size":"1073741824", "origin":"pv-1"}
+	]}]}`)
+	fake.Record("lvs --reportformat=json --units=b --nosuffix --options=lv_name,lv_size,vg_name,origin,snap_percent share/snap-1", `{"report": [{"lv": [
+		{"lv_name":"snap-1", "vg_name":"share", "lv_size":"1073741824", "origin":"pv-1", "snap_percent":"100.00"}
+	]}]}`)
+	fake.Record("lvs --reportformat=json --units=b --nosuffix --options=lv_name,vg_name,origin,lv_attr,snap_percent share", `{"report": [{"lv": [
+		{"lv_name":"pv-1", "vg_name":"share", "origin":"", "lv_attr":"owi-aos---", "snap_percent":""},
+		{"lv_name":"snap-1", "vg_name":"share", "origin":"pv-1", "lv_attr":"swi-I-s---", "snap_percent":"100.00"}
+	]}]}`)
+
+	dir := t.TempDir()
+	mounter := k8smount.NewFakeMounter([]k8smount.MountPoint{
+		{Device: "/dev/vdb", Path: "/mnt/open-local/disk-1", Opts: []string{"rw"}},
+		{Device: "/dev/vdc", Path: "/mnt/open-local/disk-2", Opts: []string{"rw"}},
+		{Device: "/dev/vdc", Path: "/mnt/open-local/disk-2", Opts: []string{"ro"}},
+	})
+	ns := &nodeServer{k8smounter: mounter}
+
+	tests := []struct {
+		name         string
+		volumeID     string
+		attributes   map[string]string
+		wantAbnormal bool
+	}{
+		{
+			name:         "lv not found",
+			volumeID:     "pv-2",
+			attributes:   map[string]string{VolumeTypeTag: LvmVolumeType, VgNameTag: "share"},
+			wantAbnormal: true,
+		},
+		{
+			name:     "invalid snapshot",
+			volumeID: "snap-pv-1",
+			attributes: map[string]string{
+				VolumeTypeTag:                   LvmVolumeType,
+				VgNameTag:                       "share",
+				localtype.ParamSnapshotName:     "snap-1",
+				localtype.ParamSnapshotReadonly: "true",
+			},
+			wantAbnormal: true,
+		},
+		{
+			name:         "device is missing",
+			volumeID:     "pv-3",
+			attributes:   map[string]string{VolumeTypeTag: DeviceVolumeType, DeviceVolumeType: filepath.Join(dir, "vdd")},
+			wantAbnormal: true,
+		},
+		{
+			name:       "device exists",
+			volumeID:   "pv-3",
+			attributes: map[string]string{VolumeTypeTag: DeviceVolumeType, DeviceVolumeType: dir},
+		},
+		{
+			name:       "mount point is healthy",
+			volumeID:   "pv-4",
+			attributes: map[string]string{VolumeTypeTag: MountPointType, MountPointType: "/mnt/open-local/disk-1"},
+		},
+		{
+			name:         "mount point is remounted read-only",
+			volumeID:     "pv-5",
+			attributes:   map[string]string{VolumeTypeTag: QuotaVolumeType, MountPointType: "/mnt/open-local/disk-2"},
+			wantAbnormal: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			condition := ns.volumeCondition(test.volumeID, "", test.attributes)
+			if condition == nil {
+				t.Fatal("volumeCondition() returns nil")
+			}
+			if condition.Abnormal != test.wantAbnormal {
+				t.Errorf("volumeCondition() = %+v, expected abnormal %t", condition, test.wantAbnormal)
+			}
+		})
+	}
+}
+
+func TestNlsVolumeCondition(t *testing.T) {
+	nls := &localv1alpha1.NodeLocalStorage{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status: localv1alpha1.NodeLocalStorageStatus{
+			NodeStorageInfo: localv1alpha1.NodeStorageInfo{
+				VolumeGroups: []localv1alpha1.VolumeGroup{
+					{
+						Name:      "share",
+						Condition: localv1alpha1.StorageReady,
+						LogicalVolumes: []localv1alpha1.LogicalVolume{
+							{Name: "pv-1", VGName: "share", Condition: localv1alpha1.StorageReady},
+							{Name: "pv-2", VGName: "share", Condition: localv1alpha1.StorageFault, Message: "snapshot is full"},
+						},
+					},
+					{Name: "faulty", Condition: localv1alpha1.StorageFault},
+				},
+				DeviceInfos: []localv1alpha1.DeviceInfo{
+					{Name: "/dev/vdb", Condition: localv1alpha1.StorageFault},
+				},
+				MountPoints: []localv1alpha1.MountPoint{
+					{Name: "/mnt/open-local/disk-1", Condition: localv1alpha1.StorageReady},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		volumeID     string
+		attributes   map[string]string
+		wantAbnormal bool
+	}{
+		{
+			name:       "healthy lv",
+			volumeID:   "pv-1",
+			attributes: map[string]string{VolumeTypeTag: LvmVolumeType, VgNameTag: "share"},
+		},
+		{
+			name:         "abnormal lv",
+			volumeID:     "pv-2",
+			attributes:   map[string]string{VolumeTypeTag: LvmVolumeType, VgNameTag: "share"},
+			wantAbnormal: true,
+		},
+		{
+			name:         "lv in faulty vg",
+			volumeID:     "pv-3",
+			attributes:   map[string]string{VolumeTypeTag: LvmVolumeType, VgNameTag: "faulty"},
+			wantAbnormal: true,
+		},
+		{
+			name:         "faulty device",
+			volumeID:     "pv-4",
+			attributes:   map[string]string{VolumeTypeTag: DeviceVolumeType, DeviceVolumeType: "/dev/vdb"},
+			wantAbnormal: true,
+		},
+		{
+			name:       "healthy mount point",
+			volumeID:   "pv-5",
+			attributes: map[string]string{VolumeTypeTag: MountPointType, MountPointType: "/mnt/open-local/disk-1"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			condition := nlsVolumeCondition(nls, test.volumeID, test.attributes)
+			if condition.Abnormal != test.wantAbnormal {
+				t.Errorf("nlsVolumeCondition() = %+v, expected abnormal %t", condition, test.wantAbnormal)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// configMapStoreRetries is the number of attempts to update the configmap on conflict
+const configMapStoreRetries = 5
+
+// configMapStore keeps the volume states in a configmap, one key per volume, so that
+// they survive the controller being restarted on another node. The configmap is read
+// on every call since the controller runs in the csi plugin of every node.
+type configMapStore struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+}
+
+// NewConfigMapStore returns the store kept in configmap namespace/name, which is
+// created on the first update
+func NewConfigMapStore(client kubernetes.Interface, namespace, name string) Store {
+	return &configMapStore{client: client, namespace: namespace, name: name}
+}
+
+func (store *configMapStore) Get(volumeID string) (VolumeState, bool) {
+	cm, _, err := store.get()
+	if err != nil {
+		log.Errorf("volume store: get volume %s error: %s", volumeID, err.Error())
+		return VolumeState{}, false
+	}
+	state, ok, err := decodeVolumeState(cm, volumeID)
+	if err != nil {
+		log.Errorf("volume store: get volume %s error: %s", volumeID, err.Error())
+		return VolumeState{}, false
+	}
+	return state, ok
+}
+
+// List returns all volumes ordered by volume id
+func (store *configMapStore) List() []VolumeState {
+	cm, _, err := store.get()
+	if err != nil {
+		log.Errorf("volume store: list volumes error: %s", err.Error())
+		return nil
+	}
+	states := make([]VolumeState, 0, len(cm.Data))
+	for volumeID := range cm.Data {
+		state, _, err := decodeVolumeState(cm, volumeID)
+		if err != nil {
+			log.Errorf("volume store: ignore volume %s: %s", volumeID, err.Error())
+			continue
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].VolumeID < states[j].VolumeID })
+	return states
+}
+
+func (store *configMapStore) Put(state VolumeState) error {
+	return store.update(func(cm *corev1.ConfigMap) (bool, error) {
+		prev, _, _ := decodeVolumeState(cm, state.VolumeID)
+		state.Version = prev.Version + 1
+		data, err := json.Marshal(state)
+		if err != nil {
+			return false, err
+		}
+		cm.Data[state.VolumeID] = string(data)
+		return true, nil
+	})
+}
+
+func (store *configMapStore) Delete(volumeID string) error {
+	return store.update(func(cm *corev1.ConfigMap) (bool, error) {
+		if _, ok := cm.Data[volumeID]; !ok {
+			return false, nil
+		}
+		delete(cm.Data, volumeID)
+		return true, nil
+	})
+}
+
+// get returns the configmap, which is empty if not created yet
+func (store *configMapStore) get() (*corev1.ConfigMap, bool, error) {
+	cm, err := store.client.CoreV1().ConfigMaps(store.namespace).Get(context.Background(), store.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: store.name, Namespace: store.namespace}}, false, nil
+	}
+	return cm, err == nil, err
+}
+
+// update applies mutate to the latest configmap and writes it back, the configmap may be
+// updated by the controllers on other nodes in the meantime
+func (store *configMapStore) update(mutate func(cm *corev1.ConfigMap) (bool, error)) error {
+	var err error
+	for i := 0; i < configMapStoreRetries; i++ {
+		var cm *corev1.ConfigMap
+		var exists, changed bool
+		if cm, exists, err = store.get(); err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		if changed, err = mutate(cm); err != nil || !changed {
+			return err
+		}
+		if !exists {
+			_, err = store.client.CoreV1().ConfigMaps(store.namespace).Create(context.Background(), cm, metav1.CreateOptions{})
+		} else {
+			_, err = store.client.CoreV1().ConfigMaps(store.namespace).Update(context.Background(), cm, metav1.UpdateOptions{})
+		}
+		if err == nil || !(apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)) {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update volume store %s/%s: %s", store.namespace, store.name, err.Error())
+	}
+	return nil
+}
+
+// decodeVolumeState parses the state of volume in configmap
+func decodeVolumeState(cm *corev1.ConfigMap, volumeID string) (VolumeState, bool, error) {
+	data, ok := cm.Data[volumeID]
+	if !ok {
+		return VolumeState{}, false, nil
+	}
+	state := VolumeState{}
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return VolumeState{}, false, err
+	}
+	return state, true, nil
+}
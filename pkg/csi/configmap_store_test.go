@@ -0,0 +1,62 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"reflect"
+	"testing"
+
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestConfigMapStore(t *testing.T) {
+	client := k8sfake.NewSimpleClientset()
+	store := NewConfigMapStore(client, "kube-system", DefaultControllerVolumeStoreName)
+	if got := store.List(); len(got) != 0 {
+		t.Errorf("List() before configmap created = %+v, want empty", got)
+	}
+	if err := store.Delete("pv-1"); err != nil {
+		t.Errorf("Delete() before configmap created error = %v", err)
+	}
+	states := []VolumeState{
+		{VolumeID: "pv-2", Phase: VolumeDeleting, NodeName: "node-2", VgName: "share"},
+		{VolumeID: "pv-1", Phase: VolumeCreating, NodeName: "node-1", VgName: "share", PVCName: "pvc-1", PVCNamespace: "default"},
+		{VolumeID: "pv-1", Phase: VolumeCreated, NodeName: "node-1", VgName: "share", Volume: []byte{1, 2}},
+	}
+	for _, state := range states {
+		if err := store.Put(state); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the states are read from apiserver by the controller on another node
+	store = NewConfigMapStore(client, "kube-system", DefaultControllerVolumeStoreName)
+	expect := []VolumeState{states[2], states[0]}
+	expect[0].Version, expect[1].Version = 2, 1
+	if got := store.List(); !reflect.DeepEqual(got, expect) {
+		t.Errorf("List() = %+v, want %+v", got, expect)
+	}
+	if err := store.Delete("pv-2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.Get("pv-2"); ok {
+		t.Errorf("Get(pv-2) after Delete() found")
+	}
+	if state, ok := store.Get("pv-1"); !ok || !reflect.DeepEqual(state, expect[0]) {
+		t.Errorf("Get(pv-1) = %+v, %v, want %+v", state, ok, expect[0])
+	}
+}
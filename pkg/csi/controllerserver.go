@@ -22,17 +22,23 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	localtype "github.com/alibaba/open-local/pkg"
+	localv1alpha1 "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
 	"github.com/alibaba/open-local/pkg/csi/adapter"
 	"github.com/alibaba/open-local/pkg/csi/client"
 	"github.com/alibaba/open-local/pkg/csi/server"
+	clientset "github.com/alibaba/open-local/pkg/generated/clientset/versioned"
+	"github.com/alibaba/open-local/pkg/utils"
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	csilib "github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/docker/go-units"
+	"github.com/golang/protobuf/proto"
 	timestamppb "github.com/golang/protobuf/ptypes/timestamp"
 	csicommon "github.com/kubernetes-csi/drivers/pkg/csi-common"
 	snapshotapi "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1"
@@ -42,6 +48,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
@@ -57,6 +64,8 @@ const (
 	MountPointType = "MountPoint"
 	// DeviceVolumeType type
 	DeviceVolumeType = "Device"
+	// QuotaVolumeType type
+	QuotaVolumeType = "Quota"
 	// PvcNameTag in annotations
 	PvcNameTag = "csi.storage.k8s.io/pvc/name"
 	// PvcNsTag in annotations
@@ -71,22 +80,28 @@ const (
 	CsiProvisionerTag = "volume.beta.kubernetes.io/storage-provisioner"
 	// StripingType striping type
 	StripingType = "striping"
+	// ThinType thin type, volume is created in the thin pool of VG
+	ThinType = "thin"
 	// connection timeout
 	DefaultConnectTimeout = 3
 
 	// TopologyNodeKey define host name of node
 	TopologyNodeKey = "kubernetes.io/hostname"
+	// cloneProgressInterval is the interval to check the progress of cloning volume
+	cloneProgressInterval = 2 * time.Second
 )
 
 type controllerServer struct {
 	*csicommon.DefaultControllerServer
 	client                kubernetes.Interface
+	localclient           clientset.Interface
 	snapclient            snapshot.Interface
 	driverName            string
 	grpcConnectionTimeout time.Duration
+	volumeStore           Store
 }
 
-var supportVolumeTypes = []string{LvmVolumeType, MountPointType, DeviceVolumeType}
+var supportVolumeTypes = []string{LvmVolumeType, MountPointType, DeviceVolumeType, QuotaVolumeType}
 
 func newControllerServer(d *csicommon.CSIDriver, grpcConnectionTimeout int) *controllerServer {
 	cfg, err := clientcmd.BuildConfigFromFlags(masterURL, kubeconfig)
@@ -101,18 +116,29 @@ func newControllerServer(d *csicommon.CSIDriver, grpcConnectionTimeout int) *con
 	if err != nil {
 		log.Fatalf("Error building snapshot clientset: %s", err.Error())
 	}
+	localClient, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		log.Fatalf("Error building open-local clientset: %s", err.Error())
+	}
+	namespace := os.Getenv(EnvNamespace)
+	if namespace == "" {
+		namespace = DefaultControllerVolumeStoreNamespace
+	}
+	// the controller may be restarted on another node, so the states are kept in apiserver
+	store := NewConfigMapStore(kubeClient, namespace, DefaultControllerVolumeStoreName)
 
-	return &controllerServer{
+	cs := &controllerServer{
 		DefaultControllerServer: csicommon.NewDefaultControllerServer(d),
 		client:                  kubeClient,
+		localclient:             localClient,
 		snapclient:              snapClient,
 		grpcConnectionTimeout:   time.Duration(grpcConnectionTimeout * int(time.Second)),
+		volumeStore:             store,
 	}
+	go cs.reconcileVolumes(context.Background())
+	return cs
 }
 
-// the map of req.Name and csi.Volume
-var createdVolumeMap = map[string]*csi.Volume{}
-
 // CreateVolume csi interface
 func (cs *controllerServer) CreateVolume(ctx context.Context, req *csilib.CreateVolumeRequest) (*csilib.CreateVolumeResponse, error) {
 	volumeID := req.GetName()
@@ -131,9 +157,12 @@ func (cs *controllerServer) CreateVolume(ctx context.Context, req *csilib.Create
 		log.Errorf("CreateVolume: local Volume Capabilities cannot be empty")
 		return nil, status.Error(codes.InvalidArgument, "Volume Capabilities cannot be empty")
 	}
-	if value, ok := createdVolumeMap[req.Name]; ok {
-		log.Infof("CreateVolume: local volume already be created, pvName: %s, VolumeId: %s", req.Name, value.VolumeId)
-		return &csi.CreateVolumeResponse{Volume: value}, nil
+	if state, ok := cs.volumeStore.Get(req.Name); ok && state.Phase == VolumeCreated {
+		value := &csi.Volume{}
+		if err := proto.Unmarshal(state.Volume, value); err == nil {
+			log.Infof("CreateVolume: local volume already be created, pvName: %s, VolumeId: %s", req.Name, value.VolumeId)
+			return &csi.CreateVolumeResponse{Volume: value}, nil
+		}
 	}
 	trace.Step("Step 1: Validate Request done")
 	// Step 2: get necessary info
@@ -149,7 +178,7 @@ func (cs *controllerServer) CreateVolume(ctx context.Context, req *csilib.Create
 	}
 	if volumeType == "" {
 		log.Errorf("CreateVolume: Create volume %s with error volumeType %v", volumeID, parameters)
-		return nil, status.Errorf(codes.InvalidArgument, "Local driver only support LVM/MountPoint/Device volume type, no type %s", volumeType)
+		return nil, status.Errorf(codes.InvalidArgument, "Local driver only support LVM/MountPoint/Device/Quota volume type, no type %s", volumeType)
 	}
 	if value, ok := parameters[PvcNameTag]; ok {
 		pvcName = value
@@ -166,12 +195,58 @@ func (cs *controllerServer) CreateVolume(ctx context.Context, req *csilib.Create
 
 	// Step 3: Storage schedule
 	isSnapshot := false
+	sourceSnapshotID := ""
+	sourceVolumeID := ""
+	var restore *restoreSource
 	paraList := map[string]string{}
 	switch volumeType {
 	case LvmVolumeType:
 		var err error
+		// check volume content source is volume
+		if sourceVolume := req.GetVolumeContentSource().GetVolume(); sourceVolume != nil {
+			srcVolumeID := sourceVolume.GetVolumeId()
+			log.Infof("CreateVolume: volume %s is cloned from volume %s", volumeID, srcVolumeID)
+			// clone is on the same node and vg of src volume
+			nodeSelected, storageSelected, srcPV, err := getPvSpec(cs.client, srcVolumeID, cs.driverName)
+			if err != nil {
+				log.Errorf("CreateVolume: get pv spec failed: %s", err.Error())
+				return nil, status.Errorf(codes.NotFound, "CreateVolume: get pv spec of source volume %s failed: %s", srcVolumeID, err.Error())
+			}
+			if srcPV.Spec.CSI.VolumeAttributes[VolumeTypeKey] != LvmVolumeType {
+				return nil, status.Errorf(codes.InvalidArgument, "CreateVolume: only support cloning from LVM volume, source volume %s is %s", srcVolumeID, srcPV.Spec.CSI.VolumeAttributes[VolumeTypeKey])
+			}
+			srcPVSize, _ := srcPV.Spec.Capacity.Storage().AsInt64()
+			size := req.GetCapacityRange().GetRequiredBytes()
+			if size < srcPVSize {
+				return nil, status.Errorf(codes.OutOfRange, "CreateVolume: requested size %d is smaller than source volume %s(%d)", size, srcVolumeID, srcPVSize)
+			}
+			// set paraList for NodeStageVolume and NodePublishVolume
+			parameters[NodeSchedueTag] = nodeSelected
+			paraList[VgNameTag] = storageSelected
+			paraList[LvmTypeTag] = LinearType
+			if isThinPV(srcPV) {
+				paraList[LvmTypeTag] = ThinType
+			}
+			if err := cs.recordCreating(volumeID, nodeSelected, storageSelected, pvcName, pvcNameSpace); err != nil {
+				return nil, err
+			}
+			if err := cs.cloneVolume(ctx, nodeSelected, storageSelected, volumeID, srcVolumeID, size); err != nil {
+				log.Errorf("CreateVolume: clone volume %s/%s from %s at node %s with error: %s", storageSelected, volumeID, srcVolumeID, nodeSelected, err.Error())
+				return nil, err
+			}
+			sourceVolumeID = srcVolumeID
+			log.Infof("CreateVolume: clone volume %s/%s from %s at node %s successfully", storageSelected, volumeID, srcVolumeID, nodeSelected)
+			// break switch
+			break
+		}
+		// backed up snapshot is restored on the node selected by scheduler like a new volume
+		restore, err = cs.getRestoreSource(req)
+		if err != nil {
+			log.Errorf("CreateVolume: get restore source of volume %s failed: %s", volumeID, err.Error())
+			return nil, err
+		}
 		// check volume content source is snapshot
-		if volumeSource := req.GetVolumeContentSource(); volumeSource != nil {
+		if volumeSource := req.GetVolumeContentSource(); volumeSource != nil && restore == nil {
 			// validate
 			if _, ok := volumeSource.GetType().(*csi.VolumeContentSource_Snapshot); !ok {
 				log.Errorf("CreateVolume: unsupported volumeContentSource type")
@@ -201,23 +276,40 @@ func (cs *controllerServer) CreateVolume(ctx context.Context, req *csilib.Create
 				return nil, status.Errorf(codes.InvalidArgument, "get snapshot class failed: %s", err.Error())
 			}
 			ro, exist := class.Parameters[localtype.ParamSnapshotReadonly]
-			if !exist || ro != "true" {
-				log.Errorf("CreateVolume: only support readonly snapshot now, you must set %s parameter in volumesnapshotclass", localtype.ParamSnapshotReadonly)
-				return nil, status.Errorf(codes.Unimplemented, "CreateVolume: only support readonly snapshot now, you must set %s parameter in volumesnapshotclass", localtype.ParamSnapshotReadonly)
-			}
+			isReadonly := exist && ro == "true"
 			// get node name and vg name from src volume
-			nodeSelected, storageSelected, _, err := getPvSpec(cs.client, srcVolumeID, cs.driverName)
+			nodeSelected, storageSelected, srcPV, err := getPvSpec(cs.client, srcVolumeID, cs.driverName)
 			if err != nil {
 				log.Errorf("CreateVolume: get pv spec failed: %s", err.Error())
 				return nil, status.Errorf(codes.Internal, "CreateVolume: get pv spec failed: %s", err.Error())
 			}
+			// only thin snapshot can be cloned as a writable volume
+			if !isReadonly && !isThinPV(srcPV) {
+				log.Errorf("CreateVolume: only support readonly snapshot or snapshot of thin volume now, you must set %s parameter in volumesnapshotclass", localtype.ParamSnapshotReadonly)
+				return nil, status.Errorf(codes.Unimplemented, "CreateVolume: only support readonly snapshot or snapshot of thin volume now, you must set %s parameter in volumesnapshotclass", localtype.ParamSnapshotReadonly)
+			}
 			// set paraList for NodeStageVolume and NodePublishVolume
 			parameters[NodeSchedueTag] = nodeSelected
 			paraList[VgNameTag] = storageSelected
-			paraList[localtype.ParamSnapshotName] = snapshotID
-			paraList[localtype.ParamSnapshotReadonly] = "true"
 			isSnapshot = true
-			log.Infof("CreateVolume: get snapshot volume %s info: node(%s) vg(%s)", volumeID, nodeSelected, storageSelected)
+			sourceSnapshotID = snapshotID
+			if isReadonly {
+				paraList[localtype.ParamSnapshotName] = snapshotID
+				paraList[localtype.ParamSnapshotReadonly] = "true"
+				log.Infof("CreateVolume: get snapshot volume %s info: node(%s) vg(%s)", volumeID, nodeSelected, storageSelected)
+				// break switch
+				break
+			}
+			// writable clone is a thin snapshot of the snapshot lv, which is a normal thin volume afterwards
+			paraList[LvmTypeTag] = ThinType
+			if err := cs.recordCreating(volumeID, nodeSelected, storageSelected, pvcName, pvcNameSpace); err != nil {
+				return nil, err
+			}
+			if err := cs.createThinClone(ctx, nodeSelected, storageSelected, volumeID, snapshotID, srcPV, req.GetCapacityRange().GetRequiredBytes()); err != nil {
+				log.Errorf("CreateVolume: create thin clone %s/%s from snapshot %s at node %s with error: %s", storageSelected, volumeID, snapshotID, nodeSelected, err.Error())
+				return nil, err
+			}
+			log.Infof("CreateVolume: create thin clone %s/%s from snapshot %s at node %s", storageSelected, volumeID, snapshotID, nodeSelected)
 			// break switch
 			break
 		}
@@ -278,6 +370,9 @@ func (cs *controllerServer) CreateVolume(ctx context.Context, req *csilib.Create
 		if value, ok := parameters[LvmTypeTag]; ok && value == StripingType {
 			options.Striping = true
 		}
+		if value, ok := parameters[LvmTypeTag]; ok && value == ThinType {
+			options.Thin = true
+		}
 		options.Size = uint64(req.GetCapacityRange().GetRequiredBytes())
 
 		if nodeSelected != "" && storageSelected != "" {
@@ -287,7 +382,18 @@ func (cs *controllerServer) CreateVolume(ctx context.Context, req *csilib.Create
 				return nil, err
 			}
 			defer conn.Close()
-			if lvmName, err := conn.GetLvm(ctx, storageSelected, volumeID); err == nil && lvmName == "" {
+			if err := cs.recordCreating(volumeID, nodeSelected, storageSelected, pvcName, pvcNameSpace); err != nil {
+				return nil, err
+			}
+			if restore != nil {
+				if err := cs.restoreVolume(ctx, conn, options, restore); err != nil {
+					log.Errorf("CreateVolume: restore lvm %s/%s from %s at node %s with error: %s", storageSelected, volumeID, restore.location.String(), nodeSelected, err.Error())
+					return nil, err
+				}
+				isSnapshot = true
+				sourceSnapshotID = restore.snapshotID
+				log.Infof("CreateVolume: restore lvm %s/%s from %s at node %s successfully", storageSelected, volumeID, restore.location.String(), nodeSelected)
+			} else if lvmName, err := conn.GetLvm(ctx, storageSelected, volumeID); err == nil && lvmName == "" {
 				outstr, err := conn.CreateLvm(ctx, options)
 				if err != nil {
 					log.Errorf("CreateVolume: Create lvm %s/%s, options: %v with error: %s", storageSelected, volumeID, options, err.Error())
@@ -375,6 +481,43 @@ func (cs *controllerServer) CreateVolume(ctx context.Context, req *csilib.Create
 			nodeSelected = nodeID
 		}
 		log.Infof("CreateVolume: Successful create device volume %s/%s at node %s", storageSelected, req.Name, nodeSelected)
+	case QuotaVolumeType:
+		var err error
+		// Node and Storage have been scheduled
+		if storageSelected != "" && nodeSelected != "" {
+			paraList, err = quotaScheduled(storageSelected, parameters)
+			if err != nil {
+				log.Errorf("CreateVolume: create quota volume %s/%s at node %s error: %s", storageSelected, req.Name, nodeSelected, err.Error())
+				code := codes.Internal
+				if strings.Contains(err.Error(), "Insufficient") {
+					code = codes.ResourceExhausted
+				}
+				return nil, status.Errorf(code, "CreateVolume: Parse quota all scheduled info error: %s", err.Error())
+			}
+		} else if nodeSelected != "" {
+			paraList, err = quotaPartScheduled(nodeSelected, pvcName, pvcNameSpace, parameters)
+			if err != nil {
+				log.Errorf("CreateVolume: part schedule quota volume %s at node %s error: %s", req.Name, nodeSelected, err.Error())
+				code := codes.Internal
+				if strings.Contains(err.Error(), "Insufficient") {
+					code = codes.ResourceExhausted
+				}
+				return nil, status.Errorf(code, "Parse quota part schedule info error: %s", err.Error())
+			}
+		} else {
+			nodeID := ""
+			nodeID, paraList, err = quotaNoScheduled(parameters)
+			if err != nil {
+				log.Errorf("CreateVolume: schedule quota volume %s error: %s", req.Name, err.Error())
+				code := codes.Internal
+				if strings.Contains(err.Error(), "Insufficient") {
+					code = codes.ResourceExhausted
+				}
+				return nil, status.Errorf(code, "Parse quota schedule info error: %s", err.Error())
+			}
+			nodeSelected = nodeID
+		}
+		log.Infof("CreateVolume: Successful create quota volume %s/%s at node %s", storageSelected, req.Name, nodeSelected)
 	default:
 		log.Errorf("CreateVolume: Create with no support volume type %s", volumeType)
 		return nil, status.Error(codes.InvalidArgument, "Create with no support type "+volumeType)
@@ -427,13 +570,29 @@ func (cs *controllerServer) CreateVolume(ctx context.Context, req *csilib.Create
 		response.Volume.ContentSource = &csi.VolumeContentSource{
 			Type: &csi.VolumeContentSource_Snapshot{
 				Snapshot: &csi.VolumeContentSource_SnapshotSource{
-					SnapshotId: paraList[localtype.ParamSnapshotName],
+					SnapshotId: sourceSnapshotID,
 				},
 			},
 		}
 	}
 
-	createdVolumeMap[req.Name] = response.Volume
+	if sourceVolumeID != "" {
+		response.Volume.ContentSource = &csi.VolumeContentSource{
+			Type: &csi.VolumeContentSource_Volume{
+				Volume: &csi.VolumeContentSource_VolumeSource{
+					VolumeId: sourceVolumeID,
+				},
+			},
+		}
+	}
+
+	volume, err := proto.Marshal(response.Volume)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "CreateVolume: marshal volume %s error: %s", volumeID, err.Error())
+	}
+	if err := cs.volumeStore.Put(VolumeState{VolumeID: volumeID, Phase: VolumeCreated, NodeName: nodeSelected, VgName: storageSelected, PVCName: pvcName, PVCNamespace: pvcNameSpace, Volume: volume}); err != nil {
+		return nil, status.Errorf(codes.Internal, "CreateVolume: record volume %s error: %s", volumeID, err.Error())
+	}
 	log.Infof("Success create Volume: %s, Size: %d", volumeID, req.GetCapacityRange().GetRequiredBytes())
 	trace.Step(fmt.Sprintf("Step 4: create volume %s done", volumeID))
 	return response, nil
@@ -442,11 +601,18 @@ func (cs *controllerServer) CreateVolume(ctx context.Context, req *csilib.Create
 func (server *controllerServer) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequest) (*csi.DeleteVolumeResponse, error) {
 	log.Infof("DeleteVolume: deleting local volume %s", req.GetVolumeId())
 	volumeID := req.GetVolumeId()
+	if state, ok := server.volumeStore.Get(volumeID); ok && state.Phase == VolumeCreating {
+		// CreateVolume has not succeeded, the lv may be still being cloned or restored
+		return server.deleteCreatingVolume(ctx, state)
+	}
 	nodeName, vgName, pvObj, err := getPvSpec(server.client, volumeID, server.driverName)
 	if err != nil {
 		log.Errorf("DeleteVolume: get pv spec %s with error: %s", volumeID, err.Error())
 		return nil, err
 	}
+	if err := server.volumeStore.Put(VolumeState{VolumeID: volumeID, Phase: VolumeDeleting, NodeName: nodeName, VgName: vgName}); err != nil {
+		return nil, status.Errorf(codes.Internal, "DeleteVolume: record volume %s error: %s", volumeID, err.Error())
+	}
 	volumeType := ""
 	if value, ok := pvObj.Spec.CSI.VolumeAttributes[VolumeTypeKey]; ok {
 		volumeType = value
@@ -594,11 +760,39 @@ func (server *controllerServer) DeleteVolume(ctx context.Context, req *csi.Delet
 			}
 		}
 		log.Infof("DeleteVolume: successful delete Device volume(%s)", volumeID)
+	case QuotaVolumeType:
+		if pvObj.Spec.PersistentVolumeReclaimPolicy == v1.PersistentVolumeReclaimDelete {
+			if nodeName == "" {
+				log.Errorf("DeleteVolume: Get Quota Spec for volume %s, with empty nodes", volumeID)
+				return nil, errors.New("Quota Pv is illegal, No node info")
+			}
+			mountPoint := ""
+			if value, ok := pvObj.Spec.CSI.VolumeAttributes[MountPointType]; ok {
+				mountPoint = value
+			}
+			if mountPoint == "" {
+				log.Errorf("DeleteVolume: Get Quota MountPoint for volume %s, with empty", volumeID)
+				return nil, errors.New("Quota MountPoint is empty")
+			}
+			conn, err := server.getNodeConn(nodeName)
+			if err != nil {
+				log.Errorf("DeleteVolume: New quota %s Connection error: %s", req.GetVolumeId(), err.Error())
+				return nil, err
+			}
+			defer conn.Close()
+			if err := conn.RemoveQuota(ctx, getQuotaSubpath(mountPoint, volumeID)); err != nil {
+				log.Errorf("DeleteVolume: Remove quota subpath for %s with error: %s", req.GetVolumeId(), err.Error())
+				return nil, errors.New("DeleteVolume: Delete quota subpath Failed: " + err.Error())
+			}
+		}
+		log.Infof("DeleteVolume: successful delete Quota volume(%s)", volumeID)
 	default:
 		log.Errorf("DeleteVolume: volumeType %s not supported %s", volumeType, volumeID)
 		return nil, status.Errorf(codes.InvalidArgument, "Local driver only support LVM volume type, no type %s", volumeType)
 	}
-	delete(createdVolumeMap, req.VolumeId)
+	if err := server.volumeStore.Delete(volumeID); err != nil {
+		return nil, status.Errorf(codes.Internal, "DeleteVolume: remove volume %s from store error: %s", volumeID, err.Error())
+	}
 	log.Infof("DeleteVolume: successful delete local volume %s", volumeID)
 	return &csi.DeleteVolumeResponse{}, nil
 }
@@ -633,9 +827,10 @@ func (cs *controllerServer) CreateSnapshot(ctx context.Context, req *csi.CreateS
 	}
 	log.Infof("CreateSnapshot: snapshot %s is in %s, whose vg is %s", snapshotName, nodeName, vgName)
 
-	// Step 4: update initialSize if initialSize is bigger than pv request size
+	// Step 4: update initialSize if initialSize is bigger than pv request size,
+	// thin snapshot is allocated from thin pool on demand, its size is the same as source volume
 	srcPVSize, _ := srcPV.Spec.Capacity.Storage().AsInt64()
-	if srcPVSize < int64(initialSize) {
+	if srcPVSize < int64(initialSize) || isThinPV(srcPV) {
 		initialSize = uint64(srcPVSize)
 	}
 
@@ -663,6 +858,11 @@ func (cs *controllerServer) CreateSnapshot(ctx context.Context, req *csi.CreateS
 	} else {
 		log.Infof("CreateSnapshot: lvm snapshot %s in node %s already exists", snapshotName, nodeName)
 	}
+
+	// Step 7: upload snapshot if backup store is set in volumesnapshotclass
+	if store := getBackupStore(req.GetParameters(), req.GetSecrets()); store != nil {
+		return cs.backupSnapshot(ctx, conn, req, vgName, store, int64(initialSize))
+	}
 	return cs.newCreateSnapshotResponse(req, int64(initialSize))
 }
 
@@ -684,6 +884,17 @@ func (cs *controllerServer) DeleteSnapshot(ctx context.Context, req *csi.DeleteS
 		return nil, status.Errorf(codes.Internal, "DeleteSnapshot: get snapContent %s error: %s", snapshotName, err.Error())
 	}
 	srcVolumeID := *snapContent.Spec.Source.VolumeHandle
+	if snapContent.Spec.VolumeSnapshotClassName != nil {
+		class, err := getVolumeSnapshotClass(cs.snapclient, *snapContent.Spec.VolumeSnapshotClassName)
+		if err != nil {
+			log.Errorf("DeleteSnapshot: get snapshot class failed: %s", err.Error())
+			return nil, status.Errorf(codes.Internal, "DeleteSnapshot: get snapshot class failed: %s", err.Error())
+		}
+		if err := deleteBackup(ctx, snapContent, class.Parameters, req.GetSecrets()); err != nil {
+			log.Errorf("DeleteSnapshot: delete backup of snapshot %s error: %s", snapshotName, err.Error())
+			return nil, status.Errorf(codes.Internal, "DeleteSnapshot: delete backup of snapshot %s error: %s", snapshotName, err.Error())
+		}
+	}
 
 	// Step 3: get nodeName and vgName
 	nodeName, vgName, _, err := getPvSpec(cs.client, srcVolumeID, cs.driverName)
@@ -751,6 +962,12 @@ func (cs *controllerServer) ControllerExpandVolume(ctx context.Context, req *csi
 		return nil, errors.New("ControllerExpandVolume: expand volume error " + err.Error())
 	}
 
+	// quota volume is expanded by NodeExpandVolume only
+	if attributes[VolumeTypeKey] == QuotaVolumeType {
+		log.Infof("ControllerExpandVolume: Successful reserve quota %s in node %s", volumeID, nodeName)
+		return &csi.ControllerExpandVolumeResponse{CapacityBytes: volSizeBytes, NodeExpansionRequired: true}, nil
+	}
+
 	// Step 3: get grpc client
 	conn, err := cs.getNodeConn(nodeName)
 	if err != nil {
@@ -781,6 +998,228 @@ func (cs *controllerServer) ControllerUnpublishVolume(ctx context.Context, req *
 	return &csi.ControllerUnpublishVolumeResponse{}, nil
 }
 
+// ControllerGetVolume returns the volume with the node it is on, and its condition
+// reported by agent in NodeLocalStorage
+func (cs *controllerServer) ControllerGetVolume(ctx context.Context, req *csi.ControllerGetVolumeRequest) (*csi.ControllerGetVolumeResponse, error) {
+	volumeID := req.GetVolumeId()
+	if volumeID == "" {
+		return nil, status.Error(codes.InvalidArgument, "ControllerGetVolume: Volume ID not provided")
+	}
+	pv, err := getPvObj(cs.client, volumeID)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, status.Errorf(codes.NotFound, "ControllerGetVolume: volume %s is not found", volumeID)
+		}
+		return nil, status.Errorf(codes.Internal, "ControllerGetVolume: get pv %s error: %s", volumeID, err.Error())
+	}
+	if !isOpenLocalPV(pv) {
+		return nil, status.Errorf(codes.NotFound, "ControllerGetVolume: pv %s is not an open-local volume", volumeID)
+	}
+	_, nodeName := utils.IsLocalPV(pv)
+	if nodeName == "" {
+		return nil, status.Errorf(codes.Internal, "ControllerGetVolume: node of pv %s is not found", volumeID)
+	}
+	publishedNodes, err := cs.listPublishedNodes(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ControllerGetVolume: %s", err.Error())
+	}
+
+	nls, err := cs.localclient.CsiV1alpha1().NodeLocalStorages().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		log.Warningf("ControllerGetVolume: get nls %s error: %s", nodeName, err.Error())
+		capacity := pv.Spec.Capacity[v1.ResourceStorage]
+		return &csi.ControllerGetVolumeResponse{
+			Volume: newCSIVolume(pv, nodeName, capacity.Value()),
+			Status: &csi.ControllerGetVolumeResponse_VolumeStatus{
+				PublishedNodeIds: publishedNodes[pv.Name],
+				VolumeCondition:  abnormalCondition("get NodeLocalStorage of node %s failed: %s", nodeName, err.Error()),
+			},
+		}, nil
+	}
+	volume, condition := nlsVolume(nls, pv)
+	return &csi.ControllerGetVolumeResponse{
+		Volume: volume,
+		Status: &csi.ControllerGetVolumeResponse_VolumeStatus{
+			PublishedNodeIds: publishedNodes[pv.Name],
+			VolumeCondition:  condition,
+		},
+	}, nil
+}
+
+// ListVolumes lists the open-local volumes of all types with their conditions reported by
+// agent in NodeLocalStorages. The starting token is the offset of the volume in the list,
+// which is sorted by node and volume id.
+func (cs *controllerServer) ListVolumes(ctx context.Context, req *csi.ListVolumesRequest) (*csi.ListVolumesResponse, error) {
+	if req.GetMaxEntries() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "ListVolumes: max entries %d is negative", req.GetMaxEntries())
+	}
+	start := 0
+	if token := req.GetStartingToken(); token != "" {
+		var err error
+		if start, err = strconv.Atoi(token); err != nil || start < 0 {
+			return nil, status.Errorf(codes.Aborted, "ListVolumes: invalid starting token %s", token)
+		}
+	}
+
+	nlsList, err := cs.localclient.CsiV1alpha1().NodeLocalStorages().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ListVolumes: list nls error: %s", err.Error())
+	}
+	pvList, err := cs.client.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ListVolumes: list pv error: %s", err.Error())
+	}
+	publishedNodes, err := cs.listPublishedNodes(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ListVolumes: %s", err.Error())
+	}
+	entries := listVolumes(nlsList.Items, pvList.Items, publishedNodes)
+	if start > len(entries) {
+		return nil, status.Errorf(codes.Aborted, "ListVolumes: starting token %d is out of range %d", start, len(entries))
+	}
+
+	end := len(entries)
+	if req.GetMaxEntries() > 0 && start+int(req.GetMaxEntries()) < end {
+		end = start + int(req.GetMaxEntries())
+	}
+	nextToken := ""
+	if end < len(entries) {
+		nextToken = strconv.Itoa(end)
+	}
+	return &csi.ListVolumesResponse{Entries: entries[start:end], NextToken: nextToken}, nil
+}
+
+// listPublishedNodes returns the nodes which volumes are attached to according to
+// VolumeAttachments, keyed by pv name. There is none if the CSIDriver does not
+// require attaching.
+func (cs *controllerServer) listPublishedNodes(ctx context.Context) (map[string][]string, error) {
+	attachments, err := cs.client.StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list volumeattachments error: %s", err.Error())
+	}
+	publishedNodes := make(map[string][]string)
+	for _, va := range attachments.Items {
+		pvName := va.Spec.Source.PersistentVolumeName
+		if pvName == nil || !va.Status.Attached || !utils.ContainsProvisioner(va.Spec.Attacher) {
+			continue
+		}
+		publishedNodes[*pvName] = append(publishedNodes[*pvName], va.Spec.NodeName)
+	}
+	for _, nodes := range publishedNodes {
+		sort.Strings(nodes)
+	}
+	return publishedNodes, nil
+}
+
+// listVolumes returns the open-local pvs in order of node and volume id. LVM pvs whose
+// logical volumes are not reported by agent yet are skipped.
+func listVolumes(nlsItems []localv1alpha1.NodeLocalStorage, pvs []v1.PersistentVolume, publishedNodes map[string][]string) []*csi.ListVolumesResponse_Entry {
+	nlses := make(map[string]*localv1alpha1.NodeLocalStorage, len(nlsItems))
+	for i := range nlsItems {
+		nlses[nlsItems[i].Name] = &nlsItems[i]
+	}
+	type nodePV struct {
+		nodeName string
+		pv       *v1.PersistentVolume
+	}
+	var localPVs []nodePV
+	for i := range pvs {
+		pv := &pvs[i]
+		if !isOpenLocalPV(pv) {
+			continue
+		}
+		if _, nodeName := utils.IsLocalPV(pv); nodeName != "" {
+			localPVs = append(localPVs, nodePV{nodeName, pv})
+		}
+	}
+	sort.Slice(localPVs, func(i, j int) bool {
+		if localPVs[i].nodeName != localPVs[j].nodeName {
+			return localPVs[i].nodeName < localPVs[j].nodeName
+		}
+		return localPVs[i].pv.Name < localPVs[j].pv.Name
+	})
+
+	var entries []*csi.ListVolumesResponse_Entry
+	for _, item := range localPVs {
+		var volume *csi.Volume
+		var condition *csi.VolumeCondition
+		nls, exist := nlses[item.nodeName]
+		switch {
+		case !exist:
+			capacity := item.pv.Spec.Capacity[v1.ResourceStorage]
+			volume = newCSIVolume(item.pv, item.nodeName, capacity.Value())
+			condition = abnormalCondition("NodeLocalStorage of node %s is not found", item.nodeName)
+		case item.pv.Spec.CSI.VolumeAttributes[VolumeTypeTag] == LvmVolumeType && !nlsHasLV(nls, item.pv):
+			continue
+		default:
+			volume, condition = nlsVolume(nls, item.pv)
+		}
+		entries = append(entries, &csi.ListVolumesResponse_Entry{
+			Volume: volume,
+			Status: &csi.ListVolumesResponse_VolumeStatus{
+				PublishedNodeIds: publishedNodes[item.pv.Name],
+				VolumeCondition:  condition,
+			},
+		})
+	}
+	return entries
+}
+
+// isOpenLocalPV checks whether pv is provisioned by open-local csi driver
+func isOpenLocalPV(pv *v1.PersistentVolume) bool {
+	return pv.Spec.CSI != nil && utils.ContainsProvisioner(pv.Spec.CSI.Driver)
+}
+
+// nlsHasLV checks whether the logical volume of LVM pv is reported in nls
+func nlsHasLV(nls *localv1alpha1.NodeLocalStorage, pv *v1.PersistentVolume) bool {
+	attributes := pv.Spec.CSI.VolumeAttributes
+	lvName := lvNameOf(pv.Name, attributes)
+	for _, vg := range nls.Status.NodeStorageInfo.VolumeGroups {
+		if vg.Name != attributes[VgNameTag] {
+			continue
+		}
+		for _, lv := range vg.LogicalVolumes {
+			if lv.Name == lvName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nlsVolume returns the volume of pv on the node of nls, whose capacity is the size
+// of the logical volume if reported by agent, and its condition
+func nlsVolume(nls *localv1alpha1.NodeLocalStorage, pv *v1.PersistentVolume) (*csi.Volume, *csi.VolumeCondition) {
+	attributes := pv.Spec.CSI.VolumeAttributes
+	quantity := pv.Spec.Capacity[v1.ResourceStorage]
+	capacity := quantity.Value()
+	if attributes[VolumeTypeTag] == LvmVolumeType {
+		lvName := lvNameOf(pv.Name, attributes)
+		for _, vg := range nls.Status.NodeStorageInfo.VolumeGroups {
+			if vg.Name != attributes[VgNameTag] {
+				continue
+			}
+			for _, lv := range vg.LogicalVolumes {
+				if lv.Name == lvName {
+					capacity = int64(lv.Total)
+				}
+			}
+		}
+	}
+	return newCSIVolume(pv, nls.Name, capacity), nlsVolumeCondition(nls, pv.Name, attributes)
+}
+
+func newCSIVolume(pv *v1.PersistentVolume, nodeName string, capacity int64) *csi.Volume {
+	return &csi.Volume{
+		VolumeId:      pv.Name,
+		CapacityBytes: capacity,
+		VolumeContext: pv.Spec.CSI.VolumeAttributes,
+		AccessibleTopology: []*csi.Topology{
+			{Segments: map[string]string{TopologyNodeKey: nodeName}},
+		},
+	}
+}
+
 func (cs *controllerServer) newCreateSnapshotResponse(req *csi.CreateSnapshotRequest, snapshotSize int64) (*csi.CreateSnapshotResponse, error) {
 	ts := &timestamppb.Timestamp{
 		Seconds: time.Now().Unix(),
@@ -798,6 +1237,98 @@ func (cs *controllerServer) newCreateSnapshotResponse(req *csi.CreateSnapshotReq
 	}, nil
 }
 
+// createThinClone creates a writable thin snapshot of snapshot lv, and expands it if larger size is requested
+func (cs *controllerServer) createThinClone(ctx context.Context, nodeName, vgName, volumeID, snapshotID string, srcPV *v1.PersistentVolume, size int64) error {
+	conn, err := cs.getNodeConn(nodeName)
+	if err != nil {
+		return status.Errorf(codes.Internal, "get grpc client at node %s error: %s", nodeName, err.Error())
+	}
+	defer conn.Close()
+	lvmName, err := conn.GetLvm(ctx, vgName, volumeID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "get lvm %s/%s error: %s", vgName, volumeID, err.Error())
+	}
+	if lvmName != "" {
+		log.Infof("createThinClone: thin clone %s/%s already exists", vgName, volumeID)
+		return nil
+	}
+	if _, err := conn.CreateSnapshot(ctx, vgName, volumeID, snapshotID, 0); err != nil {
+		return status.Errorf(status.Code(err), "create thin snapshot of %s error: %s", snapshotID, err.Error())
+	}
+	srcPVSize, _ := srcPV.Spec.Capacity.Storage().AsInt64()
+	if size > srcPVSize {
+		if err := conn.ExpandLvm(ctx, vgName, volumeID, uint64(size)); err != nil {
+			return status.Errorf(status.Code(err), "expand thin clone to %d error: %s", size, err.Error())
+		}
+	}
+	return nil
+}
+
+// cloneVolume starts cloning srcVolumeID to volumeID on node, and waits until the copy
+// is finished or ctx is done. The copy keeps running in background when ctx is done,
+// and CreateVolume will be retried by provisioner to wait for it again.
+func (cs *controllerServer) cloneVolume(ctx context.Context, nodeName, vgName, volumeID, srcVolumeID string, size int64) error {
+	conn, err := cs.getNodeConn(nodeName)
+	if err != nil {
+		return status.Errorf(codes.Internal, "get grpc client at node %s error: %s", nodeName, err.Error())
+	}
+	defer conn.Close()
+
+	if _, err := conn.CloneLvm(ctx, vgName, srcVolumeID, volumeID, uint64(size)); err != nil {
+		return status.Errorf(status.Code(err), "clone lvm error: %s", err.Error())
+	}
+	return waitForCopy(ctx, conn, vgName, volumeID)
+}
+
+// waitForCopy waits until the clone or restore of volumeID is finished, or returns Aborted if ctx is done
+func waitForCopy(ctx context.Context, conn client.Connection, vgName, volumeID string) error {
+	ticker := time.NewTicker(cloneProgressInterval)
+	defer ticker.Stop()
+	for {
+		progress, err := conn.GetCloneProgress(ctx, vgName, volumeID)
+		if err != nil {
+			return status.Errorf(status.Code(err), "get copy progress error: %s", err.Error())
+		}
+		if progress.Done {
+			if progress.Error != "" {
+				return status.Errorf(codes.Internal, "copy failed: %s", progress.Error)
+			}
+			return nil
+		}
+		log.Infof("waitForCopy: %s/%s is being copied, %d/%d bytes copied", vgName, volumeID, progress.CopiedBytes, progress.TotalBytes)
+		select {
+		case <-ctx.Done():
+			return status.Errorf(codes.Aborted, "volume is being copied, %d/%d bytes copied", progress.CopiedBytes, progress.TotalBytes)
+		case <-ticker.C:
+		}
+	}
+}
+
+// deleteCreatingVolume cancels the clone or restore and removes the lv of the volume being created
+func (cs *controllerServer) deleteCreatingVolume(ctx context.Context, state VolumeState) (*csi.DeleteVolumeResponse, error) {
+	if err := cs.removeLV(ctx, state, true); err != nil {
+		log.Errorf("DeleteVolume: remove creating volume %s/%s at node %s error: %s", state.VgName, state.VolumeID, state.NodeName, err.Error())
+		return nil, status.Errorf(codes.Internal, "DeleteVolume: remove creating volume %s error: %s", state.VolumeID, err.Error())
+	}
+	log.Infof("DeleteVolume: successful delete creating volume %s/%s at node %s", state.VgName, state.VolumeID, state.NodeName)
+	return &csi.DeleteVolumeResponse{}, nil
+}
+
+// recordCreating records that the lv of volume is being created on node, so that it can
+// be rolled back after restart if the pvc is deleted before provisioned
+func (cs *controllerServer) recordCreating(volumeID, nodeName, vgName, pvcName, pvcNamespace string) error {
+	state := VolumeState{VolumeID: volumeID, Phase: VolumeCreating, NodeName: nodeName, VgName: vgName, PVCName: pvcName, PVCNamespace: pvcNamespace}
+	if err := cs.volumeStore.Put(state); err != nil {
+		return status.Errorf(codes.Internal, "record volume %s error: %s", volumeID, err.Error())
+	}
+	return nil
+}
+
+// isThinPV checks whether the volume is allocated from thin pool
+func isThinPV(pv *v1.PersistentVolume) bool {
+	return pv.Spec.CSI != nil && pv.Spec.CSI.VolumeAttributes[LvmTypeTag] == ThinType
+}
+
 func (cs *controllerServer) getNodeConn(nodeSelected string) (client.Connection, error) {
 	addr, err := getNodeAddr(cs.client, nodeSelected)
 	if err != nil {
@@ -1031,6 +1562,50 @@ func mountpointNoScheduled(parameters map[string]string) (string, map[string]str
 	return "", paraList, nil
 }
 
+func quotaScheduled(storageSelected string, parameters map[string]string) (map[string]string, error) {
+	mountpoint := ""
+	paraList := map[string]string{}
+	if storageSelected != "" {
+		storageMap := map[string]string{}
+		err := json.Unmarshal([]byte(storageSelected), &storageMap)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "Scheduler provide error storage format: "+err.Error())
+		}
+		if value, ok := storageMap[MountPointType]; ok {
+			paraList[MountPointType] = value
+			mountpoint = value
+		}
+	}
+	if mountpoint == "" {
+		return nil, status.Error(codes.InvalidArgument, "Quota Schedule failed "+mountpoint)
+	}
+	return paraList, nil
+}
+
+func quotaPartScheduled(nodeSelected, pvcName, pvcNameSpace string, parameters map[string]string) (map[string]string, error) {
+	paraList := map[string]string{}
+	volumeInfo, err := adapter.ScheduleVolume(QuotaVolumeType, pvcName, pvcNameSpace, "", nodeSelected)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "quota schedule with error "+err.Error())
+	}
+	if volumeInfo.Disk == "" {
+		log.Errorf("quota Schedule finished, but get empty Disk: %v", volumeInfo)
+		return nil, status.Error(codes.InvalidArgument, "quota schedule finish but Disk empty")
+	}
+	paraList[MountPointType] = volumeInfo.Disk
+	return paraList, nil
+}
+
+func quotaNoScheduled(parameters map[string]string) (string, map[string]string, error) {
+	paraList := map[string]string{}
+	return "", paraList, nil
+}
+
+// getQuotaSubpath returns the directory of quota volume under the mount point
+func getQuotaSubpath(mountPoint, volumeID string) string {
+	return filepath.Join(mountPoint, volumeID)
+}
+
 func deviceScheduled(storageSelected string, parameters map[string]string) (map[string]string, error) {
 	device := ""
 	paraList := map[string]string{}
@@ -0,0 +1,306 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"testing"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	localv1alpha1 "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
+	localfake "github.com/alibaba/open-local/pkg/generated/clientset/versioned/fake"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestPV(name, nodeName string, attributes map[string]string) *v1.PersistentVolume {
+	return &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1.PersistentVolumeSpec{
+			Capacity: v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")},
+			PersistentVolumeSource: v1.PersistentVolumeSource{
+				CSI: &v1.CSIPersistentVolumeSource{Driver: localtype.ProvisionerName, VolumeHandle: name, VolumeAttributes: attributes},
+			},
+			NodeAffinity: &v1.VolumeNodeAffinity{
+				Required: &v1.NodeSelector{
+					NodeSelectorTerms: []v1.NodeSelectorTerm{{
+						MatchExpressions: []v1.NodeSelectorRequirement{{
+							Key:      localtype.KubernetesNodeIdentityKey,
+							Operator: v1.NodeSelectorOpIn,
+							Values:   []string{nodeName},
+						}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func newTestNLS(nodeName string, vgs ...localv1alpha1.VolumeGroup) *localv1alpha1.NodeLocalStorage {
+	return &localv1alpha1.NodeLocalStorage{
+		ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+		Status: localv1alpha1.NodeLocalStorageStatus{
+			NodeStorageInfo: localv1alpha1.NodeStorageInfo{VolumeGroups: vgs},
+		},
+	}
+}
+
+func TestListVolumes(t *testing.T) {
+	lvm := func(vgName string) map[string]string {
+		return map[string]string{VolumeTypeTag: LvmVolumeType, VgNameTag: vgName}
+	}
+	pvName1, pvName2 := "pv-1", "pv-2"
+	pvs := []runtime.Object{
+		newTestPV("pv-1", "node-1", lvm("share")),
+		newTestPV("pv-2", "node-1", lvm("share")),
+		newTestPV("pv-3", "node-2", lvm("share")),
+		newTestPV("pv-4", "node-2", map[string]string{VolumeTypeTag: DeviceVolumeType, DeviceVolumeType: "/dev/vdb"}),
+		newTestPV("snap-pv-1", "node-1", map[string]string{
+			VolumeTypeTag:                   LvmVolumeType,
+			VgNameTag:                       "share",
+			localtype.ParamSnapshotName:     "snap-1",
+			localtype.ParamSnapshotReadonly: "true",
+		}),
+		// lv is not reported yet
+		newTestPV("pv-5", "node-2", lvm("share")),
+		// nls is not reported yet
+		newTestPV("pv-6", "node-3", map[string]string{VolumeTypeTag: MountPointType, MountPointType: "/mnt/disk1"}),
+		&storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: "va-1"},
+			Spec: storagev1.VolumeAttachmentSpec{
+				Attacher: localtype.ProvisionerName,
+				NodeName: "node-1",
+				Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &pvName1},
+			},
+			Status: storagev1.VolumeAttachmentStatus{Attached: true},
+		},
+		&storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: "va-2"},
+			Spec: storagev1.VolumeAttachmentSpec{
+				Attacher: localtype.ProvisionerName,
+				NodeName: "node-1",
+				Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &pvName2},
+			},
+		},
+	}
+	nlses := []runtime.Object{
+		newTestNLS("node-2", localv1alpha1.VolumeGroup{Name: "share", LogicalVolumes: []localv1alpha1.LogicalVolume{
+			{Name: "pv-3", VGName: "share", Total: 2147483648, Condition: localv1alpha1.StorageFault, Message: "logical volume failed"},
+		}}),
+		newTestNLS("node-1", localv1alpha1.VolumeGroup{Name: "share", LogicalVolumes: []localv1alpha1.LogicalVolume{
+			{Name: "snap-1", VGName: "share", Total: 1073741824, Condition: localv1alpha1.StorageReady},
+			{Name: "pv-2", VGName: "share", Total: 1073741824, Condition: localv1alpha1.StorageReady},
+			{Name: "pv-1", VGName: "share", Total: 1073741824, Condition: localv1alpha1.StorageReady},
+			// not an open-local volume
+			{Name: "data", VGName: "share", Total: 1073741824, Condition: localv1alpha1.StorageReady},
+		}}),
+	}
+	cs := &controllerServer{client: fake.NewSimpleClientset(pvs...), localclient: localfake.NewSimpleClientset(nlses...)}
+	ctx := context.Background()
+
+	var volumeIDs []string
+	token := ""
+	for pages := 0; ; pages++ {
+		rsp, err := cs.ListVolumes(ctx, &csi.ListVolumesRequest{MaxEntries: 2, StartingToken: token})
+		if err != nil {
+			t.Fatalf("ListVolumes() error: %s", err.Error())
+		}
+		if len(rsp.Entries) > 2 {
+			t.Fatalf("ListVolumes() returns %d entries, expected at most 2", len(rsp.Entries))
+		}
+		for _, entry := range rsp.Entries {
+			volumeIDs = append(volumeIDs, entry.Volume.VolumeId)
+			// only pv-1 is attached according to volumeattachments
+			if published := entry.Status.PublishedNodeIds; entry.Volume.VolumeId == "pv-1" && (len(published) != 1 || published[0] != "node-1") ||
+				entry.Volume.VolumeId != "pv-1" && len(published) != 0 {
+				t.Errorf("volume %s is published to %v", entry.Volume.VolumeId, published)
+			}
+			if abnormal := entry.Volume.VolumeId == "pv-3" || entry.Volume.VolumeId == "pv-6"; entry.Status.VolumeCondition.Abnormal != abnormal {
+				t.Errorf("condition of volume %s is %+v", entry.Volume.VolumeId, entry.Status.VolumeCondition)
+			}
+		}
+		if token = rsp.NextToken; token == "" {
+			break
+		}
+		if pages > 4 {
+			t.Fatal("ListVolumes() does not stop paging")
+		}
+	}
+	expected := []string{"pv-1", "pv-2", "snap-pv-1", "pv-3", "pv-4", "pv-6"}
+	if len(volumeIDs) != len(expected) {
+		t.Fatalf("ListVolumes() = %v, expected %v", volumeIDs, expected)
+	}
+	for i := range expected {
+		if volumeIDs[i] != expected[i] {
+			t.Fatalf("ListVolumes() = %v, expected %v", volumeIDs, expected)
+		}
+	}
+
+	if _, err := cs.ListVolumes(ctx, &csi.ListVolumesRequest{StartingToken: "invalid"}); status.Code(err) != codes.Aborted {
+		t.Errorf("ListVolumes() with invalid token returns %v, expected Aborted", err)
+	}
+	if _, err := cs.ListVolumes(ctx, &csi.ListVolumesRequest{StartingToken: "7"}); status.Code(err) != codes.Aborted {
+		t.Errorf("ListVolumes() with out of range token returns %v, expected Aborted", err)
+	}
+
+	rsp, err := cs.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{VolumeId: "pv-3"})
+	if err != nil {
+		t.Fatalf("ControllerGetVolume() error: %s", err.Error())
+	}
+	if rsp.Volume.CapacityBytes != 2147483648 || !rsp.Status.VolumeCondition.Abnormal || len(rsp.Status.PublishedNodeIds) != 0 {
+		t.Errorf("ControllerGetVolume() = %+v", rsp)
+	}
+	if _, err := cs.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{VolumeId: "pv-9"}); status.Code(err) != codes.NotFound {
+		t.Errorf("ControllerGetVolume() of unknown volume returns %v, expected NotFound", err)
+	}
+}
+
+func TestGetCapacity(t *testing.T) {
+	gi := func(n int64) uint64 {
+		return uint64(n) * localtype.Gi
+	}
+	node1 := newTestNLS("node-1",
+		localv1alpha1.VolumeGroup{Name: "share", Allocatable: gi(100)},
+		localv1alpha1.VolumeGroup{Name: "thin", Allocatable: gi(60), ThinPool: &localv1alpha1.ThinPool{Name: "pool", Total: gi(50)}},
+		localv1alpha1.VolumeGroup{Name: "unfiltered", Allocatable: gi(500)},
+	)
+	node1.Status.FilteredStorageInfo = localv1alpha1.FilteredStorageInfo{
+		VolumeGroups: []string{"share", "thin"},
+		Devices:      []string{"/dev/vdb", "/dev/vdc"},
+		MountPoints:  []string{"/mnt/disk-1", "/mnt/disk-2"},
+	}
+	node1.Status.NodeStorageInfo.DeviceInfos = []localv1alpha1.DeviceInfo{
+		{Name: "/dev/vdb", MediaType: "ssd", Total: gi(200)},
+		{Name: "/dev/vdc", MediaType: "hdd", Total: gi(300)},
+		{Name: "/dev/vdd", MediaType: "ssd", Total: gi(10)},
+		{Name: "/dev/vde", MediaType: "hdd", Total: gi(20)},
+	}
+	node1.Status.NodeStorageInfo.MountPoints = []localv1alpha1.MountPoint{
+		{Name: "/mnt/disk-1", Device: "/dev/vdd", Total: gi(10)},
+		{Name: "/mnt/disk-2", Device: "/dev/vde", Total: gi(20)},
+	}
+	node2 := newTestNLS("node-2", localv1alpha1.VolumeGroup{Name: "share", Allocatable: gi(10)})
+	node2.Status.FilteredStorageInfo.VolumeGroups = []string{"share"}
+
+	pvs := []runtime.Object{
+		newTestPV("pv-1", "node-1", map[string]string{VolumeTypeTag: LvmVolumeType, VgNameTag: "share"}),
+		newTestPV("pv-2", "node-1", map[string]string{VolumeTypeTag: DeviceVolumeType, DeviceVolumeType: "/dev/vdc"}),
+		newTestPV("pv-3", "node-1", map[string]string{VolumeTypeTag: QuotaVolumeType, MountPointType: "/mnt/disk-2"}),
+		newTestPV("pv-4", "node-2", map[string]string{VolumeTypeTag: LvmVolumeType, VgNameTag: "share"}),
+	}
+	cs := &controllerServer{client: fake.NewSimpleClientset(pvs...), localclient: localfake.NewSimpleClientset(node1, node2)}
+
+	topology := nodeTopology(node1)
+	for _, key := range []string{TopologyVGKeyPrefix + "share", TopologyVGKeyPrefix + "thin", TopologyMediaTypeKeyPrefix + "ssd", TopologyMediaTypeKeyPrefix + "hdd"} {
+		if topology[key] != "true" {
+			t.Errorf("nodeTopology() = %v, expected %s", topology, key)
+		}
+	}
+	if _, exist := topology[TopologyVGKeyPrefix+"unfiltered"]; exist {
+		t.Errorf("nodeTopology() = %v, expected no unfiltered vg", topology)
+	}
+
+	tests := []struct {
+		name     string
+		segments map[string]string
+		params   map[string]string
+		expected uint64
+	}{
+		{
+			name:     "lvm",
+			segments: map[string]string{TopologyNodeKey: "node-1"},
+			params:   map[string]string{VolumeTypeKey: LvmVolumeType},
+			expected: gi(99),
+		},
+		{
+			name:     "thin lvm",
+			segments: map[string]string{TopologyNodeKey: "node-1"},
+			params:   map[string]string{VolumeTypeKey: LvmVolumeType, VgNameTag: "thin", LvmTypeTag: ThinType},
+			expected: gi(50),
+		},
+		{
+			name:     "ssd device",
+			segments: map[string]string{TopologyNodeKey: "node-1"},
+			params:   map[string]string{VolumeTypeKey: DeviceVolumeType, localtype.VolumeMediaType: "ssd"},
+			expected: gi(200),
+		},
+		{
+			name:     "hdd device is allocated",
+			segments: map[string]string{TopologyNodeKey: "node-1"},
+			params:   map[string]string{VolumeTypeKey: DeviceVolumeType, localtype.VolumeMediaType: "hdd"},
+		},
+		{
+			name:     "mount point used by quota volumes",
+			segments: map[string]string{TopologyNodeKey: "node-1"},
+			params:   map[string]string{VolumeTypeKey: MountPointType},
+			expected: gi(10),
+		},
+		{
+			name:     "quota",
+			segments: map[string]string{TopologyNodeKey: "node-1"},
+			params:   map[string]string{VolumeTypeKey: QuotaVolumeType},
+			expected: gi(19),
+		},
+		{
+			name:     "segment of vg",
+			segments: map[string]string{TopologyVGKeyPrefix + "share": "true"},
+			params:   map[string]string{VolumeTypeKey: LvmVolumeType, VgNameTag: "share"},
+			expected: gi(99),
+		},
+		{
+			name:     "segment of node and media type",
+			segments: map[string]string{TopologyNodeKey: "node-1", TopologyMediaTypeKeyPrefix + "ssd": "true"},
+			params:   map[string]string{VolumeTypeKey: DeviceVolumeType},
+			expected: gi(200),
+		},
+		{
+			name:     "segment without the node",
+			segments: map[string]string{TopologyNodeKey: "node-2", TopologyMediaTypeKeyPrefix + "ssd": "true"},
+			params:   map[string]string{VolumeTypeKey: LvmVolumeType},
+		},
+		{
+			name:     "node without nls",
+			segments: map[string]string{TopologyNodeKey: "node-3"},
+			params:   map[string]string{VolumeTypeKey: LvmVolumeType},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rsp, err := cs.GetCapacity(context.Background(), &csi.GetCapacityRequest{
+				Parameters:         test.params,
+				AccessibleTopology: &csi.Topology{Segments: test.segments},
+			})
+			if err != nil {
+				t.Fatalf("GetCapacity() error: %s", err.Error())
+			}
+			if rsp.AvailableCapacity != int64(test.expected) {
+				t.Errorf("GetCapacity() = %d, expected %d", rsp.AvailableCapacity, test.expected)
+			}
+		})
+	}
+
+	if _, err := cs.GetCapacity(context.Background(), &csi.GetCapacityRequest{Parameters: map[string]string{VolumeTypeKey: "NFS"}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetCapacity() of unsupported volume type returns %v, expected InvalidArgument", err)
+	}
+}
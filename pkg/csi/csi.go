@@ -33,6 +33,12 @@ func NewDriver(driverName, nodeID, endpoint, sysPath string, grpcConnectionTimeo
 		csilib.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
 		csilib.ControllerServiceCapability_RPC_EXPAND_VOLUME,
 		csilib.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+		csilib.ControllerServiceCapability_RPC_CLONE_VOLUME,
+		csilib.ControllerServiceCapability_RPC_LIST_VOLUMES,
+		csilib.ControllerServiceCapability_RPC_LIST_VOLUMES_PUBLISHED_NODES,
+		csilib.ControllerServiceCapability_RPC_GET_VOLUME,
+		csilib.ControllerServiceCapability_RPC_GET_CAPACITY,
+		csilib.ControllerServiceCapability_RPC_VOLUME_CONDITION,
 	})
 	plugin.driver.AddVolumeCapabilityAccessModes([]csilib.VolumeCapability_AccessMode_Mode{csilib.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER})
 
@@ -723,6 +723,7 @@ type CreateLVRequest struct {
 	Mirrors     uint32   `protobuf:"varint,4,opt,name=mirrors,proto3" json:"mirrors,omitempty"`
 	Tags        []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
 	Striping    bool     `protobuf:"varint,6,opt,name=striping,proto3" json:"striping,omitempty"`
+	Thin        bool     `protobuf:"varint,7,opt,name=thin,proto3" json:"thin,omitempty"`
 }
 
 func (x *CreateLVRequest) Reset() {
@@ -799,6 +800,13 @@ func (x *CreateLVRequest) GetStriping() bool {
 	return false
 }
 
+func (x *CreateLVRequest) GetThin() bool {
+	if x != nil {
+		return x.Thin
+	}
+	return false
+}
+
 type CreateLVReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -901,31 +909,569 @@ func (x *RemoveLVRequest) GetName() string {
 	return ""
 }
 
-type RemoveLVReply struct {
+type RemoveLVReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CommandOutput string `protobuf:"bytes,1,opt,name=command_output,json=commandOutput,proto3" json:"command_output,omitempty"`
+}
+
+func (x *RemoveLVReply) Reset() {
+	*x = RemoveLVReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvm_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoveLVReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveLVReply) ProtoMessage() {}
+
+func (x *RemoveLVReply) ProtoReflect() protoreflect.Message {
+	mi := &file_lvm_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveLVReply.ProtoReflect.Descriptor instead.
+func (*RemoveLVReply) Descriptor() ([]byte, []int) {
+	return file_lvm_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RemoveLVReply) GetCommandOutput() string {
+	if x != nil {
+		return x.CommandOutput
+	}
+	return ""
+}
+
+type CloneLVRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceName  string `protobuf:"bytes,1,opt,name=source_name,json=sourceName,proto3" json:"source_name,omitempty"`
+	DestName    string `protobuf:"bytes,2,opt,name=dest_name,json=destName,proto3" json:"dest_name,omitempty"`
+	VolumeGroup string `protobuf:"bytes,3,opt,name=volume_group,json=volumeGroup,proto3" json:"volume_group,omitempty"`
+	Size        uint64 `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+}
+
+func (x *CloneLVRequest) Reset() {
+	*x = CloneLVRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvm_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CloneLVRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloneLVRequest) ProtoMessage() {}
+
+func (x *CloneLVRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lvm_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloneLVRequest.ProtoReflect.Descriptor instead.
+func (*CloneLVRequest) Descriptor() ([]byte, []int) {
+	return file_lvm_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CloneLVRequest) GetSourceName() string {
+	if x != nil {
+		return x.SourceName
+	}
+	return ""
+}
+
+func (x *CloneLVRequest) GetDestName() string {
+	if x != nil {
+		return x.DestName
+	}
+	return ""
+}
+
+func (x *CloneLVRequest) GetVolumeGroup() string {
+	if x != nil {
+		return x.VolumeGroup
+	}
+	return ""
+}
+
+func (x *CloneLVRequest) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type CloneLVReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CommandOutput string `protobuf:"bytes,1,opt,name=command_output,json=commandOutput,proto3" json:"command_output,omitempty"`
+}
+
+func (x *CloneLVReply) Reset() {
+	*x = CloneLVReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvm_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CloneLVReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloneLVReply) ProtoMessage() {}
+
+func (x *CloneLVReply) ProtoReflect() protoreflect.Message {
+	mi := &file_lvm_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloneLVReply.ProtoReflect.Descriptor instead.
+func (*CloneLVReply) Descriptor() ([]byte, []int) {
+	return file_lvm_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CloneLVReply) GetCommandOutput() string {
+	if x != nil {
+		return x.CommandOutput
+	}
+	return ""
+}
+
+type GetCloneLVProgressRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	VolumeGroup string `protobuf:"bytes,1,opt,name=volume_group,json=volumeGroup,proto3" json:"volume_group,omitempty"`
+	DestName    string `protobuf:"bytes,2,opt,name=dest_name,json=destName,proto3" json:"dest_name,omitempty"`
+}
+
+func (x *GetCloneLVProgressRequest) Reset() {
+	*x = GetCloneLVProgressRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvm_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCloneLVProgressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCloneLVProgressRequest) ProtoMessage() {}
+
+func (x *GetCloneLVProgressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lvm_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCloneLVProgressRequest.ProtoReflect.Descriptor instead.
+func (*GetCloneLVProgressRequest) Descriptor() ([]byte, []int) {
+	return file_lvm_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetCloneLVProgressRequest) GetVolumeGroup() string {
+	if x != nil {
+		return x.VolumeGroup
+	}
+	return ""
+}
+
+func (x *GetCloneLVProgressRequest) GetDestName() string {
+	if x != nil {
+		return x.DestName
+	}
+	return ""
+}
+
+type GetCloneLVProgressReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CopiedBytes uint64 `protobuf:"varint,1,opt,name=copied_bytes,json=copiedBytes,proto3" json:"copied_bytes,omitempty"`
+	TotalBytes  uint64 `protobuf:"varint,2,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	Done        bool   `protobuf:"varint,3,opt,name=done,proto3" json:"done,omitempty"`
+	Error       string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *GetCloneLVProgressReply) Reset() {
+	*x = GetCloneLVProgressReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvm_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCloneLVProgressReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCloneLVProgressReply) ProtoMessage() {}
+
+func (x *GetCloneLVProgressReply) ProtoReflect() protoreflect.Message {
+	mi := &file_lvm_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCloneLVProgressReply.ProtoReflect.Descriptor instead.
+func (*GetCloneLVProgressReply) Descriptor() ([]byte, []int) {
+	return file_lvm_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetCloneLVProgressReply) GetCopiedBytes() uint64 {
+	if x != nil {
+		return x.CopiedBytes
+	}
+	return 0
+}
+
+func (x *GetCloneLVProgressReply) GetTotalBytes() uint64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+func (x *GetCloneLVProgressReply) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *GetCloneLVProgressReply) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type CancelCloneLVRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	VolumeGroup string `protobuf:"bytes,1,opt,name=volume_group,json=volumeGroup,proto3" json:"volume_group,omitempty"`
+	DestName    string `protobuf:"bytes,2,opt,name=dest_name,json=destName,proto3" json:"dest_name,omitempty"`
+}
+
+func (x *CancelCloneLVRequest) Reset() {
+	*x = CancelCloneLVRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvm_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelCloneLVRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelCloneLVRequest) ProtoMessage() {}
+
+func (x *CancelCloneLVRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lvm_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelCloneLVRequest.ProtoReflect.Descriptor instead.
+func (*CancelCloneLVRequest) Descriptor() ([]byte, []int) {
+	return file_lvm_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CancelCloneLVRequest) GetVolumeGroup() string {
+	if x != nil {
+		return x.VolumeGroup
+	}
+	return ""
+}
+
+func (x *CancelCloneLVRequest) GetDestName() string {
+	if x != nil {
+		return x.DestName
+	}
+	return ""
+}
+
+type CancelCloneLVReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CommandOutput string `protobuf:"bytes,1,opt,name=command_output,json=commandOutput,proto3" json:"command_output,omitempty"`
+}
+
+func (x *CancelCloneLVReply) Reset() {
+	*x = CancelCloneLVReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvm_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelCloneLVReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelCloneLVReply) ProtoMessage() {}
+
+func (x *CancelCloneLVReply) ProtoReflect() protoreflect.Message {
+	mi := &file_lvm_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelCloneLVReply.ProtoReflect.Descriptor instead.
+func (*CancelCloneLVReply) Descriptor() ([]byte, []int) {
+	return file_lvm_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CancelCloneLVReply) GetCommandOutput() string {
+	if x != nil {
+		return x.CommandOutput
+	}
+	return ""
+}
+
+type BackupStore struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Endpoint        string `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	Region          string `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
+	Bucket          string `protobuf:"bytes,3,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	AccessKeyId     string `protobuf:"bytes,4,opt,name=access_key_id,json=accessKeyId,proto3" json:"access_key_id,omitempty"`
+	SecretAccessKey string `protobuf:"bytes,5,opt,name=secret_access_key,json=secretAccessKey,proto3" json:"secret_access_key,omitempty"`
+}
+
+func (x *BackupStore) Reset() {
+	*x = BackupStore{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvm_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BackupStore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupStore) ProtoMessage() {}
+
+func (x *BackupStore) ProtoReflect() protoreflect.Message {
+	mi := &file_lvm_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupStore.ProtoReflect.Descriptor instead.
+func (*BackupStore) Descriptor() ([]byte, []int) {
+	return file_lvm_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BackupStore) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *BackupStore) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *BackupStore) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *BackupStore) GetAccessKeyId() string {
+	if x != nil {
+		return x.AccessKeyId
+	}
+	return ""
+}
+
+func (x *BackupStore) GetSecretAccessKey() string {
+	if x != nil {
+		return x.SecretAccessKey
+	}
+	return ""
+}
+
+type BackupSnapshotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	VolumeGroup  string       `protobuf:"bytes,1,opt,name=volume_group,json=volumeGroup,proto3" json:"volume_group,omitempty"`
+	SnapshotName string       `protobuf:"bytes,2,opt,name=snapshot_name,json=snapshotName,proto3" json:"snapshot_name,omitempty"`
+	Store        *BackupStore `protobuf:"bytes,3,opt,name=store,proto3" json:"store,omitempty"`
+}
+
+func (x *BackupSnapshotRequest) Reset() {
+	*x = BackupSnapshotRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvm_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BackupSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupSnapshotRequest) ProtoMessage() {}
+
+func (x *BackupSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lvm_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*BackupSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_lvm_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BackupSnapshotRequest) GetVolumeGroup() string {
+	if x != nil {
+		return x.VolumeGroup
+	}
+	return ""
+}
+
+func (x *BackupSnapshotRequest) GetSnapshotName() string {
+	if x != nil {
+		return x.SnapshotName
+	}
+	return ""
+}
+
+func (x *BackupSnapshotRequest) GetStore() *BackupStore {
+	if x != nil {
+		return x.Store
+	}
+	return nil
+}
+
+type BackupSnapshotReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	CommandOutput string `protobuf:"bytes,1,opt,name=command_output,json=commandOutput,proto3" json:"command_output,omitempty"`
+	CopiedBytes uint64 `protobuf:"varint,1,opt,name=copied_bytes,json=copiedBytes,proto3" json:"copied_bytes,omitempty"`
+	TotalBytes  uint64 `protobuf:"varint,2,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	Done        bool   `protobuf:"varint,3,opt,name=done,proto3" json:"done,omitempty"`
+	Error       string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
 }
 
-func (x *RemoveLVReply) Reset() {
-	*x = RemoveLVReply{}
+func (x *BackupSnapshotReply) Reset() {
+	*x = BackupSnapshotReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[7]
+		mi := &file_lvm_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RemoveLVReply) String() string {
+func (x *BackupSnapshotReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoveLVReply) ProtoMessage() {}
+func (*BackupSnapshotReply) ProtoMessage() {}
 
-func (x *RemoveLVReply) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[7]
+func (x *BackupSnapshotReply) ProtoReflect() protoreflect.Message {
+	mi := &file_lvm_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -936,44 +1482,69 @@ func (x *RemoveLVReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemoveLVReply.ProtoReflect.Descriptor instead.
-func (*RemoveLVReply) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use BackupSnapshotReply.ProtoReflect.Descriptor instead.
+func (*BackupSnapshotReply) Descriptor() ([]byte, []int) {
+	return file_lvm_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *RemoveLVReply) GetCommandOutput() string {
+func (x *BackupSnapshotReply) GetCopiedBytes() uint64 {
 	if x != nil {
-		return x.CommandOutput
+		return x.CopiedBytes
+	}
+	return 0
+}
+
+func (x *BackupSnapshotReply) GetTotalBytes() uint64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+func (x *BackupSnapshotReply) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *BackupSnapshotReply) GetError() string {
+	if x != nil {
+		return x.Error
 	}
 	return ""
 }
 
-type CloneLVRequest struct {
+type RestoreLVRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SourceName string `protobuf:"bytes,1,opt,name=source_name,json=sourceName,proto3" json:"source_name,omitempty"`
-	DestName   string `protobuf:"bytes,2,opt,name=dest_name,json=destName,proto3" json:"dest_name,omitempty"`
+	VolumeGroup string       `protobuf:"bytes,1,opt,name=volume_group,json=volumeGroup,proto3" json:"volume_group,omitempty"`
+	Name        string       `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Size        uint64       `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	BackupName  string       `protobuf:"bytes,4,opt,name=backup_name,json=backupName,proto3" json:"backup_name,omitempty"`
+	Store       *BackupStore `protobuf:"bytes,5,opt,name=store,proto3" json:"store,omitempty"`
+	Thin        bool         `protobuf:"varint,6,opt,name=thin,proto3" json:"thin,omitempty"`
 }
 
-func (x *CloneLVRequest) Reset() {
-	*x = CloneLVRequest{}
+func (x *RestoreLVRequest) Reset() {
+	*x = RestoreLVRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[8]
+		mi := &file_lvm_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CloneLVRequest) String() string {
+func (x *RestoreLVRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CloneLVRequest) ProtoMessage() {}
+func (*RestoreLVRequest) ProtoMessage() {}
 
-func (x *CloneLVRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[8]
+func (x *RestoreLVRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lvm_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -984,26 +1555,54 @@ func (x *CloneLVRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CloneLVRequest.ProtoReflect.Descriptor instead.
-func (*CloneLVRequest) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use RestoreLVRequest.ProtoReflect.Descriptor instead.
+func (*RestoreLVRequest) Descriptor() ([]byte, []int) {
+	return file_lvm_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *CloneLVRequest) GetSourceName() string {
+func (x *RestoreLVRequest) GetVolumeGroup() string {
 	if x != nil {
-		return x.SourceName
+		return x.VolumeGroup
 	}
 	return ""
 }
 
-func (x *CloneLVRequest) GetDestName() string {
+func (x *RestoreLVRequest) GetName() string {
 	if x != nil {
-		return x.DestName
+		return x.Name
 	}
 	return ""
 }
 
-type CloneLVReply struct {
+func (x *RestoreLVRequest) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *RestoreLVRequest) GetBackupName() string {
+	if x != nil {
+		return x.BackupName
+	}
+	return ""
+}
+
+func (x *RestoreLVRequest) GetStore() *BackupStore {
+	if x != nil {
+		return x.Store
+	}
+	return nil
+}
+
+func (x *RestoreLVRequest) GetThin() bool {
+	if x != nil {
+		return x.Thin
+	}
+	return false
+}
+
+type RestoreLVReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -1011,23 +1610,23 @@ type CloneLVReply struct {
 	CommandOutput string `protobuf:"bytes,1,opt,name=command_output,json=commandOutput,proto3" json:"command_output,omitempty"`
 }
 
-func (x *CloneLVReply) Reset() {
-	*x = CloneLVReply{}
+func (x *RestoreLVReply) Reset() {
+	*x = RestoreLVReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[9]
+		mi := &file_lvm_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CloneLVReply) String() string {
+func (x *RestoreLVReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CloneLVReply) ProtoMessage() {}
+func (*RestoreLVReply) ProtoMessage() {}
 
-func (x *CloneLVReply) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[9]
+func (x *RestoreLVReply) ProtoReflect() protoreflect.Message {
+	mi := &file_lvm_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1038,12 +1637,12 @@ func (x *CloneLVReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CloneLVReply.ProtoReflect.Descriptor instead.
-func (*CloneLVReply) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use RestoreLVReply.ProtoReflect.Descriptor instead.
+func (*RestoreLVReply) Descriptor() ([]byte, []int) {
+	return file_lvm_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *CloneLVReply) GetCommandOutput() string {
+func (x *RestoreLVReply) GetCommandOutput() string {
 	if x != nil {
 		return x.CommandOutput
 	}
@@ -1063,7 +1662,7 @@ type ExpandLVRequest struct {
 func (x *ExpandLVRequest) Reset() {
 	*x = ExpandLVRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[10]
+		mi := &file_lvm_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1076,7 +1675,7 @@ func (x *ExpandLVRequest) String() string {
 func (*ExpandLVRequest) ProtoMessage() {}
 
 func (x *ExpandLVRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[10]
+	mi := &file_lvm_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1089,7 +1688,7 @@ func (x *ExpandLVRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExpandLVRequest.ProtoReflect.Descriptor instead.
 func (*ExpandLVRequest) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{10}
+	return file_lvm_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *ExpandLVRequest) GetVolumeGroup() string {
@@ -1124,7 +1723,7 @@ type ExpandLVReply struct {
 func (x *ExpandLVReply) Reset() {
 	*x = ExpandLVReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[11]
+		mi := &file_lvm_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1137,7 +1736,7 @@ func (x *ExpandLVReply) String() string {
 func (*ExpandLVReply) ProtoMessage() {}
 
 func (x *ExpandLVReply) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[11]
+	mi := &file_lvm_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1150,7 +1749,7 @@ func (x *ExpandLVReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExpandLVReply.ProtoReflect.Descriptor instead.
 func (*ExpandLVReply) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{11}
+	return file_lvm_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *ExpandLVReply) GetCommandOutput() string {
@@ -1174,7 +1773,7 @@ type CreateSnapshotRequest struct {
 func (x *CreateSnapshotRequest) Reset() {
 	*x = CreateSnapshotRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[12]
+		mi := &file_lvm_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1187,7 +1786,7 @@ func (x *CreateSnapshotRequest) String() string {
 func (*CreateSnapshotRequest) ProtoMessage() {}
 
 func (x *CreateSnapshotRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[12]
+	mi := &file_lvm_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1200,7 +1799,7 @@ func (x *CreateSnapshotRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateSnapshotRequest.ProtoReflect.Descriptor instead.
 func (*CreateSnapshotRequest) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{12}
+	return file_lvm_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *CreateSnapshotRequest) GetVolumeGroup() string {
@@ -1242,7 +1841,7 @@ type CreateSnapshotReply struct {
 func (x *CreateSnapshotReply) Reset() {
 	*x = CreateSnapshotReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[13]
+		mi := &file_lvm_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1255,7 +1854,7 @@ func (x *CreateSnapshotReply) String() string {
 func (*CreateSnapshotReply) ProtoMessage() {}
 
 func (x *CreateSnapshotReply) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[13]
+	mi := &file_lvm_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1268,7 +1867,7 @@ func (x *CreateSnapshotReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateSnapshotReply.ProtoReflect.Descriptor instead.
 func (*CreateSnapshotReply) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{13}
+	return file_lvm_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *CreateSnapshotReply) GetCommandOutput() string {
@@ -1290,7 +1889,7 @@ type RemoveSnapshotRequest struct {
 func (x *RemoveSnapshotRequest) Reset() {
 	*x = RemoveSnapshotRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[14]
+		mi := &file_lvm_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1303,7 +1902,7 @@ func (x *RemoveSnapshotRequest) String() string {
 func (*RemoveSnapshotRequest) ProtoMessage() {}
 
 func (x *RemoveSnapshotRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[14]
+	mi := &file_lvm_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1316,7 +1915,7 @@ func (x *RemoveSnapshotRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveSnapshotRequest.ProtoReflect.Descriptor instead.
 func (*RemoveSnapshotRequest) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{14}
+	return file_lvm_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *RemoveSnapshotRequest) GetVolumeGroup() string {
@@ -1344,7 +1943,7 @@ type RemoveSnapshotReply struct {
 func (x *RemoveSnapshotReply) Reset() {
 	*x = RemoveSnapshotReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[15]
+		mi := &file_lvm_proto_msgTypes[24]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1357,7 +1956,7 @@ func (x *RemoveSnapshotReply) String() string {
 func (*RemoveSnapshotReply) ProtoMessage() {}
 
 func (x *RemoveSnapshotReply) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[15]
+	mi := &file_lvm_proto_msgTypes[24]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1370,7 +1969,7 @@ func (x *RemoveSnapshotReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveSnapshotReply.ProtoReflect.Descriptor instead.
 func (*RemoveSnapshotReply) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{15}
+	return file_lvm_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *RemoveSnapshotReply) GetCommandOutput() string {
@@ -1389,7 +1988,7 @@ type ListVGRequest struct {
 func (x *ListVGRequest) Reset() {
 	*x = ListVGRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[16]
+		mi := &file_lvm_proto_msgTypes[25]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1402,7 +2001,7 @@ func (x *ListVGRequest) String() string {
 func (*ListVGRequest) ProtoMessage() {}
 
 func (x *ListVGRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[16]
+	mi := &file_lvm_proto_msgTypes[25]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1415,7 +2014,7 @@ func (x *ListVGRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListVGRequest.ProtoReflect.Descriptor instead.
 func (*ListVGRequest) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{16}
+	return file_lvm_proto_rawDescGZIP(), []int{25}
 }
 
 type ListVGReply struct {
@@ -1429,7 +2028,7 @@ type ListVGReply struct {
 func (x *ListVGReply) Reset() {
 	*x = ListVGReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[17]
+		mi := &file_lvm_proto_msgTypes[26]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1442,7 +2041,7 @@ func (x *ListVGReply) String() string {
 func (*ListVGReply) ProtoMessage() {}
 
 func (x *ListVGReply) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[17]
+	mi := &file_lvm_proto_msgTypes[26]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1455,7 +2054,7 @@ func (x *ListVGReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListVGReply.ProtoReflect.Descriptor instead.
 func (*ListVGReply) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{17}
+	return file_lvm_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *ListVGReply) GetVolumeGroups() []*VolumeGroup {
@@ -1478,7 +2077,7 @@ type CreateVGRequest struct {
 func (x *CreateVGRequest) Reset() {
 	*x = CreateVGRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[18]
+		mi := &file_lvm_proto_msgTypes[27]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1491,7 +2090,7 @@ func (x *CreateVGRequest) String() string {
 func (*CreateVGRequest) ProtoMessage() {}
 
 func (x *CreateVGRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[18]
+	mi := &file_lvm_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1504,7 +2103,7 @@ func (x *CreateVGRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateVGRequest.ProtoReflect.Descriptor instead.
 func (*CreateVGRequest) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{18}
+	return file_lvm_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *CreateVGRequest) GetName() string {
@@ -1539,7 +2138,7 @@ type CreateVGReply struct {
 func (x *CreateVGReply) Reset() {
 	*x = CreateVGReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[19]
+		mi := &file_lvm_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1552,7 +2151,7 @@ func (x *CreateVGReply) String() string {
 func (*CreateVGReply) ProtoMessage() {}
 
 func (x *CreateVGReply) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[19]
+	mi := &file_lvm_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1565,7 +2164,7 @@ func (x *CreateVGReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateVGReply.ProtoReflect.Descriptor instead.
 func (*CreateVGReply) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{19}
+	return file_lvm_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *CreateVGReply) GetCommandOutput() string {
@@ -1586,7 +2185,7 @@ type RemoveVGRequest struct {
 func (x *RemoveVGRequest) Reset() {
 	*x = RemoveVGRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[20]
+		mi := &file_lvm_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1599,7 +2198,7 @@ func (x *RemoveVGRequest) String() string {
 func (*RemoveVGRequest) ProtoMessage() {}
 
 func (x *RemoveVGRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[20]
+	mi := &file_lvm_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1612,7 +2211,7 @@ func (x *RemoveVGRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveVGRequest.ProtoReflect.Descriptor instead.
 func (*RemoveVGRequest) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{20}
+	return file_lvm_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *RemoveVGRequest) GetName() string {
@@ -1633,7 +2232,7 @@ type RemoveVGReply struct {
 func (x *RemoveVGReply) Reset() {
 	*x = RemoveVGReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[21]
+		mi := &file_lvm_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1646,7 +2245,7 @@ func (x *RemoveVGReply) String() string {
 func (*RemoveVGReply) ProtoMessage() {}
 
 func (x *RemoveVGReply) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[21]
+	mi := &file_lvm_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1659,7 +2258,7 @@ func (x *RemoveVGReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveVGReply.ProtoReflect.Descriptor instead.
 func (*RemoveVGReply) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{21}
+	return file_lvm_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *RemoveVGReply) GetCommandOutput() string {
@@ -1682,7 +2281,7 @@ type AddTagLVRequest struct {
 func (x *AddTagLVRequest) Reset() {
 	*x = AddTagLVRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[22]
+		mi := &file_lvm_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1695,7 +2294,7 @@ func (x *AddTagLVRequest) String() string {
 func (*AddTagLVRequest) ProtoMessage() {}
 
 func (x *AddTagLVRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[22]
+	mi := &file_lvm_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1708,7 +2307,7 @@ func (x *AddTagLVRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddTagLVRequest.ProtoReflect.Descriptor instead.
 func (*AddTagLVRequest) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{22}
+	return file_lvm_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *AddTagLVRequest) GetVolumeGroup() string {
@@ -1743,7 +2342,7 @@ type AddTagLVReply struct {
 func (x *AddTagLVReply) Reset() {
 	*x = AddTagLVReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[23]
+		mi := &file_lvm_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1756,7 +2355,7 @@ func (x *AddTagLVReply) String() string {
 func (*AddTagLVReply) ProtoMessage() {}
 
 func (x *AddTagLVReply) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[23]
+	mi := &file_lvm_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1769,7 +2368,7 @@ func (x *AddTagLVReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddTagLVReply.ProtoReflect.Descriptor instead.
 func (*AddTagLVReply) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{23}
+	return file_lvm_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *AddTagLVReply) GetCommandOutput() string {
@@ -1792,7 +2391,7 @@ type RemoveTagLVRequest struct {
 func (x *RemoveTagLVRequest) Reset() {
 	*x = RemoveTagLVRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[24]
+		mi := &file_lvm_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1805,7 +2404,7 @@ func (x *RemoveTagLVRequest) String() string {
 func (*RemoveTagLVRequest) ProtoMessage() {}
 
 func (x *RemoveTagLVRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[24]
+	mi := &file_lvm_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1818,7 +2417,7 @@ func (x *RemoveTagLVRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveTagLVRequest.ProtoReflect.Descriptor instead.
 func (*RemoveTagLVRequest) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{24}
+	return file_lvm_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *RemoveTagLVRequest) GetVolumeGroup() string {
@@ -1853,7 +2452,7 @@ type RemoveTagLVReply struct {
 func (x *RemoveTagLVReply) Reset() {
 	*x = RemoveTagLVReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[25]
+		mi := &file_lvm_proto_msgTypes[34]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1866,7 +2465,7 @@ func (x *RemoveTagLVReply) String() string {
 func (*RemoveTagLVReply) ProtoMessage() {}
 
 func (x *RemoveTagLVReply) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[25]
+	mi := &file_lvm_proto_msgTypes[34]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1879,7 +2478,7 @@ func (x *RemoveTagLVReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveTagLVReply.ProtoReflect.Descriptor instead.
 func (*RemoveTagLVReply) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{25}
+	return file_lvm_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *RemoveTagLVReply) GetCommandOutput() string {
@@ -1900,20 +2499,114 @@ type CleanPathRequest struct {
 func (x *CleanPathRequest) Reset() {
 	*x = CleanPathRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[26]
+		mi := &file_lvm_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CleanPathRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CleanPathRequest) ProtoMessage() {}
+
+func (x *CleanPathRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lvm_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CleanPathRequest.ProtoReflect.Descriptor instead.
+func (*CleanPathRequest) Descriptor() ([]byte, []int) {
+	return file_lvm_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *CleanPathRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type CleanPathReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CommandOutput string `protobuf:"bytes,1,opt,name=command_output,json=commandOutput,proto3" json:"command_output,omitempty"`
+}
+
+func (x *CleanPathReply) Reset() {
+	*x = CleanPathReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvm_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CleanPathReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CleanPathReply) ProtoMessage() {}
+
+func (x *CleanPathReply) ProtoReflect() protoreflect.Message {
+	mi := &file_lvm_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CleanPathReply.ProtoReflect.Descriptor instead.
+func (*CleanPathReply) Descriptor() ([]byte, []int) {
+	return file_lvm_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *CleanPathReply) GetCommandOutput() string {
+	if x != nil {
+		return x.CommandOutput
+	}
+	return ""
+}
+
+type CleanDeviceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Device string `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+}
+
+func (x *CleanDeviceRequest) Reset() {
+	*x = CleanDeviceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvm_proto_msgTypes[37]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CleanPathRequest) String() string {
+func (x *CleanDeviceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CleanPathRequest) ProtoMessage() {}
+func (*CleanDeviceRequest) ProtoMessage() {}
 
-func (x *CleanPathRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[26]
+func (x *CleanDeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lvm_proto_msgTypes[37]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1924,19 +2617,19 @@ func (x *CleanPathRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CleanPathRequest.ProtoReflect.Descriptor instead.
-func (*CleanPathRequest) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use CleanDeviceRequest.ProtoReflect.Descriptor instead.
+func (*CleanDeviceRequest) Descriptor() ([]byte, []int) {
+	return file_lvm_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *CleanPathRequest) GetPath() string {
+func (x *CleanDeviceRequest) GetDevice() string {
 	if x != nil {
-		return x.Path
+		return x.Device
 	}
 	return ""
 }
 
-type CleanPathReply struct {
+type CleanDeviceReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -1944,23 +2637,23 @@ type CleanPathReply struct {
 	CommandOutput string `protobuf:"bytes,1,opt,name=command_output,json=commandOutput,proto3" json:"command_output,omitempty"`
 }
 
-func (x *CleanPathReply) Reset() {
-	*x = CleanPathReply{}
+func (x *CleanDeviceReply) Reset() {
+	*x = CleanDeviceReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[27]
+		mi := &file_lvm_proto_msgTypes[38]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CleanPathReply) String() string {
+func (x *CleanDeviceReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CleanPathReply) ProtoMessage() {}
+func (*CleanDeviceReply) ProtoMessage() {}
 
-func (x *CleanPathReply) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[27]
+func (x *CleanDeviceReply) ProtoReflect() protoreflect.Message {
+	mi := &file_lvm_proto_msgTypes[38]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1971,43 +2664,43 @@ func (x *CleanPathReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CleanPathReply.ProtoReflect.Descriptor instead.
-func (*CleanPathReply) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use CleanDeviceReply.ProtoReflect.Descriptor instead.
+func (*CleanDeviceReply) Descriptor() ([]byte, []int) {
+	return file_lvm_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *CleanPathReply) GetCommandOutput() string {
+func (x *CleanDeviceReply) GetCommandOutput() string {
 	if x != nil {
 		return x.CommandOutput
 	}
 	return ""
 }
 
-type CleanDeviceRequest struct {
+type RemoveQuotaRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Device string `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	QuotaSubpath string `protobuf:"bytes,1,opt,name=quota_subpath,json=quotaSubpath,proto3" json:"quota_subpath,omitempty"`
 }
 
-func (x *CleanDeviceRequest) Reset() {
-	*x = CleanDeviceRequest{}
+func (x *RemoveQuotaRequest) Reset() {
+	*x = RemoveQuotaRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[28]
+		mi := &file_lvm_proto_msgTypes[39]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CleanDeviceRequest) String() string {
+func (x *RemoveQuotaRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CleanDeviceRequest) ProtoMessage() {}
+func (*RemoveQuotaRequest) ProtoMessage() {}
 
-func (x *CleanDeviceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[28]
+func (x *RemoveQuotaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lvm_proto_msgTypes[39]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2018,19 +2711,19 @@ func (x *CleanDeviceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CleanDeviceRequest.ProtoReflect.Descriptor instead.
-func (*CleanDeviceRequest) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use RemoveQuotaRequest.ProtoReflect.Descriptor instead.
+func (*RemoveQuotaRequest) Descriptor() ([]byte, []int) {
+	return file_lvm_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *CleanDeviceRequest) GetDevice() string {
+func (x *RemoveQuotaRequest) GetQuotaSubpath() string {
 	if x != nil {
-		return x.Device
+		return x.QuotaSubpath
 	}
 	return ""
 }
 
-type CleanDeviceReply struct {
+type RemoveQuotaReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -2038,23 +2731,23 @@ type CleanDeviceReply struct {
 	CommandOutput string `protobuf:"bytes,1,opt,name=command_output,json=commandOutput,proto3" json:"command_output,omitempty"`
 }
 
-func (x *CleanDeviceReply) Reset() {
-	*x = CleanDeviceReply{}
+func (x *RemoveQuotaReply) Reset() {
+	*x = RemoveQuotaReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[29]
+		mi := &file_lvm_proto_msgTypes[40]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CleanDeviceReply) String() string {
+func (x *RemoveQuotaReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CleanDeviceReply) ProtoMessage() {}
+func (*RemoveQuotaReply) ProtoMessage() {}
 
-func (x *CleanDeviceReply) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[29]
+func (x *RemoveQuotaReply) ProtoReflect() protoreflect.Message {
+	mi := &file_lvm_proto_msgTypes[40]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2065,12 +2758,12 @@ func (x *CleanDeviceReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CleanDeviceReply.ProtoReflect.Descriptor instead.
-func (*CleanDeviceReply) Descriptor() ([]byte, []int) {
-	return file_lvm_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use RemoveQuotaReply.ProtoReflect.Descriptor instead.
+func (*RemoveQuotaReply) Descriptor() ([]byte, []int) {
+	return file_lvm_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *CleanDeviceReply) GetCommandOutput() string {
+func (x *RemoveQuotaReply) GetCommandOutput() string {
 	if x != nil {
 		return x.CommandOutput
 	}
@@ -2097,7 +2790,7 @@ type LogicalVolume_Attributes struct {
 func (x *LogicalVolume_Attributes) Reset() {
 	*x = LogicalVolume_Attributes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvm_proto_msgTypes[30]
+		mi := &file_lvm_proto_msgTypes[41]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2110,7 +2803,7 @@ func (x *LogicalVolume_Attributes) String() string {
 func (*LogicalVolume_Attributes) ProtoMessage() {}
 
 func (x *LogicalVolume_Attributes) ProtoReflect() protoreflect.Message {
-	mi := &file_lvm_proto_msgTypes[30]
+	mi := &file_lvm_proto_msgTypes[41]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2347,7 +3040,7 @@ var file_lvm_proto_rawDesc = []byte{
 	0x65, 0x70, 0x6c, 0x79, 0x12, 0x2e, 0x0a, 0x07, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x73, 0x18,
 	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x6f,
 	0x67, 0x69, 0x63, 0x61, 0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52, 0x07, 0x76, 0x6f, 0x6c,
-	0x75, 0x6d, 0x65, 0x73, 0x22, 0xa6, 0x01, 0x0a, 0x0f, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c,
+	0x75, 0x6d, 0x65, 0x73, 0x22, 0xba, 0x01, 0x0a, 0x0f, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c,
 	0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x76, 0x6f, 0x6c, 0x75,
 	0x6d, 0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
 	0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x6e,
@@ -2357,170 +3050,273 @@ var file_lvm_proto_rawDesc = []byte{
 	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x6d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x12, 0x0a,
 	0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67,
 	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x74, 0x72, 0x69, 0x70, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x08, 0x73, 0x74, 0x72, 0x69, 0x70, 0x69, 0x6e, 0x67, 0x22, 0x36, 0x0a,
-	0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x25,
-	0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4f,
-	0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x48, 0x0a, 0x0f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c,
-	0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x76, 0x6f, 0x6c, 0x75,
-	0x6d, 0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
-	0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22,
-	0x36, 0x0a, 0x0d, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x73, 0x74, 0x72, 0x69, 0x70, 0x69, 0x6e, 0x67, 0x12, 0x12, 0x0a,
+	0x04, 0x74, 0x68, 0x69, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x74, 0x68, 0x69,
+	0x6e, 0x22, 0x36, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x6d,
+	0x61, 0x6e, 0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x48, 0x0a, 0x0f, 0x52, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c,
+	0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x22, 0x36, 0x0a, 0x0d, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x56, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x85, 0x01, 0x0a, 0x0e,
+	0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f,
+	0x0a, 0x0b, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x1b, 0x0a, 0x09, 0x64, 0x65, 0x73, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c,
+	0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12,
+	0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73,
+	0x69, 0x7a, 0x65, 0x22, 0x35, 0x0a, 0x0c, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4c, 0x56, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x5b, 0x0a, 0x19, 0x47, 0x65,
+	0x74, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4c, 0x56, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x76, 0x6f, 0x6c, 0x75, 0x6d,
+	0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x76,
+	0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65,
+	0x73, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64,
+	0x65, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x87, 0x01, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x43,
+	0x6c, 0x6f, 0x6e, 0x65, 0x4c, 0x56, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x64, 0x5f, 0x62, 0x79,
+	0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x63, 0x6f, 0x70, 0x69, 0x65,
+	0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f,
+	0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x22, 0x56, 0x0a, 0x14, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x43, 0x6c, 0x6f, 0x6e, 0x65,
+	0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x76, 0x6f, 0x6c,
+	0x75, 0x6d, 0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x1b, 0x0a, 0x09,
+	0x64, 0x65, 0x73, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x64, 0x65, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x3b, 0x0a, 0x12, 0x43, 0x61, 0x6e,
+	0x63, 0x65, 0x6c, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12,
+	0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0xa9, 0x01, 0x0a, 0x0b, 0x42, 0x61, 0x63, 0x6b, 0x75,
+	0x70, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x75,
+	0x63, 0x6b, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x75, 0x63, 0x6b,
+	0x65, 0x74, 0x12, 0x22, 0x0a, 0x0d, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x6b, 0x65, 0x79,
+	0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x73,
+	0x73, 0x4b, 0x65, 0x79, 0x49, 0x64, 0x12, 0x2a, 0x0a, 0x11, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x5f, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x4b,
+	0x65, 0x79, 0x22, 0x89, 0x01, 0x0a, 0x15, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c,
+	0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12,
+	0x23, 0x0a, 0x0d, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x61, 0x63, 0x6b,
+	0x75, 0x70, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x22, 0x83,
+	0x01, 0x0a, 0x13, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
+	0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x64,
+	0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x63, 0x6f,
+	0x70, 0x69, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x6f,
+	0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x22, 0xbc, 0x01, 0x0a, 0x10, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x76, 0x6f, 0x6c,
+	0x75, 0x6d, 0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04,
+	0x73, 0x69, 0x7a, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x75,
+	0x70, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x61, 0x63,
+	0x6b, 0x75, 0x70, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x74, 0x68, 0x69, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x74,
+	0x68, 0x69, 0x6e, 0x22, 0x37, 0x0a, 0x0e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x4c, 0x56,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x5c, 0x0a, 0x0f,
+	0x45, 0x78, 0x70, 0x61, 0x6e, 0x64, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x21, 0x0a, 0x0c, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x36, 0x0a, 0x0d, 0x45, 0x78,
+	0x70, 0x61, 0x6e, 0x64, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x63,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x22, 0x84, 0x01, 0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c,
+	0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12,
+	0x1b, 0x0a, 0x09, 0x73, 0x6e, 0x61, 0x70, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07,
+	0x6c, 0x76, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c,
+	0x76, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x3c, 0x0a, 0x13, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79,
 	0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70,
 	0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
-	0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x4e, 0x0a, 0x0e, 0x43, 0x6c, 0x6f, 0x6e, 0x65,
-	0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x6f, 0x75,
-	0x72, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
-	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65,
-	0x73, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64,
-	0x65, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x35, 0x0a, 0x0c, 0x43, 0x6c, 0x6f, 0x6e, 0x65,
-	0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61,
+	0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x57, 0x0a, 0x15, 0x52, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x21, 0x0a, 0x0c, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x6e, 0x61, 0x70, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x4e, 0x61, 0x6d, 0x65,
+	0x22, 0x3c, 0x0a, 0x13, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61,
 	0x6e, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x5c,
-	0x0a, 0x0f, 0x45, 0x78, 0x70, 0x61, 0x6e, 0x64, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x21, 0x0a, 0x0c, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75,
-	0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x47,
-	0x72, 0x6f, 0x75, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x36, 0x0a, 0x0d,
-	0x45, 0x78, 0x70, 0x61, 0x6e, 0x64, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a,
-	0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4f, 0x75,
-	0x74, 0x70, 0x75, 0x74, 0x22, 0x84, 0x01, 0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53,
-	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21,
+	0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x0f,
+	0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x47, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
+	0x46, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x47, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x37,
+	0x0a, 0x0d, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x56, 0x6f,
+	0x6c, 0x75, 0x6d, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x0c, 0x76, 0x6f, 0x6c, 0x75, 0x6d,
+	0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x22, 0x62, 0x0a, 0x0f, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x56, 0x47, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x27,
+	0x0a, 0x0f, 0x70, 0x68, 0x79, 0x73, 0x69, 0x63, 0x61, 0x6c, 0x5f, 0x76, 0x6f, 0x6c, 0x75, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x68, 0x79, 0x73, 0x69, 0x63, 0x61,
+	0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x22, 0x36, 0x0a, 0x0d, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x47, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x0e,
+	0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x22, 0x25, 0x0a, 0x0f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x56, 0x47, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x36, 0x0a, 0x0d, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x56, 0x47, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x63,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x22, 0x5c, 0x0a, 0x0f, 0x41, 0x64, 0x64, 0x54, 0x61, 0x67, 0x4c, 0x56, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f,
+	0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x76, 0x6f, 0x6c,
+	0x75, 0x6d, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x61, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73,
+	0x22, 0x36, 0x0a, 0x0d, 0x41, 0x64, 0x64, 0x54, 0x61, 0x67, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x5f, 0x0a, 0x12, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x54, 0x61, 0x67, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21,
 	0x0a, 0x0c, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x47, 0x72, 0x6f, 0x75,
-	0x70, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x6e, 0x61, 0x70, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x17,
-	0x0a, 0x07, 0x6c, 0x76, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x06, 0x6c, 0x76, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x3c, 0x0a, 0x13, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x70,
-	0x6c, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f, 0x75,
-	0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x6d,
-	0x61, 0x6e, 0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x57, 0x0a, 0x15, 0x52, 0x65, 0x6d,
-	0x6f, 0x76, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f, 0x67, 0x72, 0x6f,
-	0x75, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65,
-	0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x6e, 0x61, 0x70, 0x5f, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x4e, 0x61,
-	0x6d, 0x65, 0x22, 0x3c, 0x0a, 0x13, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x53, 0x6e, 0x61, 0x70,
-	0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d,
-	0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74,
-	0x22, 0x0f, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x47, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x22, 0x46, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x47, 0x52, 0x65, 0x70, 0x6c, 0x79,
-	0x12, 0x37, 0x0a, 0x0d, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70,
-	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
-	0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x0c, 0x76, 0x6f, 0x6c,
-	0x75, 0x6d, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x22, 0x62, 0x0a, 0x0f, 0x43, 0x72, 0x65,
-	0x61, 0x74, 0x65, 0x56, 0x47, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x12, 0x27, 0x0a, 0x0f, 0x70, 0x68, 0x79, 0x73, 0x69, 0x63, 0x61, 0x6c, 0x5f, 0x76, 0x6f, 0x6c,
-	0x75, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x68, 0x79, 0x73, 0x69,
-	0x63, 0x61, 0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67,
-	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x22, 0x36, 0x0a,
-	0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x47, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x25,
+	0x70, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x22, 0x39, 0x0a, 0x10, 0x52, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x54, 0x61, 0x67, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a,
+	0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x22, 0x26, 0x0a, 0x10, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x50, 0x61, 0x74,
+	0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x37, 0x0a, 0x0e,
+	0x43, 0x6c, 0x65, 0x61, 0x6e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x25,
 	0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4f,
-	0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x25, 0x0a, 0x0f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x56,
-	0x47, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x36, 0x0a, 0x0d,
-	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x56, 0x47, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x2c, 0x0a, 0x12, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x44, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x64,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x22, 0x39, 0x0a, 0x10, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x44, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x39,
+	0x0a, 0x12, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x5f, 0x73, 0x75,
+	0x62, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x71, 0x75, 0x6f,
+	0x74, 0x61, 0x53, 0x75, 0x62, 0x70, 0x61, 0x74, 0x68, 0x22, 0x39, 0x0a, 0x10, 0x52, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a,
 	0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18,
 	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4f, 0x75,
-	0x74, 0x70, 0x75, 0x74, 0x22, 0x5c, 0x0a, 0x0f, 0x41, 0x64, 0x64, 0x54, 0x61, 0x67, 0x4c, 0x56,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x76, 0x6f, 0x6c, 0x75, 0x6d,
-	0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x76,
-	0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12,
-	0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61,
-	0x67, 0x73, 0x22, 0x36, 0x0a, 0x0d, 0x41, 0x64, 0x64, 0x54, 0x61, 0x67, 0x4c, 0x56, 0x52, 0x65,
-	0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f,
-	0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d,
-	0x6d, 0x61, 0x6e, 0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x5f, 0x0a, 0x12, 0x52, 0x65,
-	0x6d, 0x6f, 0x76, 0x65, 0x54, 0x61, 0x67, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x21, 0x0a, 0x0c, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x47, 0x72,
-	0x6f, 0x75, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18,
-	0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x22, 0x39, 0x0a, 0x10, 0x52,
-	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x54, 0x61, 0x67, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12,
-	0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75,
-	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
-	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x26, 0x0a, 0x10, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x50,
-	0x61, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61,
-	0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x37,
-	0x0a, 0x0e, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x70, 0x6c, 0x79,
-	0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70,
-	0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
-	0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x2c, 0x0a, 0x12, 0x43, 0x6c, 0x65, 0x61, 0x6e,
-	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a,
-	0x06, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64,
-	0x65, 0x76, 0x69, 0x63, 0x65, 0x22, 0x39, 0x0a, 0x10, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x44, 0x65,
-	0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d,
-	0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74,
-	0x32, 0xf7, 0x06, 0x0a, 0x03, 0x4c, 0x56, 0x4d, 0x12, 0x34, 0x0a, 0x06, 0x4c, 0x69, 0x73, 0x74,
-	0x4c, 0x56, 0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4c,
-	0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3a,
-	0x0a, 0x08, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3a, 0x0a, 0x08, 0x52, 0x65,
-	0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x56, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52,
-	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x56, 0x52,
-	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x07, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4c,
-	0x56, 0x12, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4c,
-	0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12,
-	0x3a, 0x0a, 0x08, 0x45, 0x78, 0x70, 0x61, 0x6e, 0x64, 0x4c, 0x56, 0x12, 0x16, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x78, 0x70, 0x61, 0x6e, 0x64, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x78, 0x70, 0x61,
-	0x6e, 0x64, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4c, 0x0a, 0x0e, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x1c, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70,
-	0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
-	0x6f, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4c, 0x0a, 0x0e, 0x52, 0x65, 0x6d,
-	0x6f, 0x76, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x1c, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
-	0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
-	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3a, 0x0a, 0x08, 0x41, 0x64, 0x64, 0x54, 0x61,
-	0x67, 0x4c, 0x56, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x64, 0x54,
-	0x61, 0x67, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x64, 0x54, 0x61, 0x67, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c,
-	0x79, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x0b, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x54, 0x61, 0x67,
-	0x4c, 0x56, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76,
-	0x65, 0x54, 0x61, 0x67, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x54, 0x61, 0x67, 0x4c,
-	0x56, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x34, 0x0a, 0x06, 0x4c, 0x69, 0x73, 0x74,
-	0x56, 0x47, 0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x56,
-	0x47, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x47, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3a,
-	0x0a, 0x08, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x47, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x47, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x56, 0x47, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3a, 0x0a, 0x08, 0x52, 0x65,
-	0x6d, 0x6f, 0x76, 0x65, 0x56, 0x47, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x47, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x56, 0x47, 0x52,
-	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x09, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x50,
-	0x61, 0x74, 0x68, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6c, 0x65, 0x61,
-	0x6e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65,
-	0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x0b, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x44, 0x65,
-	0x76, 0x69, 0x63, 0x65, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6c, 0x65,
-	0x61, 0x6e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x44, 0x65, 0x76,
-	0x69, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x2b, 0x5a, 0x29, 0x67, 0x69,
-	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x6c, 0x69, 0x62, 0x61, 0x62, 0x61,
-	0x2f, 0x6f, 0x70, 0x65, 0x6e, 0x2d, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x2f, 0x70, 0x6b, 0x67, 0x2f,
-	0x63, 0x73, 0x69, 0x2f, 0x6c, 0x69, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x70, 0x75, 0x74, 0x32, 0xee, 0x09, 0x0a, 0x03, 0x4c, 0x56, 0x4d, 0x12, 0x34, 0x0a, 0x06,
+	0x4c, 0x69, 0x73, 0x74, 0x4c, 0x56, 0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x22, 0x00, 0x12, 0x3a, 0x0a, 0x08, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x12, 0x16,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3a,
+	0x0a, 0x08, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x56, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x07, 0x43, 0x6c,
+	0x6f, 0x6e, 0x65, 0x4c, 0x56, 0x12, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6c,
+	0x6f, 0x6e, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4c,
+	0x56, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4c, 0x56, 0x50, 0x72, 0x6f, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4c, 0x56, 0x50, 0x72,
+	0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x49, 0x0a,
+	0x0d, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4c, 0x56, 0x12, 0x1b,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x43, 0x6c, 0x6f,
+	0x6e, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4c,
+	0x56, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4c, 0x0a, 0x0e, 0x42, 0x61, 0x63, 0x6b,
+	0x75, 0x70, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x1c, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x09, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x4c, 0x56, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x74,
+	0x6f, 0x72, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x4c, 0x56, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3a, 0x0a, 0x08, 0x45, 0x78, 0x70, 0x61, 0x6e, 0x64, 0x4c,
+	0x56, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x78, 0x70, 0x61, 0x6e, 0x64,
+	0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x45, 0x78, 0x70, 0x61, 0x6e, 0x64, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22,
+	0x00, 0x12, 0x4c, 0x0a, 0x0e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x12, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12,
+	0x4c, 0x0a, 0x0e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
+	0x74, 0x12, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x53, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3a, 0x0a,
+	0x08, 0x41, 0x64, 0x64, 0x54, 0x61, 0x67, 0x4c, 0x56, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x41, 0x64, 0x64, 0x54, 0x61, 0x67, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x64, 0x54, 0x61, 0x67,
+	0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x0b, 0x52, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x54, 0x61, 0x67, 0x4c, 0x56, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x54, 0x61, 0x67, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x54, 0x61, 0x67, 0x4c, 0x56, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x34,
+	0x0a, 0x06, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x47, 0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x47, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x47, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x22, 0x00, 0x12, 0x3a, 0x0a, 0x08, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x47,
+	0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x56,
+	0x47, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x47, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x3a, 0x0a, 0x08, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x56, 0x47, 0x12, 0x16, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x47, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x56, 0x47, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x09,
+	0x43, 0x6c, 0x65, 0x61, 0x6e, 0x50, 0x61, 0x74, 0x68, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6c, 0x65, 0x61, 0x6e,
+	0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x0b, 0x43,
+	0x6c, 0x65, 0x61, 0x6e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6c,
+	0x65, 0x61, 0x6e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x43, 0x0a, 0x0b, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x12,
+	0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x51, 0x75,
+	0x6f, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x2b, 0x5a, 0x29, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x6c, 0x69, 0x62, 0x61, 0x62, 0x61, 0x2f, 0x6f, 0x70, 0x65, 0x6e,
+	0x2d, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x63, 0x73, 0x69, 0x2f, 0x6c,
+	0x69, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -2536,7 +3332,7 @@ func file_lvm_proto_rawDescGZIP() []byte {
 }
 
 var file_lvm_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
-var file_lvm_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
+var file_lvm_proto_msgTypes = make([]protoimpl.MessageInfo, 42)
 var file_lvm_proto_goTypes = []interface{}{
 	(LogicalVolume_Attributes_Type)(0),        // 0: proto.LogicalVolume.Attributes.Type
 	(LogicalVolume_Attributes_Permissions)(0), // 1: proto.LogicalVolume.Attributes.Permissions
@@ -2554,71 +3350,94 @@ var file_lvm_proto_goTypes = []interface{}{
 	(*RemoveLVReply)(nil),                     // 13: proto.RemoveLVReply
 	(*CloneLVRequest)(nil),                    // 14: proto.CloneLVRequest
 	(*CloneLVReply)(nil),                      // 15: proto.CloneLVReply
-	(*ExpandLVRequest)(nil),                   // 16: proto.ExpandLVRequest
-	(*ExpandLVReply)(nil),                     // 17: proto.ExpandLVReply
-	(*CreateSnapshotRequest)(nil),             // 18: proto.CreateSnapshotRequest
-	(*CreateSnapshotReply)(nil),               // 19: proto.CreateSnapshotReply
-	(*RemoveSnapshotRequest)(nil),             // 20: proto.RemoveSnapshotRequest
-	(*RemoveSnapshotReply)(nil),               // 21: proto.RemoveSnapshotReply
-	(*ListVGRequest)(nil),                     // 22: proto.ListVGRequest
-	(*ListVGReply)(nil),                       // 23: proto.ListVGReply
-	(*CreateVGRequest)(nil),                   // 24: proto.CreateVGRequest
-	(*CreateVGReply)(nil),                     // 25: proto.CreateVGReply
-	(*RemoveVGRequest)(nil),                   // 26: proto.RemoveVGRequest
-	(*RemoveVGReply)(nil),                     // 27: proto.RemoveVGReply
-	(*AddTagLVRequest)(nil),                   // 28: proto.AddTagLVRequest
-	(*AddTagLVReply)(nil),                     // 29: proto.AddTagLVReply
-	(*RemoveTagLVRequest)(nil),                // 30: proto.RemoveTagLVRequest
-	(*RemoveTagLVReply)(nil),                  // 31: proto.RemoveTagLVReply
-	(*CleanPathRequest)(nil),                  // 32: proto.CleanPathRequest
-	(*CleanPathReply)(nil),                    // 33: proto.CleanPathReply
-	(*CleanDeviceRequest)(nil),                // 34: proto.CleanDeviceRequest
-	(*CleanDeviceReply)(nil),                  // 35: proto.CleanDeviceReply
-	(*LogicalVolume_Attributes)(nil),          // 36: proto.LogicalVolume.Attributes
+	(*GetCloneLVProgressRequest)(nil),         // 16: proto.GetCloneLVProgressRequest
+	(*GetCloneLVProgressReply)(nil),           // 17: proto.GetCloneLVProgressReply
+	(*CancelCloneLVRequest)(nil),              // 18: proto.CancelCloneLVRequest
+	(*CancelCloneLVReply)(nil),                // 19: proto.CancelCloneLVReply
+	(*BackupStore)(nil),                       // 20: proto.BackupStore
+	(*BackupSnapshotRequest)(nil),             // 21: proto.BackupSnapshotRequest
+	(*BackupSnapshotReply)(nil),               // 22: proto.BackupSnapshotReply
+	(*RestoreLVRequest)(nil),                  // 23: proto.RestoreLVRequest
+	(*RestoreLVReply)(nil),                    // 24: proto.RestoreLVReply
+	(*ExpandLVRequest)(nil),                   // 25: proto.ExpandLVRequest
+	(*ExpandLVReply)(nil),                     // 26: proto.ExpandLVReply
+	(*CreateSnapshotRequest)(nil),             // 27: proto.CreateSnapshotRequest
+	(*CreateSnapshotReply)(nil),               // 28: proto.CreateSnapshotReply
+	(*RemoveSnapshotRequest)(nil),             // 29: proto.RemoveSnapshotRequest
+	(*RemoveSnapshotReply)(nil),               // 30: proto.RemoveSnapshotReply
+	(*ListVGRequest)(nil),                     // 31: proto.ListVGRequest
+	(*ListVGReply)(nil),                       // 32: proto.ListVGReply
+	(*CreateVGRequest)(nil),                   // 33: proto.CreateVGRequest
+	(*CreateVGReply)(nil),                     // 34: proto.CreateVGReply
+	(*RemoveVGRequest)(nil),                   // 35: proto.RemoveVGRequest
+	(*RemoveVGReply)(nil),                     // 36: proto.RemoveVGReply
+	(*AddTagLVRequest)(nil),                   // 37: proto.AddTagLVRequest
+	(*AddTagLVReply)(nil),                     // 38: proto.AddTagLVReply
+	(*RemoveTagLVRequest)(nil),                // 39: proto.RemoveTagLVRequest
+	(*RemoveTagLVReply)(nil),                  // 40: proto.RemoveTagLVReply
+	(*CleanPathRequest)(nil),                  // 41: proto.CleanPathRequest
+	(*CleanPathReply)(nil),                    // 42: proto.CleanPathReply
+	(*CleanDeviceRequest)(nil),                // 43: proto.CleanDeviceRequest
+	(*CleanDeviceReply)(nil),                  // 44: proto.CleanDeviceReply
+	(*RemoveQuotaRequest)(nil),                // 45: proto.RemoveQuotaRequest
+	(*RemoveQuotaReply)(nil),                  // 46: proto.RemoveQuotaReply
+	(*LogicalVolume_Attributes)(nil),          // 47: proto.LogicalVolume.Attributes
 }
 var file_lvm_proto_depIdxs = []int32{
-	36, // 0: proto.LogicalVolume.attributes:type_name -> proto.LogicalVolume.Attributes
+	47, // 0: proto.LogicalVolume.attributes:type_name -> proto.LogicalVolume.Attributes
 	6,  // 1: proto.ListLVReply.volumes:type_name -> proto.LogicalVolume
-	7,  // 2: proto.ListVGReply.volume_groups:type_name -> proto.VolumeGroup
-	0,  // 3: proto.LogicalVolume.Attributes.type:type_name -> proto.LogicalVolume.Attributes.Type
-	1,  // 4: proto.LogicalVolume.Attributes.permissions:type_name -> proto.LogicalVolume.Attributes.Permissions
-	2,  // 5: proto.LogicalVolume.Attributes.allocation:type_name -> proto.LogicalVolume.Attributes.Allocation
-	3,  // 6: proto.LogicalVolume.Attributes.state:type_name -> proto.LogicalVolume.Attributes.State
-	4,  // 7: proto.LogicalVolume.Attributes.target_type:type_name -> proto.LogicalVolume.Attributes.TargetType
-	5,  // 8: proto.LogicalVolume.Attributes.health:type_name -> proto.LogicalVolume.Attributes.Health
-	8,  // 9: proto.LVM.ListLV:input_type -> proto.ListLVRequest
-	10, // 10: proto.LVM.CreateLV:input_type -> proto.CreateLVRequest
-	12, // 11: proto.LVM.RemoveLV:input_type -> proto.RemoveLVRequest
-	14, // 12: proto.LVM.CloneLV:input_type -> proto.CloneLVRequest
-	16, // 13: proto.LVM.ExpandLV:input_type -> proto.ExpandLVRequest
-	18, // 14: proto.LVM.CreateSnapshot:input_type -> proto.CreateSnapshotRequest
-	20, // 15: proto.LVM.RemoveSnapshot:input_type -> proto.RemoveSnapshotRequest
-	28, // 16: proto.LVM.AddTagLV:input_type -> proto.AddTagLVRequest
-	30, // 17: proto.LVM.RemoveTagLV:input_type -> proto.RemoveTagLVRequest
-	22, // 18: proto.LVM.ListVG:input_type -> proto.ListVGRequest
-	24, // 19: proto.LVM.CreateVG:input_type -> proto.CreateVGRequest
-	24, // 20: proto.LVM.RemoveVG:input_type -> proto.CreateVGRequest
-	32, // 21: proto.LVM.CleanPath:input_type -> proto.CleanPathRequest
-	34, // 22: proto.LVM.CleanDevice:input_type -> proto.CleanDeviceRequest
-	9,  // 23: proto.LVM.ListLV:output_type -> proto.ListLVReply
-	11, // 24: proto.LVM.CreateLV:output_type -> proto.CreateLVReply
-	13, // 25: proto.LVM.RemoveLV:output_type -> proto.RemoveLVReply
-	15, // 26: proto.LVM.CloneLV:output_type -> proto.CloneLVReply
-	17, // 27: proto.LVM.ExpandLV:output_type -> proto.ExpandLVReply
-	19, // 28: proto.LVM.CreateSnapshot:output_type -> proto.CreateSnapshotReply
-	21, // 29: proto.LVM.RemoveSnapshot:output_type -> proto.RemoveSnapshotReply
-	29, // 30: proto.LVM.AddTagLV:output_type -> proto.AddTagLVReply
-	31, // 31: proto.LVM.RemoveTagLV:output_type -> proto.RemoveTagLVReply
-	23, // 32: proto.LVM.ListVG:output_type -> proto.ListVGReply
-	25, // 33: proto.LVM.CreateVG:output_type -> proto.CreateVGReply
-	27, // 34: proto.LVM.RemoveVG:output_type -> proto.RemoveVGReply
-	33, // 35: proto.LVM.CleanPath:output_type -> proto.CleanPathReply
-	35, // 36: proto.LVM.CleanDevice:output_type -> proto.CleanDeviceReply
-	23, // [23:37] is the sub-list for method output_type
-	9,  // [9:23] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	20, // 2: proto.BackupSnapshotRequest.store:type_name -> proto.BackupStore
+	20, // 3: proto.RestoreLVRequest.store:type_name -> proto.BackupStore
+	7,  // 4: proto.ListVGReply.volume_groups:type_name -> proto.VolumeGroup
+	0,  // 5: proto.LogicalVolume.Attributes.type:type_name -> proto.LogicalVolume.Attributes.Type
+	1,  // 6: proto.LogicalVolume.Attributes.permissions:type_name -> proto.LogicalVolume.Attributes.Permissions
+	2,  // 7: proto.LogicalVolume.Attributes.allocation:type_name -> proto.LogicalVolume.Attributes.Allocation
+	3,  // 8: proto.LogicalVolume.Attributes.state:type_name -> proto.LogicalVolume.Attributes.State
+	4,  // 9: proto.LogicalVolume.Attributes.target_type:type_name -> proto.LogicalVolume.Attributes.TargetType
+	5,  // 10: proto.LogicalVolume.Attributes.health:type_name -> proto.LogicalVolume.Attributes.Health
+	8,  // 11: proto.LVM.ListLV:input_type -> proto.ListLVRequest
+	10, // 12: proto.LVM.CreateLV:input_type -> proto.CreateLVRequest
+	12, // 13: proto.LVM.RemoveLV:input_type -> proto.RemoveLVRequest
+	14, // 14: proto.LVM.CloneLV:input_type -> proto.CloneLVRequest
+	16, // 15: proto.LVM.GetCloneLVProgress:input_type -> proto.GetCloneLVProgressRequest
+	18, // 16: proto.LVM.CancelCloneLV:input_type -> proto.CancelCloneLVRequest
+	21, // 17: proto.LVM.BackupSnapshot:input_type -> proto.BackupSnapshotRequest
+	23, // 18: proto.LVM.RestoreLV:input_type -> proto.RestoreLVRequest
+	25, // 19: proto.LVM.ExpandLV:input_type -> proto.ExpandLVRequest
+	27, // 20: proto.LVM.CreateSnapshot:input_type -> proto.CreateSnapshotRequest
+	29, // 21: proto.LVM.RemoveSnapshot:input_type -> proto.RemoveSnapshotRequest
+	37, // 22: proto.LVM.AddTagLV:input_type -> proto.AddTagLVRequest
+	39, // 23: proto.LVM.RemoveTagLV:input_type -> proto.RemoveTagLVRequest
+	31, // 24: proto.LVM.ListVG:input_type -> proto.ListVGRequest
+	33, // 25: proto.LVM.CreateVG:input_type -> proto.CreateVGRequest
+	33, // 26: proto.LVM.RemoveVG:input_type -> proto.CreateVGRequest
+	41, // 27: proto.LVM.CleanPath:input_type -> proto.CleanPathRequest
+	43, // 28: proto.LVM.CleanDevice:input_type -> proto.CleanDeviceRequest
+	45, // 29: proto.LVM.RemoveQuota:input_type -> proto.RemoveQuotaRequest
+	9,  // 30: proto.LVM.ListLV:output_type -> proto.ListLVReply
+	11, // 31: proto.LVM.CreateLV:output_type -> proto.CreateLVReply
+	13, // 32: proto.LVM.RemoveLV:output_type -> proto.RemoveLVReply
+	15, // 33: proto.LVM.CloneLV:output_type -> proto.CloneLVReply
+	17, // 34: proto.LVM.GetCloneLVProgress:output_type -> proto.GetCloneLVProgressReply
+	19, // 35: proto.LVM.CancelCloneLV:output_type -> proto.CancelCloneLVReply
+	22, // 36: proto.LVM.BackupSnapshot:output_type -> proto.BackupSnapshotReply
+	24, // 37: proto.LVM.RestoreLV:output_type -> proto.RestoreLVReply
+	26, // 38: proto.LVM.ExpandLV:output_type -> proto.ExpandLVReply
+	28, // 39: proto.LVM.CreateSnapshot:output_type -> proto.CreateSnapshotReply
+	30, // 40: proto.LVM.RemoveSnapshot:output_type -> proto.RemoveSnapshotReply
+	38, // 41: proto.LVM.AddTagLV:output_type -> proto.AddTagLVReply
+	40, // 42: proto.LVM.RemoveTagLV:output_type -> proto.RemoveTagLVReply
+	32, // 43: proto.LVM.ListVG:output_type -> proto.ListVGReply
+	34, // 44: proto.LVM.CreateVG:output_type -> proto.CreateVGReply
+	36, // 45: proto.LVM.RemoveVG:output_type -> proto.RemoveVGReply
+	42, // 46: proto.LVM.CleanPath:output_type -> proto.CleanPathReply
+	44, // 47: proto.LVM.CleanDevice:output_type -> proto.CleanDeviceReply
+	46, // 48: proto.LVM.RemoveQuota:output_type -> proto.RemoveQuotaReply
+	30, // [30:49] is the sub-list for method output_type
+	11, // [11:30] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_lvm_proto_init() }
@@ -2748,7 +3567,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ExpandLVRequest); i {
+			switch v := v.(*GetCloneLVProgressRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2760,7 +3579,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ExpandLVReply); i {
+			switch v := v.(*GetCloneLVProgressReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2772,7 +3591,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateSnapshotRequest); i {
+			switch v := v.(*CancelCloneLVRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2784,7 +3603,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateSnapshotReply); i {
+			switch v := v.(*CancelCloneLVReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2796,7 +3615,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemoveSnapshotRequest); i {
+			switch v := v.(*BackupStore); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2808,7 +3627,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemoveSnapshotReply); i {
+			switch v := v.(*BackupSnapshotRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2820,7 +3639,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListVGRequest); i {
+			switch v := v.(*BackupSnapshotReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2832,7 +3651,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListVGReply); i {
+			switch v := v.(*RestoreLVRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2844,7 +3663,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateVGRequest); i {
+			switch v := v.(*RestoreLVReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2856,7 +3675,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateVGReply); i {
+			switch v := v.(*ExpandLVRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2868,7 +3687,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemoveVGRequest); i {
+			switch v := v.(*ExpandLVReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2880,7 +3699,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemoveVGReply); i {
+			switch v := v.(*CreateSnapshotRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2892,7 +3711,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddTagLVRequest); i {
+			switch v := v.(*CreateSnapshotReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2904,7 +3723,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddTagLVReply); i {
+			switch v := v.(*RemoveSnapshotRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2916,7 +3735,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemoveTagLVRequest); i {
+			switch v := v.(*RemoveSnapshotReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2928,7 +3747,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemoveTagLVReply); i {
+			switch v := v.(*ListVGRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2940,7 +3759,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CleanPathRequest); i {
+			switch v := v.(*ListVGReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2952,7 +3771,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CleanPathReply); i {
+			switch v := v.(*CreateVGRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2964,7 +3783,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CleanDeviceRequest); i {
+			switch v := v.(*CreateVGReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2976,7 +3795,7 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CleanDeviceReply); i {
+			switch v := v.(*RemoveVGRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2988,6 +3807,138 @@ func file_lvm_proto_init() {
 			}
 		}
 		file_lvm_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveVGReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvm_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddTagLVRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvm_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddTagLVReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvm_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveTagLVRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvm_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveTagLVReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvm_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CleanPathRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvm_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CleanPathReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvm_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CleanDeviceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvm_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CleanDeviceReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvm_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveQuotaRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvm_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveQuotaReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvm_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*LogicalVolume_Attributes); i {
 			case 0:
 				return &v.state
@@ -3006,7 +3957,7 @@ func file_lvm_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_lvm_proto_rawDesc,
 			NumEnums:      6,
-			NumMessages:   31,
+			NumMessages:   42,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,6 +22,10 @@ type LVMClient interface {
 	CreateLV(ctx context.Context, in *CreateLVRequest, opts ...grpc.CallOption) (*CreateLVReply, error)
 	RemoveLV(ctx context.Context, in *RemoveLVRequest, opts ...grpc.CallOption) (*RemoveLVReply, error)
 	CloneLV(ctx context.Context, in *CloneLVRequest, opts ...grpc.CallOption) (*CloneLVReply, error)
+	GetCloneLVProgress(ctx context.Context, in *GetCloneLVProgressRequest, opts ...grpc.CallOption) (*GetCloneLVProgressReply, error)
+	CancelCloneLV(ctx context.Context, in *CancelCloneLVRequest, opts ...grpc.CallOption) (*CancelCloneLVReply, error)
+	BackupSnapshot(ctx context.Context, in *BackupSnapshotRequest, opts ...grpc.CallOption) (*BackupSnapshotReply, error)
+	RestoreLV(ctx context.Context, in *RestoreLVRequest, opts ...grpc.CallOption) (*RestoreLVReply, error)
 	ExpandLV(ctx context.Context, in *ExpandLVRequest, opts ...grpc.CallOption) (*ExpandLVReply, error)
 	CreateSnapshot(ctx context.Context, in *CreateSnapshotRequest, opts ...grpc.CallOption) (*CreateSnapshotReply, error)
 	RemoveSnapshot(ctx context.Context, in *RemoveSnapshotRequest, opts ...grpc.CallOption) (*RemoveSnapshotReply, error)
@@ -32,6 +36,7 @@ type LVMClient interface {
 	RemoveVG(ctx context.Context, in *CreateVGRequest, opts ...grpc.CallOption) (*RemoveVGReply, error)
 	CleanPath(ctx context.Context, in *CleanPathRequest, opts ...grpc.CallOption) (*CleanPathReply, error)
 	CleanDevice(ctx context.Context, in *CleanDeviceRequest, opts ...grpc.CallOption) (*CleanDeviceReply, error)
+	RemoveQuota(ctx context.Context, in *RemoveQuotaRequest, opts ...grpc.CallOption) (*RemoveQuotaReply, error)
 }
 
 type lVMClient struct {
@@ -78,6 +83,42 @@ func (c *lVMClient) CloneLV(ctx context.Context, in *CloneLVRequest, opts ...grp
 	return out, nil
 }
 
+func (c *lVMClient) GetCloneLVProgress(ctx context.Context, in *GetCloneLVProgressRequest, opts ...grpc.CallOption) (*GetCloneLVProgressReply, error) {
+	out := new(GetCloneLVProgressReply)
+	err := c.cc.Invoke(ctx, "/proto.LVM/GetCloneLVProgress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lVMClient) CancelCloneLV(ctx context.Context, in *CancelCloneLVRequest, opts ...grpc.CallOption) (*CancelCloneLVReply, error) {
+	out := new(CancelCloneLVReply)
+	err := c.cc.Invoke(ctx, "/proto.LVM/CancelCloneLV", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lVMClient) BackupSnapshot(ctx context.Context, in *BackupSnapshotRequest, opts ...grpc.CallOption) (*BackupSnapshotReply, error) {
+	out := new(BackupSnapshotReply)
+	err := c.cc.Invoke(ctx, "/proto.LVM/BackupSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lVMClient) RestoreLV(ctx context.Context, in *RestoreLVRequest, opts ...grpc.CallOption) (*RestoreLVReply, error) {
+	out := new(RestoreLVReply)
+	err := c.cc.Invoke(ctx, "/proto.LVM/RestoreLV", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lVMClient) ExpandLV(ctx context.Context, in *ExpandLVRequest, opts ...grpc.CallOption) (*ExpandLVReply, error) {
 	out := new(ExpandLVReply)
 	err := c.cc.Invoke(ctx, "/proto.LVM/ExpandLV", in, out, opts...)
@@ -168,6 +209,15 @@ func (c *lVMClient) CleanDevice(ctx context.Context, in *CleanDeviceRequest, opt
 	return out, nil
 }
 
+func (c *lVMClient) RemoveQuota(ctx context.Context, in *RemoveQuotaRequest, opts ...grpc.CallOption) (*RemoveQuotaReply, error) {
+	out := new(RemoveQuotaReply)
+	err := c.cc.Invoke(ctx, "/proto.LVM/RemoveQuota", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LVMServer is the server API for LVM service.
 // All implementations must embed UnimplementedLVMServer
 // for forward compatibility
@@ -176,6 +226,10 @@ type LVMServer interface {
 	CreateLV(context.Context, *CreateLVRequest) (*CreateLVReply, error)
 	RemoveLV(context.Context, *RemoveLVRequest) (*RemoveLVReply, error)
 	CloneLV(context.Context, *CloneLVRequest) (*CloneLVReply, error)
+	GetCloneLVProgress(context.Context, *GetCloneLVProgressRequest) (*GetCloneLVProgressReply, error)
+	CancelCloneLV(context.Context, *CancelCloneLVRequest) (*CancelCloneLVReply, error)
+	BackupSnapshot(context.Context, *BackupSnapshotRequest) (*BackupSnapshotReply, error)
+	RestoreLV(context.Context, *RestoreLVRequest) (*RestoreLVReply, error)
 	ExpandLV(context.Context, *ExpandLVRequest) (*ExpandLVReply, error)
 	CreateSnapshot(context.Context, *CreateSnapshotRequest) (*CreateSnapshotReply, error)
 	RemoveSnapshot(context.Context, *RemoveSnapshotRequest) (*RemoveSnapshotReply, error)
@@ -186,6 +240,7 @@ type LVMServer interface {
 	RemoveVG(context.Context, *CreateVGRequest) (*RemoveVGReply, error)
 	CleanPath(context.Context, *CleanPathRequest) (*CleanPathReply, error)
 	CleanDevice(context.Context, *CleanDeviceRequest) (*CleanDeviceReply, error)
+	RemoveQuota(context.Context, *RemoveQuotaRequest) (*RemoveQuotaReply, error)
 	mustEmbedUnimplementedLVMServer()
 }
 
@@ -205,6 +260,18 @@ func (UnimplementedLVMServer) RemoveLV(context.Context, *RemoveLVRequest) (*Remo
 func (UnimplementedLVMServer) CloneLV(context.Context, *CloneLVRequest) (*CloneLVReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CloneLV not implemented")
 }
+func (UnimplementedLVMServer) GetCloneLVProgress(context.Context, *GetCloneLVProgressRequest) (*GetCloneLVProgressReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCloneLVProgress not implemented")
+}
+func (UnimplementedLVMServer) CancelCloneLV(context.Context, *CancelCloneLVRequest) (*CancelCloneLVReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelCloneLV not implemented")
+}
+func (UnimplementedLVMServer) BackupSnapshot(context.Context, *BackupSnapshotRequest) (*BackupSnapshotReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BackupSnapshot not implemented")
+}
+func (UnimplementedLVMServer) RestoreLV(context.Context, *RestoreLVRequest) (*RestoreLVReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreLV not implemented")
+}
 func (UnimplementedLVMServer) ExpandLV(context.Context, *ExpandLVRequest) (*ExpandLVReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ExpandLV not implemented")
 }
@@ -235,6 +302,9 @@ func (UnimplementedLVMServer) CleanPath(context.Context, *CleanPathRequest) (*Cl
 func (UnimplementedLVMServer) CleanDevice(context.Context, *CleanDeviceRequest) (*CleanDeviceReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CleanDevice not implemented")
 }
+func (UnimplementedLVMServer) RemoveQuota(context.Context, *RemoveQuotaRequest) (*RemoveQuotaReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveQuota not implemented")
+}
 func (UnimplementedLVMServer) mustEmbedUnimplementedLVMServer() {}
 
 // UnsafeLVMServer may be embedded to opt out of forward compatibility for this service.
@@ -320,6 +390,78 @@ func _LVM_CloneLV_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LVM_GetCloneLVProgress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCloneLVProgressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LVMServer).GetCloneLVProgress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LVM/GetCloneLVProgress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LVMServer).GetCloneLVProgress(ctx, req.(*GetCloneLVProgressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LVM_CancelCloneLV_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelCloneLVRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LVMServer).CancelCloneLV(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LVM/CancelCloneLV",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LVMServer).CancelCloneLV(ctx, req.(*CancelCloneLVRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LVM_BackupSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LVMServer).BackupSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LVM/BackupSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LVMServer).BackupSnapshot(ctx, req.(*BackupSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LVM_RestoreLV_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreLVRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LVMServer).RestoreLV(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LVM/RestoreLV",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LVMServer).RestoreLV(ctx, req.(*RestoreLVRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _LVM_ExpandLV_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ExpandLVRequest)
 	if err := dec(in); err != nil {
@@ -500,6 +642,24 @@ func _LVM_CleanDevice_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LVM_RemoveQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LVMServer).RemoveQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LVM/RemoveQuota",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LVMServer).RemoveQuota(ctx, req.(*RemoveQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LVM_ServiceDesc is the grpc.ServiceDesc for LVM service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -523,6 +683,22 @@ var LVM_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CloneLV",
 			Handler:    _LVM_CloneLV_Handler,
 		},
+		{
+			MethodName: "GetCloneLVProgress",
+			Handler:    _LVM_GetCloneLVProgress_Handler,
+		},
+		{
+			MethodName: "CancelCloneLV",
+			Handler:    _LVM_CancelCloneLV_Handler,
+		},
+		{
+			MethodName: "BackupSnapshot",
+			Handler:    _LVM_BackupSnapshot_Handler,
+		},
+		{
+			MethodName: "RestoreLV",
+			Handler:    _LVM_RestoreLV_Handler,
+		},
 		{
 			MethodName: "ExpandLV",
 			Handler:    _LVM_ExpandLV_Handler,
@@ -563,6 +739,10 @@ var LVM_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CleanDevice",
 			Handler:    _LVM_CleanDevice_Handler,
 		},
+		{
+			MethodName: "RemoveQuota",
+			Handler:    _LVM_RemoveQuota_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "lvm.proto",
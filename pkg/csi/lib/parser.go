@@ -20,11 +20,10 @@ package lib
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
-
-	"github.com/alibaba/open-local/pkg"
 )
 
 // VolumeType is volume type
@@ -317,131 +316,167 @@ func (vg VG) ToProto() *VolumeGroup {
 	}
 }
 
-func parse(line string, numComponents int) (map[string]string, error) {
-	components := strings.Split(line, pkg.Separator)
-	if len(components) != numComponents {
-		return nil, fmt.Errorf("expected %d components, got %d", numComponents, len(components))
-	}
+type lvReport struct {
+	Report []struct {
+		Lv []struct {
+			Name        string `json:"lv_name"`
+			Size        string `json:"lv_size"`
+			UUID        string `json:"lv_uuid"`
+			Attr        string `json:"lv_attr"`
+			CopyPercent string `json:"copy_percent"`
+			KernelMajor string `json:"lv_kernel_major"`
+			KernelMinor string `json:"lv_kernel_minor"`
+			Tags        string `json:"lv_tags"`
+		} `json:"lv"`
+	} `json:"report"`
+}
 
-	fields := map[string]string{}
-	for _, c := range components {
-		idx := strings.Index(c, "=")
-		if idx == -1 {
-			return nil, fmt.Errorf("failed to parse component '%s'", c)
-		}
-		key := c[0:idx]
-		value := c[idx+1:]
-		if len(value) < 2 {
-			return nil, fmt.Errorf("failed to parse component '%s'", c)
-		}
-		if value[0] != '\'' || value[len(value)-1] != '\'' {
-			return nil, fmt.Errorf("failed to parse component '%s'", c)
-		}
-		value = value[1 : len(value)-1]
-		fields[key] = value
-	}
+type vgReport struct {
+	Report []struct {
+		Vg []struct {
+			Name    string `json:"vg_name"`
+			Size    string `json:"vg_size"`
+			Free    string `json:"vg_free"`
+			UUID    string `json:"vg_uuid"`
+			Tags    string `json:"vg_tags"`
+			PvCount string `json:"pv_count"`
+		} `json:"vg"`
+	} `json:"report"`
+}
 
-	return fields, nil
+type pvReport struct {
+	Report []struct {
+		Pv []struct {
+			Name   string `json:"pv_name"`
+			Size   string `json:"pv_size"`
+			Free   string `json:"pv_free"`
+			UUID   string `json:"pv_uuid"`
+			Tags   string `json:"pv_tags"`
+			VgName string `json:"vg_name"`
+		} `json:"pv"`
+	} `json:"report"`
 }
 
-// ParseLV parses a line from lvs
-func ParseLV(line string) (*LV, error) {
-	// lvs --units=b --separator="<:SEP:>" --nosuffix --noheadings -o lv_name,lv_size,lv_uuid,lv_attr,copy_percent,lv_kernel_major,lv_kernel_minor,lv_tags --nameprefixes -a
+// ParseLVReport parses the json report of lvs
+func ParseLVReport(out []byte) ([]*LV, error) {
+	// lvs --reportformat=json --units=b --nosuffix -o lv_name,lv_size,lv_uuid,lv_attr,copy_percent,lv_kernel_major,lv_kernel_minor,lv_tags -a
 	// todo: devices, lv_ancestors, lv_descendants, lv_major, lv_minor, mirror_log, modules, move_pv, origin, region_size
 	//       seg_count, seg_size, seg_start, seg_tags, segtype, snap_percent, stripes, stripe_size
-	fields, err := parse(line, 8)
-	if err != nil {
-		return nil, err
-	}
-
-	size, err := strconv.ParseUint(fields[pkg.Lvm2LVSizeTag], 10, 64)
-	if err != nil {
-		return nil, err
-	}
-
-	kernelMajNumber, err := strconv.ParseUint(fields[pkg.Lvm2LVKernelMajorTag], 10, 32)
-	if err != nil {
-		return nil, err
-	}
-
-	kernelMinNumber, err := strconv.ParseUint(fields[pkg.Lvm2LVKernelMinorTag], 10, 32)
-	if err != nil {
-		return nil, err
+	report := new(lvReport)
+	if err := json.Unmarshal(out, report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal lvs report: %s", err.Error())
 	}
-
-	attrs, err := parseAttrs(fields[pkg.Lvm2LVAttrTag])
-	if err != nil {
-		return nil, err
+	lvs := []*LV{}
+	for _, r := range report.Report {
+		for _, lv := range r.Lv {
+			size, err := strconv.ParseUint(lv.Size, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			kernelMajNumber, err := parseKernelNumber(lv.KernelMajor)
+			if err != nil {
+				return nil, err
+			}
+			kernelMinNumber, err := parseKernelNumber(lv.KernelMinor)
+			if err != nil {
+				return nil, err
+			}
+			attrs, err := parseAttrs(lv.Attr)
+			if err != nil {
+				return nil, err
+			}
+			lvs = append(lvs, &LV{
+				Name:               lv.Name,
+				Size:               size,
+				UUID:               lv.UUID,
+				Attributes:         *attrs,
+				CopyPercent:        lv.CopyPercent,
+				ActualDevMajNumber: kernelMajNumber,
+				ActualDevMinNumber: kernelMinNumber,
+				Tags:               strings.Split(lv.Tags, ","),
+			})
+		}
 	}
-
-	return &LV{
-		Name:               fields[pkg.Lvm2LVNameTag],
-		Size:               size,
-		UUID:               fields[pkg.Lvm2LVUuidTag],
-		Attributes:         *attrs,
-		CopyPercent:        fields[pkg.Lvm2CopyPercentTag],
-		ActualDevMajNumber: uint32(kernelMajNumber),
-		ActualDevMinNumber: uint32(kernelMinNumber),
-		Tags:               strings.Split(fields[pkg.Lvm2LVTagsTag], ","),
-	}, nil
+	return lvs, nil
 }
 
-// ParseVG parse volume group
-func ParseVG(line string) (*VG, error) {
-	// vgs --units=b --separator="<:SEP:>" --nosuffix --noheadings -o vg_name,vg_size,vg_free,vg_uuid,vg_tags,pv_count --nameprefixes -a
-	fields, err := parse(line, 6)
-	if err != nil {
-		return nil, err
+// ParseVGReport parses the json report of vgs
+func ParseVGReport(out []byte) ([]*VG, error) {
+	// vgs --reportformat=json --units=b --nosuffix -o vg_name,vg_size,vg_free,vg_uuid,vg_tags,pv_count -a
+	report := new(vgReport)
+	if err := json.Unmarshal(out, report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal vgs report: %s", err.Error())
 	}
-
-	size, err := strconv.ParseUint(fields[pkg.Lvm2VGSizeTag], 10, 64)
-	if err != nil {
-		return nil, err
+	vgs := []*VG{}
+	for _, r := range report.Report {
+		for _, vg := range r.Vg {
+			size, err := strconv.ParseUint(vg.Size, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			freeSize, err := strconv.ParseUint(vg.Free, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			pvCount, err := strconv.ParseUint(vg.PvCount, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			vgs = append(vgs, &VG{
+				Name:     vg.Name,
+				Size:     size,
+				FreeSize: freeSize,
+				UUID:     vg.UUID,
+				Tags:     strings.Split(vg.Tags, ","),
+				PvCount:  pvCount,
+			})
+		}
 	}
+	return vgs, nil
+}
 
-	freeSize, err := strconv.ParseUint(fields[pkg.Lvm2VGFreeTag], 10, 64)
-	if err != nil {
-		return nil, err
+// ParsePVReport parses the json report of pvs
+func ParsePVReport(out []byte) ([]*PV, error) {
+	// pvs --reportformat=json --units=b --nosuffix -o pv_name,pv_size,pv_free,pv_uuid,pv_tags,vg_name -S vg_name=<vg> -a
+	report := new(pvReport)
+	if err := json.Unmarshal(out, report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pvs report: %s", err.Error())
 	}
-	pvCount, err := strconv.ParseUint(fields[pkg.Lvm2PVCountTag], 10, 64)
-	if err != nil {
-		return nil, err
+	pvs := []*PV{}
+	for _, r := range report.Report {
+		for _, pv := range r.Pv {
+			size, err := strconv.ParseUint(pv.Size, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			freeSize, err := strconv.ParseUint(pv.Free, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			pvs = append(pvs, &PV{
+				Name:     pv.Name,
+				Size:     size,
+				FreeSize: freeSize,
+				UUID:     pv.UUID,
+				Tags:     strings.Split(pv.Tags, ","),
+				VgName:   pv.VgName,
+			})
+		}
 	}
-	return &VG{
-		Name:     fields[pkg.Lvm2VGNameTag],
-		Size:     size,
-		FreeSize: freeSize,
-		UUID:     fields[pkg.Lvm2VGUuidTag],
-		Tags:     strings.Split(fields[pkg.Lvm2VGTagsTag], ","),
-		PvCount:  pvCount,
-	}, nil
+	return pvs, nil
 }
 
-// ParsePV parses physical volume
-func ParsePV(line string) (*PV, error) {
-	// pvs --units=b --separator="<:SEP:>" --nosuffix --noheadings -o pv_name,pv_size,pv_free,pv_uuid,pv_tags,vg_name -S vg_name=<vg> --nameprefixes -a
-	fields, err := parse(line, 6)
-	if err != nil {
-		return nil, err
-	}
-
-	size, err := strconv.ParseUint(fields[pkg.Lvm2PVSizeTag], 10, 64)
+// parseKernelNumber parses the kernel major/minor number of lv,
+// which is -1 when the lv is not active
+func parseKernelNumber(number string) (uint32, error) {
+	n, err := strconv.ParseInt(number, 10, 64)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-
-	freeSize, err := strconv.ParseUint(fields[pkg.Lvm2PVFreeTag], 10, 64)
-	if err != nil {
-		return nil, err
+	if n < 0 {
+		return 0, nil
 	}
-	return &PV{
-		Name:     fields[pkg.Lvm2PVNameTag],
-		Size:     size,
-		FreeSize: freeSize,
-		UUID:     fields[pkg.Lvm2PVUuidTag],
-		Tags:     strings.Split(fields[pkg.Lvm2PVTagsTag], ","),
-		VgName:   fields[pkg.Lvm2VGNameTag],
-	}, nil
+	return uint32(n), nil
 }
 
 func parseAttrs(attrs string) (*LVAttributes, error) {
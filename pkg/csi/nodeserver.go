@@ -18,13 +18,13 @@ package csi
 
 import (
 	"fmt"
-	"os/exec"
-	"path"
+	"os"
 	"path/filepath"
-	"strings"
-	"syscall"
+	"strconv"
 
 	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/csi/server"
+	clientset "github.com/alibaba/open-local/pkg/generated/clientset/versioned"
 	"github.com/alibaba/open-local/pkg/utils"
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	csicommon "github.com/kubernetes-csi/drivers/pkg/csi-common"
@@ -35,7 +35,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	mountutils "k8s.io/mount-utils"
@@ -66,14 +66,16 @@ const (
 
 type nodeServer struct {
 	*csicommon.DefaultNodeServer
-	nodeID               string
-	driverName           string
-	mounter              utils.Mounter
-	client               kubernetes.Interface
-	k8smounter           k8smount.Interface
-	sysPath              string
-	ephemeralVolumeStore Store
-	inFlight             *InFlight
+	nodeID      string
+	driverName  string
+	mounter     utils.Mounter
+	client      kubernetes.Interface
+	localclient clientset.Interface
+	k8smounter  k8smount.Interface
+	sysPath     string
+	volumeStore Store
+	inFlight    *InFlight
+	throttler   *ioThrottler
 }
 
 var (
@@ -92,24 +94,35 @@ func newNodeServer(d *csicommon.CSIDriver, dName, nodeID, sysPath string) csi.No
 		log.Fatalf("Error building kubernetes clientset: %s", err.Error())
 	}
 
+	localClient, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		log.Fatalf("Error building open-local clientset: %s", err.Error())
+	}
+
 	mounter := k8smount.New("")
 
-	store, err := NewVolumeStore(DefaultEphemeralVolumeDataFilePath)
+	store, err := NewVolumeStore(DefaultNodeVolumeStoreFilePath)
 	if err != nil {
-		log.Fatalf("fail to initialize ephemeral volume store: %s", err.Error())
+		log.Fatalf("fail to initialize node volume store: %s", err.Error())
 	}
 
-	return &nodeServer{
-		DefaultNodeServer:    csicommon.NewDefaultNodeServer(d),
-		nodeID:               nodeID,
-		mounter:              utils.NewMounter(),
-		k8smounter:           mounter,
-		client:               kubeClient,
-		driverName:           dName,
-		sysPath:              sysPath,
-		ephemeralVolumeStore: store,
-		inFlight:             NewInFlight(),
+	ns := &nodeServer{
+		DefaultNodeServer: csicommon.NewDefaultNodeServer(d),
+		nodeID:            nodeID,
+		mounter:           utils.NewMounter(),
+		k8smounter:        mounter,
+		client:            kubeClient,
+		localclient:       localClient,
+		driverName:        dName,
+		sysPath:           sysPath,
+		volumeStore:       store,
+		inFlight:          NewInFlight(),
+		throttler:         newIOThrottler(filepath.Join(sysPath, "fs/cgroup"), kubeClient),
 	}
+	go ns.throttler.Run(wait.NeverStop)
+	// finish or roll back the operations interrupted by restart before serving
+	ns.reconcileVolumes(context.Background())
+	return ns
 }
 
 // volume_id: yoda-70597cb6-c08b-4bbb-8d41-c4afcfa91866
@@ -147,6 +160,17 @@ func (ns *nodeServer) NodePublishVolume(ctx context.Context, req *csi.NodePublis
 		ns.inFlight.Delete(volumeID)
 	}()
 
+	// lv of ephemeral volume is created here and removed in NodeUnpublishVolume
+	state := VolumeState{VolumeID: volumeID, Phase: VolumePublishing, TargetPath: targetPath}
+	if ephemeralVolume {
+		state.VgName = req.VolumeContext[VgNameTag]
+		state.Device = filepath.Join("/dev/", state.VgName, volumeID)
+	}
+	finish, err := ns.startOperation(state)
+	if err != nil {
+		return nil, err
+	}
+
 	volCap := req.GetVolumeCapability()
 	switch volumeType {
 	case LvmVolumeType:
@@ -162,7 +186,7 @@ func (ns *nodeServer) NodePublishVolume(ctx context.Context, req *csi.NodePublis
 				return nil, status.Errorf(codes.Internal, "NodePublishVolume(mountLvmFS): mount lvm volume %s with path %s with error: %s", volumeID, targetPath, err.Error())
 			}
 		}
-		if err := ns.setIOThrottling(ctx, req); err != nil {
+		if err := ns.setIOThrottling(ctx, req, volumeType); err != nil {
 			return nil, err
 		}
 	case MountPointType:
@@ -170,6 +194,11 @@ func (ns *nodeServer) NodePublishVolume(ctx context.Context, req *csi.NodePublis
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "NodePublishVolume: mount mountpoint volume %s with path %s with error: %s", volumeID, targetPath, err.Error())
 		}
+	case QuotaVolumeType:
+		err := ns.mountQuotaVolume(ctx, req)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "NodePublishVolume: mount quota volume %s with path %s with error: %s", volumeID, targetPath, err.Error())
+		}
 	case DeviceVolumeType:
 		switch volCap.GetAccessType().(type) {
 		case *csi.VolumeCapability_Block:
@@ -183,9 +212,15 @@ func (ns *nodeServer) NodePublishVolume(ctx context.Context, req *csi.NodePublis
 				return nil, status.Errorf(codes.Internal, "NodePublishVolume(FileSystem): mount device volume %s with path %s with error: %s", volumeID, targetPath, err.Error())
 			}
 		}
+		if err := ns.setIOThrottling(ctx, req, volumeType); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, status.Errorf(codes.Internal, "NodePublishVolume: unsupported volume %s with type %s", volumeID, volumeType)
 	}
+	if err := finish(); err != nil {
+		return nil, status.Errorf(codes.Internal, "NodePublishVolume: record volume %s error: %s", volumeID, err.Error())
+	}
 
 	log.Infof("NodePublishVolume: Successful mount local volume %s to %s", volumeID, targetPath)
 	return &csi.NodePublishVolumeResponse{}, nil
@@ -209,6 +244,15 @@ func (ns *nodeServer) NodeUnpublishVolume(ctx context.Context, req *csi.NodeUnpu
 		ns.inFlight.Delete(volumeID)
 	}()
 
+	state, ephemeral := ns.volumeStore.Get(volumeID)
+	ephemeral = ephemeral && state.Device != ""
+	if ephemeral {
+		state.Phase = VolumeDeleting
+		if err := ns.volumeStore.Put(state); err != nil {
+			return nil, status.Errorf(codes.Internal, "NodeUnpublishVolume: record volume %s error: %s", volumeID, err.Error())
+		}
+	}
+
 	isMnt, err := ns.mounter.IsMounted(targetPath)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "NodeUnpublishVolume: fail to check if targetPath %s is mounted", targetPath)
@@ -220,15 +264,13 @@ func (ns *nodeServer) NodeUnpublishVolume(ctx context.Context, req *csi.NodeUnpu
 		}
 	}
 
-	ephemeralDevice := ns.ephemeralVolumeStore.GetDevice(volumeID)
-	if ephemeralDevice != "" {
-		// /dev/mapper/yoda--pool0-yoda--5c523416--7288--4138--95e0--f9392995959f
-		err := removeLVMByDevicePath(ephemeralDevice)
-		if err != nil {
+	ns.throttler.Remove(targetPath)
+
+	if ephemeral {
+		if err := removeEphemeralLV(state.Device); err != nil {
 			return nil, status.Error(codes.Internal, err.Error())
 		}
-		err = ns.ephemeralVolumeStore.DeleteVolume(volumeID)
-		if err != nil {
+		if err := ns.volumeStore.Delete(volumeID); err != nil {
 			log.Warningf("failed to remove volume: %s", err.Error())
 		}
 	}
@@ -237,11 +279,101 @@ func (ns *nodeServer) NodeUnpublishVolume(ctx context.Context, req *csi.NodeUnpu
 	return &csi.NodeUnpublishVolumeResponse{}, nil
 }
 
+// NodeStageVolume creates the lv, formats it and mounts it to the staging path once per node,
+// all pods using the volume bind mount the staging path in NodePublishVolume.
+// Block volumes, mountpoint and quota volumes are not mounted at the staging path.
 func (ns *nodeServer) NodeStageVolume(ctx context.Context, req *csi.NodeStageVolumeRequest) (*csi.NodeStageVolumeResponse, error) {
+	volumeID := req.GetVolumeId()
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "NodeStageVolume: Volume ID not provided")
+	}
+	stagingPath := req.GetStagingTargetPath()
+	if stagingPath == "" {
+		return nil, status.Error(codes.InvalidArgument, "NodeStageVolume: staging target path is empty")
+	}
+	volCap := req.GetVolumeCapability()
+	if volCap == nil {
+		return nil, status.Error(codes.InvalidArgument, "NodeStageVolume: volume capability not provided")
+	}
+	log.Infof("NodeStageVolume: Start to stage volume %s to staging path %s", volumeID, stagingPath)
+
+	if ok := ns.inFlight.Insert(volumeID); !ok {
+		return nil, status.Errorf(codes.Aborted, VolumeOperationAlreadyExists, volumeID)
+	}
+	defer func() {
+		ns.inFlight.Delete(volumeID)
+	}()
+
+	finish, err := ns.startOperation(VolumeState{VolumeID: volumeID, Phase: VolumePublishing, TargetPath: stagingPath})
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.VolumeContext[VolumeTypeTag] {
+	case LvmVolumeType:
+		switch volCap.GetAccessType().(type) {
+		case *csi.VolumeCapability_Block:
+			if _, err := ns.createLV(ctx, volumeID, req.VolumeContext); err != nil {
+				return nil, status.Errorf(codes.Internal, "NodeStageVolume(Block): create lvm volume %s with error: %s", volumeID, err.Error())
+			}
+		case *csi.VolumeCapability_Mount:
+			if err := ns.stageLvmFS(ctx, req); err != nil {
+				return nil, status.Errorf(codes.Internal, "NodeStageVolume(stageLvmFS): mount lvm volume %s with path %s with error: %s", volumeID, stagingPath, err.Error())
+			}
+		}
+	case DeviceVolumeType:
+		if volCap.GetMount() != nil {
+			if err := ns.stageDeviceVolumeFS(ctx, req); err != nil {
+				return nil, status.Errorf(codes.Internal, "NodeStageVolume(FileSystem): mount device volume %s with path %s with error: %s", volumeID, stagingPath, err.Error())
+			}
+		}
+	}
+
+	if err := finish(); err != nil {
+		return nil, status.Errorf(codes.Internal, "NodeStageVolume: record volume %s error: %s", volumeID, err.Error())
+	}
+	log.Infof("NodeStageVolume: Successful stage local volume %s to %s", volumeID, stagingPath)
 	return &csi.NodeStageVolumeResponse{}, nil
 }
 
+// NodeUnstageVolume unmounts the staging path. It fails if the staging path is still bind
+// mounted to any target path, so that the volume is never unmounted under a running pod.
 func (ns *nodeServer) NodeUnstageVolume(ctx context.Context, req *csi.NodeUnstageVolumeRequest) (*csi.NodeUnstageVolumeResponse, error) {
+	volumeID := req.GetVolumeId()
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "NodeUnstageVolume: Volume ID not provided")
+	}
+	stagingPath := req.GetStagingTargetPath()
+	if stagingPath == "" {
+		return nil, status.Error(codes.InvalidArgument, "NodeUnstageVolume: staging target path is empty")
+	}
+	log.Infof("NodeUnstageVolume: Start to unstage volume %s from staging path %s", volumeID, stagingPath)
+
+	if ok := ns.inFlight.Insert(volumeID); !ok {
+		return nil, status.Errorf(codes.Aborted, VolumeOperationAlreadyExists, volumeID)
+	}
+	defer func() {
+		ns.inFlight.Delete(volumeID)
+	}()
+
+	notMnt, err := ns.k8smounter.IsLikelyNotMountPoint(stagingPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, status.Errorf(codes.Internal, "NodeUnstageVolume: fail to check if staging path %s is mounted: %s", stagingPath, err.Error())
+	}
+	if err == nil && !notMnt {
+		refs, err := ns.k8smounter.GetMountRefs(stagingPath)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "NodeUnstageVolume: fail to get mount references of %s: %s", stagingPath, err.Error())
+		}
+		if len(refs) > 0 {
+			return nil, status.Errorf(codes.FailedPrecondition, "NodeUnstageVolume: volume %s is still published at %v", volumeID, refs)
+		}
+		if err := ns.k8smounter.Unmount(stagingPath); err != nil {
+			return nil, status.Errorf(codes.Internal, "NodeUnstageVolume: Umount volume %s for path %s with error %v", volumeID, stagingPath, err)
+		}
+	}
+
+	log.Infof("NodeUnstageVolume: Successful unstage volume %s from %s", volumeID, stagingPath)
 	return &csi.NodeUnstageVolumeResponse{}, nil
 }
 
@@ -251,9 +383,16 @@ func (ns *nodeServer) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandV
 	volumeID := req.VolumeId
 	targetPath := req.VolumePath
 	expectSize := req.CapacityRange.RequiredBytes
-	if err := ns.resizeVolume(ctx, volumeID, targetPath); err != nil {
+	finish, err := ns.startOperation(VolumeState{VolumeID: volumeID, Phase: VolumeExpanding, TargetPath: targetPath, Size: expectSize})
+	if err != nil {
+		return nil, err
+	}
+	if err := ns.resizeVolume(ctx, volumeID, targetPath, expectSize); err != nil {
 		return nil, status.Errorf(codes.Internal, "NodePublishVolume: Resize local volume %s with error: %s", volumeID, err.Error())
 	}
+	if err := finish(); err != nil {
+		return nil, status.Errorf(codes.Internal, "NodeExpandVolume: record volume %s error: %s", volumeID, err.Error())
+	}
 
 	log.Infof("NodeExpandVolume: Successful expand local volume: %v to %d", req.VolumeId, expectSize)
 	return &csi.NodeExpandVolumeResponse{}, nil
@@ -287,22 +426,35 @@ func (ns *nodeServer) NodeGetCapabilities(ctx context.Context, req *csi.NodeGetC
 		},
 	}
 
+	nscap4 := &csi.NodeServiceCapability{
+		Type: &csi.NodeServiceCapability_Rpc{
+			Rpc: &csi.NodeServiceCapability_RPC{
+				Type: csi.NodeServiceCapability_RPC_VOLUME_CONDITION,
+			},
+		},
+	}
+
 	return &csi.NodeGetCapabilitiesResponse{
 		Capabilities: []*csi.NodeServiceCapability{
-			nscap, nscap2, nscap3,
+			nscap, nscap2, nscap3, nscap4,
 		},
 	}, nil
 }
 
 func (ns *nodeServer) NodeGetInfo(ctx context.Context, req *csi.NodeGetInfoRequest) (*csi.NodeGetInfoResponse, error) {
+	segments := map[string]string{}
+	// VGs and media types are reported for storage capacity tracking, which are updated
+	// when the plugin is registered again
+	if nls, err := ns.localclient.CsiV1alpha1().NodeLocalStorages().Get(ctx, ns.nodeID, metav1.GetOptions{}); err != nil {
+		log.Warningf("NodeGetInfo: get nls %s error, only node is reported in topology: %s", ns.nodeID, err.Error())
+	} else {
+		segments = nodeTopology(nls)
+	}
+	// make sure that the driver works on this particular node only
+	segments[TopologyNodeKey] = ns.nodeID
 	return &csi.NodeGetInfoResponse{
-		NodeId: ns.nodeID,
-		// make sure that the driver works on this particular node only
-		AccessibleTopology: &csi.Topology{
-			Segments: map[string]string{
-				TopologyNodeKey: ns.nodeID,
-			},
-		},
+		NodeId:             ns.nodeID,
+		AccessibleTopology: &csi.Topology{Segments: segments},
 	}, nil
 }
 
@@ -314,11 +466,36 @@ func (ns *nodeServer) NodeGetVolumeStats(ctx context.Context, req *csi.NodeGetVo
 		err = fmt.Errorf("NodeGetVolumeStats target local path %v is empty", targetPath)
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
+	if _, err := os.Stat(targetPath); os.IsNotExist(err) {
+		return nil, status.Errorf(codes.NotFound, "NodeGetVolumeStats: volume path %s of volume %s is not found", targetPath, req.GetVolumeId())
+	}
 
-	return utils.GetMetrics(targetPath)
+	var attributes map[string]string
+	if _, _, pv := getPvInfo(ns.client, req.GetVolumeId()); pv != nil && pv.Spec.CSI != nil {
+		attributes = pv.Spec.CSI.VolumeAttributes
+	} else if state, ok := ns.volumeStore.Get(req.GetVolumeId()); ok && state.VgName != "" {
+		// ephemeral volume
+		attributes = map[string]string{VolumeTypeTag: LvmVolumeType, VgNameTag: state.VgName}
+	}
+	var condition *csi.VolumeCondition
+	if attributes != nil {
+		condition = ns.volumeCondition(req.GetVolumeId(), req.GetStagingTargetPath(), attributes)
+	}
+
+	rsp, err := utils.GetMetrics(targetPath)
+	if err != nil {
+		log.Errorf("NodeGetVolumeStats: get metrics of volume %s error: %s", req.GetVolumeId(), err.Error())
+		// usage is not available, but the condition is still reported
+		if condition == nil || !condition.Abnormal {
+			condition = abnormalCondition("get usage of %s failed: %s", targetPath, err.Error())
+		}
+		return &csi.NodeGetVolumeStatsResponse{VolumeCondition: condition}, nil
+	}
+	rsp.VolumeCondition = condition
+	return rsp, nil
 }
 
-func (ns *nodeServer) resizeVolume(ctx context.Context, volumeID, targetPath string) error {
+func (ns *nodeServer) resizeVolume(ctx context.Context, volumeID, targetPath string, expectSize int64) error {
 	vgName := ""
 
 	// Get volumeType
@@ -357,6 +534,21 @@ func (ns *nodeServer) resizeVolume(ctx context.Context, volumeID, targetPath str
 		}
 		log.Infof("NodeExpandVolume:: lvm resizefs successful volumeId: %s, devicePath: %s, volumePath: %s", volumeID, devicePath, targetPath)
 		return nil
+	case QuotaVolumeType:
+		mountPoint := ""
+		if value, ok := pv.Spec.CSI.VolumeAttributes[MountPointType]; ok {
+			mountPoint = value
+		}
+		if mountPoint == "" {
+			return status.Errorf(codes.Internal, "resizeVolume: Volume %s with mount point empty", pv.Name)
+		}
+		subPath := getQuotaSubpath(mountPoint, volumeID)
+		blockHardlimit := strconv.FormatInt((expectSize+1023)/1024, 10)
+		if _, err := server.SetSubpathProjQuota(ctx, subPath, blockHardlimit, blockHardlimit); err != nil {
+			return fmt.Errorf("NodeExpandVolume: Quota Resize Error, volumeId: %s, subPath: %s, err: %s", volumeID, subPath, err.Error())
+		}
+		log.Infof("NodeExpandVolume:: quota resize successful volumeId: %s, subPath: %s, hard limit: %sKB", volumeID, subPath, blockHardlimit)
+		return nil
 	}
 	return nil
 }
@@ -381,88 +573,27 @@ func getPvInfo(client kubernetes.Interface, volumeID string) (int64, string, *v1
 	//return pvSizeGB, "g", pv
 }
 
-func (ns *nodeServer) setIOThrottling(ctx context.Context, req *csi.NodePublishVolumeRequest) error {
-	volCap := req.GetVolumeCapability()
-	targetPath := req.GetTargetPath()
-	volumeID := req.VolumeId
-	iops, iopsExist := req.VolumeContext[localtype.VolumeIOPS]
-	bps, bpsExist := req.VolumeContext[localtype.VolumeBPS]
-	if iopsExist || bpsExist {
-		// get pod
-		var pod v1.Pod
-		var podUID string
-		switch volCap.GetAccessType().(type) {
-		case *csi.VolumeCapability_Block:
-			// /var/lib/kubelet/plugins/kubernetes.io/csi/volumeDevices/publish/yoda-c018ff81-d346-452e-b7b8-a45f1d1c230e/76cf946e-d074-4455-a272-4d3a81264fab
-			podUID = strings.Split(targetPath, "/")[10]
-		case *csi.VolumeCapability_Mount:
-			// /var/lib/kubelet/pods/2a7bbb9c-c915-4006-84d7-0e3ac9d8d70f/volumes/kubernetes.io~csi/yoda-70597cb6-c08b-4bbb-8d41-c4afcfa91866/mount
-			podUID = strings.Split(targetPath, "/")[5]
-		}
-		log.Debugf("pod(volume id %s) uuid is %s", volumeID, podUID)
-		namespace := req.VolumeContext[localtype.PVCNameSpace]
-		// set ResourceVersion to 0
-		// https://arthurchiao.art/blog/k8s-reliability-list-data-zh/
-		pods, err := ns.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{ResourceVersion: "0"})
-		for _, podItem := range pods.Items {
-			if podItem.UID == types.UID(podUID) {
-				pod = podItem
-			}
-		}
-		if err != nil {
-			return status.Errorf(codes.Internal, "NodePublishVolume: failed to get pod(uuid: %s): %s", podUID, err.Error())
-		}
-		// pod qosClass and blkioPath
-		qosClass := pod.Status.QOSClass
-		blkioPath := fmt.Sprintf("%s/fs/cgroup/blkio/kubepods.slice", ns.sysPath)
-		switch qosClass {
-		case v1.PodQOSGuaranteed:
-			blkioPath = path.Join(blkioPath, fmt.Sprintf("kubepods-pod%s.slice", strings.Replace(podUID, "-", "_", -1)))
-		case v1.PodQOSBurstable:
-			blkioPath = path.Join(blkioPath, "kubepods-burstable.slice", fmt.Sprintf("kubepods-burstable-pod%s.slice", strings.Replace(podUID, "-", "_", -1)))
-		case v1.PodQOSBestEffort:
-			blkioPath = path.Join(blkioPath, "kubepods-besteffort.slice", fmt.Sprintf("kubepods-besteffort-pod%s.slice", strings.Replace(podUID, "-", "_", -1)))
-		}
-		log.Debugf("pod(volume id %s) qosClass: %s", volumeID, qosClass)
-		log.Debugf("pod(volume id %s) blkio path: %s", volumeID, blkioPath)
-		// get lv lvpath
-		// todo: not support device kind
-		lvpath, err := ns.createLV(ctx, req)
-		if err != nil {
-			return status.Errorf(codes.Internal, "failed to get lv path %s: %s", volumeID, err.Error())
-		}
-		stat := syscall.Stat_t{}
-		_ = syscall.Stat(lvpath, &stat)
-		maj := uint64(stat.Rdev / 256)
-		min := uint64(stat.Rdev % 256)
-		log.Debugf("volume %s maj:min: %d:%d", volumeID, maj, min)
-		log.Debugf("volume %s path: %s", volumeID, lvpath)
-		if iopsExist {
-			log.Debugf("volume %s iops: %s", volumeID, iops)
-			cmdstr := fmt.Sprintf("echo %s > %s", fmt.Sprintf("%d:%d %s", maj, min, iops), fmt.Sprintf("%s/%s", blkioPath, localtype.IOPSReadFile))
-			_, err := exec.Command("sh", "-c", cmdstr).CombinedOutput()
-			if err != nil {
-				return status.Errorf(codes.Internal, "failed to write blkio file %s: %s", fmt.Sprintf("%s/%s", blkioPath, localtype.IOPSReadFile), err.Error())
-			}
-			cmdstr = fmt.Sprintf("echo %s > %s", fmt.Sprintf("%d:%d %s", maj, min, iops), fmt.Sprintf("%s/%s", blkioPath, localtype.IOPSWriteFile))
-			_, err = exec.Command("sh", "-c", cmdstr).CombinedOutput()
-			if err != nil {
-				return status.Errorf(codes.Internal, "failed to write blkio file %s: %s", fmt.Sprintf("%s/%s", blkioPath, localtype.IOPSWriteFile), err.Error())
-			}
-		}
-		if bpsExist {
-			log.Debugf("volume %s bps: %s", volumeID, bps)
-			cmdstr := fmt.Sprintf("echo %s > %s", fmt.Sprintf("%d:%d %s", maj, min, bps), fmt.Sprintf("%s/%s", blkioPath, localtype.BPSReadFile))
-			_, err := exec.Command("sh", "-c", cmdstr).CombinedOutput()
-			if err != nil {
-				return status.Errorf(codes.Internal, "failed to write blkio file %s: %s", fmt.Sprintf("%s/%s", blkioPath, localtype.BPSReadFile), err.Error())
-			}
-			cmdstr = fmt.Sprintf("echo %s > %s", fmt.Sprintf("%d:%d %s", maj, min, bps), fmt.Sprintf("%s/%s", blkioPath, localtype.BPSWriteFile))
-			_, err = exec.Command("sh", "-c", cmdstr).CombinedOutput()
-			if err != nil {
-				return status.Errorf(codes.Internal, "failed to write blkio file %s: %s", fmt.Sprintf("%s/%s", blkioPath, localtype.BPSWriteFile), err.Error())
-			}
+// setIOThrottling limits the io of lv or device in the cgroup of the pod which the volume is published to
+func (ns *nodeServer) setIOThrottling(ctx context.Context, req *csi.NodePublishVolumeRequest, volumeType string) error {
+	limits, err := parseIOLimits(req.VolumeContext)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "NodePublishVolume: %s", err.Error())
+	}
+	// the annotations of pvc override storageclass, and are kept applied by agent afterwards
+	if limits, err = ns.throttler.PVCLimits(ctx, req.VolumeContext, limits); err != nil {
+		log.Warningf("setIOThrottling: ignore io limits in annotations of pvc: %s", err.Error())
+	}
+	if limits.IsZero() {
+		return nil
+	}
+	device := req.VolumeContext[DeviceVolumeType]
+	if volumeType == LvmVolumeType {
+		if device, err = ns.createLV(ctx, req.VolumeId, req.VolumeContext); err != nil {
+			return status.Errorf(codes.Internal, "failed to get lv path %s: %s", req.VolumeId, err.Error())
 		}
 	}
+	if err := ns.throttler.Set(req.VolumeId, req.TargetPath, device, req.VolumeContext, limits); err != nil {
+		return status.Errorf(codes.Internal, "NodePublishVolume: failed to set io throttling of volume %s: %s", req.VolumeId, err.Error())
+	}
 	return nil
 }
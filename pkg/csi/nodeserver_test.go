@@ -0,0 +1,103 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	k8smount "k8s.io/utils/mount"
+)
+
+func TestPublishStagedVolume(t *testing.T) {
+	dir := t.TempDir()
+	stagingPath := filepath.Join(dir, "globalmount")
+	targets := []string{filepath.Join(dir, "pod-1", "mount"), filepath.Join(dir, "pod-2", "mount")}
+	mounter := k8smount.NewFakeMounter(nil)
+	store, err := NewVolumeStore(filepath.Join(dir, "volumes.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns := &nodeServer{k8smounter: mounter, volumeStore: store, inFlight: NewInFlight()}
+	ctx := context.Background()
+	volCap := &csi.VolumeCapability{AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}}}
+	volumeContext := map[string]string{VolumeTypeTag: LvmVolumeType, VgNameTag: "share"}
+
+	publish := func(target string) error {
+		_, err := ns.NodePublishVolume(ctx, &csi.NodePublishVolumeRequest{
+			VolumeId:          "pv-1",
+			StagingTargetPath: stagingPath,
+			TargetPath:        target,
+			VolumeCapability:  volCap,
+			VolumeContext:     volumeContext,
+		})
+		return err
+	}
+	unstage := func() error {
+		_, err := ns.NodeUnstageVolume(ctx, &csi.NodeUnstageVolumeRequest{VolumeId: "pv-1", StagingTargetPath: stagingPath})
+		return err
+	}
+
+	if err := publish(targets[0]); err == nil {
+		t.Errorf("NodePublishVolume() before staging should fail")
+	}
+	// staged by NodeStageVolume
+	if err := os.MkdirAll(stagingPath, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := mounter.Mount("/dev/share/pv-1", stagingPath, "ext4", nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, target := range targets {
+		if err := publish(target); err != nil {
+			t.Fatalf("NodePublishVolume(%s) error = %v", target, err)
+		}
+	}
+	if state, ok := store.Get("pv-1"); ok {
+		t.Errorf("volume is left in store as %s after published", state.Phase)
+	}
+	// the device is mounted once, and shared by both pods
+	for _, mp := range mounter.MountPoints {
+		if mp.Device != "/dev/share/pv-1" {
+			t.Errorf("mount point %s of device %s, want /dev/share/pv-1", mp.Path, mp.Device)
+		}
+	}
+
+	for i, target := range targets {
+		if err := unstage(); status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("NodeUnstageVolume() with %d pods error = %v, want %v", len(targets)-i, err, codes.FailedPrecondition)
+		}
+		if err := mounter.Unmount(target); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := unstage(); err != nil {
+		t.Errorf("NodeUnstageVolume() error = %v", err)
+	}
+	if len(mounter.MountPoints) != 0 {
+		t.Errorf("NodeUnstageVolume() left mount points %v", mounter.MountPoints)
+	}
+	// unstaging again is a no-op
+	if err := unstage(); err != nil {
+		t.Errorf("NodeUnstageVolume() again error = %v", err)
+	}
+}
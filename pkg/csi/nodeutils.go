@@ -5,54 +5,55 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	localtype "github.com/alibaba/open-local/pkg"
 	"github.com/alibaba/open-local/pkg/csi/server"
 	"github.com/alibaba/open-local/pkg/utils"
+	"github.com/alibaba/open-local/pkg/utils/lvm"
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
 	"golang.org/x/sys/unix"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	utilexec "k8s.io/utils/exec"
 	k8smount "k8s.io/utils/mount"
 )
 
-func (ns *nodeServer) createLV(ctx context.Context, req *csi.NodePublishVolumeRequest) (string, error) {
+func (ns *nodeServer) createLV(ctx context.Context, volumeID string, volumeContext map[string]string) (string, error) {
 	// parse vgname, consider invalid if empty
 	vgName := ""
-	if _, ok := req.VolumeContext[VgNameTag]; ok {
-		vgName = req.VolumeContext[VgNameTag]
+	if _, ok := volumeContext[VgNameTag]; ok {
+		vgName = volumeContext[VgNameTag]
 	}
 	if vgName == "" {
-		log.Errorf("createLV: request with empty vgName in volume: %s", req.VolumeId)
+		log.Errorf("createLV: request with empty vgName in volume: %s", volumeID)
 		return "", status.Error(codes.Internal, "error with input vgName is empty")
 	}
 
 	// parse lvm type
 	lvmType := LinearType
-	if _, ok := req.VolumeContext[LvmTypeTag]; ok {
-		lvmType = req.VolumeContext[LvmTypeTag]
+	if _, ok := volumeContext[LvmTypeTag]; ok {
+		lvmType = volumeContext[LvmTypeTag]
 	}
 
-	log.Infof("createLV: vg %s, volume %s, LVM Type %s", vgName, req.GetVolumeId(), lvmType)
+	log.Infof("createLV: vg %s, volume %s, LVM Type %s", vgName, volumeID, lvmType)
 
-	volumeID := req.GetVolumeId()
-	var isSnapshot bool
-	if _, isSnapshot = req.VolumeContext[localtype.ParamSnapshotName]; isSnapshot {
-		if ro, exist := req.VolumeContext[localtype.ParamSnapshotReadonly]; exist && ro == "true" {
+	if _, isSnapshot := volumeContext[localtype.ParamSnapshotName]; isSnapshot {
+		if isReadonlySnapshot(volumeContext) {
 			// if volume is ro snapshot, then mount snapshot lv
-			log.Infof("createLV: volume %s is readonly snapshot, mount snapshot lv %s directly", volumeID, req.VolumeContext[localtype.ParamSnapshotName])
-			volumeID = req.VolumeContext[localtype.ParamSnapshotName]
+			log.Infof("createLV: volume %s is readonly snapshot, mount snapshot lv %s directly", volumeID, volumeContext[localtype.ParamSnapshotName])
+			volumeID = volumeContext[localtype.ParamSnapshotName]
 		} else {
 			return "", status.Errorf(codes.Unimplemented, "createLV: support ro snapshot only, please set %s parameter in volumesnapshotclass", localtype.ParamSnapshotReadonly)
 		}
 	}
 	devicePath := filepath.Join("/dev/", vgName, volumeID)
 	if _, err := os.Stat(devicePath); os.IsNotExist(err) {
-		err := ns.createVolume(req.VolumeContext, volumeID, vgName, lvmType)
+		err := ns.createVolume(volumeContext, volumeID, vgName, lvmType)
 		if err != nil {
 			log.Errorf("createLV: create volume %s with error: %s", volumeID, err.Error())
 			return "", status.Error(codes.Internal, err.Error())
@@ -62,71 +63,38 @@ func (ns *nodeServer) createLV(ctx context.Context, req *csi.NodePublishVolumeRe
 	return devicePath, nil
 }
 
-// include normal lvm & aep lvm type
-func (ns *nodeServer) mountLvmFS(ctx context.Context, req *csi.NodePublishVolumeRequest) error {
-	// target path
-	targetPath := req.TargetPath
-	// fs type
-	fsType := req.GetVolumeCapability().GetMount().FsType
-	if len(fsType) == 0 {
-		fsType = DefaultFs
-	}
-	// device path
-	devicePath, err := ns.createLV(ctx, req)
+// stageLvmFS formats the lv and mounts it to the staging path, which is shared by all
+// pods using the volume on this node
+func (ns *nodeServer) stageLvmFS(ctx context.Context, req *csi.NodeStageVolumeRequest) error {
+	devicePath, err := ns.createLV(ctx, req.VolumeId, req.VolumeContext)
 	if err != nil {
 		return err
 	}
+	options := mountOptions(req.GetVolumeCapability(), isReadonlySnapshot(req.VolumeContext))
+	return ns.formatAndMount(req.VolumeId, devicePath, req.StagingTargetPath, fsTypeOf(req.GetVolumeCapability()), options)
+}
 
-	var isSnapshotReadOnly bool = false
-	if _, isSnapshot := req.VolumeContext[localtype.ParamSnapshotName]; isSnapshot {
-		if ro, exist := req.VolumeContext[localtype.ParamSnapshotReadonly]; exist && ro == "true" {
-			// if volume is ro snapshot, then mount snapshot lv
-			isSnapshotReadOnly = true
-		} else {
-			return status.Errorf(codes.Unimplemented, "mountLvmFS: support ro snapshot only, please set %s parameter in volumesnapshotclass", localtype.ParamSnapshotReadonly)
-		}
-	}
-
-	// Check targetPath
-	if _, err := os.Stat(targetPath); os.IsNotExist(err) {
-		if err := os.MkdirAll(targetPath, 0750); err != nil {
-			log.Errorf("mountLvmFS: mkdir target path %s with error: %s", targetPath, err.Error())
-			return status.Error(codes.Internal, err.Error())
-		}
+// include normal lvm & aep lvm type
+func (ns *nodeServer) mountLvmFS(ctx context.Context, req *csi.NodePublishVolumeRequest) error {
+	readonly := req.GetReadonly() || isReadonlySnapshot(req.VolumeContext)
+	if req.StagingTargetPath != "" {
+		return ns.mountStagedFS(req, readonly)
 	}
 
-	isMnt := utils.IsMounted(targetPath)
-	if !isMnt {
-		var options []string
-		if req.GetReadonly() || isSnapshotReadOnly {
-			options = append(options, "ro")
-		} else {
-			options = append(options, "rw")
-		}
-		mountFlags := req.GetVolumeCapability().GetMount().GetMountFlags()
-		options = append(options, mountFlags...)
-
-		diskMounter := &k8smount.SafeFormatAndMount{Interface: ns.k8smounter, Exec: utilexec.New()}
-		if err := diskMounter.FormatAndMount(devicePath, targetPath, fsType, options); err != nil {
-			log.Errorf("mountLvmFS: Volume: %s, Device: %s, FormatAndMount error: %s", req.VolumeId, devicePath, err.Error())
-			return status.Error(codes.Internal, err.Error())
-		}
-		log.Infof("mountLvmFS:: mount successful devicePath: %s, targetPath: %s, options: %v", devicePath, targetPath, options)
-	}
-	ephemeralVolume := req.GetVolumeContext()["csi.storage.k8s.io/ephemeral"] == "true"
-	if ephemeralVolume {
-		if err := ns.ephemeralVolumeStore.AddVolume(req.VolumeId, devicePath); err != nil {
-			log.Warningf("fail to add volume: %s", err.Error())
-		}
+	// ephemeral volumes are not staged, mount the lv to target path directly
+	devicePath, err := ns.createLV(ctx, req.VolumeId, req.VolumeContext)
+	if err != nil {
+		return err
 	}
-	return nil
+	options := mountOptions(req.GetVolumeCapability(), readonly)
+	return ns.formatAndMount(req.VolumeId, devicePath, req.TargetPath, fsTypeOf(req.GetVolumeCapability()), options)
 }
 
 func (ns *nodeServer) mountLvmBlock(ctx context.Context, req *csi.NodePublishVolumeRequest) error {
 	// target path
 	targetPath := req.TargetPath
 	// device path
-	devicePath, err := ns.createLV(ctx, req)
+	devicePath, err := ns.createLV(ctx, req.VolumeId, req.VolumeContext)
 	if err != nil {
 		return err
 	}
@@ -177,7 +145,6 @@ func (ns *nodeServer) mountLvmBlock(ctx context.Context, req *csi.NodePublishVol
 
 func (ns *nodeServer) mountMountPointVolume(ctx context.Context, req *csi.NodePublishVolumeRequest) error {
 	sourcePath := ""
-	targetPath := req.TargetPath
 	if value, ok := req.VolumeContext[MountPointType]; ok {
 		sourcePath = value
 	}
@@ -186,6 +153,40 @@ func (ns *nodeServer) mountMountPointVolume(ctx context.Context, req *csi.NodePu
 		return status.Error(codes.Internal, "Mount LocalVolume with empty source path "+req.VolumeId)
 	}
 
+	return ns.bindMountVolume(req, sourcePath)
+}
+
+func (ns *nodeServer) mountQuotaVolume(ctx context.Context, req *csi.NodePublishVolumeRequest) error {
+	mountPoint := ""
+	if value, ok := req.VolumeContext[MountPointType]; ok {
+		mountPoint = value
+	}
+	if mountPoint == "" {
+		log.Errorf("mountQuotaVolume: volume: %s, mount point empty", req.VolumeId)
+		return status.Error(codes.Internal, "Mount QuotaVolume with empty mount point "+req.VolumeId)
+	}
+
+	sourcePath := getQuotaSubpath(mountPoint, req.VolumeId)
+	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+		_, _, pv := getPvInfo(ns.client, req.VolumeId)
+		if pv == nil {
+			return status.Errorf(codes.Internal, "mountQuotaVolume: get pv info error %s", req.VolumeId)
+		}
+		pvQuantity := pv.Spec.Capacity[v1.ResourceStorage]
+		blockHardlimit := strconv.FormatInt((pvQuantity.Value()+1023)/1024, 10)
+		if _, err := server.CreateProjQuotaSubpath(ctx, mountPoint, req.VolumeId, blockHardlimit); err != nil {
+			log.Errorf("mountQuotaVolume: create quota subpath %s with error: %s", sourcePath, err.Error())
+			return status.Error(codes.Internal, err.Error())
+		}
+		log.Infof("mountQuotaVolume: create quota subpath %s with hard limit %sKB", sourcePath, blockHardlimit)
+	}
+
+	return ns.bindMountVolume(req, sourcePath)
+}
+
+// bindMountVolume bind mounts the source path to the target path of request
+func (ns *nodeServer) bindMountVolume(req *csi.NodePublishVolumeRequest, sourcePath string) error {
+	targetPath := req.TargetPath
 	notmounted, err := ns.k8smounter.IsLikelyNotMountPoint(targetPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -221,9 +222,23 @@ func (ns *nodeServer) mountMountPointVolume(ctx context.Context, req *csi.NodePu
 	return nil
 }
 
+// stageDeviceVolumeFS formats the device and mounts it to the staging path
+func (ns *nodeServer) stageDeviceVolumeFS(ctx context.Context, req *csi.NodeStageVolumeRequest) error {
+	sourceDevice := req.VolumeContext[DeviceVolumeType]
+	if sourceDevice == "" {
+		log.Errorf("stageDeviceVolumeFS: device volume: %s, sourcePath empty", req.VolumeId)
+		return status.Error(codes.Internal, "Mount Device with empty source path "+req.VolumeId)
+	}
+	options := mountOptions(req.GetVolumeCapability(), false)
+	return ns.formatAndMount(req.VolumeId, sourceDevice, req.StagingTargetPath, fsTypeOf(req.GetVolumeCapability()), options)
+}
+
 func (ns *nodeServer) mountDeviceVolumeFS(ctx context.Context, req *csi.NodePublishVolumeRequest) error {
+	if req.StagingTargetPath != "" {
+		return ns.mountStagedFS(req, req.GetReadonly())
+	}
+
 	sourceDevice := ""
-	targetPath := req.TargetPath
 	if value, ok := req.VolumeContext[DeviceVolumeType]; ok {
 		sourceDevice = value
 	}
@@ -231,44 +246,88 @@ func (ns *nodeServer) mountDeviceVolumeFS(ctx context.Context, req *csi.NodePubl
 		log.Errorf("mountDeviceVolume: device volume: %s, sourcePath empty", req.VolumeId)
 		return status.Error(codes.Internal, "Mount Device with empty source path "+req.VolumeId)
 	}
+	options := mountOptions(req.GetVolumeCapability(), req.GetReadonly())
+	return ns.formatAndMount(req.VolumeId, sourceDevice, req.TargetPath, fsTypeOf(req.GetVolumeCapability()), options)
+}
 
-	// Step Start to format
-	// fs type
-	fsType := req.GetVolumeCapability().GetMount().FsType
-	if len(fsType) == 0 {
-		fsType = DefaultFs
-	}
-
-	// Check targetPath
+// formatAndMount formats the device if it has no filesystem yet, and mounts it to
+// targetPath unless targetPath is already mounted
+func (ns *nodeServer) formatAndMount(volumeID, devicePath, targetPath, fsType string, options []string) error {
 	if _, err := os.Stat(targetPath); os.IsNotExist(err) {
 		if err := os.MkdirAll(targetPath, 0750); err != nil {
-			log.Errorf("mountDeviceVolume: volume %s mkdir target path %s with error: %s", sourceDevice, targetPath, err.Error())
+			log.Errorf("formatAndMount: volume %s mkdir target path %s with error: %s", volumeID, targetPath, err.Error())
 			return status.Error(codes.Internal, err.Error())
 		}
 	}
-
-	isMnt := utils.IsMounted(targetPath)
-	if !isMnt {
-		var options []string
-		if req.GetReadonly() {
-			options = append(options, "ro")
-		} else {
-			options = append(options, "rw")
-		}
-		mountFlags := req.GetVolumeCapability().GetMount().GetMountFlags()
-		options = append(options, mountFlags...)
-		// do format-mount or mount
-		diskMounter := &k8smount.SafeFormatAndMount{Interface: ns.k8smounter, Exec: utilexec.New()}
-		if err := diskMounter.FormatAndMount(sourceDevice, targetPath, fsType, options); err != nil {
-			log.Errorf("mountDeviceVolume: Volume: %s, Device: %s, FormatAndMount error: %s", req.VolumeId, sourceDevice, err.Error())
-			return status.Error(codes.Internal, err.Error())
-		}
-		log.Infof("mountDeviceVolume:: mount successful sourceDevice: %s, targetPath: %s, options: %v", sourceDevice, targetPath, options)
+	if utils.IsMounted(targetPath) {
+		log.Infof("formatAndMount: volume %s, target path %s is already mounted", volumeID, targetPath)
+		return nil
 	}
+	diskMounter := &k8smount.SafeFormatAndMount{Interface: ns.k8smounter, Exec: utilexec.New()}
+	if err := diskMounter.FormatAndMount(devicePath, targetPath, fsType, options); err != nil {
+		log.Errorf("formatAndMount: Volume: %s, Device: %s, FormatAndMount error: %s", volumeID, devicePath, err.Error())
+		return status.Error(codes.Internal, err.Error())
+	}
+	log.Infof("formatAndMount: mount successful devicePath: %s, targetPath: %s, options: %v", devicePath, targetPath, options)
+	return nil
+}
 
+// mountStagedFS bind mounts the filesystem at the staging path to the target path of request
+func (ns *nodeServer) mountStagedFS(req *csi.NodePublishVolumeRequest, readonly bool) error {
+	stagingPath, targetPath := req.StagingTargetPath, req.TargetPath
+	notMnt, err := ns.k8smounter.IsLikelyNotMountPoint(stagingPath)
+	if err != nil || notMnt {
+		return status.Errorf(codes.FailedPrecondition, "volume %s is not staged at %s", req.VolumeId, stagingPath)
+	}
+	if err := os.MkdirAll(targetPath, 0750); err != nil {
+		log.Errorf("mountStagedFS: volume %s mkdir target path %s with error: %s", req.VolumeId, targetPath, err.Error())
+		return status.Error(codes.Internal, err.Error())
+	}
+	notMnt, err = ns.k8smounter.IsLikelyNotMountPoint(targetPath)
+	if err != nil {
+		return status.Errorf(codes.Internal, "check if %s is mountpoint failed: %s", targetPath, err.Error())
+	}
+	if !notMnt {
+		log.Infof("mountStagedFS: volume %s, target path %s is already mounted", req.VolumeId, targetPath)
+		return nil
+	}
+	options := []string{"bind"}
+	if readonly {
+		options = append(options, "ro")
+	}
+	if err := ns.k8smounter.Mount(stagingPath, targetPath, "", options); err != nil {
+		log.Errorf("mountStagedFS: bind mount volume %s from %s to %s with error: %s", req.VolumeId, stagingPath, targetPath, err.Error())
+		return status.Error(codes.Internal, err.Error())
+	}
+	log.Infof("mountStagedFS: bind mount successful stagingPath: %s, targetPath: %s, options: %v", stagingPath, targetPath, options)
 	return nil
 }
 
+// mountOptions returns the filesystem mount options of volume capability
+func mountOptions(volCap *csi.VolumeCapability, readonly bool) []string {
+	options := []string{"rw"}
+	if readonly {
+		options = []string{"ro"}
+	}
+	return append(options, volCap.GetMount().GetMountFlags()...)
+}
+
+// fsTypeOf returns the filesystem type of volume capability, ext4 by default
+func fsTypeOf(volCap *csi.VolumeCapability) string {
+	if fsType := volCap.GetMount().GetFsType(); fsType != "" {
+		return fsType
+	}
+	return DefaultFs
+}
+
+// isReadonlySnapshot checks whether the volume is a readonly snapshot, which is mounted ro
+func isReadonlySnapshot(volumeContext map[string]string) bool {
+	if _, isSnapshot := volumeContext[localtype.ParamSnapshotName]; !isSnapshot {
+		return false
+	}
+	return volumeContext[localtype.ParamSnapshotReadonly] == "true"
+}
+
 func (ns *nodeServer) mountDeviceVolumeBlock(ctx context.Context, req *csi.NodePublishVolumeRequest) error {
 	// Step 1: get targetPath and sourceDevice
 	targetPath := req.GetTargetPath()
@@ -348,8 +407,7 @@ func (ns *nodeServer) createVolume(volumeContext map[string]string, volumeID, vg
 	var err error
 
 	// check vg exist
-	ckCmd := fmt.Sprintf("%s vgck %s", localtype.NsenterCmd, vgName)
-	_, err = utils.Run(ckCmd)
+	_, err = lvm.Run("vgck", vgName)
 	if err != nil {
 		log.Errorf("createVolume:: VG is not exist: %s", vgName)
 		return err
@@ -371,33 +429,32 @@ func createLvm(vgName, volumeID, lvmType, unit string, pvSize int64) error {
 			log.Errorf("createVolume:: VG is exist: %s, bug get pv number as 0", vgName)
 			return errors.New("")
 		}
-		cmd := fmt.Sprintf("%s lvcreate -i %d -n %s -L %d%s %s", localtype.NsenterCmd, pvNumber, volumeID, pvSize, unit, vgName)
-		_, err := utils.Run(cmd)
+		args := []string{"-i", strconv.Itoa(pvNumber), "-n", volumeID, "-L", fmt.Sprintf("%d%s", pvSize, unit), vgName}
+		_, err := lvm.Run("lvcreate", args...)
 		if err != nil {
-			log.Errorf("createVolume:: lvcreate command %s error: %v", cmd, err)
+			log.Errorf("createVolume:: lvcreate striping volume %s/%s error: %v", vgName, volumeID, err)
 			return err
 		}
-		log.Infof("Successful Create Striping LVM volume: %s, with command: %s", volumeID, cmd)
+		log.Infof("Successful Create Striping LVM volume: %s, with args: %v", volumeID, args)
 	} else if lvmType == LinearType {
-		cmd := fmt.Sprintf("%s lvcreate -n %s -L %d%s -Wy -y %s", localtype.NsenterCmd, volumeID, pvSize, unit, vgName)
-		_, err := utils.Run(cmd)
+		args := []string{"-n", volumeID, "-L", fmt.Sprintf("%d%s", pvSize, unit), "-Wy", "-y", vgName}
+		_, err := lvm.Run("lvcreate", args...)
 		if err != nil {
-			log.Errorf("createVolume:: lvcreate linear command %s error: %v", cmd, err)
+			log.Errorf("createVolume:: lvcreate linear volume %s/%s error: %v", vgName, volumeID, err)
 			return err
 		}
-		log.Infof("Successful Create Linear LVM volume: %s, with command: %s", volumeID, cmd)
+		log.Infof("Successful Create Linear LVM volume: %s, with args: %v", volumeID, args)
 	}
 	return nil
 }
 
 func removeLVMByDevicePath(devicePath string) error {
-	cmd := fmt.Sprintf("%s lvremove -v -f %s", localtype.NsenterCmd, devicePath)
-	_, err := utils.Run(cmd)
+	_, err := lvm.Run("lvremove", "-v", "-f", devicePath)
 	if err != nil {
-		log.Errorf("removeLVMByDevicePath:: lvremove command %s error: %v", cmd, err)
+		log.Errorf("removeLVMByDevicePath:: lvremove %s error: %v", devicePath, err)
 		return err
 	}
-	log.Infof("Successful Remove LVM devicePath: %s, with command: %s", devicePath, cmd)
+	log.Infof("Successful Remove LVM devicePath: %s", devicePath)
 	return nil
 }
 
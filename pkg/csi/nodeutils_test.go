@@ -0,0 +1,96 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alibaba/open-local/pkg/utils/lvm"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreateVolume(t *testing.T) {
+	tests := []struct {
+		name     string
+		lvmType  string
+		vgMissed bool
+		wantErr  bool
+		commands []string
+	}{
+		{
+			name:     "linear",
+			lvmType:  LinearType,
+			commands: []string{"vgck share", "lvcreate -n pv-1 -L 1024m -Wy -y share"},
+		},
+		{
+			name:    "striping",
+			lvmType: StripingType,
+			commands: []string{
+				"vgck share",
+				"vgs --reportformat=json --units=b --nosuffix -o vg_name,vg_size,vg_free,vg_uuid,vg_tags,pv_count -a",
+				"lvcreate -i 2 -n pv-1 -L 1024m share",
+			},
+		},
+		{
+			name:     "vg not found",
+			lvmType:  LinearType,
+			vgMissed: true,
+			wantErr:  true,
+			commands: []string{"vgck share"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := lvm.NewFakeExecutor()
+			defer lvm.SetExecutor(lvm.SetExecutor(fake))
+			if test.vgMissed {
+				fake.RecordError("vgck share", `Volume group "share" not found`)
+			} else {
+				fake.Record("vgck share", "")
+			}
+			fake.Record("vgs --reportformat=json --units=b --nosuffix -o vg_name,vg_size,vg_free,vg_uuid,vg_tags,pv_count -a",
+				`{"report": [{"vg": [{"vg_name":"share", "vg_size":"21474836480", "vg_free":"21474836480", "vg_uuid":"uuid", "vg_tags":"", "pv_count":"2"}]}]}`)
+			fake.Record("lvcreate -n pv-1 -L 1024m -Wy -y share", "")
+			fake.Record("lvcreate -i 2 -n pv-1 -L 1024m share", "")
+
+			// the size of ephemeral volume defaults to 1Gi as pv-1 is not found
+			ns := &nodeServer{client: k8sfake.NewSimpleClientset()}
+			err := ns.createVolume(map[string]string{}, "pv-1", "share", test.lvmType)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("createVolume error: %v, expected error: %t", err, test.wantErr)
+			}
+			if commands := fake.Commands(); !reflect.DeepEqual(commands, test.commands) {
+				t.Errorf("commands are %v, expected %v", commands, test.commands)
+			}
+		})
+	}
+}
+
+func TestRemoveLVMByDevicePath(t *testing.T) {
+	fake := lvm.NewFakeExecutor()
+	defer lvm.SetExecutor(lvm.SetExecutor(fake))
+	fake.Record("lvremove -v -f /dev/share/pv-1", "")
+	fake.RecordError("lvremove -v -f /dev/share/pv-2", `Failed to find logical volume "share/pv-2"`)
+
+	if err := removeLVMByDevicePath("/dev/share/pv-1"); err != nil {
+		t.Errorf("failed to remove lv: %s", err.Error())
+	}
+	if err := removeLVMByDevicePath("/dev/share/pv-2"); err == nil {
+		t.Errorf("removing missing lv succeeded, expected error")
+	}
+}
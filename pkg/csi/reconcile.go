@@ -0,0 +1,212 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// startOperation records the volume is in the phase of state before the operation, and returns
+// the function to call when it succeeds. Ephemeral volumes stay in store as Created until they
+// are unpublished, other volumes are removed from store.
+func (ns *nodeServer) startOperation(state VolumeState) (func() error, error) {
+	if prev, ok := ns.volumeStore.Get(state.VolumeID); ok && state.Device == "" {
+		state.Device, state.VgName = prev.Device, prev.VgName
+	}
+	if err := ns.volumeStore.Put(state); err != nil {
+		return nil, status.Errorf(codes.Internal, "record volume %s error: %s", state.VolumeID, err.Error())
+	}
+	return func() error {
+		if state.Device != "" {
+			state.Phase = VolumeCreated
+			return ns.volumeStore.Put(state)
+		}
+		return ns.volumeStore.Delete(state.VolumeID)
+	}, nil
+}
+
+// reconcileVolumes finishes or rolls back the operations interrupted by restart, according to
+// the mount table and the lvs on node
+func (ns *nodeServer) reconcileVolumes(ctx context.Context) {
+	for _, state := range ns.volumeStore.List() {
+		if err := ns.reconcileVolume(ctx, state); err != nil {
+			log.Errorf("reconcileVolumes: reconcile %s volume %s error: %s", state.Phase, state.VolumeID, err.Error())
+		}
+	}
+}
+
+func (ns *nodeServer) reconcileVolume(ctx context.Context, state VolumeState) error {
+	mounted := state.TargetPath != "" && ns.isMountPoint(state.TargetPath)
+	switch state.Phase {
+	case VolumePublishing:
+		if mounted || state.Device == "" {
+			// kubelet retries publishing the volume if it is not mounted
+			break
+		}
+		log.Infof("reconcileVolumes: roll back ephemeral volume %s which is not published", state.VolumeID)
+		if err := removeEphemeralLV(state.Device); err != nil {
+			return err
+		}
+		return ns.volumeStore.Delete(state.VolumeID)
+	case VolumeExpanding:
+		if mounted {
+			log.Infof("reconcileVolumes: expand volume %s at %s again", state.VolumeID, state.TargetPath)
+			if err := ns.resizeVolume(ctx, state.VolumeID, state.TargetPath, state.Size); err != nil {
+				return err
+			}
+		}
+	case VolumeDeleting:
+		log.Infof("reconcileVolumes: finish deleting ephemeral volume %s", state.VolumeID)
+		if mounted {
+			if err := ns.k8smounter.Unmount(state.TargetPath); err != nil {
+				return err
+			}
+		}
+		if err := removeEphemeralLV(state.Device); err != nil {
+			return err
+		}
+		return ns.volumeStore.Delete(state.VolumeID)
+	case VolumeCreated:
+		if state.Device == "" {
+			return ns.volumeStore.Delete(state.VolumeID)
+		}
+		if _, err := os.Stat(state.Device); os.IsNotExist(err) {
+			log.Infof("reconcileVolumes: lv %s of ephemeral volume %s is removed", state.Device, state.VolumeID)
+			return ns.volumeStore.Delete(state.VolumeID)
+		}
+		return nil
+	default:
+		return ns.volumeStore.Delete(state.VolumeID)
+	}
+
+	if state.Device != "" {
+		state.Phase = VolumeCreated
+		return ns.volumeStore.Put(state)
+	}
+	return ns.volumeStore.Delete(state.VolumeID)
+}
+
+func (ns *nodeServer) isMountPoint(path string) bool {
+	notMnt, err := ns.k8smounter.IsLikelyNotMountPoint(path)
+	return err == nil && !notMnt
+}
+
+// removeEphemeralLV removes the lv of ephemeral volume if it exists
+func removeEphemeralLV(device string) error {
+	if device == "" {
+		return nil
+	}
+	if _, err := os.Stat(device); os.IsNotExist(err) {
+		return nil
+	}
+	// /dev/mapper/yoda--pool0-yoda--5c523416--7288--4138--95e0--f9392995959f
+	return removeLVMByDevicePath(device)
+}
+
+// reconcileVolumes finishes or rolls back the volumes which were being created or deleted
+// when controller restarted. The lvs being cloned or restored are tagged on node, and
+// canceled along with the lvs if the pvc is deleted before provisioned.
+func (cs *controllerServer) reconcileVolumes(ctx context.Context) {
+	for _, state := range cs.volumeStore.List() {
+		if err := cs.reconcileVolume(ctx, state); err != nil {
+			log.Errorf("reconcileVolumes: reconcile %s volume %s error: %s", state.Phase, state.VolumeID, err.Error())
+		}
+	}
+}
+
+func (cs *controllerServer) reconcileVolume(ctx context.Context, state VolumeState) error {
+	pvExists, err := objectExists(cs.client.CoreV1().PersistentVolumes().Get(ctx, state.VolumeID, metav1.GetOptions{}))
+	if err != nil {
+		return err
+	}
+	switch state.Phase {
+	case VolumeCreating, VolumeCreated:
+		if pvExists {
+			// provisioned already, the cached volume is not needed
+			return cs.volumeStore.Delete(state.VolumeID)
+		}
+		pvcExists := false
+		if state.PVCName != "" {
+			pvcExists, err = objectExists(cs.client.CoreV1().PersistentVolumeClaims(state.PVCNamespace).Get(ctx, state.PVCName, metav1.GetOptions{}))
+			if err != nil {
+				return err
+			}
+		}
+		if pvcExists {
+			// provisioner retries CreateVolume, and DeleteVolume removes the lv being created
+			// according to the store if pvc is deleted before that
+			return nil
+		}
+		if state.Phase == VolumeCreated {
+			// the pv may be retained and deleted manually, so the lv is left to user
+			return cs.volumeStore.Delete(state.VolumeID)
+		}
+		log.Infof("reconcileVolumes: roll back volume %s/%s at node %s whose pvc is deleted", state.VgName, state.VolumeID, state.NodeName)
+		return cs.removeLV(ctx, state, true)
+	case VolumeDeleting:
+		if pvExists {
+			// provisioner retries DeleteVolume
+			return nil
+		}
+		log.Infof("reconcileVolumes: finish deleting volume %s/%s at node %s", state.VgName, state.VolumeID, state.NodeName)
+		return cs.removeLV(ctx, state, false)
+	default:
+		return cs.volumeStore.Delete(state.VolumeID)
+	}
+}
+
+// removeLV removes the lv of volume on node and then the volume from store
+func (cs *controllerServer) removeLV(ctx context.Context, state VolumeState, cancelClone bool) error {
+	if state.NodeName != "" && state.VgName != "" {
+		conn, err := cs.getNodeConn(state.NodeName)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		if cancelClone {
+			if err := conn.CancelClone(ctx, state.VgName, state.VolumeID); err != nil {
+				return err
+			}
+		}
+		lvmName, err := conn.GetLvm(ctx, state.VgName, state.VolumeID)
+		if err != nil && !strings.Contains(err.Error(), "Failed to find logical volume") {
+			return err
+		}
+		if lvmName != "" {
+			if err := conn.DeleteLvm(ctx, state.VgName, state.VolumeID); err != nil {
+				return err
+			}
+		}
+	}
+	return cs.volumeStore.Delete(state.VolumeID)
+}
+
+// objectExists checks whether the object got from apiserver exists
+func objectExists(_ interface{}, err error) (bool, error) {
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
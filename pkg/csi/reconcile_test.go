@@ -0,0 +1,103 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/net/context"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8smount "k8s.io/utils/mount"
+)
+
+func TestNodeReconcileVolumes(t *testing.T) {
+	dir := t.TempDir()
+	mounted, unmounted, deleting := filepath.Join(dir, "mounted"), filepath.Join(dir, "unmounted"), filepath.Join(dir, "deleting")
+	for _, path := range []string{mounted, unmounted, deleting} {
+		if err := os.MkdirAll(path, 0750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mounter := k8smount.NewFakeMounter([]k8smount.MountPoint{
+		{Device: "/dev/share/pv-1", Path: mounted},
+		{Device: "/dev/share/pv-2", Path: deleting},
+	})
+	store, err := NewVolumeStore(filepath.Join(dir, "volumes.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ephemeral := filepath.Join(dir, "removed-lv")
+	states := []VolumeState{
+		{VolumeID: "published", Phase: VolumePublishing, TargetPath: mounted},
+		{VolumeID: "publishing", Phase: VolumePublishing, TargetPath: unmounted},
+		{VolumeID: "ephemeral-published", Phase: VolumePublishing, TargetPath: mounted, Device: ephemeral},
+		{VolumeID: "ephemeral-publishing", Phase: VolumePublishing, TargetPath: unmounted, Device: ephemeral},
+		{VolumeID: "ephemeral-removed", Phase: VolumeCreated, Device: ephemeral},
+		{VolumeID: "deleting", Phase: VolumeDeleting, TargetPath: deleting, Device: ephemeral},
+	}
+	for _, state := range states {
+		if err := store.Put(state); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ns := &nodeServer{k8smounter: mounter, volumeStore: store, inFlight: NewInFlight()}
+	ns.reconcileVolumes(context.Background())
+
+	got := store.List()
+	if len(got) != 1 || got[0].VolumeID != "ephemeral-published" || got[0].Phase != VolumeCreated {
+		t.Errorf("volumes after reconcile = %+v, want ephemeral-published Created", got)
+	}
+	if len(mounter.MountPoints) != 1 || mounter.MountPoints[0].Path != mounted {
+		t.Errorf("mount points after reconcile = %+v, want %s only", mounter.MountPoints, mounted)
+	}
+}
+
+func TestControllerReconcileVolumes(t *testing.T) {
+	client := k8sfake.NewSimpleClientset(
+		&v1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "provisioned"}},
+		&v1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "deleting"}},
+		&v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "pvc-cloning", Namespace: "default"}},
+	)
+	store := NewConfigMapStore(client, "kube-system", DefaultControllerVolumeStoreName)
+	states := []VolumeState{
+		{VolumeID: "provisioned", Phase: VolumeCreating, NodeName: "node-1", VgName: "share"},
+		{VolumeID: "cloning", Phase: VolumeCreating, NodeName: "node-1", VgName: "share", PVCName: "pvc-cloning", PVCNamespace: "default"},
+		{VolumeID: "created", Phase: VolumeCreated, NodeName: "node-1", VgName: "share", PVCName: "pvc-deleted", PVCNamespace: "default"},
+		{VolumeID: "deleting", Phase: VolumeDeleting, NodeName: "node-1", VgName: "share"},
+	}
+	for _, state := range states {
+		if err := store.Put(state); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cs := &controllerServer{client: client, volumeStore: store}
+	cs.reconcileVolumes(context.Background())
+
+	got := store.List()
+	if len(got) != 2 || got[0].VolumeID != "cloning" || got[1].VolumeID != "deleting" {
+		t.Errorf("volumes after reconcile = %+v, want cloning and deleting", got)
+	}
+	if cloning := got[0]; cloning.Phase != VolumeCreating || cloning.NodeName != "node-1" || cloning.VgName != "share" {
+		t.Errorf("cloning volume after reconcile = %+v, want Creating in share at node-1", cloning)
+	}
+}
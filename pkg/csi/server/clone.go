@@ -0,0 +1,197 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// CloningTagName is added to the dest lv until the block copy is finished,
+	// so that an interrupted clone can be found and restarted
+	CloningTagName = "cloning"
+	// cloneBufferSize is the size of each block copied
+	cloneBufferSize = 4 << 20
+)
+
+// ErrCloneCanceled is set as the error of a canceled clone task
+var ErrCloneCanceled = errors.New("clone is canceled")
+
+// devicePath returns the device path of lv, it is a variable for testing
+var devicePath = func(vg, name string) string {
+	return filepath.Join("/dev", vg, name)
+}
+
+// copyTask copies data to or from lv in background, such as clone, backup and restore
+type copyTask struct {
+	total  int64
+	copied int64
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+// Copied returns bytes copied so far
+func (t *copyTask) Copied() int64 {
+	return atomic.LoadInt64(&t.copied)
+}
+
+// SetTotal sets bytes to copy
+func (t *copyTask) SetTotal(total int64) {
+	atomic.StoreInt64(&t.total, total)
+}
+
+// Add adds n to bytes copied
+func (t *copyTask) Add(n int64) {
+	atomic.AddInt64(&t.copied, n)
+}
+
+// Total returns bytes to copy
+func (t *copyTask) Total() int64 {
+	return atomic.LoadInt64(&t.total)
+}
+
+// Done returns whether the task is finished, and its error if failed
+func (t *copyTask) Done() (bool, error) {
+	select {
+	case <-t.done:
+		return true, t.err
+	default:
+		return false, nil
+	}
+}
+
+type copyManager struct {
+	mu    sync.Mutex
+	tasks map[string]*copyTask
+}
+
+var (
+	// clones are tasks writing to lvs, keyed by vg/dest
+	clones = &copyManager{tasks: make(map[string]*copyTask)}
+	// backups are tasks uploading snapshots, keyed by vg/snapshot
+	backups = &copyManager{tasks: make(map[string]*copyTask)}
+)
+
+// Start starts copying src to dest of vg in background, onSuccess is called after the copy is done.
+// It does nothing if the clone is in progress or succeeded, a failed clone will be restarted
+func (m *copyManager) Start(vg, src, dest string, onSuccess func() error) *copyTask {
+	return m.Run(vg, dest, func(ctx context.Context, task *copyTask) error {
+		return copyDevice(ctx, devicePath(vg, src), devicePath(vg, dest), task)
+	}, onSuccess)
+}
+
+// Run runs copy of lv name in background, like Start
+func (m *copyManager) Run(vg, name string, copy func(ctx context.Context, task *copyTask) error, onSuccess func() error) *copyTask {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := vg + "/" + name
+	if task, ok := m.tasks[key]; ok {
+		if done, err := task.Done(); !done || err == nil {
+			return task
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	task := &copyTask{cancel: cancel, done: make(chan struct{})}
+	m.tasks[key] = task
+	go func() {
+		defer close(task.done)
+		if task.err = copy(ctx, task); task.err == nil && onSuccess != nil {
+			task.err = onSuccess()
+		}
+		if task.err != nil {
+			log.Errorf("copy task of %s failed: %s", key, task.err.Error())
+			return
+		}
+		log.Infof("copy task of %s is done, %d bytes copied", key, task.Copied())
+	}()
+	return task
+}
+
+// Get returns the clone task of dest lv, or nil if not found
+func (m *copyManager) Get(vg, dest string) *copyTask {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tasks[vg+"/"+dest]
+}
+
+// Cancel stops the clone task of dest lv and waits until it exits
+func (m *copyManager) Cancel(vg, dest string) bool {
+	m.mu.Lock()
+	task, ok := m.tasks[vg+"/"+dest]
+	delete(m.tasks, vg+"/"+dest)
+	m.mu.Unlock()
+	if !ok {
+		return false
+	}
+	task.cancel()
+	<-task.done
+	return true
+}
+
+// copyDevice copies the whole src device to dest device, the progress is recorded in task
+func copyDevice(ctx context.Context, src, dest string, task *copyTask) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dest, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	total, err := in.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := in.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	task.SetTotal(total)
+
+	buf := make([]byte, cloneBufferSize)
+	for {
+		if ctx.Err() != nil {
+			return ErrCloneCanceled
+		}
+		n, err := in.Read(buf)
+		if n > 0 {
+			if _, werr := out.Write(buf[:n]); werr != nil {
+				return fmt.Errorf("write %s: %s", dest, werr.Error())
+			}
+			task.Add(int64(n))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("read %s: %s", src, err.Error())
+		}
+	}
+	return out.Sync()
+}
@@ -0,0 +1,91 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alibaba/open-local/pkg/utils/lvm"
+	"golang.org/x/net/context"
+)
+
+func TestCloneLV(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	oldDevicePath := devicePath
+	defer func() { devicePath = oldDevicePath }()
+	devicePath = func(vg, name string) string {
+		return filepath.Join(dir, vg+"-"+name)
+	}
+	data := bytes.Repeat([]byte("open-local"), cloneBufferSize/5)
+	if err := ioutil.WriteFile(devicePath("share", "pv-1"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(devicePath("share", "pv-2"), make([]byte, len(data)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fake := lvm.NewFakeExecutor()
+	defer lvm.SetExecutor(lvm.SetExecutor(fake))
+	fake.Record(lvsOptions+"share/pv-2", `{"report": [{"lv": []}]}`)
+	fake.Record("vgs --reportformat=json --units=b --nosuffix --options=vg_name share", `{"report": [{"vg": [{"vg_name":"share"}]}]}`)
+	fake.Record("lvs --reportformat=json --units=b --nosuffix --options=lv_name,lv_size,vg_name,origin,pool_lv share", `{"report": [{"lv": [
+		{"lv_name":"pv-1", "vg_name":"share", "lv_size":"20971520", "origin":"", "pool_lv":""}
+	]}]}`)
+	fake.Record("lvcreate -n pv-2 -L 20971520b -W y -y --add-tag cloning share", "")
+	fake.Record("lvchange --deltag cloning share/pv-2", "")
+
+	if _, err := CloneLV(context.Background(), "share", "pv-1", "pv-2", 1<<20); err == nil {
+		t.Errorf("CloneLV() to smaller lv should fail")
+	}
+	if _, err := CloneLV(context.Background(), "share", "pv-1", "pv-2", 20971520); err != nil {
+		t.Fatalf("CloneLV() error = %v", err)
+	}
+	task := clones.Get("share", "pv-2")
+	if task == nil {
+		t.Fatalf("clone task of share/pv-2 is not started")
+	}
+	<-task.done
+	progress, err := GetCloneLVProgress(context.Background(), "share", "pv-2")
+	if err != nil {
+		t.Fatalf("GetCloneLVProgress() error = %v", err)
+	}
+	if !progress.Done || progress.Error != "" || progress.CopiedBytes != uint64(len(data)) || progress.TotalBytes != uint64(len(data)) {
+		t.Errorf("GetCloneLVProgress() = %+v", progress)
+	}
+	if copied, _ := ioutil.ReadFile(devicePath("share", "pv-2")); !bytes.Equal(copied, data) {
+		t.Errorf("data of clone is different from source")
+	}
+	// canceled copy stops with error
+	task = &copyTask{done: make(chan struct{})}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := copyDevice(ctx, devicePath("share", "pv-1"), devicePath("share", "pv-2"), task); !errors.Is(err, ErrCloneCanceled) {
+		t.Errorf("copyDevice() error = %v, want %v", err, ErrCloneCanceled)
+	}
+	if _, err := CancelCloneLV(context.Background(), "share", "pv-2"); err != nil || clones.Get("share", "pv-2") != nil {
+		t.Errorf("CancelCloneLV() error = %v", err)
+	}
+}
@@ -31,8 +31,11 @@ import (
 	"unicode"
 
 	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/backup"
 	"github.com/alibaba/open-local/pkg/csi/lib"
 	"github.com/alibaba/open-local/pkg/utils"
+	"github.com/alibaba/open-local/pkg/utils/lvm"
+	log "github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
 )
 
@@ -45,39 +48,29 @@ const (
 
 // ListLV lists lvm volumes
 func ListLV(listspec string) ([]*lib.LV, error) {
-	lvs := []*lib.LV{}
-	cmdList := []string{localtype.NsenterCmd, "lvs", "--units=b", fmt.Sprintf("--separator=\"%s\"", localtype.Separator), "--nosuffix", "--noheadings",
-		"-o", "lv_name,lv_size,lv_uuid,lv_attr,copy_percent,lv_kernel_major,lv_kernel_minor,lv_tags", "--nameprefixes", "-a", listspec}
-	cmd := strings.Join(cmdList, " ")
-	out, err := utils.Run(cmd)
+	out, err := lvm.RunReport("lvs", "-o", "lv_name,lv_size,lv_uuid,lv_attr,copy_percent,lv_kernel_major,lv_kernel_minor,lv_tags", "-a", listspec)
 	if err != nil {
-		if strings.Contains(err.Error(), "Failed to find logical volume") {
-			return lvs, nil
+		if errors.Is(err, lvm.ErrLogicalVolumeNotFound) {
+			return []*lib.LV{}, nil
 		}
 		return nil, err
 	}
-	outStr := strings.TrimSpace(string(out))
-	outLines := strings.Split(outStr, "\n")
-	for _, line := range outLines {
-		line = strings.TrimSpace(line)
-		if !strings.Contains(line, "LVM2_LV_NAME") {
-			continue
-		}
-		lv, err := lib.ParseLV(line)
-		if err != nil {
-			return nil, errors.New("Parse LVM: " + line + ", with error: " + err.Error())
-		}
-		lvs = append(lvs, lv)
+	lvs, err := lib.ParseLVReport(out)
+	if err != nil {
+		return nil, fmt.Errorf("parse lvs report of %s with error: %s", listspec, err.Error())
 	}
 	return lvs, nil
 }
 
 // CreateLV creates a new volume
-func CreateLV(ctx context.Context, vg string, name string, size uint64, mirrors uint32, tags []string, striping bool) (string, error) {
+func CreateLV(ctx context.Context, vg string, name string, size uint64, mirrors uint32, tags []string, striping bool, thin bool) (string, error) {
 	if size == 0 {
 		return "", errors.New("size must be greater than 0")
 	}
-	args := []string{localtype.NsenterCmd, "lvcreate", "-n", name, "-L", fmt.Sprintf("%db", size), "-W", "y", "-y"}
+	if thin {
+		return createThinLV(vg, name, size, tags)
+	}
+	args := []string{"-n", name, "-L", fmt.Sprintf("%db", size), "-W", "y", "-y"}
 	if mirrors > 0 {
 		args = append(args, "-m", fmt.Sprintf("%d", mirrors), "--nosync")
 	}
@@ -96,9 +89,25 @@ func CreateLV(ctx context.Context, vg string, name string, size uint64, mirrors
 	}
 
 	args = append(args, vg)
-	cmd := strings.Join(args, " ")
-	out, err := utils.Run(cmd)
-	return string(out), err
+	return lvm.Run("lvcreate", args...)
+}
+
+// createThinLV creates a thin volume with virtual size in the thin pool of vg
+func createThinLV(vg string, name string, size uint64, tags []string) (string, error) {
+	volumeGroup, err := lvm.LookupVolumeGroup(vg)
+	if err != nil {
+		return "", err
+	}
+	pool, err := volumeGroup.LookupThinPool()
+	if err != nil {
+		return "", err
+	}
+	args := []string{"-n", name, "-V", fmt.Sprintf("%db", size), "--thinpool", pool.Name(), "-y"}
+	for _, tag := range tags {
+		args = append(args, "--add-tag", tag)
+	}
+	args = append(args, vg)
+	return lvm.Run("lvcreate", args...)
 }
 
 func getRequiredPVNumber(vgName string, lvSize uint64) (int, error) {
@@ -138,100 +147,325 @@ func RemoveLV(ctx context.Context, vg string, name string) (string, error) {
 	if len(lvs) != 1 {
 		return "", fmt.Errorf("expected 1 LV, got %d", len(lvs))
 	}
-	for _, tag := range lvs[0].Tags {
-		if tag == ProtectedTagName {
-			return "", errors.New("volume is protected")
+	if hasTag(lvs[0], ProtectedTagName) {
+		return "", errors.New("volume is protected")
+	}
+	// stop writing to the volume if it is being cloned
+	clones.Cancel(vg, name)
+
+	return lvm.Run("lvremove", "-v", "-f", fmt.Sprintf("%s/%s", vg, name))
+}
+
+// CloneLV clones src to dest in vg. Clone of thin volume is a thin snapshot which is done
+// immediately, otherwise dest is created with size and block copied from src in background
+func CloneLV(ctx context.Context, vg, src, dest string, size uint64) (string, error) {
+	if task := clones.Get(vg, dest); task != nil {
+		if done, err := task.Done(); !done || err == nil {
+			return "lvm " + vg + "/" + dest + " is being cloned", nil
 		}
 	}
+	lvs, err := ListLV(fmt.Sprintf("%s/%s", vg, dest))
+	if err != nil {
+		return "", fmt.Errorf("failed to list LVs: %v", err)
+	}
+	if len(lvs) == 1 && !hasTag(lvs[0], CloningTagName) {
+		return "lvm " + vg + "/" + dest + " is already cloned", nil
+	}
 
-	args := []string{localtype.NsenterCmd, "lvremove", "-v", "-f", fmt.Sprintf("%s/%s", vg, name)}
-	cmd := strings.Join(args, " ")
-	out, err := utils.Run(cmd)
+	volumeGroup, err := lvm.LookupVolumeGroup(vg)
+	if err != nil {
+		return "", err
+	}
+	source, err := volumeGroup.LookupLogicalVolume(src)
+	if err != nil {
+		return "", err
+	}
+	if size < source.SizeInBytes() {
+		return "", fmt.Errorf("size %d is smaller than source lv %s/%s(%d)", size, vg, src, source.SizeInBytes())
+	}
+	if source.IsThin() {
+		out, err := CreateSnapshot(ctx, vg, dest, src, 0)
+		if err != nil {
+			return "", err
+		}
+		if size > source.SizeInBytes() {
+			return ExpandLV(ctx, vg, dest, size)
+		}
+		return out, nil
+	}
+	if len(lvs) == 0 {
+		if _, err := CreateLV(ctx, vg, dest, size, 0, []string{CloningTagName}, false, false); err != nil {
+			return "", err
+		}
+	}
+	clones.Start(vg, src, dest, func() error {
+		_, err := lvm.Run("lvchange", "--deltag", CloningTagName, fmt.Sprintf("%s/%s", vg, dest))
+		return err
+	})
+	return "lvm " + vg + "/" + dest + " starts cloning from " + src, nil
+}
 
-	return string(out), err
+// GetCloneLVProgress returns the copy progress of dest
+func GetCloneLVProgress(ctx context.Context, vg, dest string) (*lib.GetCloneLVProgressReply, error) {
+	if task := clones.Get(vg, dest); task != nil {
+		reply := &lib.GetCloneLVProgressReply{CopiedBytes: uint64(task.Copied()), TotalBytes: uint64(task.Total())}
+		var err error
+		if reply.Done, err = task.Done(); err != nil {
+			reply.Error = err.Error()
+		}
+		return reply, nil
+	}
+	lvs, err := ListLV(fmt.Sprintf("%s/%s", vg, dest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list LVs: %v", err)
+	}
+	if len(lvs) != 1 {
+		return nil, lvm.ErrLogicalVolumeNotFound
+	}
+	// clone task is lost after restarting, it will be restarted by CloneLV
+	if hasTag(lvs[0], CloningTagName) {
+		return &lib.GetCloneLVProgressReply{TotalBytes: lvs[0].Size, Error: "clone is interrupted"}, nil
+	}
+	return &lib.GetCloneLVProgressReply{CopiedBytes: lvs[0].Size, TotalBytes: lvs[0].Size, Done: true}, nil
 }
 
-// CloneLV clones a volume via dd
-func CloneLV(ctx context.Context, src, dest string) (string, error) {
-	// FIXME(farcaller): bloody insecure. And broken.
+// CancelCloneLV stops copying to dest
+func CancelCloneLV(ctx context.Context, vg, dest string) (string, error) {
+	if !clones.Cancel(vg, dest) {
+		return "no clone task of " + vg + "/" + dest, nil
+	}
+	return "clone task of " + vg + "/" + dest + " is canceled", nil
+}
 
-	args := []string{localtype.NsenterCmd, "dd", fmt.Sprintf("if=%s", src), fmt.Sprintf("of=%s", dest), "bs=4M"}
-	cmd := strings.Join(args, " ")
-	out, err := utils.Run(cmd)
+// BackupSnapshot starts uploading snapshot to the backup store, and returns the progress.
+// A failed backup is reported once, and restarted by the next call
+func BackupSnapshot(ctx context.Context, vg, snapshot string, store *lib.BackupStore) (*lib.BackupSnapshotReply, error) {
+	if task := backups.Get(vg, snapshot); task != nil {
+		reply := &lib.BackupSnapshotReply{CopiedBytes: uint64(task.Copied()), TotalBytes: uint64(task.Total())}
+		done, err := task.Done()
+		if !done || err == nil {
+			reply.Done = done
+			return reply, nil
+		}
+		backups.Cancel(vg, snapshot)
+		reply.Error = err.Error()
+		return reply, nil
+	}
 
-	return string(out), err
+	objectStore, err := newBackupStore(store)
+	if err != nil {
+		return nil, err
+	}
+	volumeGroup, err := lvm.LookupVolumeGroup(vg)
+	if err != nil {
+		return nil, err
+	}
+	lv, err := volumeGroup.LookupLogicalVolume(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	// backup task is lost after restarting, the index is saved when the backup is done
+	exists, err := backup.HasIndex(ctx, objectStore, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("check backup %s with error: %s", snapshot, err.Error())
+	}
+	if exists {
+		return &lib.BackupSnapshotReply{CopiedBytes: lv.SizeInBytes(), TotalBytes: lv.SizeInBytes(), Done: true}, nil
+	}
+	path, err := lv.Path()
+	if err != nil {
+		return nil, err
+	}
+	task := backups.Run(vg, snapshot, func(ctx context.Context, task *copyTask) error {
+		parent, changed, err := findBackupParent(ctx, objectStore, volumeGroup, lv)
+		if err != nil {
+			return err
+		}
+		if parent == "" {
+			_, err = backup.Backup(ctx, objectStore, path, snapshot, task)
+			return err
+		}
+		if changed != nil {
+			log.Infof("backup %s/%s incrementally from %s, %d extents changed", vg, snapshot, parent, len(changed))
+		} else {
+			log.Infof("backup %s/%s incrementally from %s by comparing chunks", vg, snapshot, parent)
+		}
+		_, err = backup.BackupIncremental(ctx, objectStore, path, snapshot, parent, changed, task)
+		return err
+	}, nil)
+	return &lib.BackupSnapshotReply{CopiedBytes: uint64(task.Copied()), TotalBytes: uint64(task.Total())}, nil
 }
 
-// ExpandLV expand a volume
-func ExpandLV(ctx context.Context, vgName string, volumeId string, expectSize uint64) (string, error) {
-	// resize lvm volume
-	// lvextend -L3G /dev/vgtest/lvm-5db74864-ea6b-11e9-a442-00163e07fb69
-	resizeCmd := fmt.Sprintf("%s lvextend -L%dB %s/%s", localtype.NsenterCmd, expectSize, vgName, volumeId)
-	out, err := utils.Run(resizeCmd)
+// RestoreLV creates lv name and starts writing the backup to it. The progress is
+// reported by GetCloneLVProgress, and it is canceled by CancelCloneLV
+func RestoreLV(ctx context.Context, vg, name string, size uint64, backupName string, store *lib.BackupStore, thin bool) (string, error) {
+	if task := clones.Get(vg, name); task != nil {
+		if done, err := task.Done(); !done || err == nil {
+			return "lvm " + vg + "/" + name + " is being restored", nil
+		}
+	}
+	lvs, err := ListLV(fmt.Sprintf("%s/%s", vg, name))
+	if err != nil {
+		return "", fmt.Errorf("failed to list LVs: %v", err)
+	}
+	if len(lvs) == 1 && !hasTag(lvs[0], CloningTagName) {
+		return "lvm " + vg + "/" + name + " is already restored", nil
+	}
+
+	objectStore, err := newBackupStore(store)
 	if err != nil {
 		return "", err
 	}
+	index, err := backup.GetIndex(ctx, objectStore, backupName)
+	if err != nil {
+		return "", fmt.Errorf("get backup %s with error: %s", backupName, err.Error())
+	}
+	if size < uint64(index.Size) {
+		return "", fmt.Errorf("size %d is smaller than backup %s(%d)", size, backupName, index.Size)
+	}
+	if len(lvs) == 0 {
+		if _, err := CreateLV(ctx, vg, name, size, 0, []string{CloningTagName}, false, thin); err != nil {
+			return "", err
+		}
+	}
+	volumeGroup, err := lvm.LookupVolumeGroup(vg)
+	if err != nil {
+		return "", err
+	}
+	lv, err := volumeGroup.LookupLogicalVolume(name)
+	if err != nil {
+		return "", err
+	}
+	path, err := lv.Path()
+	if err != nil {
+		return "", err
+	}
+	clones.Run(vg, name, func(ctx context.Context, task *copyTask) error {
+		return backup.Restore(ctx, objectStore, backupName, path, thin, task)
+	}, func() error {
+		_, err := lvm.Run("lvchange", "--deltag", CloningTagName, fmt.Sprintf("%s/%s", vg, name))
+		return err
+	})
+	return "lvm " + vg + "/" + name + " starts restoring from backup " + backupName, nil
+}
 
-	return string(out), err
+// findBackupParent returns the latest snapshot of the same origin which is older than
+// snapshot and already backed up. For thin snapshots the changed extents between them
+// are returned as well, otherwise changed chunks are found by comparing hashes.
+func findBackupParent(ctx context.Context, store backup.ObjectStore, vg *lvm.VolumeGroup, snapshot *lvm.LogicalVolume) (string, []backup.Extent, error) {
+	if !snapshot.IsSnapshot() {
+		return "", nil, nil
+	}
+	snapshots, err := vg.ListSnapshots(snapshot.OriginLVName())
+	if err != nil {
+		return "", nil, err
+	}
+	var older []*lvm.LogicalVolume
+	found := false
+	for i, lv := range snapshots {
+		if lv.Name() == snapshot.Name() {
+			older, found = snapshots[:i], true
+			break
+		}
+	}
+	if !found {
+		// the order of snapshot is unknown, a newer snapshot must not be taken as parent
+		log.Warningf("snapshot %s is not found in snapshots of %s, back up it fully", snapshot.Name(), snapshot.OriginLVName())
+		return "", nil, nil
+	}
+	for i := len(older) - 1; i >= 0; i-- {
+		parent := older[i]
+		exists, err := backup.HasIndex(ctx, store, parent.Name())
+		if err != nil {
+			return "", nil, fmt.Errorf("check backup %s with error: %s", parent.Name(), err.Error())
+		}
+		if !exists {
+			continue
+		}
+		if !snapshot.IsThin() || !parent.IsThin() {
+			return parent.Name(), nil, nil
+		}
+		extents, err := vg.ThinDelta(parent.Name(), snapshot.Name())
+		if err != nil {
+			log.Warningf("get thin delta between %s and %s failed, fall back to comparing chunks: %s", parent.Name(), snapshot.Name(), err.Error())
+			return parent.Name(), nil, nil
+		}
+		changed := make([]backup.Extent, 0, len(extents))
+		for _, e := range extents {
+			changed = append(changed, backup.Extent{Offset: int64(e.Offset), Length: int64(e.Length)})
+		}
+		return parent.Name(), changed, nil
+	}
+	return "", nil, nil
+}
+
+func newBackupStore(store *lib.BackupStore) (backup.ObjectStore, error) {
+	if store == nil {
+		return nil, errors.New("backup store is not set")
+	}
+	return backup.NewS3Store(backup.S3Config{
+		Endpoint:        store.Endpoint,
+		Region:          store.Region,
+		Bucket:          store.Bucket,
+		AccessKeyID:     store.AccessKeyId,
+		SecretAccessKey: store.SecretAccessKey,
+	})
+}
+
+func hasTag(lv *lib.LV, tag string) bool {
+	for _, t := range lv.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpandLV expand a volume
+func ExpandLV(ctx context.Context, vgName string, volumeId string, expectSize uint64) (string, error) {
+	// resize lvm volume
+	// lvextend -L3G /dev/vgtest/lvm-5db74864-ea6b-11e9-a442-00163e07fb69
+	return lvm.Run("lvextend", fmt.Sprintf("-L%dB", expectSize), fmt.Sprintf("%s/%s", vgName, volumeId))
 }
 
 // ListVG get vg info
 func ListVG() ([]*lib.VG, error) {
-	args := []string{localtype.NsenterCmd, "vgs", "--units=b", fmt.Sprintf("--separator=\"%s\"", localtype.Separator), "--nosuffix", "--noheadings",
-		"-o", "vg_name,vg_size,vg_free,vg_uuid,vg_tags,pv_count", "--nameprefixes", "-a"}
-	cmd := strings.Join(args, " ")
-	out, err := utils.Run(cmd)
+	out, err := lvm.RunReport("vgs", "-o", "vg_name,vg_size,vg_free,vg_uuid,vg_tags,pv_count", "-a")
 	if err != nil {
 		return nil, err
 	}
-	outStr := strings.TrimSpace(string(out))
-	outLines := strings.Split(outStr, "\n")
-	vgs := make([]*lib.VG, len(outLines))
-	for i, line := range outLines {
-		line = strings.TrimSpace(line)
-		vg, err := lib.ParseVG(line)
-		if err != nil {
-			return nil, err
-		}
-		vgs[i] = vg
-	}
-	return vgs, nil
+	return lib.ParseVGReport(out)
 }
 
 // ListPV get pv info in vg
 func ListPV(vgName string) ([]*lib.PV, error) {
-	args := []string{localtype.NsenterCmd, "pvs", "--units=b", fmt.Sprintf("--separator=\"%s\"", localtype.Separator), "--nosuffix", "--noheadings",
-		"-o", "pv_name,pv_size,pv_free,pv_uuid,pv_tags,vg_name", "-S", fmt.Sprintf("%s=%s", "vg_name", vgName), "--nameprefixes", "-a"}
-	cmd := strings.Join(args, " ")
-	out, err := utils.Run(cmd)
+	out, err := lvm.RunReport("pvs", "-o", "pv_name,pv_size,pv_free,pv_uuid,pv_tags,vg_name", "-S", fmt.Sprintf("%s=%s", "vg_name", vgName), "-a")
 	if err != nil {
 		return nil, err
 	}
-	outStr := strings.TrimSpace(string(out))
-	outLines := strings.Split(outStr, "\n")
-	pvs := make([]*lib.PV, len(outLines))
-	for i, line := range outLines {
-		line = strings.TrimSpace(line)
-		pv, err := lib.ParsePV(line)
-		if err != nil {
-			return nil, err
-		}
-		pvs[i] = pv
-	}
-	return pvs, nil
+	return lib.ParsePVReport(out)
 }
 
-// CreateSnapshot creates a new volume snapshot
+// CreateSnapshot creates a new volume snapshot. The snapshot of thin volume is
+// a thin snapshot allocated from the same thin pool, so size is ignored
 func CreateSnapshot(ctx context.Context, vg string, snapshotName string, originLVName string, size uint64) (string, error) {
+	volumeGroup, err := lvm.LookupVolumeGroup(vg)
+	if err != nil {
+		return "", err
+	}
+	origin, err := volumeGroup.LookupLogicalVolume(originLVName)
+	if err != nil {
+		return "", err
+	}
+	if origin.IsThin() {
+		// thin snapshot is skipped in activation by default, which makes it unable to be mounted
+		return lvm.Run("lvcreate", "-s", "-n", snapshotName, "--setactivationskip", "n", "--activate", "y", fmt.Sprintf("%s/%s", vg, originLVName))
+	}
 	if size == 0 {
 		return "", errors.New("size must be greater than 0")
 	}
 
-	args := []string{localtype.NsenterCmd, "lvcreate", "-s", "-n", snapshotName, "-L", fmt.Sprintf("%db", size), fmt.Sprintf("%s/%s", vg, originLVName), "-y"}
-	cmd := strings.Join(args, " ")
-	out, err := utils.Run(cmd)
-
-	return string(out), err
+	return lvm.Run("lvcreate", "-s", "-n", snapshotName, "-L", fmt.Sprintf("%db", size), fmt.Sprintf("%s/%s", vg, originLVName), "-y")
 }
 
 // RemoveSnapshot removes a volume snapshot
@@ -246,24 +480,18 @@ func RemoveSnapshot(ctx context.Context, vg string, name string) (string, error)
 	if len(lvs) != 1 {
 		return "", fmt.Errorf("expected 1 LV, got %d", len(lvs))
 	}
+	backups.Cancel(vg, name)
 
-	args := []string{localtype.NsenterCmd, "lvremove", "-v", "-f", fmt.Sprintf("%s/%s", vg, name)}
-	cmd := strings.Join(args, " ")
-	out, err := utils.Run(cmd)
-
-	return string(out), err
+	return lvm.Run("lvremove", "-v", "-f", fmt.Sprintf("%s/%s", vg, name))
 }
 
 // CreateVG create volume group
 func CreateVG(ctx context.Context, name string, physicalVolume string, tags []string) (string, error) {
-	args := []string{localtype.NsenterCmd, "vgcreate", name, physicalVolume, "-v"}
+	args := []string{name, physicalVolume, "-v"}
 	for _, tag := range tags {
 		args = append(args, "--add-tag", tag)
 	}
-	cmd := strings.Join(args, " ")
-	out, err := utils.Run(cmd)
-
-	return string(out), err
+	return lvm.Run("vgcreate", args...)
 }
 
 // RemoveVG remove volume group
@@ -288,11 +516,7 @@ func RemoveVG(ctx context.Context, name string) (string, error) {
 		}
 	}
 
-	args := []string{localtype.NsenterCmd, "vgremove", "-v", "-f", name}
-	cmd := strings.Join(args, " ")
-	out, err := utils.Run(cmd)
-
-	return string(out), err
+	return lvm.Run("vgremove", "-v", "-f", name)
 }
 
 // CleanPath deletes all the contents under the given directory
@@ -349,17 +573,11 @@ func AddTagLV(ctx context.Context, vg string, name string, tags []string) (strin
 	}
 
 	args := make([]string, 0)
-	args = append(args, localtype.NsenterCmd)
-	args = append(args, "lvchange")
 	for _, tag := range tags {
 		args = append(args, "--addtag", tag)
 	}
-
 	args = append(args, fmt.Sprintf("%s/%s", vg, name))
-	cmd := strings.Join(args, " ")
-	out, err := utils.Run(cmd)
-
-	return string(out), err
+	return lvm.Run("lvchange", args...)
 }
 
 // RemoveTagLV remove tag
@@ -374,16 +592,11 @@ func RemoveTagLV(ctx context.Context, vg string, name string, tags []string) (st
 	}
 
 	args := make([]string, 0)
-	args = append(args, localtype.NsenterCmd)
-	args = append(args, "lvchange")
 	for _, tag := range tags {
 		args = append(args, "--deltag", tag)
 	}
-
 	args = append(args, fmt.Sprintf("%s/%s", vg, name))
-	cmd := strings.Join(args, " ")
-	out, err := utils.Run(cmd)
-	return string(out), err
+	return lvm.Run("lvchange", args...)
 }
 
 // CreateNameSpace creates a new namespace
@@ -532,6 +745,37 @@ func SetSubpathProjQuota(ctx context.Context, projQuotaSubpath, blockHardlimit,
 	return "", err
 }
 
+// CreateProjQuotaSubpath creates the quota subpath under the mount point,
+// assigns the project ID of the subpath and sets the block hard limit(in KB)
+func CreateProjQuotaSubpath(ctx context.Context, mountPoint, subPath, blockHardlimit string) (string, error) {
+	fullPath := filepath.Join(mountPoint, subPath)
+	args := []string{localtype.NsenterCmd, "mkdir", "-p", fullPath}
+	cmd := strings.Join(args, " ")
+	if _, err := utils.Run(cmd); err != nil {
+		return "", fmt.Errorf("failed to create proj quota subpath %s with error: %v", fullPath, err)
+	}
+	if _, err := SetProjectID2PVSubpath(subPath, fullPath, utils.Run); err != nil {
+		return "", err
+	}
+	if _, err := SetSubpathProjQuota(ctx, fullPath, blockHardlimit, blockHardlimit); err != nil {
+		return "", err
+	}
+	return fullPath, nil
+}
+
+// ClearSubpathProjQuota drops the block limit of the project ID
+// which belongs to the quota subpath
+func ClearSubpathProjQuota(ctx context.Context, projQuotaSubpath string) (string, error) {
+	projectID := ConvertString2int(filepath.Base(projQuotaSubpath))
+	args := []string{localtype.NsenterCmd, "setquota", "-P", fmt.Sprintf("%s 0 0 0 0 %s", projectID, filepath.Dir(projQuotaSubpath))}
+	cmd := strings.Join(args, " ")
+	out, err := utils.Run(cmd)
+	if err != nil {
+		return "", fmt.Errorf("failed to clear quota of subpath with error: %v", err)
+	}
+	return out, nil
+}
+
 // RemoveProjQuotaSubpath ...
 func RemoveProjQuotaSubpath(ctx context.Context, quotaSubpath string) (string, error) {
 	args := []string{localtype.NsenterCmd, "rm", "-rf", quotaSubpath}
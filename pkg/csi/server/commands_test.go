@@ -0,0 +1,179 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"testing"
+
+	"github.com/alibaba/open-local/pkg/backup"
+	"github.com/alibaba/open-local/pkg/csi/lib"
+	"github.com/alibaba/open-local/pkg/utils/lvm"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const lvsOptions = "lvs --reportformat=json --units=b --nosuffix -o lv_name,lv_size,lv_uuid,lv_attr,copy_percent,lv_kernel_major,lv_kernel_minor,lv_tags -a "
+
+func TestListLV(t *testing.T) {
+	fake := lvm.NewFakeExecutor()
+	defer lvm.SetExecutor(lvm.SetExecutor(fake))
+	fake.Record(lvsOptions+"share", `{"report": [{"lv": [
+		{"lv_name":"pv-1", "lv_size":"1073741824", "lv_uuid":"uuid-1", "lv_attr":"-wi-ao----", "copy_percent":"", "lv_kernel_major":"253", "lv_kernel_minor":"0", "lv_tags":"protected"},
+		{"lv_name":"pv-2", "lv_size":"2147483648", "lv_uuid":"uuid-2", "lv_attr":"-wi-------", "copy_percent":"", "lv_kernel_major":"-1", "lv_kernel_minor":"-1", "lv_tags":""}
+	]}]}`)
+	fake.RecordError(lvsOptions+"share/pv-3", "  Failed to find logical volume \"share/pv-3\"")
+
+	lvs, err := ListLV("share")
+	if err != nil {
+		t.Fatalf("ListLV() error = %v", err)
+	}
+	if len(lvs) != 2 {
+		t.Fatalf("ListLV() got %d lvs, want 2", len(lvs))
+	}
+	if lvs[0].Name != "pv-1" || lvs[0].Size != 1073741824 || lvs[0].ActualDevMajNumber != 253 || lvs[0].Attributes.State != lib.VolumeStateActive {
+		t.Errorf("ListLV() got %+v", lvs[0])
+	}
+	if lvs[1].ActualDevMajNumber != 0 || lvs[1].Attributes.Open != lib.VolumeOpenIsNotOpen {
+		t.Errorf("ListLV() got %+v", lvs[1])
+	}
+
+	lvs, err = ListLV("share/pv-3")
+	if err != nil || len(lvs) != 0 {
+		t.Errorf("ListLV() of missing lv = %v, %v, want empty", lvs, err)
+	}
+}
+
+func TestServerRemoveLV(t *testing.T) {
+	fake := lvm.NewFakeExecutor()
+	defer lvm.SetExecutor(lvm.SetExecutor(fake))
+	fake.Record(lvsOptions+"share/pv-1", `{"report": [{"lv": [
+		{"lv_name":"pv-1", "lv_size":"1073741824", "lv_uuid":"uuid-1", "lv_attr":"-wi-a-----", "copy_percent":"", "lv_kernel_major":"253", "lv_kernel_minor":"0", "lv_tags":"protected"}
+	]}]}`)
+	fake.Record(lvsOptions+"share/pv-2", `{"report": [{"lv": [
+		{"lv_name":"pv-2", "lv_size":"1073741824", "lv_uuid":"uuid-2", "lv_attr":"-wi-a-----", "copy_percent":"", "lv_kernel_major":"253", "lv_kernel_minor":"1", "lv_tags":""}
+	]}]}`)
+	fake.RecordError("lvremove -v -f share/pv-2", "  Can't get lock for share.")
+
+	s := NewServer()
+	if _, err := s.RemoveLV(context.Background(), &lib.RemoveLVRequest{VolumeGroup: "share", Name: "pv-1"}); err == nil {
+		t.Errorf("RemoveLV() of protected lv should fail")
+	}
+	_, err := s.RemoveLV(context.Background(), &lib.RemoveLVRequest{VolumeGroup: "share", Name: "pv-2"})
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("RemoveLV() code = %v, want %v", status.Code(err), codes.Aborted)
+	}
+}
+
+func TestCreateSnapshot(t *testing.T) {
+	fake := lvm.NewFakeExecutor()
+	defer lvm.SetExecutor(lvm.SetExecutor(fake))
+	fake.Record("vgs --reportformat=json --units=b --nosuffix --options=vg_name share", `{"report": [{"vg": [{"vg_name":"share"}]}]}`)
+	fake.Record("lvs --reportformat=json --units=b --nosuffix --options=lv_name,lv_size,vg_name,origin,pool_lv share", `{"report": [{"lv": [
+		{"lv_name":"pv-1", "vg_name":"share", "lv_size":"1073741824", "origin":"", "pool_lv":"thinpool"},
+		{"lv_name":"pv-2", "vg_name":"share", "lv_size":"1073741824", "origin":"", "pool_lv":""}
+	]}]}`)
+	fake.Record("lvcreate -s -n snap-1 --setactivationskip n --activate y share/pv-1", "")
+	fake.Record("lvcreate -s -n snap-2 -L 4294967296b share/pv-2 -y", "")
+
+	if _, err := CreateSnapshot(context.Background(), "share", "snap-1", "pv-1", 4294967296); err != nil {
+		t.Errorf("CreateSnapshot() of thin volume error = %v", err)
+	}
+	if _, err := CreateSnapshot(context.Background(), "share", "snap-2", "pv-2", 4294967296); err != nil {
+		t.Errorf("CreateSnapshot() of thick volume error = %v", err)
+	}
+	if _, err := CreateSnapshot(context.Background(), "share", "snap-3", "pv-2", 0); err == nil {
+		t.Errorf("CreateSnapshot() of thick volume without size should fail")
+	}
+}
+
+// memStore is an in-memory backup.ObjectStore
+type memStore map[string][]byte
+
+func (m memStore) Put(ctx context.Context, key string, data []byte) error {
+	m[key] = data
+	return nil
+}
+
+func (m memStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, ok := m[key]
+	if !ok {
+		return nil, backup.ErrObjectNotFound
+	}
+	return data, nil
+}
+
+func (m memStore) Exists(ctx context.Context, key string) (bool, error) {
+	_, ok := m[key]
+	return ok, nil
+}
+
+func (m memStore) Delete(ctx context.Context, key string) error {
+	delete(m, key)
+	return nil
+}
+
+func TestFindBackupParent(t *testing.T) {
+	fake := lvm.NewFakeExecutor()
+	defer lvm.SetExecutor(lvm.SetExecutor(fake))
+	fake.Record("vgs --reportformat=json --units=b --nosuffix --options=vg_name share", `{"report": [{"vg": [{"vg_name":"share"}]}]}`)
+	fake.Record("lvs --reportformat=json --units=b --nosuffix --options=lv_name,lv_size,vg_name,origin,pool_lv share", `{"report": [{"lv": [
+		{"lv_name":"snap-3", "vg_name":"share", "lv_size":"1073741824", "origin":"pv-1", "pool_lv":"thinpool"},
+		{"lv_name":"snap-5", "vg_name":"share", "lv_size":"1073741824", "origin":"pv-2", "pool_lv":""},
+		{"lv_name":"snap-6", "vg_name":"share", "lv_size":"1073741824", "origin":"pv-1", "pool_lv":"thinpool"}
+	]}]}`)
+	fake.Record("lvs --reportformat=json --units=b --nosuffix --options=lv_name,lv_size,vg_name,origin,pool_lv,lv_time share", `{"report": [{"lv": [
+		{"lv_name":"snap-1", "vg_name":"share", "origin":"pv-1", "pool_lv":"thinpool", "lv_time":"2021-09-10 10:00:00 +0800"},
+		{"lv_name":"snap-3", "vg_name":"share", "origin":"pv-1", "pool_lv":"thinpool", "lv_time":"2021-09-10 12:00:00 +0800"},
+		{"lv_name":"snap-2", "vg_name":"share", "origin":"pv-1", "pool_lv":"thinpool", "lv_time":"2021-09-10 11:00:00 +0800"},
+		{"lv_name":"snap-4", "vg_name":"share", "origin":"pv-2", "pool_lv":"", "lv_time":"2021-09-10 10:00:00 +0800"},
+		{"lv_name":"snap-5", "vg_name":"share", "origin":"pv-2", "pool_lv":"", "lv_time":"2021-09-10 11:00:00 +0800"}
+	]}]}`)
+	fake.Record("lvs --reportformat=json --units=b --nosuffix --options=lv_name,vg_name,pool_lv,thin_id share", `{"report": [{"lv": [
+		{"lv_name":"snap-1", "vg_name":"share", "pool_lv":"thinpool", "thin_id":"2"},
+		{"lv_name":"snap-3", "vg_name":"share", "pool_lv":"thinpool", "thin_id":"4"}
+	]}]}`)
+	fake.Record("dmsetup message share-thinpool-tpool 0 reserve_metadata_snap", "")
+	fake.Record("dmsetup message share-thinpool-tpool 0 release_metadata_snap", "")
+	fake.Record("thin_delta --metadata-snap --snap1 2 --snap2 4 /dev/mapper/share-thinpool_tmeta",
+		`<superblock data_block_size="128"><diff left="2" right="4"><different begin="1" length="1"/></diff></superblock>`)
+
+	ctx := context.Background()
+	// snap-2 is not backed up, so snap-3 is based on snap-1
+	store := memStore{"indexes/snap-1.json": nil, "indexes/snap-4.json": nil}
+	vg, _ := lvm.LookupVolumeGroup("share")
+	snap3, _ := vg.LookupLogicalVolume("snap-3")
+	parent, changed, err := findBackupParent(ctx, store, vg, snap3)
+	if err != nil || parent != "snap-1" || len(changed) != 1 || changed[0] != (backup.Extent{Offset: 1 << 16, Length: 1 << 16}) {
+		t.Errorf("findBackupParent(snap-3) = %q, %v, %v", parent, changed, err)
+	}
+	// thick snapshots are compared by chunks
+	snap5, _ := vg.LookupLogicalVolume("snap-5")
+	parent, changed, err = findBackupParent(ctx, store, vg, snap5)
+	if err != nil || parent != "snap-4" || changed != nil {
+		t.Errorf("findBackupParent(snap-5) = %q, %v, %v", parent, changed, err)
+	}
+	delete(store, "indexes/snap-4.json")
+	if parent, _, _ = findBackupParent(ctx, store, vg, snap5); parent != "" {
+		t.Errorf("findBackupParent(snap-5) = %q, want full backup", parent)
+	}
+	// snap-6 is not listed, so no snapshot is known to be older than it
+	snap6, _ := vg.LookupLogicalVolume("snap-6")
+	if parent, changed, err = findBackupParent(ctx, store, vg, snap6); err != nil || parent != "" || changed != nil {
+		t.Errorf("findBackupParent(snap-6) = %q, %v, %v, want full backup", parent, changed, err)
+	}
+}
@@ -17,9 +17,11 @@ limitations under the License.
 package server
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/alibaba/open-local/pkg/csi/lib"
+	"github.com/alibaba/open-local/pkg/utils/lvm"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
@@ -56,10 +58,10 @@ func (s Server) ListLV(ctx context.Context, in *lib.ListLVRequest) (*lib.ListLVR
 // CreateLV create lvm volume
 func (s Server) CreateLV(ctx context.Context, in *lib.CreateLVRequest) (*lib.CreateLVReply, error) {
 	log.Debugf("Create LVM with: %+v", in)
-	out, err := CreateLV(ctx, in.VolumeGroup, in.Name, in.Size, in.Mirrors, in.Tags, in.Striping)
+	out, err := CreateLV(ctx, in.VolumeGroup, in.Name, in.Size, in.Mirrors, in.Tags, in.Striping, in.Thin)
 	if err != nil {
 		log.Errorf("Create LVM with error: %s", err.Error())
-		return nil, status.Errorf(codes.Internal, "failed to create lv: %v", err)
+		return nil, status.Errorf(lvmErrorCode(err), "failed to create lv: %v", err)
 	}
 	log.Debugf("Create LVM Successful with result: %+v", out)
 	return &lib.CreateLVReply{CommandOutput: out}, nil
@@ -71,7 +73,7 @@ func (s Server) RemoveLV(ctx context.Context, in *lib.RemoveLVRequest) (*lib.Rem
 	out, err := RemoveLV(ctx, in.VolumeGroup, in.Name)
 	if err != nil {
 		log.Errorf("Remove LVM with error: %s", err.Error())
-		return nil, status.Errorf(codes.Internal, "failed to remove lv: %v", err)
+		return nil, status.Errorf(lvmErrorCode(err), "failed to remove lv: %v", err)
 	}
 	log.Debugf("Remove LVM Successful with result: %+v", out)
 	return &lib.RemoveLVReply{CommandOutput: out}, nil
@@ -79,21 +81,65 @@ func (s Server) RemoveLV(ctx context.Context, in *lib.RemoveLVRequest) (*lib.Rem
 
 // CloneLV clone lvm volume
 func (s Server) CloneLV(ctx context.Context, in *lib.CloneLVRequest) (*lib.CloneLVReply, error) {
-	out, err := CloneLV(ctx, in.SourceName, in.DestName)
+	log.Debugf("Clone LVM with: %+v", in)
+	out, err := CloneLV(ctx, in.VolumeGroup, in.SourceName, in.DestName, in.Size)
 	if err != nil {
 		log.Errorf("Clone LVM with error: %s", err.Error())
-		return nil, status.Errorf(codes.Internal, "failed to clone lv: %v", err)
+		return nil, status.Errorf(lvmErrorCode(err), "failed to clone lv: %v", err)
 	}
 	log.Debugf("Clone LVM with result: %+v", out)
 	return &lib.CloneLVReply{CommandOutput: out}, nil
 }
 
+// GetCloneLVProgress get copy progress of cloning lvm volume
+func (s Server) GetCloneLVProgress(ctx context.Context, in *lib.GetCloneLVProgressRequest) (*lib.GetCloneLVProgressReply, error) {
+	reply, err := GetCloneLVProgress(ctx, in.VolumeGroup, in.DestName)
+	if err != nil {
+		log.Errorf("Get clone progress of LVM with error: %s", err.Error())
+		return nil, status.Errorf(lvmErrorCode(err), "failed to get clone progress of lv: %v", err)
+	}
+	return reply, nil
+}
+
+// CancelCloneLV cancel cloning lvm volume
+func (s Server) CancelCloneLV(ctx context.Context, in *lib.CancelCloneLVRequest) (*lib.CancelCloneLVReply, error) {
+	out, err := CancelCloneLV(ctx, in.VolumeGroup, in.DestName)
+	if err != nil {
+		log.Errorf("Cancel clone LVM with error: %s", err.Error())
+		return nil, status.Errorf(codes.Internal, "failed to cancel clone lv: %v", err)
+	}
+	log.Debugf("Cancel clone LVM with result: %+v", out)
+	return &lib.CancelCloneLVReply{CommandOutput: out}, nil
+}
+
+// BackupSnapshot upload lvm snapshot to backup store
+func (s Server) BackupSnapshot(ctx context.Context, in *lib.BackupSnapshotRequest) (*lib.BackupSnapshotReply, error) {
+	reply, err := BackupSnapshot(ctx, in.VolumeGroup, in.SnapshotName, in.Store)
+	if err != nil {
+		log.Errorf("Backup snapshot with error: %s", err.Error())
+		return nil, status.Errorf(lvmErrorCode(err), "failed to backup snapshot: %v", err)
+	}
+	log.Debugf("Backup snapshot with result: %+v", reply)
+	return reply, nil
+}
+
+// RestoreLV restore lvm volume from backup store
+func (s Server) RestoreLV(ctx context.Context, in *lib.RestoreLVRequest) (*lib.RestoreLVReply, error) {
+	out, err := RestoreLV(ctx, in.VolumeGroup, in.Name, in.Size, in.BackupName, in.Store, in.Thin)
+	if err != nil {
+		log.Errorf("Restore LVM with error: %s", err.Error())
+		return nil, status.Errorf(lvmErrorCode(err), "failed to restore lv: %v", err)
+	}
+	log.Debugf("Restore LVM with result: %+v", out)
+	return &lib.RestoreLVReply{CommandOutput: out}, nil
+}
+
 // ExpandLV expand lvm volume
 func (s Server) ExpandLV(ctx context.Context, in *lib.ExpandLVRequest) (*lib.ExpandLVReply, error) {
 	out, err := ExpandLV(ctx, in.VolumeGroup, in.Name, in.Size)
 	if err != nil {
 		log.Errorf("Expand LVM with error: %s", err.Error())
-		return nil, status.Errorf(codes.Internal, "failed to expand lv: %v", err)
+		return nil, status.Errorf(lvmErrorCode(err), "failed to expand lv: %v", err)
 	}
 	log.Debugf("Expand LVM with result: %+v", out)
 	return &lib.ExpandLVReply{CommandOutput: out}, nil
@@ -120,7 +166,7 @@ func (s Server) CreateSnapshot(ctx context.Context, in *lib.CreateSnapshotReques
 	log.Debugf("Create LVM Snapshot with: %+v", in)
 	out, err := CreateSnapshot(ctx, in.VolumeGroup, in.SnapName, in.LvName, in.Size)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "CreateSnapshot: create snapshot with error: %s", err.Error())
+		return nil, status.Errorf(lvmErrorCode(err), "CreateSnapshot: create snapshot with error: %s", err.Error())
 	}
 	log.Debugf("Create LVM Snapshot Successful with result: %+v", out)
 	return &lib.CreateSnapshotReply{CommandOutput: out}, nil
@@ -131,7 +177,7 @@ func (s Server) RemoveSnapshot(ctx context.Context, in *lib.RemoveSnapshotReques
 	log.Debugf("Remove LVM Snapshot with: %+v", in)
 	out, err := RemoveSnapshot(ctx, in.VolumeGroup, in.SnapName)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "RemoveSnapshot: remove snapshot with error: %s", err.Error())
+		return nil, status.Errorf(lvmErrorCode(err), "RemoveSnapshot: remove snapshot with error: %s", err.Error())
 	}
 	log.Debugf("Remove LVM Snapshot Successful with result: %+v", out)
 	return &lib.RemoveSnapshotReply{CommandOutput: out}, nil
@@ -142,7 +188,7 @@ func (s Server) CreateVG(ctx context.Context, in *lib.CreateVGRequest) (*lib.Cre
 	out, err := CreateVG(ctx, in.Name, in.PhysicalVolume, in.Tags)
 	if err != nil {
 		log.Errorf("Create VG with error: %s", err.Error())
-		return nil, status.Errorf(codes.Internal, "failed to create vg: %v", err)
+		return nil, status.Errorf(lvmErrorCode(err), "failed to create vg: %v", err)
 	}
 	log.Debugf("Create VG with result: %+v", out)
 	return &lib.CreateVGReply{CommandOutput: out}, nil
@@ -153,7 +199,7 @@ func (s Server) RemoveVG(ctx context.Context, in *lib.CreateVGRequest) (*lib.Rem
 	out, err := RemoveVG(ctx, in.Name)
 	if err != nil {
 		log.Errorf("Remove VG with error: %s", err.Error())
-		return nil, status.Errorf(codes.Internal, "failed to remove vg: %v", err)
+		return nil, status.Errorf(lvmErrorCode(err), "failed to remove vg: %v", err)
 	}
 	log.Debugf("Remove VG with result: %+v", out)
 	return &lib.RemoveVGReply{CommandOutput: out}, nil
@@ -181,11 +227,26 @@ func (s Server) CleanDevice(ctx context.Context, in *lib.CleanDeviceRequest) (*l
 	return &lib.CleanDeviceReply{CommandOutput: fmt.Sprintf("clean device %s successfully with output: %s", in.Device, out)}, nil
 }
 
+// RemoveQuota drops the project quota and removes the quota subpath
+func (s Server) RemoveQuota(ctx context.Context, in *lib.RemoveQuotaRequest) (*lib.RemoveQuotaReply, error) {
+	if _, err := ClearSubpathProjQuota(ctx, in.QuotaSubpath); err != nil {
+		log.Errorf("failed to clear quota of %s: %s", in.QuotaSubpath, err.Error())
+		return nil, status.Errorf(codes.Internal, "failed to clear quota of %s: %v", in.QuotaSubpath, err)
+	}
+	out, err := RemoveProjQuotaSubpath(ctx, in.QuotaSubpath)
+	if err != nil {
+		log.Errorf("failed to remove quota subpath %s: %s", in.QuotaSubpath, err.Error())
+		return nil, status.Errorf(codes.Internal, "failed to remove quota subpath %s: %v", in.QuotaSubpath, err)
+	}
+	log.Debugf("remove quota subpath %s successfully", in.QuotaSubpath)
+	return &lib.RemoveQuotaReply{CommandOutput: fmt.Sprintf("remove quota subpath %s successfully with output: %s", in.QuotaSubpath, out)}, nil
+}
+
 // AddTagLV add tag
 func (s Server) AddTagLV(ctx context.Context, in *lib.AddTagLVRequest) (*lib.AddTagLVReply, error) {
 	log, err := AddTagLV(ctx, in.VolumeGroup, in.Name, in.Tags)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to add tags to lv: %v", err)
+		return nil, status.Errorf(lvmErrorCode(err), "failed to add tags to lv: %v", err)
 	}
 	return &lib.AddTagLVReply{CommandOutput: log}, nil
 }
@@ -194,7 +255,22 @@ func (s Server) AddTagLV(ctx context.Context, in *lib.AddTagLVRequest) (*lib.Add
 func (s Server) RemoveTagLV(ctx context.Context, in *lib.RemoveTagLVRequest) (*lib.RemoveTagLVReply, error) {
 	log, err := RemoveTagLV(ctx, in.VolumeGroup, in.Name, in.Tags)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to remove tags from lv: %v", err)
+		return nil, status.Errorf(lvmErrorCode(err), "failed to remove tags from lv: %v", err)
 	}
 	return &lib.RemoveTagLVReply{CommandOutput: log}, nil
 }
+
+// lvmErrorCode maps the typed error of lvm command to grpc code
+func lvmErrorCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, lvm.ErrNoSpace):
+		return codes.ResourceExhausted
+	case errors.Is(err, lvm.ErrLocked):
+		return codes.Aborted
+	case errors.Is(err, lvm.ErrLogicalVolumeNotFound), errors.Is(err, lvm.ErrVolumeGroupNotFound), errors.Is(err, lvm.ErrPhysicalVolumeNotFound),
+		errors.Is(err, lvm.ErrThinPoolNotFound):
+		return codes.NotFound
+	default:
+		return codes.Internal
+	}
+}
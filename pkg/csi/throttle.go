@@ -0,0 +1,238 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/alibaba/open-local/pkg/utils/cgroup"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ioThrottleResyncInterval is the interval to check the io limits of published volumes
+const ioThrottleResyncInterval = 30 * time.Second
+
+// throttledVolume is a volume published with io limits
+type throttledVolume struct {
+	volumeID string
+	// volumeContext is used to re-read the io limits of storageclass and pvc annotations
+	volumeContext map[string]string
+	podUID        string
+	major         uint32
+	minor         uint32
+	limits        cgroup.Limits
+	// cgroupIno is the inode of the pod cgroup when the limits are set
+	cgroupIno uint64
+}
+
+// ioThrottler sets the io limits of volumes in the cgroups of pods, and re-applies
+// them when the cgroup of pod is recreated, e.g. by restarting kubelet
+type ioThrottler struct {
+	lock       sync.Mutex
+	cgroupRoot string
+	manager    *cgroup.Manager
+	client     kubernetes.Interface
+	// the map of target path and throttledVolume
+	volumes map[string]throttledVolume
+}
+
+func newIOThrottler(cgroupRoot string, client kubernetes.Interface) *ioThrottler {
+	return &ioThrottler{cgroupRoot: cgroupRoot, client: client, volumes: map[string]throttledVolume{}}
+}
+
+// PVCLimits overrides the io limits of storageclass with the annotations of pvc in volume context.
+// Invalid annotations are ignored, and limits are returned unchanged with error if the pvc fails to be got.
+func (t *ioThrottler) PVCLimits(ctx context.Context, volumeContext map[string]string, limits cgroup.Limits) (cgroup.Limits, error) {
+	pvcName, pvcNamespace := volumeContext[PvcNameTag], volumeContext[PvcNsTag]
+	if pvcName == "" {
+		return limits, nil
+	}
+	pvc, err := t.client.CoreV1().PersistentVolumeClaims(pvcNamespace).Get(ctx, pvcName, metav1.GetOptions{})
+	if err != nil {
+		return limits, fmt.Errorf("get pvc %s/%s: %s", pvcNamespace, pvcName, err.Error())
+	}
+	annotated := limits
+	if err := cgroup.ParseLimits(pvc.Annotations, cgroup.AnnotationLimitKeys, &annotated); err != nil {
+		log.Warningf("ioThrottler: ignore io limits in annotations of pvc %s/%s: %s", pvcNamespace, pvcName, err.Error())
+		return limits, nil
+	}
+	return annotated, nil
+}
+
+// Set sets the io limits of device for the pod which the volume is published to at targetPath
+func (t *ioThrottler) Set(volumeID, targetPath, device string, volumeContext map[string]string, limits cgroup.Limits) error {
+	podUID, err := podUIDFromTargetPath(targetPath)
+	if err != nil {
+		return err
+	}
+	major, minor, err := cgroup.DeviceNumber(device)
+	if err != nil {
+		return err
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	manager, err := t.getManager()
+	if err != nil {
+		return err
+	}
+	if err := manager.SetLimits(podUID, major, minor, limits); err != nil {
+		return fmt.Errorf("set io limits of %s for pod %s: %s", device, podUID, err.Error())
+	}
+	ino, err := podCgroupIno(manager, podUID)
+	if err != nil {
+		return err
+	}
+	t.volumes[targetPath] = throttledVolume{volumeID: volumeID, volumeContext: volumeContext, podUID: podUID, major: major, minor: minor, limits: limits, cgroupIno: ino}
+	log.Infof("ioThrottler: set io limits %+v of volume %s(%d:%d) for pod %s with cgroup %s/%s", limits, volumeID, major, minor, podUID, manager.Driver, versionName(manager.Version))
+	return nil
+}
+
+// Remove stops re-applying the io limits of the volume published at targetPath
+func (t *ioThrottler) Remove(targetPath string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.volumes, targetPath)
+}
+
+// Run re-applies the io limits periodically until stopCh is closed
+func (t *ioThrottler) Run(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(ioThrottleResyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			t.resync()
+		}
+	}
+}
+
+// resync re-applies the io limits which are lost in the recreated cgroups of pods
+func (t *ioThrottler) resync() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if len(t.volumes) == 0 {
+		return
+	}
+	manager, err := t.getManager()
+	if err != nil {
+		log.Errorf("ioThrottler: %s", err.Error())
+		return
+	}
+	for targetPath, volume := range t.volumes {
+		ino, err := podCgroupIno(manager, volume.podUID)
+		if errors.Is(err, os.ErrNotExist) {
+			// the cgroup is being recreated, or the pod is deleted and the volume will be unpublished
+			continue
+		}
+		if err != nil {
+			log.Errorf("ioThrottler: %s", err.Error())
+			continue
+		}
+		if ino == volume.cgroupIno {
+			// limits in the same cgroup may be updated by the annotations of pvc in agent
+			continue
+		}
+		// re-read the annotations of pvc, which may be changed since published and are applied by agent
+		// volume context is validated when published
+		limits, _ := parseIOLimits(volume.volumeContext)
+		limits, err = t.PVCLimits(context.Background(), volume.volumeContext, limits)
+		if err != nil {
+			// retry in next resync rather than overwriting the limits of agent
+			log.Errorf("ioThrottler: re-read io limits of volume %s: %s", volume.volumeID, err.Error())
+			continue
+		}
+		volume.limits = limits
+		if err := manager.SetLimits(volume.podUID, volume.major, volume.minor, volume.limits); err != nil {
+			log.Errorf("ioThrottler: re-apply io limits of volume %s for pod %s: %s", volume.volumeID, volume.podUID, err.Error())
+			continue
+		}
+		volume.cgroupIno = ino
+		t.volumes[targetPath] = volume
+		log.Infof("ioThrottler: re-applied io limits %+v of volume %s for pod %s", volume.limits, volume.volumeID, volume.podUID)
+	}
+}
+
+// podCgroupIno returns the inode of the pod cgroup, which changes when the cgroup is recreated
+func podCgroupIno(manager *cgroup.Manager, podUID string) (uint64, error) {
+	path, err := manager.PodPath(podUID)
+	if err != nil {
+		return 0, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, fmt.Errorf("failed to get inode of %s", path)
+	}
+	return stat.Ino, nil
+}
+
+// getManager detects the cgroup version and driver on first use
+func (t *ioThrottler) getManager() (*cgroup.Manager, error) {
+	if t.manager != nil {
+		return t.manager, nil
+	}
+	manager, err := cgroup.Detect(t.cgroupRoot)
+	if err != nil {
+		return nil, err
+	}
+	t.manager = manager
+	return manager, nil
+}
+
+// parseIOLimits gets the io limits from volume context. iops and bps limit both read and
+// write, and are overridden by readIOPS, writeIOPS, readBPS and writeBPS.
+func parseIOLimits(volumeContext map[string]string) (cgroup.Limits, error) {
+	limits := cgroup.Limits{}
+	err := cgroup.ParseLimits(volumeContext, cgroup.VolumeLimitKeys, &limits)
+	return limits, err
+}
+
+// podUIDFromTargetPath gets the pod uid from target path of volume, which is
+//
+//	/var/lib/kubelet/pods/<pod uid>/volumes/kubernetes.io~csi/<volume id>/mount for filesystem volume
+//	/var/lib/kubelet/plugins/kubernetes.io/csi/volumeDevices/publish/<volume id>/<pod uid> for block volume
+func podUIDFromTargetPath(targetPath string) (string, error) {
+	parts := strings.Split(filepath.Clean(targetPath), string(filepath.Separator))
+	for i, part := range parts {
+		if part == "pods" && i+2 < len(parts) && parts[i+2] == "volumes" {
+			return parts[i+1], nil
+		}
+		if part == "volumeDevices" && i+3 < len(parts) && parts[i+1] == "publish" {
+			return parts[i+3], nil
+		}
+	}
+	return "", fmt.Errorf("no pod uid found in target path %s", targetPath)
+}
+
+func versionName(version cgroup.Version) string {
+	return fmt.Sprintf("v%d", version)
+}
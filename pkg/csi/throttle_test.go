@@ -0,0 +1,134 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/utils/cgroup"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseIOLimits(t *testing.T) {
+	tests := []struct {
+		context map[string]string
+		limits  cgroup.Limits
+		err     bool
+	}{
+		{context: map[string]string{}},
+		{
+			context: map[string]string{"iops": "1000", "bps": "100Mi"},
+			limits:  cgroup.Limits{ReadIOPS: 1000, WriteIOPS: 1000, ReadBPS: 100 << 20, WriteBPS: 100 << 20},
+		},
+		{
+			context: map[string]string{"iops": "1000", "writeIOPS": "200", "readBPS": "1048576"},
+			limits:  cgroup.Limits{ReadIOPS: 1000, WriteIOPS: 200, ReadBPS: 1 << 20},
+		},
+		{context: map[string]string{"bps": "fast"}, err: true},
+		{context: map[string]string{"readIOPS": "-1"}, err: true},
+	}
+	for _, test := range tests {
+		limits, err := parseIOLimits(test.context)
+		if (err != nil) != test.err {
+			t.Errorf("parseIOLimits(%v) error = %v, want error %v", test.context, err, test.err)
+			continue
+		}
+		if !test.err && limits != test.limits {
+			t.Errorf("parseIOLimits(%v) = %+v, want %+v", test.context, limits, test.limits)
+		}
+	}
+}
+
+func TestPodUIDFromTargetPath(t *testing.T) {
+	tests := []struct {
+		targetPath string
+		podUID     string
+	}{
+		{"/var/lib/kubelet/pods/2a7bbb9c-c915-4006-84d7-0e3ac9d8d70f/volumes/kubernetes.io~csi/yoda-70597cb6/mount", "2a7bbb9c-c915-4006-84d7-0e3ac9d8d70f"},
+		{"/data/kubelet/pods/2a7bbb9c-c915-4006-84d7-0e3ac9d8d70f/volumes/kubernetes.io~csi/yoda-70597cb6/mount", "2a7bbb9c-c915-4006-84d7-0e3ac9d8d70f"},
+		{"/var/lib/kubelet/plugins/kubernetes.io/csi/volumeDevices/publish/yoda-c018ff81/76cf946e-d074-4455-a272-4d3a81264fab", "76cf946e-d074-4455-a272-4d3a81264fab"},
+		{"/mnt/yoda-70597cb6", ""},
+	}
+	for _, test := range tests {
+		podUID, err := podUIDFromTargetPath(test.targetPath)
+		if podUID != test.podUID || (err != nil) != (test.podUID == "") {
+			t.Errorf("podUIDFromTargetPath(%s) = %s, %v, want %s", test.targetPath, podUID, err, test.podUID)
+		}
+	}
+}
+
+func TestIOThrottlerResync(t *testing.T) {
+	root := t.TempDir()
+	podPath := filepath.Join(root, "kubepods", "burstable", "pod76cf946e")
+	newPod := func() {
+		if err := os.MkdirAll(podPath, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(podPath, "io.max"), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "cgroup.controllers"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	newPod()
+
+	// limits of pvc annotations applied by agent override storageclass
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+		Name:        "pvc-1",
+		Namespace:   "default",
+		Annotations: map[string]string{localtype.AnnIOPS: "500"},
+	}}
+	throttler := newIOThrottler(root, fake.NewSimpleClientset(pvc))
+	volumeContext := map[string]string{"iops": "100", PvcNameTag: "pvc-1", PvcNsTag: "default"}
+	throttler.volumes["/target"] = throttledVolume{volumeID: "yoda-1", volumeContext: volumeContext, podUID: "76cf946e", major: 253, minor: 1, limits: cgroup.Limits{ReadIOPS: 100, WriteIOPS: 100}}
+	limits := cgroup.Limits{ReadIOPS: 500, WriteIOPS: 500}
+	// pod cgroup is removed and recreated without limits
+	if err := os.RemoveAll(podPath); err != nil {
+		t.Fatal(err)
+	}
+	throttler.resync()
+	newPod()
+	throttler.resync()
+	got, err := throttler.manager.GetLimits("76cf946e", 253, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != limits {
+		t.Errorf("limits after resync = %+v, want %+v", got, limits)
+	}
+	// limits updated in the same cgroup are kept
+	updated := cgroup.Limits{ReadIOPS: 200}
+	if err := throttler.manager.SetLimits("76cf946e", 253, 1, updated); err != nil {
+		t.Fatal(err)
+	}
+	throttler.resync()
+	if got, _ = throttler.manager.GetLimits("76cf946e", 253, 1); got != updated {
+		t.Errorf("limits after update = %+v, want %+v", got, updated)
+	}
+
+	throttler.Remove("/target")
+	if len(throttler.volumes) != 0 {
+		t.Errorf("volumes after Remove() = %+v", throttler.volumes)
+	}
+}
@@ -22,9 +22,16 @@ import (
 )
 
 const (
-	DefaultEndpoint                    string = "unix://tmp/csi.sock"
-	DefaultDriverName                  string = "local.csi.aliyun.com"
-	DefaultEphemeralVolumeDataFilePath string = "/var/lib/kubelet/open-local-volumes.json"
+	DefaultEndpoint   string = "unix://tmp/csi.sock"
+	DefaultDriverName string = "local.csi.aliyun.com"
+	// DefaultNodeVolumeStoreFilePath records the states of volumes on node, including ephemeral volumes
+	DefaultNodeVolumeStoreFilePath string = "/var/lib/kubelet/open-local-volumes.json"
+	// DefaultControllerVolumeStoreName is the configmap recording the states of volumes being created or deleted by controller
+	DefaultControllerVolumeStoreName string = "open-local-controller-volumes"
+	// DefaultControllerVolumeStoreNamespace is used if the namespace of csi plugin is not set in EnvNamespace
+	DefaultControllerVolumeStoreNamespace string = "kube-system"
+	// EnvNamespace is the namespace of csi plugin pod
+	EnvNamespace = "NAMESPACE"
 	// VolumeOperationAlreadyExists is message fmt returned to CO when there is another in-flight call on the given volumeID
 	VolumeOperationAlreadyExists = "An operation with the given volume=%q is already in progress"
 )
@@ -13,95 +13,334 @@ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
 See the License for the specific language governing permissions and
 limitations under the License.
 */
+
 package csi
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sort"
 	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// VolumePhase is the lifecycle phase of a volume recorded in Store
+type VolumePhase string
+
+const (
+	// VolumeCreating means the volume is being created by CreateVolume
+	VolumeCreating VolumePhase = "Creating"
+	// VolumeCreated means no operation is in progress on the volume
+	VolumeCreated VolumePhase = "Created"
+	// VolumePublishing means the volume is being staged or published
+	VolumePublishing VolumePhase = "Publishing"
+	// VolumeExpanding means the filesystem of the volume is being expanded
+	VolumeExpanding VolumePhase = "Expanding"
+	// VolumeDeleting means the volume is being deleted
+	VolumeDeleting VolumePhase = "Deleting"
 )
 
+const (
+	// storeFormatVersion is the version of the store file format
+	storeFormatVersion = 1
+	// storeCompactRecords is the number of log records which triggers compaction
+	storeCompactRecords = 1024
+	walSuffix           = ".wal"
+)
+
+// VolumeState is the state of a volume, which is recorded before an operation
+// starts so that a half-done operation can be finished or rolled back after restart
+type VolumeState struct {
+	VolumeID string      `json:"volumeID"`
+	Phase    VolumePhase `json:"phase"`
+	NodeName string      `json:"nodeName,omitempty"`
+	VgName   string      `json:"vgName,omitempty"`
+	// Device is the lv of ephemeral volume, which is removed when the volume is unpublished
+	Device string `json:"device,omitempty"`
+	// TargetPath is the path where the volume is published or expanded
+	TargetPath   string `json:"targetPath,omitempty"`
+	Size         int64  `json:"size,omitempty"`
+	PVCName      string `json:"pvcName,omitempty"`
+	PVCNamespace string `json:"pvcNamespace,omitempty"`
+	// Volume is the csi.Volume returned by CreateVolume in protobuf
+	Volume []byte `json:"volume,omitempty"`
+	// Version is increased by every update of the volume
+	Version uint64 `json:"version"`
+}
+
+// Store records the states of volumes. Every update is persisted before it returns.
 type Store interface {
-	AddVolume(volumeID, device string) error
-	DeleteVolume(volumeID string) error
-	GetDevice(volumeID string) string
+	Get(volumeID string) (VolumeState, bool)
+	List() []VolumeState
+	Put(state VolumeState) error
+	Delete(volumeID string) error
+}
+
+// storeSnapshot is the content of the store file
+type storeSnapshot struct {
+	Version int                    `json:"version"`
+	Seq     uint64                 `json:"seq"`
+	Volumes map[string]VolumeState `json:"volumes"`
 }
 
+// walRecord is a line of the write-ahead log
+type walRecord struct {
+	Seq    uint64      `json:"seq"`
+	Delete bool        `json:"delete,omitempty"`
+	Volume VolumeState `json:"volume"`
+}
+
+// walFile is the file of write-ahead log, which is replaced in tests to inject failures
+type walFile interface {
+	io.Writer
+	Sync() error
+	Truncate(size int64) error
+	Seek(offset int64, whence int) (int64, error)
+}
+
+// volumeStore keeps a snapshot file and a write-ahead log beside it. Updates are
+// appended to the log and synced, and the log is compacted into the snapshot, which
+// is replaced by atomic rename, when it grows large.
 type volumeStore struct {
-	rwLock             sync.RWMutex
-	volumeDeviceMapper map[string]string
-	dataFilePath       string
+	lock         sync.RWMutex
+	dataFilePath string
+	volumes      map[string]VolumeState
+	seq          uint64
+	wal          walFile
+	walRecords   int
 }
 
+// NewVolumeStore loads the store at dataFilePath, the json file of volume devices
+// written by the previous versions is migrated as well
 func NewVolumeStore(dataFilePath string) (Store, error) {
-	var err error
-	var volumeDeviceMapper map[string]string
-	if volumeDeviceMapper, err = loadVolumeData(dataFilePath); err != nil {
-		if os.IsNotExist(err) {
-			volumeDeviceMapper = make(map[string]string)
-		} else {
-			return nil, err
-		}
+	store := &volumeStore{
+		dataFilePath: dataFilePath,
+		volumes:      map[string]VolumeState{},
+	}
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+	if err := store.compact(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (store *volumeStore) Get(volumeID string) (VolumeState, bool) {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+	state, ok := store.volumes[volumeID]
+	return state, ok
+}
+
+// List returns all volumes ordered by volume id
+func (store *volumeStore) List() []VolumeState {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+	states := make([]VolumeState, 0, len(store.volumes))
+	for _, state := range store.volumes {
+		states = append(states, state)
 	}
-	return &volumeStore{
-		volumeDeviceMapper: volumeDeviceMapper,
-		dataFilePath:       dataFilePath,
-	}, nil
+	sort.Slice(states, func(i, j int) bool { return states[i].VolumeID < states[j].VolumeID })
+	return states
 }
 
-func (store *volumeStore) AddVolume(volumeID, device string) error {
-	store.rwLock.Lock()
-	defer store.rwLock.Unlock()
-	store.volumeDeviceMapper[volumeID] = device
-	if err := saveVolumeData(store.dataFilePath, store.volumeDeviceMapper); err != nil {
+func (store *volumeStore) Put(state VolumeState) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	state.Version = store.volumes[state.VolumeID].Version + 1
+	if err := store.append(walRecord{Volume: state}); err != nil {
 		return err
 	}
+	store.volumes[state.VolumeID] = state
+	return store.compactIfNeeded()
+}
 
-	return nil
+func (store *volumeStore) Delete(volumeID string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	if _, ok := store.volumes[volumeID]; !ok {
+		return nil
+	}
+	if err := store.append(walRecord{Delete: true, Volume: VolumeState{VolumeID: volumeID}}); err != nil {
+		return err
+	}
+	delete(store.volumes, volumeID)
+	return store.compactIfNeeded()
 }
 
-func (store *volumeStore) DeleteVolume(volumeID string) error {
-	store.rwLock.Lock()
-	defer store.rwLock.Unlock()
-	delete(store.volumeDeviceMapper, volumeID)
-	if err := saveVolumeData(store.dataFilePath, store.volumeDeviceMapper); err != nil {
+func (store *volumeStore) append(record walRecord) error {
+	record.Seq = store.seq + 1
+	data, err := json.Marshal(record)
+	if err != nil {
 		return err
 	}
+	offset, err := store.wal.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("failed to seek volume store log: %s", err.Error())
+	}
+	if _, err := store.wal.Write(append(data, '\n')); err != nil {
+		return store.truncateLog(offset, fmt.Errorf("failed to write volume store log: %s", err.Error()))
+	}
+	if err := store.wal.Sync(); err != nil {
+		return store.truncateLog(offset, fmt.Errorf("failed to sync volume store log: %s", err.Error()))
+	}
+	store.seq = record.Seq
+	store.walRecords++
 	return nil
 }
 
-func (store *volumeStore) GetDevice(volumeID string) string {
-	store.rwLock.RLock()
-	defer store.rwLock.RUnlock()
+// truncateLog removes the bytes of the failed record after offset, which would stop replaying
+// the later records. If truncating fails, the log is compacted without the record.
+func (store *volumeStore) truncateLog(offset int64, cause error) error {
+	err := store.wal.Truncate(offset)
+	if err == nil {
+		err = store.wal.Sync()
+	}
+	if err != nil {
+		log.Warningf("volume store: failed to truncate log to %d: %s", offset, err.Error())
+		if err := store.compact(); err != nil {
+			log.Errorf("volume store: failed to compact log: %s", err.Error())
+		}
+	}
+	return cause
+}
 
-	return store.volumeDeviceMapper[volumeID]
+func (store *volumeStore) compactIfNeeded() error {
+	if store.walRecords < storeCompactRecords {
+		return nil
+	}
+	return store.compact()
 }
 
-// saveVolumeData persists parameter data as json file at the provided location
-func saveVolumeData(dataFilePath string, data map[string]string) error {
-	file, err := os.Create(dataFilePath)
+// compact writes all volumes to the snapshot file and truncates the log
+func (store *volumeStore) compact() error {
+	data, err := json.Marshal(storeSnapshot{Version: storeFormatVersion, Seq: store.seq, Volumes: store.volumes})
 	if err != nil {
-		return fmt.Errorf("failed to save volume data file %s: %v", dataFilePath, err)
+		return err
 	}
-	defer file.Close()
-	if err := json.NewEncoder(file).Encode(data); err != nil {
-		return fmt.Errorf("failed to save volume data file: %s", err.Error())
+	if err := writeFileAtomic(store.dataFilePath, data); err != nil {
+		return fmt.Errorf("failed to save volume store %s: %s", store.dataFilePath, err.Error())
 	}
-	return nil
+	if store.wal == nil {
+		wal, err := os.OpenFile(store.dataFilePath+walSuffix, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open volume store log: %s", err.Error())
+		}
+		store.wal = wal
+	}
+	// records in the log are in the snapshot now, they are skipped by seq if truncating fails
+	if err := store.wal.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate volume store log: %s", err.Error())
+	}
+	store.walRecords = 0
+	return store.wal.Sync()
 }
 
-// loadVolumeData loads volume info from specified json file/location
-func loadVolumeData(dataFilePath string) (map[string]string, error) {
-	file, err := os.Open(dataFilePath)
+// load reads the snapshot file and replays the log after it
+func (store *volumeStore) load() error {
+	data, err := ioutil.ReadFile(store.dataFilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		if err := store.loadSnapshot(data); err != nil {
+			return fmt.Errorf("failed to parse volume store %s: %s", store.dataFilePath, err.Error())
+		}
+	}
+
+	data, err = ioutil.ReadFile(store.dataFilePath + walSuffix)
+	if os.IsNotExist(err) {
+		return nil
+	}
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer file.Close()
-	data := map[string]string{}
-	if err := json.NewDecoder(file).Decode(&data); err != nil {
-		return nil, fmt.Errorf("failed to parse volume data file: %s", err.Error())
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		record := walRecord{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// the last record may be partially written when crashed
+			log.Warningf("volume store: ignore broken log record after seq %d: %s", store.seq, err.Error())
+			break
+		}
+		if record.Seq <= store.seq {
+			continue
+		}
+		if record.Delete {
+			delete(store.volumes, record.Volume.VolumeID)
+		} else {
+			store.volumes[record.Volume.VolumeID] = record.Volume
+		}
+		store.seq = record.Seq
 	}
+	return nil
+}
 
-	return data, nil
+func (store *volumeStore) loadSnapshot(data []byte) error {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if _, ok := fields["version"]; !ok {
+		// map of ephemeral volume id to device
+		devices := map[string]string{}
+		if err := json.Unmarshal(data, &devices); err != nil {
+			return err
+		}
+		for volumeID, device := range devices {
+			store.volumes[volumeID] = VolumeState{VolumeID: volumeID, Phase: VolumeCreated, Device: device, Version: 1}
+		}
+		log.Infof("volume store: migrated %d ephemeral volumes from %s", len(devices), store.dataFilePath)
+		return nil
+	}
+	snapshot := storeSnapshot{}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+	if snapshot.Version > storeFormatVersion {
+		return fmt.Errorf("unsupported version %d", snapshot.Version)
+	}
+	if snapshot.Volumes != nil {
+		store.volumes = snapshot.Volumes
+	}
+	store.seq = snapshot.Seq
+	return nil
+}
+
+// writeFileAtomic replaces the file with data, so that the file is either old or new after crash
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
 }
@@ -0,0 +1,190 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestVolumeStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "volumes.json")
+	store, err := NewVolumeStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	states := []VolumeState{
+		{VolumeID: "pv-1", Phase: VolumeCreating, NodeName: "node-1", VgName: "share"},
+		{VolumeID: "pv-2", Phase: VolumePublishing, TargetPath: "/mnt/pv-2"},
+		{VolumeID: "pv-1", Phase: VolumeCreated, NodeName: "node-1", VgName: "share", Volume: []byte{1, 2}},
+	}
+	for _, state := range states {
+		if err := store.Put(state); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Delete("pv-2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("pv-3"); err != nil {
+		t.Errorf("Delete() of unknown volume error = %v", err)
+	}
+
+	// updates are replayed from the log
+	expect := []VolumeState{states[2]}
+	expect[0].Version = 2
+	store, err = NewVolumeStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := store.List(); !reflect.DeepEqual(got, expect) {
+		t.Errorf("List() after reload = %+v, want %+v", got, expect)
+	}
+
+	// the torn record at the tail of log is ignored
+	if err := store.Put(VolumeState{VolumeID: "pv-4", Phase: VolumeExpanding}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path+walSuffix, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(`{"seq":100,"volume":{"volumeID":"pv-5"`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	store, err = NewVolumeStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.Get("pv-4"); !ok {
+		t.Errorf("Get(pv-4) after reload not found")
+	}
+	if _, ok := store.Get("pv-5"); ok {
+		t.Errorf("Get(pv-5) of torn record found")
+	}
+}
+
+// failingWAL writes a part of the record, or fails to sync, once
+type failingWAL struct {
+	walFile
+	failWrite bool
+	failSync  bool
+}
+
+func (wal *failingWAL) Write(data []byte) (int, error) {
+	if wal.failWrite {
+		wal.failWrite = false
+		n, _ := wal.walFile.Write(data[:len(data)/2])
+		return n, errors.New("no space left on device")
+	}
+	return wal.walFile.Write(data)
+}
+
+func (wal *failingWAL) Sync() error {
+	if wal.failSync {
+		wal.failSync = false
+		return errors.New("input/output error")
+	}
+	return wal.walFile.Sync()
+}
+
+func TestVolumeStoreAppendFailure(t *testing.T) {
+	for _, wal := range []*failingWAL{{failWrite: true}, {failSync: true}} {
+		path := filepath.Join(t.TempDir(), "volumes.json")
+		store, err := NewVolumeStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Put(VolumeState{VolumeID: "pv-1", Phase: VolumeCreating}); err != nil {
+			t.Fatal(err)
+		}
+		wal.walFile = store.(*volumeStore).wal
+		store.(*volumeStore).wal = wal
+		if err := store.Put(VolumeState{VolumeID: "pv-2", Phase: VolumeCreating}); err == nil {
+			t.Errorf("Put() with %+v error = nil", wal)
+		}
+		// the failed record is removed, so the later records are replayed
+		if err := store.Put(VolumeState{VolumeID: "pv-3", Phase: VolumeCreating}); err != nil {
+			t.Fatal(err)
+		}
+		store, err = NewVolumeStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, state := range store.List() {
+			got = append(got, state.VolumeID)
+		}
+		if want := []string{"pv-1", "pv-3"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("volumes after reload with %+v = %v, want %v", wal, got, want)
+		}
+	}
+}
+
+func TestVolumeStoreCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "volumes.json")
+	store, err := NewVolumeStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < storeCompactRecords; i++ {
+		if err := store.Put(VolumeState{VolumeID: "pv-1", Phase: VolumeCreated}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	info, err := os.Stat(path + walSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 0 {
+		t.Errorf("log has %d bytes after compaction, want 0", info.Size())
+	}
+	store, err = NewVolumeStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state, _ := store.Get("pv-1"); state.Version != storeCompactRecords {
+		t.Errorf("Version after compaction = %d, want %d", state.Version, storeCompactRecords)
+	}
+}
+
+func TestVolumeStoreMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "volumes.json")
+	if err := ioutil.WriteFile(path, []byte(`{"csi-1":"/dev/share/csi-1"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewVolumeStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := VolumeState{VolumeID: "csi-1", Phase: VolumeCreated, Device: "/dev/share/csi-1", Version: 1}
+	if state, _ := store.Get("csi-1"); !reflect.DeepEqual(state, expect) {
+		t.Errorf("Get() of legacy volume = %+v, want %+v", state, expect)
+	}
+	// the file is rewritten in the new format
+	if store, err = NewVolumeStore(path); err != nil {
+		t.Fatal(err)
+	}
+	if state, _ := store.Get("csi-1"); !reflect.DeepEqual(state, expect) {
+		t.Errorf("Get() after migration = %+v, want %+v", state, expect)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	"github.com/alibaba/open-local/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	clientgocache "k8s.io/client-go/tools/cache"
+)
+
+// AllocationAnnotations returns the annotations recording the allocation of unit on its pvc
+func AllocationAnnotations(unit cache.AllocatedUnit) map[string]string {
+	annotations := map[string]string{localtype.AnnAllocatedNode: unit.NodeName}
+	switch unit.VolumeType {
+	case localtype.VolumeTypeLVM:
+		annotations[localtype.AnnAllocatedVG] = unit.VgName
+	case localtype.VolumeTypeMountPoint, localtype.VolumeTypeQuota:
+		annotations[localtype.AnnAllocatedMountPoint] = unit.MountPoint
+	case localtype.VolumeTypeDevice:
+		annotations[localtype.AnnAllocatedDevice] = unit.Device
+	}
+	return annotations
+}
+
+// AnnotateAllocation records the allocation of units in annotations of their pvcs. It is called
+// before the pvcs are provisioned, so that the same allocation is used if scheduler restarts
+// before provisioner calls back.
+func AnnotateAllocation(kubeClient kubernetes.Interface, units []cache.AllocatedUnit) error {
+	for _, unit := range units {
+		namespace, name, err := clientgocache.SplitMetaNamespaceKey(unit.PVCName)
+		if err != nil {
+			return err
+		}
+		annotations := AllocationAnnotations(unit)
+		patchData := map[string]interface{}{"metadata": map[string]map[string]string{"annotations": annotations}}
+		patchBytes, err := json.Marshal(patchData)
+		if err != nil {
+			return err
+		}
+		if _, err := kubeClient.CoreV1().PersistentVolumeClaims(namespace).Patch(context.Background(), name, types.MergePatchType, patchBytes, metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("failed to annotate allocation of pvc %s: %s", unit.PVCName, err.Error())
+		}
+		log.Infof("annotated allocation %+v to pvc %s", annotations, unit.PVCName)
+	}
+	return nil
+}
+
+// GetAllocatedUnitFromPVC returns the unit recorded in the allocation annotations of pvc,
+// nil is returned if pvc is not annotated or allocated on another node
+func GetAllocatedUnitFromPVC(pvc *corev1.PersistentVolumeClaim, node *corev1.Node, ctx *algorithm.SchedulingContext) (*cache.AllocatedUnit, error) {
+	if nodeName, ok := pvc.Annotations[localtype.AnnAllocatedNode]; !ok || nodeName != node.Name {
+		return nil, nil
+	}
+	containReadonlySnapshot := false
+	isLocal, volumeType := utils.IsLocalPVC(pvc, ctx.StorageV1Informers, containReadonlySnapshot)
+	if !isLocal {
+		return nil, fmt.Errorf("pvc %s is not a local pvc", utils.PVCName(pvc))
+	}
+	requestedSize := utils.GetPVCRequested(pvc)
+	unit := cache.AllocatedUnit{
+		NodeName:   node.Name,
+		VolumeType: volumeType,
+		Requested:  requestedSize,
+		Allocated:  requestedSize,
+		PVCName:    utils.PVCName(pvc),
+	}
+	switch volumeType {
+	case localtype.VolumeTypeLVM:
+		unit.VgName = pvc.Annotations[localtype.AnnAllocatedVG]
+		unit.Thin = IsThinPVC(pvc, ctx)
+		if unit.VgName == "" {
+			return nil, fmt.Errorf("no allocated vg found in annotations of pvc %s", unit.PVCName)
+		}
+	case localtype.VolumeTypeQuota:
+		unit.MountPoint = pvc.Annotations[localtype.AnnAllocatedMountPoint]
+		if unit.MountPoint == "" {
+			return nil, fmt.Errorf("no allocated mount point found in annotations of pvc %s", unit.PVCName)
+		}
+	case localtype.VolumeTypeMountPoint, localtype.VolumeTypeDevice:
+		nodeCache := ctx.ClusterNodeCache.GetNodeCache(node.Name)
+		if nodeCache == nil {
+			return nil, fmt.Errorf("node %s not found from cache", node.Name)
+		}
+		resources, name := nodeCache.MountPoints, pvc.Annotations[localtype.AnnAllocatedMountPoint]
+		if volumeType == localtype.VolumeTypeDevice {
+			resources, name = nodeCache.Devices, pvc.Annotations[localtype.AnnAllocatedDevice]
+		}
+		er, ok := resources[cache.ResourceName(name)]
+		if !ok {
+			return nil, fmt.Errorf("allocated %s %q of pvc %s not found on node %s", volumeType, name, unit.PVCName, node.Name)
+		}
+		// for exclusive resource the whole disk is allocated
+		unit.Allocated = er.Capacity
+		unit.Device = er.Name
+		unit.MountPoint = er.Name
+	default:
+		return nil, fmt.Errorf("unsupported volume type %s of pvc %s", volumeType, unit.PVCName)
+	}
+	units := []cache.AllocatedUnit{unit}
+	SetIORequests(units, ctx)
+	return &units[0], nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algo
+
+import (
+	"testing"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetAllocatedUnitFromPVC(t *testing.T) {
+	nodeName := "testnode"
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+	factory := informers.NewSharedInformerFactory(k8sfake.NewSimpleClientset(), 0)
+	for name, volumeType := range map[string]localtype.VolumeType{
+		"open-local-lvm":    localtype.VolumeTypeLVM,
+		"open-local-device": localtype.VolumeTypeDevice,
+	} {
+		factory.Storage().V1().StorageClasses().Informer().GetIndexer().Add(&storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: name},
+			Provisioner: localtype.ProvisionerName,
+			Parameters:  map[string]string{localtype.VolumeTypeKey: string(volumeType)},
+		})
+	}
+	ctx := &algorithm.SchedulingContext{
+		ClusterNodeCache:   cache.NewClusterNodeCache(),
+		CoreV1Informers:    factory.Core().V1(),
+		StorageV1Informers: factory.Storage().V1(),
+	}
+	nc := cache.NewNodeCache(nodeName)
+	nc.VGs["share"] = cache.SharedResource{Name: "share", Capacity: 100 << 30}
+	nc.Devices["/dev/vdb"] = cache.ExclusiveResource{Name: "/dev/vdb", Capacity: 100 << 30}
+	ctx.ClusterNodeCache.SetNodeCache(nc)
+
+	tests := []struct {
+		name     string
+		pvc      *corev1.PersistentVolumeClaim
+		unit     cache.AllocatedUnit
+		expected *cache.AllocatedUnit
+		err      bool
+	}{
+		{
+			name:     "lvm",
+			pvc:      newTierPVC("lvm", "open-local-lvm", "10Gi"),
+			unit:     cache.AllocatedUnit{NodeName: nodeName, VolumeType: localtype.VolumeTypeLVM, VgName: "share"},
+			expected: &cache.AllocatedUnit{NodeName: nodeName, VolumeType: localtype.VolumeTypeLVM, Requested: 10 << 30, Allocated: 10 << 30, VgName: "share", PVCName: "default/lvm"},
+		},
+		{
+			name:     "device is allocated as a whole",
+			pvc:      newTierPVC("device", "open-local-device", "10Gi"),
+			unit:     cache.AllocatedUnit{NodeName: nodeName, VolumeType: localtype.VolumeTypeDevice, Device: "/dev/vdb"},
+			expected: &cache.AllocatedUnit{NodeName: nodeName, VolumeType: localtype.VolumeTypeDevice, Requested: 10 << 30, Allocated: 100 << 30, Device: "/dev/vdb", MountPoint: "/dev/vdb", PVCName: "default/device"},
+		},
+		{
+			name: "allocated on another node",
+			pvc:  newTierPVC("other", "open-local-lvm", "10Gi"),
+			unit: cache.AllocatedUnit{NodeName: "othernode", VolumeType: localtype.VolumeTypeLVM, VgName: "share"},
+		},
+		{
+			name: "device not found",
+			pvc:  newTierPVC("missing", "open-local-device", "10Gi"),
+			unit: cache.AllocatedUnit{NodeName: nodeName, VolumeType: localtype.VolumeTypeDevice, Device: "/dev/vdc"},
+			err:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.pvc.Annotations = AllocationAnnotations(test.unit)
+			unit, err := GetAllocatedUnitFromPVC(test.pvc, node, ctx)
+			if (err != nil) != test.err {
+				t.Fatalf("GetAllocatedUnitFromPVC() error = %v, expected error %t", err, test.err)
+			}
+			if test.expected == nil {
+				if unit != nil {
+					t.Errorf("GetAllocatedUnitFromPVC() = %+v, expected nil", unit)
+				}
+				return
+			}
+			if unit == nil || *unit != *test.expected {
+				t.Errorf("GetAllocatedUnitFromPVC() = %+v, expected %+v", unit, test.expected)
+			}
+		})
+	}
+}
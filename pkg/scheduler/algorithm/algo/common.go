@@ -58,21 +58,47 @@ func AllocateLVMVolume(pod *corev1.Pod, pvcs []*corev1.PersistentVolumeClaim, no
 }
 
 func ProcessLVMPVCPredicate(pvcs []*corev1.PersistentVolumeClaim, node *corev1.Node, ctx *algorithm.SchedulingContext) (fits bool, units []cache.AllocatedUnit, err error) {
-	pvcsWithVG, pvcsWithoutVG := DivideLVMPVCs(pvcs, ctx)
-	cacheVGsMap, err := GetNodeVGMap(node, ctx)
-	if err != nil {
-		return false, units, err
+	thickPVCs, thinPVCs := DivideThinPVCs(pvcs, ctx)
+	if len(thickPVCs) > 0 {
+		cacheVGsMap, err := GetNodeVGMap(node, ctx)
+		if err != nil {
+			return false, units, err
+		}
+		if fits, units, err = processLVMPVCPredicate(thickPVCs, node, ctx, cacheVGsMap); !fits {
+			return false, units, err
+		}
+	}
+	if len(thinPVCs) > 0 {
+		cacheThinPoolsMap, err := GetNodeThinPoolMap(node, ctx)
+		if err != nil {
+			return false, units, err
+		}
+		fits, thinUnits, err := processLVMPVCPredicate(thinPVCs, node, ctx, cacheThinPoolsMap)
+		if !fits {
+			return false, units, err
+		}
+		units = append(units, markThin(thinUnits)...)
 	}
+	return true, units, nil
+}
+
+// processLVMPVCPredicate allocates pvcs from cacheVGsMap, which is either the VGs or the thin pools of node
+func processLVMPVCPredicate(pvcs []*corev1.PersistentVolumeClaim, node *corev1.Node, ctx *algorithm.SchedulingContext, cacheVGsMap map[cache.ResourceName]cache.SharedResource) (fits bool, units []cache.AllocatedUnit, err error) {
+	pvcsWithVG, pvcsWithoutVG := DivideLVMPVCs(pvcs, ctx)
+	faulty := faultyResources(node, ctx)
 
 	// process pvcsWithVG first
 	for _, pvc := range pvcsWithVG {
-		vgName := utils.GetVGNameFromPVC(pvc, ctx.StorageV1Informers)
+		vgName := GetVGNameFromPVC(pvc, ctx)
 		requestedSize := utils.GetPVCRequested(pvc)
 
 		vg, ok := cacheVGsMap[cache.ResourceName(vgName)]
 		if !ok {
 			return false, units, errors.NewNoSuchVGError(vgName, node.GetName())
 		}
+		if faulty[cache.ResourceName(vgName)] {
+			return false, units, errors.NewStorageFaultError(localtype.VolumeTypeLVM, vgName, node.GetName())
+		}
 
 		freeSize := vg.Capacity - vg.Requested
 		log.Debugf("validating vg(name=%s,free=%d) for pvc(name=%s,requested=%d)", vgName, freeSize, pvc.Name, requestedSize)
@@ -105,26 +131,48 @@ func ProcessLVMPVCPredicate(pvcs []*corev1.PersistentVolumeClaim, node *corev1.N
 	if len(cacheVGsSlice) <= 0 {
 		return false, units, errors.NewNoAvailableVGError(node.Name)
 	}
+	var tiers map[cache.ResourceName]string
+	if nodeCache := ctx.ClusterNodeCache.GetNodeCache(node.Name); nodeCache != nil {
+		tiers = nodeCache.PerformanceTiers
+	}
 	// process pvcsWithoutVG
 	for _, pvc := range pvcsWithoutVG {
 		requestedSize := utils.GetPVCRequested(pvc)
 
+		// only VGs of the performance tier which are not on faulty disks are candidates
+		tier := utils.GetPerformanceTierFromPVC(pvc, ctx.StorageV1Informers)
+		candidates := make([]*cache.SharedResource, 0, len(cacheVGsSlice))
+		for i := range cacheVGsSlice {
+			if faulty[cache.ResourceName(cacheVGsSlice[i].Name)] {
+				continue
+			}
+			if tier == "" || tiers[cache.ResourceName(cacheVGsSlice[i].Name)] == tier {
+				candidates = append(candidates, &cacheVGsSlice[i])
+			}
+		}
+		if len(candidates) <= 0 {
+			if tier == "" {
+				return false, units, errors.NewNoAvailableVGError(node.Name)
+			}
+			return false, units, errors.NewNoPerformanceTierError(localtype.VolumeTypeLVM, tier, node.Name)
+		}
+
 		// sort by available size
-		sort.Slice(cacheVGsSlice, func(i, j int) bool {
-			return (cacheVGsSlice[i].Capacity - cacheVGsSlice[i].Requested) < (cacheVGsSlice[j].Capacity - cacheVGsSlice[j].Requested)
+		sort.Slice(candidates, func(i, j int) bool {
+			return (candidates[i].Capacity - candidates[i].Requested) < (candidates[j].Capacity - candidates[j].Requested)
 		})
 
-		for i, vg := range cacheVGsSlice {
+		for i, vg := range candidates {
 			freeSize := vg.Capacity - vg.Requested
 			log.Debugf("validating vg(name=%s,free=%d) for pvc(name=%s,requested=%d)", vg.Name, freeSize, pvc.Name, requestedSize)
 
 			if freeSize < requestedSize {
-				if i == len(cacheVGsSlice)-1 {
+				if i == len(candidates)-1 {
 					return false, units, errors.NewInsufficientLVMError(requestedSize, vg.Requested, vg.Capacity, vg.Name, node.GetName())
 				}
 				continue
 			}
-			cacheVGsSlice[i].Requested += requestedSize
+			vg.Requested += requestedSize
 			u := cache.AllocatedUnit{
 				NodeName:   node.Name,
 				VolumeType: localtype.VolumeTypeLVM,
@@ -159,6 +207,9 @@ func HandleInlineLVMVolume(ctx *algorithm.SchedulingContext, node *corev1.Node,
 			if !ok {
 				return false, units, errors.NewNoSuchVGError(vgName, node.GetName())
 			}
+			if faultyResources(node, ctx)[cache.ResourceName(vgName)] {
+				return false, units, errors.NewStorageFaultError(localtype.VolumeTypeLVM, vgName, node.GetName())
+			}
 
 			freeSize := vg.Capacity - vg.Requested
 
@@ -188,7 +239,7 @@ func HandleInlineLVMVolume(ctx *algorithm.SchedulingContext, node *corev1.Node,
 // DivideLVMPVCs divide pvcs into pvcsWithVG and pvcsWithoutVG
 func DivideLVMPVCs(pvcs []*corev1.PersistentVolumeClaim, ctx *algorithm.SchedulingContext) (pvcsWithVG, pvcsWithoutVG []*corev1.PersistentVolumeClaim) {
 	for _, pvc := range pvcs {
-		if vgName := utils.GetVGNameFromPVC(pvc, ctx.StorageV1Informers); vgName == "" {
+		if vgName := GetVGNameFromPVC(pvc, ctx); vgName == "" {
 			pvcsWithoutVG = append(pvcsWithoutVG, pvc)
 		} else {
 			pvcsWithVG = append(pvcsWithVG, pvc)
@@ -197,6 +248,73 @@ func DivideLVMPVCs(pvcs []*corev1.PersistentVolumeClaim, ctx *algorithm.Scheduli
 	return
 }
 
+// DivideThinPVCs divide pvcs into thickPVCs and thinPVCs
+func DivideThinPVCs(pvcs []*corev1.PersistentVolumeClaim, ctx *algorithm.SchedulingContext) (thickPVCs, thinPVCs []*corev1.PersistentVolumeClaim) {
+	for _, pvc := range pvcs {
+		if IsThinPVC(pvc, ctx) {
+			thinPVCs = append(thinPVCs, pvc)
+		} else {
+			thickPVCs = append(thickPVCs, pvc)
+		}
+	}
+	return
+}
+
+// GetVGNameFromPVC returns the vg of source volume for clone pvc, otherwise the vg in storage class
+func GetVGNameFromPVC(pvc *corev1.PersistentVolumeClaim, ctx *algorithm.SchedulingContext) string {
+	if srcPV := getCloneSourcePV(pvc, ctx); srcPV != nil {
+		return utils.GetVGNameFromCsiPV(srcPV)
+	}
+	return utils.GetVGNameFromPVC(pvc, ctx.StorageV1Informers)
+}
+
+// IsThinPVC returns true if the pvc is allocated from thin pool, clone pvc follows its source volume
+func IsThinPVC(pvc *corev1.PersistentVolumeClaim, ctx *algorithm.SchedulingContext) bool {
+	if srcPV := getCloneSourcePV(pvc, ctx); srcPV != nil {
+		return utils.IsThinCsiPV(srcPV)
+	}
+	return utils.IsThinPVC(pvc, ctx.StorageV1Informers)
+}
+
+// getCloneSourcePV returns the bound pv of source pvc if pvc is a clone pvc
+func getCloneSourcePV(pvc *corev1.PersistentVolumeClaim, ctx *algorithm.SchedulingContext) *corev1.PersistentVolume {
+	if !utils.IsClonePVC(pvc) {
+		return nil
+	}
+	srcPVC, err := ctx.CoreV1Informers.PersistentVolumeClaims().Lister().PersistentVolumeClaims(pvc.Namespace).Get(pvc.Spec.DataSource.Name)
+	if err != nil || srcPVC.Spec.VolumeName == "" {
+		return nil
+	}
+	srcPV, err := ctx.CoreV1Informers.PersistentVolumes().Lister().Get(srcPVC.Spec.VolumeName)
+	if err != nil {
+		return nil
+	}
+	return srcPV
+}
+
+// markThin marks units as allocated from thin pool
+func markThin(units []cache.AllocatedUnit) []cache.AllocatedUnit {
+	for i := range units {
+		units[i].Thin = true
+	}
+	return units
+}
+
+// GetNodeThinPoolMap make a copy map of NodeCache ThinPools
+func GetNodeThinPoolMap(node *corev1.Node, ctx *algorithm.SchedulingContext) (cacheThinPoolsMap map[cache.ResourceName]cache.SharedResource, err error) {
+	nodeCache := ctx.ClusterNodeCache.GetNodeCache(node.Name)
+	if nodeCache == nil {
+		return nil, fmt.Errorf("node %s not found from cache", node.Name)
+	}
+
+	cacheThinPoolsMap = make(map[cache.ResourceName]cache.SharedResource, len(nodeCache.ThinPools))
+	for k, v := range nodeCache.ThinPools {
+		cacheThinPoolsMap[k] = v
+	}
+
+	return
+}
+
 // GetNodeVGMap make a copy map of NodeCache VGs
 func GetNodeVGMap(node *corev1.Node, ctx *algorithm.SchedulingContext) (cacheVGsMap map[cache.ResourceName]cache.SharedResource, err error) {
 	nodeCache := ctx.ClusterNodeCache.GetNodeCache(node.Name)
@@ -212,6 +330,14 @@ func GetNodeVGMap(node *corev1.Node, ctx *algorithm.SchedulingContext) (cacheVGs
 	return
 }
 
+// faultyResources returns the VGs, devices and mount points on faulty disks of node
+func faultyResources(node *corev1.Node, ctx *algorithm.SchedulingContext) map[cache.ResourceName]bool {
+	if nodeCache := ctx.ClusterNodeCache.GetNodeCache(node.Name); nodeCache != nil {
+		return nodeCache.FaultyResources
+	}
+	return nil
+}
+
 func AllocateMountPointVolume(
 	pod *corev1.Pod, pvcs []*corev1.PersistentVolumeClaim, node *corev1.Node,
 	ctx *algorithm.SchedulingContext) (fits bool, units []cache.AllocatedUnit, err error) {
@@ -314,6 +440,13 @@ func GetFreeMP(node *corev1.Node, ctx *algorithm.SchedulingContext) (freeMPSSD,
 	}
 
 	for _, mp := range nodeCache.MountPoints {
+		// mount point which is shared by quota volumes can not be allocated exclusively
+		if quota, ok := nodeCache.Quotas[cache.ResourceName(mp.Name)]; ok && quota.Requested > 0 {
+			continue
+		}
+		if nodeCache.FaultyResources[cache.ResourceName(mp.Name)] {
+			continue
+		}
 		if mp.MediaType == localtype.MediaTypeSSD && !mp.IsAllocated {
 			freeMPSSD = append(freeMPSSD, mp)
 		} else if mp.MediaType == localtype.MediaTypeHDD && !mp.IsAllocated {
@@ -393,6 +526,102 @@ func CheckExclusiveResourceMeetsPVCSize(resource localtype.VolumeType, ers []cac
 	return true, units, nil
 }
 
+// AllocateQuotaVolume contains two policy: BINPACK/SPREAD
+func AllocateQuotaVolume(pod *corev1.Pod, pvcs []*corev1.PersistentVolumeClaim, node *corev1.Node, ctx *algorithm.SchedulingContext) (fits bool, units []cache.AllocatedUnit, err error) {
+	if len(pvcs) <= 0 {
+		return
+	}
+	if pod != nil {
+		log.Infof("allocating quota volume for pod %s/%s", pod.Namespace, pod.Name)
+	}
+
+	fits, units, err = ProcessQuotaPVC(pod, pvcs, node, ctx)
+
+	return fits, units, err
+}
+
+// GetNodeQuotaMap make a copy map of NodeCache Quotas, mount points which
+// are allocated exclusively by MountPoint volumes are excluded
+func GetNodeQuotaMap(node *corev1.Node, ctx *algorithm.SchedulingContext) (cacheQuotasMap map[cache.ResourceName]cache.SharedResource, err error) {
+	nodeCache := ctx.ClusterNodeCache.GetNodeCache(node.Name)
+	if nodeCache == nil {
+		return nil, fmt.Errorf("node %s not found from cache", node.Name)
+	}
+
+	cacheQuotasMap = make(map[cache.ResourceName]cache.SharedResource, len(nodeCache.Quotas))
+	for k, v := range nodeCache.Quotas {
+		if mp, ok := nodeCache.MountPoints[k]; ok && mp.IsAllocated {
+			continue
+		}
+		if nodeCache.FaultyResources[k] {
+			continue
+		}
+		cacheQuotasMap[k] = v
+	}
+
+	return
+}
+
+// ProcessQuotaPVC places every quota pvc on a mount point of the node,
+// mount points are picked according to the strategy of each pvc
+func ProcessQuotaPVC(pod *corev1.Pod, pvcs []*corev1.PersistentVolumeClaim, node *corev1.Node, ctx *algorithm.SchedulingContext) (fits bool, units []cache.AllocatedUnit, err error) {
+	cacheQuotasMap, err := GetNodeQuotaMap(node, ctx)
+	if err != nil {
+		return false, units, err
+	}
+	if len(cacheQuotasMap) <= 0 {
+		return false, units, fmt.Errorf("no mount point available for quota volume on node %s", node.Name)
+	}
+	nodeCache := ctx.ClusterNodeCache.GetNodeCache(node.Name)
+	volumes := resourceVolumes(nodeCache)
+
+	for _, pvc := range pvcs {
+		strategy := GetStrategyFromPVC(pvc, ctx)
+		request := StrategyRequest{
+			Size:      utils.GetPVCRequested(pvc),
+			MediaType: utils.GetMediaTypeFromPVC(pvc, ctx.StorageV1Informers),
+		}
+
+		candidates := make([]Candidate, 0, len(cacheQuotasMap))
+		for name, quota := range cacheQuotasMap {
+			mediaType := nodeCache.MountPoints[name].MediaType
+			if request.MediaType != "" && mediaType != request.MediaType {
+				continue
+			}
+			candidates = append(candidates, Candidate{SharedResource: quota, MediaType: mediaType, Volumes: volumes[name]})
+		}
+		quota, ok := selectCandidate(strategy, request, candidates)
+		if !ok {
+			quanReq := resource.NewQuantity(request.Size, resource.BinarySI)
+			if pod == nil {
+				return false, units, fmt.Errorf("not enough quota storage on %s, requested size %s, strategy %s",
+					node.Name, quanReq.String(), strategy.Name())
+			}
+			return false, units, fmt.Errorf("not enough quota storage on %s for pod %s/%s, requested size %s, strategy %s",
+				node.Name, pod.Namespace, pod.Name, quanReq.String(), strategy.Name())
+		}
+		log.Debugf("allocating mount point(name=%s,free=%d) for quota pvc(name=%s,requested=%d)", quota.Name, quota.free(), pvc.Name, request.Size)
+		tmp := cacheQuotasMap[cache.ResourceName(quota.Name)]
+		tmp.Requested += request.Size
+		cacheQuotasMap[cache.ResourceName(quota.Name)] = tmp
+		volumes[cache.ResourceName(quota.Name)]++
+		u := cache.AllocatedUnit{
+			NodeName:   node.Name,
+			VolumeType: localtype.VolumeTypeQuota,
+			Requested:  request.Size,
+			Allocated:  request.Size, // for Quota requested is always equal to allocated
+			VgName:     "",
+			Device:     "",
+			MountPoint: quota.Name,
+			PVCName:    utils.PVCName(pvc),
+		}
+		units = append(units, u)
+	}
+
+	log.Debugf("node %s is capable of quota %d pvcs", node.Name, len(pvcs))
+	return true, units, nil
+}
+
 func AllocateDeviceVolume(pod *corev1.Pod, pvcs []*corev1.PersistentVolumeClaim, node *corev1.Node,
 	ctx *algorithm.SchedulingContext) (fits bool, units []cache.AllocatedUnit, err error) {
 	if len(pvcs) <= 0 {
@@ -415,6 +644,9 @@ func GetFreeDevice(node *corev1.Node, ctx *algorithm.SchedulingContext) (freeDev
 	}
 
 	for _, device := range nodeCache.Devices {
+		if nodeCache.FaultyResources[cache.ResourceName(device.Name)] {
+			continue
+		}
 		if device.MediaType == localtype.MediaTypeSSD && !device.IsAllocated {
 			freeDeviceSSD = append(freeDeviceSSD, device)
 		} else if device.MediaType == localtype.MediaTypeHDD && !device.IsAllocated {
@@ -436,6 +668,7 @@ func GetCacheDeviceCount(node *corev1.Node, ctx *algorithm.SchedulingContext) (c
 }
 
 func ProcessDevicePVC(pod *corev1.Pod, pvcs []*corev1.PersistentVolumeClaim, node *corev1.Node, ctx *algorithm.SchedulingContext) (fits bool, units []cache.AllocatedUnit, err error) {
+	pvcsWithTier, pvcs := DividePVCAccordingToPerformanceTier(pvcs, ctx)
 	pvcsWithTypeSSD, pvcsWithTypeHDD := DividePVCAccordingToMediaType(pvcs, ctx)
 	freeDeviceSSD, freeDeviceHDD, err := GetFreeDevice(node, ctx)
 	if err != nil {
@@ -446,6 +679,17 @@ func ProcessDevicePVC(pod *corev1.Pod, pvcs []*corev1.PersistentVolumeClaim, nod
 	if err != nil {
 		return false, units, err
 	}
+
+	// process pvcsWithTier first, media type of them is ignored
+	if len(pvcsWithTier) > 0 {
+		fits, tierUnits, err := processTierDevicePVC(pvcsWithTier, freeDeviceSSD, freeDeviceHDD, totalCount, node, ctx)
+		if err != nil || !fits {
+			return false, units, err
+		}
+		units = append(units, tierUnits...)
+		freeDeviceSSD = excludeAllocatedDevices(freeDeviceSSD, tierUnits)
+		freeDeviceHDD = excludeAllocatedDevices(freeDeviceHDD, tierUnits)
+	}
 	// ssd
 	freeDeviceSSDCount := int64(len(freeDeviceSSD))
 	requestedSSDCount := int64(len(pvcsWithTypeSSD))
@@ -495,6 +739,77 @@ func ProcessDevicePVC(pod *corev1.Pod, pvcs []*corev1.PersistentVolumeClaim, nod
 	return true, units, nil
 }
 
+// DividePVCAccordingToPerformanceTier divide pvcs into pvcsWithTier keyed by tier and pvcsWithoutTier
+func DividePVCAccordingToPerformanceTier(pvcs []*corev1.PersistentVolumeClaim, ctx *algorithm.SchedulingContext) (pvcsWithTier map[string][]*corev1.PersistentVolumeClaim, pvcsWithoutTier []*corev1.PersistentVolumeClaim) {
+	pvcsWithTier = make(map[string][]*corev1.PersistentVolumeClaim)
+	for _, pvc := range pvcs {
+		if tier := utils.GetPerformanceTierFromPVC(pvc, ctx.StorageV1Informers); tier != "" {
+			pvcsWithTier[tier] = append(pvcsWithTier[tier], pvc)
+		} else {
+			pvcsWithoutTier = append(pvcsWithoutTier, pvc)
+		}
+	}
+	return
+}
+
+// processTierDevicePVC allocates the free devices of each performance tier to pvcsWithTier
+func processTierDevicePVC(pvcsWithTier map[string][]*corev1.PersistentVolumeClaim, freeDeviceSSD, freeDeviceHDD []cache.ExclusiveResource, totalCount int64, node *corev1.Node, ctx *algorithm.SchedulingContext) (fits bool, units []cache.AllocatedUnit, err error) {
+	nodeCache := ctx.ClusterNodeCache.GetNodeCache(node.Name)
+	if nodeCache == nil {
+		return false, units, fmt.Errorf("node %s not found from cache", node.Name)
+	}
+	tiers := make([]string, 0, len(pvcsWithTier))
+	for tier := range pvcsWithTier {
+		tiers = append(tiers, tier)
+	}
+	sort.Strings(tiers)
+
+	for _, tier := range tiers {
+		var freeDevices []cache.ExclusiveResource
+		for _, devices := range [][]cache.ExclusiveResource{freeDeviceSSD, freeDeviceHDD} {
+			for _, device := range devices {
+				if nodeCache.PerformanceTiers[cache.ResourceName(device.Name)] == tier {
+					freeDevices = append(freeDevices, device)
+				}
+			}
+		}
+		pvcs := pvcsWithTier[tier]
+		if len(freeDevices) < len(pvcs) {
+			return false, units, errors.NewInsufficientDeviceCountError(
+				int64(len(pvcs)),
+				int64(len(freeDevices)),
+				totalCount,
+				localtype.MediaType(tier),
+				node.GetName(),
+			)
+		}
+		fits, rstUnits, err := CheckExclusiveResourceMeetsPVCSize(localtype.VolumeTypeDevice, freeDevices, pvcs, node, ctx)
+		if err != nil || !fits {
+			return false, rstUnits, err
+		}
+		units = append(units, rstUnits...)
+	}
+	return true, units, nil
+}
+
+// excludeAllocatedDevices returns devices which are not allocated to units
+func excludeAllocatedDevices(devices []cache.ExclusiveResource, units []cache.AllocatedUnit) []cache.ExclusiveResource {
+	allocated := make(map[string]bool, len(units))
+	for _, unit := range units {
+		allocated[unit.Device] = true
+	}
+	var free []cache.ExclusiveResource
+	for _, device := range devices {
+		if !allocated[device.Name] {
+			free = append(free, device)
+		}
+	}
+	return free
+}
+
+// ProcessSnapshotPVC checks snapshot pvcs must be on the node of source pvc, except those restored from backup.
+// Snapshot pvc needs no extra free space of node: readonly snapshot mounts the snapshot lv
+// directly, and writable clone of thin volume is a thin snapshot sharing the thin pool.
 // If there is no snapshot pvc, just return true
 func ProcessSnapshotPVC(pvcs []*corev1.PersistentVolumeClaim, node *corev1.Node, ctx *algorithm.SchedulingContext) (fits bool, err error) {
 	nodeName := node.Name
@@ -503,6 +818,10 @@ func ProcessSnapshotPVC(pvcs []*corev1.PersistentVolumeClaim, node *corev1.Node,
 		if !utils.IsSnapshotPVC(pvc) {
 			continue
 		}
+		if algorithm.IsRestorePVC(pvc, ctx) {
+			log.Infof("[ProcessSnapshotPVC]pvc %s/%s is restored from backup, skip checking source node", pvc.Namespace, pvc.Name)
+			continue
+		}
 		log.Infof("[ProcessSnapshotPVC]data source of pvc %s/%s is snapshot", pvc.Namespace, pvc.Name)
 		// step 1: get snapshot api
 		snapName := pvc.Spec.DataSource.Name
@@ -527,6 +846,33 @@ func ProcessSnapshotPVC(pvcs []*corev1.PersistentVolumeClaim, node *corev1.Node,
 		if srcNodeName != nodeName {
 			return false, nil
 		}
+		if utils.IsThinPVC(srcPVC, ctx.StorageV1Informers) {
+			log.Infof("[ProcessSnapshotPVC]source pvc %s/%s is thin, clone of snapshot %s needs no extra free space", srcPVC.Namespace, srcPVC.Name, snapName)
+		}
+	}
+
+	return true, nil
+}
+
+// ProcessClonePVC checks clone pvcs must be on the node of source pvc, the vg of source
+// pvc is selected when allocating. If there is no clone pvc, just return true
+func ProcessClonePVC(pvcs []*corev1.PersistentVolumeClaim, node *corev1.Node, ctx *algorithm.SchedulingContext) (fits bool, err error) {
+	for _, pvc := range pvcs {
+		if !utils.IsClonePVC(pvc) {
+			continue
+		}
+		srcPVC, err := ctx.CoreV1Informers.PersistentVolumeClaims().Lister().PersistentVolumeClaims(pvc.Namespace).Get(pvc.Spec.DataSource.Name)
+		if err != nil {
+			return false, fmt.Errorf("[ProcessClonePVC]get src pvc %s/%s failed: %s", pvc.Namespace, pvc.Spec.DataSource.Name, err.Error())
+		}
+		if srcPVC.Status.Phase != corev1.ClaimBound {
+			return false, fmt.Errorf("[ProcessClonePVC]src pvc %s/%s of pvc %s is not bound", srcPVC.Namespace, srcPVC.Name, pvc.Name)
+		}
+		srcNodeName := srcPVC.Annotations[localtype.AnnoSelectedNode]
+		log.Infof("[ProcessClonePVC]pvc %s/%s is cloned from pvc %s on node %s", pvc.Namespace, pvc.Name, srcPVC.Name, srcNodeName)
+		if srcNodeName != node.Name {
+			return false, nil
+		}
 	}
 
 	return true, nil
@@ -549,7 +895,11 @@ func ScoreInlineLVMVolume(pod *corev1.Pod, node *corev1.Node, ctx *algorithm.Sch
 	if err != nil {
 		return MinScore, units, err
 	}
-	score = ScoreLVM(units, cacheVGsMap)
+	cacheThinPoolsMap, err := GetNodeThinPoolMap(node, ctx)
+	if err != nil {
+		return MinScore, units, err
+	}
+	score = ScoreLVM(units, cacheVGsMap, cacheThinPoolsMap, nil, DefaultStrategy(ctx))
 	return score, units, nil
 }
 
@@ -573,20 +923,49 @@ func ScoreLVMVolume(pod *corev1.Pod, pvcs []*corev1.PersistentVolumeClaim, node
 	if err != nil {
 		return MinScore, units, err
 	}
-	score = ScoreLVM(units, cacheVGsMap)
+	cacheThinPoolsMap, err := GetNodeThinPoolMap(node, ctx)
+	if err != nil {
+		return MinScore, units, err
+	}
+	score = ScoreLVM(units, cacheVGsMap, cacheThinPoolsMap, pvcStrategies(pvcs, ctx), DefaultStrategy(ctx))
 	return score, units, nil
 }
 
 func ProcessLVMPVCPriority(pod *corev1.Pod, pvcs []*corev1.PersistentVolumeClaim, node *corev1.Node, ctx *algorithm.SchedulingContext) (fits bool, units []cache.AllocatedUnit, err error) {
-	pvcsWithVG, pvcsWithoutVG := DivideLVMPVCs(pvcs, ctx)
-	cacheVGsMap, err := GetNodeVGMap(node, ctx)
-	if err != nil {
-		return false, units, err
+	thickPVCs, thinPVCs := DivideThinPVCs(pvcs, ctx)
+	if len(thickPVCs) > 0 {
+		cacheVGsMap, err := GetNodeVGMap(node, ctx)
+		if err != nil {
+			return false, units, err
+		}
+		if fits, units, err = processLVMPVCPriority(pod, thickPVCs, node, ctx, cacheVGsMap); !fits {
+			return false, units, err
+		}
+	}
+	if len(thinPVCs) > 0 {
+		cacheThinPoolsMap, err := GetNodeThinPoolMap(node, ctx)
+		if err != nil {
+			return false, units, err
+		}
+		fits, thinUnits, err := processLVMPVCPriority(pod, thinPVCs, node, ctx, cacheThinPoolsMap)
+		if !fits {
+			return false, units, err
+		}
+		units = append(units, markThin(thinUnits)...)
 	}
+	if len(units) <= 0 {
+		return false, units, nil
+	}
+	return true, units, nil
+}
+
+// processLVMPVCPriority allocates pvcs from cacheVGsMap, which is either the VGs or the thin pools of node
+func processLVMPVCPriority(pod *corev1.Pod, pvcs []*corev1.PersistentVolumeClaim, node *corev1.Node, ctx *algorithm.SchedulingContext, cacheVGsMap map[cache.ResourceName]cache.SharedResource) (fits bool, units []cache.AllocatedUnit, err error) {
+	pvcsWithVG, pvcsWithoutVG := DivideLVMPVCs(pvcs, ctx)
 
 	// process pvcsWithVG first
 	for _, pvc := range pvcsWithVG {
-		vgName := utils.GetVGNameFromPVC(pvc, ctx.StorageV1Informers)
+		vgName := GetVGNameFromPVC(pvc, ctx)
 		if _, ok := cacheVGsMap[cache.ResourceName(vgName)]; !ok {
 			return false, units, fmt.Errorf("no vg named %s on node %s", vgName, node.Name)
 		}
@@ -619,150 +998,102 @@ func ProcessLVMPVCPriority(pod *corev1.Pod, pvcs []*corev1.PersistentVolumeClaim
 		units = append(units, u)
 	}
 
-	// process pvcsWithoutVG(default strategy: Binpack)
-	for _, pvc := range pvcsWithoutVG {
-		switch localtype.SchedulerStrategy {
-		case localtype.StrategyBinpack:
-			fits, tmpunits, err := Binpack(pod, pvc, node, cacheVGsMap)
-			if !fits {
-				return false, units, err
-			}
-			units = append(units, tmpunits...)
-		case localtype.StrategySpread:
-			fits, tmpunits, err := Spread(pod, pvc, node, cacheVGsMap)
-			if !fits {
-				return false, units, err
-			}
-			units = append(units, tmpunits...)
+	// VGs on faulty disks are not candidates of pvcsWithoutVG
+	faulty := faultyResources(node, ctx)
+	candidateVGsMap := make(map[cache.ResourceName]cache.SharedResource, len(cacheVGsMap))
+	for name, vg := range cacheVGsMap {
+		if !faulty[name] {
+			candidateVGsMap[name] = vg
 		}
 	}
-	if len(units) <= 0 {
-		return false, units, nil
-	}
-	return true, units, nil
-}
-
-func Binpack(pod *corev1.Pod, pvc *corev1.PersistentVolumeClaim, node *corev1.Node, cacheVGsMap map[cache.ResourceName]cache.SharedResource) (fits bool, units []cache.AllocatedUnit, err error) {
-	requestedSize := utils.GetPVCRequested(pvc)
-
-	// make a copy slice of cacheVGsMap
-	cacheVGsSlice := make([]cache.SharedResource, len(cacheVGsMap))
-	for _, vg := range cacheVGsMap {
-		cacheVGsSlice = append(cacheVGsSlice, vg)
+	if len(pvcsWithoutVG) > 0 && len(candidateVGsMap) <= 0 {
+		return false, units, errors.NewNoAvailableVGError(node.Name)
 	}
 
-	// sort from small to large according to free size
-	sort.Slice(cacheVGsSlice, func(i, j int) bool {
-		return (cacheVGsSlice[i].Capacity - cacheVGsSlice[i].Requested) < (cacheVGsSlice[j].Capacity - cacheVGsSlice[j].Requested)
-	})
-	for i, vg := range cacheVGsSlice {
-		freeSize := vg.Capacity - vg.Requested
-		quanFree := resource.NewQuantity(freeSize, resource.BinarySI)
-		quanReq := resource.NewQuantity(requestedSize, resource.BinarySI)
-		if freeSize < requestedSize {
-			if i == len(cacheVGsSlice)-1 {
-				if pod == nil {
-					return false, units, fmt.Errorf("[multipleVGs]not enough lv storage on %s, requested size %s, max free size[VG: %s] %s, strategiy %s. you need to expand the vg",
-						node.Name, quanReq.String(), vg.Name, quanFree.String(), localtype.SchedulerStrategy)
-
-				}
-				return false, units, fmt.Errorf("[multipleVGs]not enough lv storage on %s for pod %s/%s, requested size %s, max free size[VG: %s] %s, strategiy %s. you need to expand the vg",
-					node.Name, pod.Namespace, pod.Name, quanReq.String(), vg.Name, quanFree.String(), localtype.SchedulerStrategy)
+	// process pvcsWithoutVG according to the strategy of each pvc
+	nodeCache := ctx.ClusterNodeCache.GetNodeCache(node.Name)
+	volumes := resourceVolumes(nodeCache)
+	for _, pvc := range pvcsWithoutVG {
+		strategy := GetStrategyFromPVC(pvc, ctx)
+		request := StrategyRequest{
+			Size:      utils.GetPVCRequested(pvc),
+			MediaType: utils.GetMediaTypeFromPVC(pvc, ctx.StorageV1Informers),
+		}
+		candidates := make([]Candidate, 0, len(candidateVGsMap))
+		for name, vg := range candidateVGsMap {
+			c := Candidate{SharedResource: vg, Volumes: volumes[name]}
+			if nodeCache != nil {
+				c.MediaType = nodeCache.MediaTypes[name]
 			}
-			continue
+			candidates = append(candidates, c)
 		}
-		tmp := cacheVGsMap[cache.ResourceName(vg.Name)]
-		tmp.Requested += requestedSize
-		cacheVGsMap[cache.ResourceName(vg.Name)] = tmp
+		vg, ok := selectCandidate(strategy, request, candidates)
+		if !ok {
+			max := maxFreeCandidate(candidates)
+			quanFree := resource.NewQuantity(max.free(), resource.BinarySI)
+			quanReq := resource.NewQuantity(request.Size, resource.BinarySI)
+			if pod == nil {
+				return false, units, fmt.Errorf("[multipleVGs]not enough lv storage on %s, requested size %s, max free size[VG: %s] %s, strategy %s. you need to expand the vg",
+					node.Name, quanReq.String(), max.Name, quanFree.String(), strategy.Name())
+			}
+			return false, units, fmt.Errorf("[multipleVGs]not enough lv storage on %s for pod %s/%s, requested size %s, max free size[VG: %s] %s, strategy %s. you need to expand the vg",
+				node.Name, pod.Namespace, pod.Name, quanReq.String(), max.Name, quanFree.String(), strategy.Name())
+		}
+		tmp := candidateVGsMap[cache.ResourceName(vg.Name)]
+		tmp.Requested += request.Size
+		candidateVGsMap[cache.ResourceName(vg.Name)] = tmp
+		volumes[cache.ResourceName(vg.Name)]++
 		u := cache.AllocatedUnit{
 			NodeName:   node.Name,
 			VolumeType: localtype.VolumeTypeLVM,
-			Requested:  requestedSize,
-			Allocated:  requestedSize, // for LVM requested is always equal to allocated
-			VgName:     string(vg.Name),
+			Requested:  request.Size,
+			Allocated:  request.Size, // for LVM requested is always equal to allocated
+			VgName:     vg.Name,
 			Device:     "",
 			MountPoint: "",
 			PVCName:    utils.PVCName(pvc),
 		}
 		units = append(units, u)
-		break
 	}
-
-	return true, units, nil
-}
-
-func Spread(pod *corev1.Pod, pvc *corev1.PersistentVolumeClaim, node *corev1.Node, cacheVGsMap map[cache.ResourceName]cache.SharedResource) (fits bool, units []cache.AllocatedUnit, err error) {
-	requestedSize := utils.GetPVCRequested(pvc)
-
-	// make a copy slice of cacheVGsMap
-	cacheVGsSlice := make([]cache.SharedResource, len(cacheVGsMap))
-	for _, vg := range cacheVGsMap {
-		cacheVGsSlice = append(cacheVGsSlice, vg)
+	if len(units) <= 0 {
+		return false, units, nil
 	}
-
-	// sort from large to small according to free size
-	sort.Slice(cacheVGsSlice, func(i, j int) bool {
-		return (cacheVGsSlice[i].Capacity - cacheVGsSlice[i].Requested) > (cacheVGsSlice[j].Capacity - cacheVGsSlice[j].Requested)
-	})
-	// the free size of cacheVGsSlice[0] is largest
-	freeSize := cacheVGsSlice[0].Capacity - cacheVGsSlice[0].Requested
-	quanFree := resource.NewQuantity(freeSize, resource.BinarySI)
-	quanReq := resource.NewQuantity(requestedSize, resource.BinarySI)
-	if freeSize < requestedSize {
-		if pod == nil {
-			return false, units, fmt.Errorf("[multipleVGs]not enough lv storage on %s/%s, requested size %s,  free size %s, strategiy %s. you need to expand the vg",
-				node.Name, cacheVGsSlice[0].Name, quanReq.String(), quanFree.String(), localtype.SchedulerStrategy)
-		}
-		return false, units, fmt.Errorf("[multipleVGs]not enough lv storage on %s/%s for pod %s/%s, requested size %s,  free size %s, strategiy %s. you need to expand the vg",
-			node.Name, cacheVGsSlice[0].Name, pod.Namespace, pod.Name, quanReq.String(), quanFree.String(), localtype.SchedulerStrategy)
-	}
-	cacheVGsSlice[0].Requested += requestedSize
-	u := cache.AllocatedUnit{
-		NodeName:   node.Name,
-		VolumeType: localtype.VolumeTypeLVM,
-		Requested:  requestedSize,
-		Allocated:  requestedSize, // for LVM requested is always equal to allocated
-		VgName:     string(cacheVGsSlice[0].Name),
-		Device:     "",
-		MountPoint: "",
-		PVCName:    utils.PVCName(pvc),
-	}
-	units = append(units, u)
-
 	return true, units, nil
 }
 
-func ScoreLVM(units []cache.AllocatedUnit, cacheVGsMap map[cache.ResourceName]cache.SharedResource) (score int) {
+// ScoreLVM scores the usage of VGs and thin pools by units, each unit is scored by the strategy of its pvc in strategies,
+// or defaultStrategy for inline volumes
+func ScoreLVM(units []cache.AllocatedUnit, cacheVGsMap, cacheThinPoolsMap map[cache.ResourceName]cache.SharedResource, strategies map[string]Strategy, defaultStrategy Strategy) (score int) {
 	if len(units) == 0 {
 		return MinScore
 	}
-	// make a map store VG size pvcs used
-	// key: VG name
+	type scoreKey struct {
+		vg       string
+		thin     bool
+		strategy localtype.StrategyType
+	}
+	// make a map store VG size pvcs used of each strategy
+	// key: VG name, whether thin and strategy name
 	// value: used size
-	scoreMap := make(map[string]int64)
+	scoreMap := make(map[scoreKey]int64)
+	strategyMap := make(map[localtype.StrategyType]Strategy)
 	for _, unit := range units {
-		if size, ok := scoreMap[unit.VgName]; ok {
-			size += unit.Allocated
-			scoreMap[unit.VgName] = size
-		} else {
-			scoreMap[unit.VgName] = unit.Allocated
-		}
+		strategy := strategyOf(strategies, defaultStrategy, unit.PVCName)
+		strategyMap[strategy.Name()] = strategy
+		// thin volumes are scored against the thin pool of VG
+		scoreMap[scoreKey{vg: unit.VgName, thin: unit.Thin, strategy: strategy.Name()}] += unit.Allocated
 	}
 
 	// score
 	var scoref float64 = 0
 	count := 0
-	switch localtype.SchedulerStrategy {
-	case localtype.StrategyBinpack:
-		for vg, used := range scoreMap {
-			scoref += float64(used) / float64(cacheVGsMap[cache.ResourceName(vg)].Capacity)
-			count++
-		}
-	case localtype.StrategySpread:
-		for vg, used := range scoreMap {
-			scoref += (1.0 - float64(used)/float64(cacheVGsMap[cache.ResourceName(vg)].Capacity))
-			count++
+	for key, used := range scoreMap {
+		capacity := cacheVGsMap[cache.ResourceName(key.vg)].Capacity
+		if key.thin {
+			capacity = cacheThinPoolsMap[cache.ResourceName(key.vg)].Capacity
 		}
+		scoref += strategyMap[key.strategy].Score(float64(used) / float64(capacity))
+		count++
 	}
 	score = int(scoref / float64(count) * float64(MaxScore))
 
@@ -844,3 +1175,153 @@ func ScoreDevice(units []cache.AllocatedUnit) (score int) {
 	score = int(scoref / float64(len(units)) * float64(MaxScore))
 	return score
 }
+
+func ScoreQuotaVolume(
+	pod *corev1.Pod, pvcs []*corev1.PersistentVolumeClaim, node *corev1.Node,
+	ctx *algorithm.SchedulingContext) (score int, units []cache.AllocatedUnit, err error) {
+	if len(pvcs) <= 0 {
+		return
+	}
+	if pod != nil {
+		log.Infof("allocating quota volume for pod %s/%s", pod.Namespace, pod.Name)
+	}
+
+	fits, units, err := ProcessQuotaPVC(pod, pvcs, node, ctx)
+	if err != nil {
+		return MinScore, units, err
+	}
+	if !fits {
+		return MinScore, units, nil
+	}
+
+	cacheQuotasMap, err := GetNodeQuotaMap(node, ctx)
+	if err != nil {
+		return MinScore, units, err
+	}
+	score = ScoreQuota(units, cacheQuotasMap, pvcStrategies(pvcs, ctx), DefaultStrategy(ctx))
+	return score, units, nil
+}
+
+// ScoreQuota scores the usage of mount points by units, each unit is scored by the strategy of its pvc in strategies
+func ScoreQuota(units []cache.AllocatedUnit, cacheQuotasMap map[cache.ResourceName]cache.SharedResource, strategies map[string]Strategy, defaultStrategy Strategy) (score int) {
+	if len(units) == 0 {
+		return MinScore
+	}
+	type scoreKey struct {
+		mp       string
+		strategy localtype.StrategyType
+	}
+	// key: mount point name and strategy name
+	// value: used size
+	scoreMap := make(map[scoreKey]int64)
+	strategyMap := make(map[localtype.StrategyType]Strategy)
+	for _, unit := range units {
+		strategy := strategyOf(strategies, defaultStrategy, unit.PVCName)
+		strategyMap[strategy.Name()] = strategy
+		scoreMap[scoreKey{mp: unit.MountPoint, strategy: strategy.Name()}] += unit.Allocated
+	}
+
+	// score
+	var scoref float64 = 0
+	count := 0
+	for key, used := range scoreMap {
+		quota := cacheQuotasMap[cache.ResourceName(key.mp)]
+		if quota.Capacity <= 0 {
+			continue
+		}
+		ratio := float64(quota.Requested+used) / float64(quota.Capacity)
+		scoref += strategyMap[key.strategy].Score(ratio)
+		count++
+	}
+	if count == 0 {
+		return MinScore
+	}
+	score = int(scoref / float64(count) * float64(MaxScore))
+
+	return
+}
+
+// AllocateVolumes allocates the local storage and io capacity on node for the pvcs of each volume type
+func AllocateVolumes(pod *corev1.Pod, lvmPVCs, mpPVCs, devicePVCs, quotaPVCs []*corev1.PersistentVolumeClaim, node *corev1.Node, ctx *algorithm.SchedulingContext) (allocatedUnits []cache.AllocatedUnit, err error) {
+	_, lvmUnits, err := ScoreLVMVolume(pod, lvmPVCs, node, ctx)
+	if err != nil {
+		return nil, err
+	}
+	allocatedUnits = append(allocatedUnits, lvmUnits...)
+	_, mpUnits, err := ScoreMountPointVolume(pod, mpPVCs, node, ctx)
+	if err != nil {
+		return nil, err
+	}
+	allocatedUnits = append(allocatedUnits, mpUnits...)
+	_, deviceUnits, err := ScoreDeviceVolume(pod, devicePVCs, node, ctx)
+	if err != nil {
+		return nil, err
+	}
+	allocatedUnits = append(allocatedUnits, deviceUnits...)
+	_, quotaUnits, err := ScoreQuotaVolume(pod, quotaPVCs, node, ctx)
+	if err != nil {
+		return nil, err
+	}
+	allocatedUnits = append(allocatedUnits, quotaUnits...)
+
+	if len(allocatedUnits) != len(lvmPVCs)+len(mpPVCs)+len(devicePVCs)+len(quotaPVCs) {
+		return nil, fmt.Errorf("unexpected allocated unit number: %d", len(allocatedUnits))
+	}
+	SetIORequests(allocatedUnits, ctx)
+	if fits, err := ProcessIOCapacity(allocatedUnits, node, ctx); !fits {
+		return nil, fmt.Errorf("failed to allocate io capacity: %v", err)
+	}
+	return allocatedUnits, nil
+}
+
+// PlanVolumes returns the allocation plan for all the pvcs of a pod on node, so that either all of
+// them fit on node or none. The pvcs with allocation annotations are planned as annotated, and the
+// others are allocated with the annotated ones accounted.
+func PlanVolumes(pod *corev1.Pod, lvmPVCs, mpPVCs, devicePVCs, quotaPVCs []*corev1.PersistentVolumeClaim, node *corev1.Node, ctx *algorithm.SchedulingContext) (plan []cache.AllocatedUnit, err error) {
+	filter := func(pvcs []*corev1.PersistentVolumeClaim) (pending []*corev1.PersistentVolumeClaim, err error) {
+		for _, pvc := range pvcs {
+			unit, err := GetAllocatedUnitFromPVC(pvc, node, ctx)
+			if err != nil {
+				return nil, err
+			}
+			if unit == nil {
+				pending = append(pending, pvc)
+			} else {
+				plan = append(plan, *unit)
+			}
+		}
+		return pending, nil
+	}
+	if lvmPVCs, err = filter(lvmPVCs); err != nil {
+		return nil, err
+	}
+	if mpPVCs, err = filter(mpPVCs); err != nil {
+		return nil, err
+	}
+	if devicePVCs, err = filter(devicePVCs); err != nil {
+		return nil, err
+	}
+	if quotaPVCs, err = filter(quotaPVCs); err != nil {
+		return nil, err
+	}
+	if len(lvmPVCs)+len(mpPVCs)+len(devicePVCs)+len(quotaPVCs) == 0 {
+		return plan, nil
+	}
+
+	for i := range plan {
+		if err := ctx.ClusterNodeCache.Assume(plan[i : i+1]); err != nil {
+			if err := ctx.ClusterNodeCache.Unassume(plan[:i]); err != nil {
+				log.Errorf("failed to unassume annotated units: %s", err.Error())
+			}
+			return nil, fmt.Errorf("annotated allocation does not fit node %s: %s", node.Name, err.Error())
+		}
+	}
+	units, err := AllocateVolumes(pod, lvmPVCs, mpPVCs, devicePVCs, quotaPVCs, node, ctx)
+	if err := ctx.ClusterNodeCache.Unassume(plan); err != nil {
+		log.Errorf("failed to unassume annotated units: %s", err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(plan, units...), nil
+}
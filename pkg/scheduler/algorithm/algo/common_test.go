@@ -0,0 +1,244 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algo
+
+import (
+	"testing"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func newTierPVC(name, storageClass string, size string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+			},
+		},
+	}
+}
+
+func TestPerformanceTier(t *testing.T) {
+	nodeName := "testnode"
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+	factory := informers.NewSharedInformerFactory(k8sfake.NewSimpleClientset(), 0)
+	for _, sc := range []*storagev1.StorageClass{
+		{ObjectMeta: metav1.ObjectMeta{Name: "lvm-fast"}, Parameters: map[string]string{localtype.VolumePerformanceTier: "nvme-fast"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "lvm-archive"}, Parameters: map[string]string{localtype.VolumePerformanceTier: "archive"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "device-fast"}, Parameters: map[string]string{localtype.VolumePerformanceTier: "nvme-fast"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "device-ssd"}, Parameters: map[string]string{localtype.VolumeMediaType: string(localtype.MediaTypeSSD)}},
+	} {
+		factory.Storage().V1().StorageClasses().Informer().GetIndexer().Add(sc)
+	}
+	ctx := &algorithm.SchedulingContext{
+		ClusterNodeCache:   cache.NewClusterNodeCache(),
+		CoreV1Informers:    factory.Core().V1(),
+		StorageV1Informers: factory.Storage().V1(),
+	}
+	nc := cache.NewNodeCache(nodeName)
+	nc.VGs["fast"] = cache.SharedResource{Name: "fast", Capacity: 100 << 30}
+	nc.VGs["slow"] = cache.SharedResource{Name: "slow", Capacity: 1000 << 30}
+	nc.Devices["/dev/nvme0n1"] = cache.ExclusiveResource{Name: "/dev/nvme0n1", Capacity: 100 << 30, MediaType: localtype.MediaTypeSSD}
+	nc.Devices["/dev/sdb"] = cache.ExclusiveResource{Name: "/dev/sdb", Capacity: 100 << 30, MediaType: localtype.MediaTypeSSD}
+	nc.PerformanceTiers["fast"] = "nvme-fast"
+	nc.PerformanceTiers["/dev/nvme0n1"] = "nvme-fast"
+	ctx.ClusterNodeCache.SetNodeCache(nc)
+
+	lvmTests := []struct {
+		name string
+		pvcs []*corev1.PersistentVolumeClaim
+		fits bool
+		vgs  []string
+	}{
+		{
+			name: "lvm pvc is allocated from vg of tier",
+			pvcs: []*corev1.PersistentVolumeClaim{newTierPVC("data", "lvm-fast", "10Gi")},
+			fits: true,
+			vgs:  []string{"fast"},
+		},
+		{
+			name: "vg of tier is not enough",
+			pvcs: []*corev1.PersistentVolumeClaim{newTierPVC("data", "lvm-fast", "200Gi")},
+		},
+		{
+			name: "no vg of tier",
+			pvcs: []*corev1.PersistentVolumeClaim{newTierPVC("data", "lvm-archive", "10Gi")},
+		},
+	}
+	for _, test := range lvmTests {
+		t.Run(test.name, func(t *testing.T) {
+			fits, units, err := ProcessLVMPVCPredicate(test.pvcs, node, ctx)
+			if fits != test.fits {
+				t.Fatalf("ProcessLVMPVCPredicate() = %t, expected %t, err: %v", fits, test.fits, err)
+			}
+			if !fits {
+				if err == nil {
+					t.Errorf("expected error")
+				}
+				return
+			}
+			for i, unit := range units {
+				if unit.VgName != test.vgs[i] {
+					t.Errorf("pvc %s is allocated from vg %s, expected %s", unit.PVCName, unit.VgName, test.vgs[i])
+				}
+			}
+		})
+	}
+
+	deviceTests := []struct {
+		name    string
+		pvcs    []*corev1.PersistentVolumeClaim
+		fits    bool
+		devices map[string]string
+	}{
+		{
+			name: "device of tier is allocated before media type",
+			pvcs: []*corev1.PersistentVolumeClaim{
+				newTierPVC("ssd", "device-ssd", "10Gi"),
+				newTierPVC("fast", "device-fast", "10Gi"),
+			},
+			fits:    true,
+			devices: map[string]string{"default/fast": "/dev/nvme0n1", "default/ssd": "/dev/sdb"},
+		},
+		{
+			name: "insufficient devices of tier",
+			pvcs: []*corev1.PersistentVolumeClaim{
+				newTierPVC("fast-1", "device-fast", "10Gi"),
+				newTierPVC("fast-2", "device-fast", "10Gi"),
+			},
+		},
+	}
+	for _, test := range deviceTests {
+		t.Run(test.name, func(t *testing.T) {
+			fits, units, err := ProcessDevicePVC(nil, test.pvcs, node, ctx)
+			if fits != test.fits {
+				t.Fatalf("ProcessDevicePVC() = %t, expected %t, err: %v", fits, test.fits, err)
+			}
+			if !fits {
+				if err == nil {
+					t.Errorf("expected error")
+				}
+				return
+			}
+			if len(units) != len(test.devices) {
+				t.Fatalf("unexpected units %+v", units)
+			}
+			for _, unit := range units {
+				if unit.Device != test.devices[unit.PVCName] {
+					t.Errorf("pvc %s is allocated device %s, expected %s", unit.PVCName, unit.Device, test.devices[unit.PVCName])
+				}
+			}
+		})
+	}
+}
+
+func TestStorageFault(t *testing.T) {
+	nodeName := "testnode"
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+	factory := informers.NewSharedInformerFactory(k8sfake.NewSimpleClientset(), 0)
+	for _, sc := range []*storagev1.StorageClass{
+		{ObjectMeta: metav1.ObjectMeta{Name: "lvm"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "lvm-faulty"}, Parameters: map[string]string{localtype.VGName: "faulty"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "device-ssd"}, Parameters: map[string]string{localtype.VolumeMediaType: string(localtype.MediaTypeSSD)}},
+	} {
+		factory.Storage().V1().StorageClasses().Informer().GetIndexer().Add(sc)
+	}
+	ctx := &algorithm.SchedulingContext{
+		ClusterNodeCache:   cache.NewClusterNodeCache(),
+		CoreV1Informers:    factory.Core().V1(),
+		StorageV1Informers: factory.Storage().V1(),
+	}
+	nc := cache.NewNodeCache(nodeName)
+	nc.VGs["faulty"] = cache.SharedResource{Name: "faulty", Capacity: 1000 << 30}
+	nc.VGs["healthy"] = cache.SharedResource{Name: "healthy", Capacity: 100 << 30}
+	nc.Devices["/dev/sdb"] = cache.ExclusiveResource{Name: "/dev/sdb", Capacity: 100 << 30, MediaType: localtype.MediaTypeSSD}
+	nc.Devices["/dev/sdc"] = cache.ExclusiveResource{Name: "/dev/sdc", Capacity: 100 << 30, MediaType: localtype.MediaTypeSSD}
+	nc.FaultyResources["faulty"] = true
+	nc.FaultyResources["/dev/sdb"] = true
+	ctx.ClusterNodeCache.SetNodeCache(nc)
+
+	fits, units, err := ProcessLVMPVCPredicate([]*corev1.PersistentVolumeClaim{newTierPVC("data", "lvm", "10Gi")}, node, ctx)
+	if !fits || len(units) != 1 || units[0].VgName != "healthy" {
+		t.Errorf("lvm pvc is expected to be allocated from healthy vg, got %t, %+v, %v", fits, units, err)
+	}
+	_, units, err = ScoreLVMVolume(nil, []*corev1.PersistentVolumeClaim{newTierPVC("data", "lvm", "10Gi")}, node, ctx)
+	if err != nil || len(units) != 1 || units[0].VgName != "healthy" {
+		t.Errorf("lvm pvc is expected to be scored on healthy vg, got %+v, %v", units, err)
+	}
+	if fits, _, err = ProcessLVMPVCPredicate([]*corev1.PersistentVolumeClaim{newTierPVC("data", "lvm-faulty", "10Gi")}, node, ctx); fits || err == nil {
+		t.Errorf("lvm pvc of faulty vg is expected to be refused, got %t, %v", fits, err)
+	}
+	if fits, _, err = ProcessLVMPVCPredicate([]*corev1.PersistentVolumeClaim{newTierPVC("data", "lvm", "200Gi")}, node, ctx); fits || err == nil {
+		t.Errorf("lvm pvc larger than healthy vg is expected to be refused, got %t, %v", fits, err)
+	}
+
+	fits, units, err = ProcessDevicePVC(nil, []*corev1.PersistentVolumeClaim{newTierPVC("data", "device-ssd", "10Gi")}, node, ctx)
+	if !fits || len(units) != 1 || units[0].Device != "/dev/sdc" {
+		t.Errorf("device pvc is expected to be allocated healthy device, got %t, %+v, %v", fits, units, err)
+	}
+	fits, _, err = ProcessDevicePVC(nil, []*corev1.PersistentVolumeClaim{
+		newTierPVC("data-1", "device-ssd", "10Gi"),
+		newTierPVC("data-2", "device-ssd", "10Gi"),
+	}, node, ctx)
+	if fits || err == nil {
+		t.Errorf("faulty device is expected not to be allocated, got %t, %v", fits, err)
+	}
+}
+
+func TestPlanVolumes(t *testing.T) {
+	nodeName := "testnode"
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+	factory := informers.NewSharedInformerFactory(k8sfake.NewSimpleClientset(), 0)
+	factory.Storage().V1().StorageClasses().Informer().GetIndexer().Add(&storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "open-local-lvm"},
+		Provisioner: localtype.ProvisionerName,
+		Parameters:  map[string]string{localtype.VolumeTypeKey: string(localtype.VolumeTypeLVM)},
+	})
+	ctx := &algorithm.SchedulingContext{
+		ClusterNodeCache:   cache.NewClusterNodeCache(),
+		CoreV1Informers:    factory.Core().V1(),
+		StorageV1Informers: factory.Storage().V1(),
+	}
+	nc := cache.NewNodeCache(nodeName)
+	nc.VGs["share"] = cache.SharedResource{Name: "share", Capacity: 100 << 30}
+	ctx.ClusterNodeCache.SetNodeCache(nc)
+
+	annotated := newTierPVC("annotated", "open-local-lvm", "60Gi")
+	annotated.Annotations = AllocationAnnotations(cache.AllocatedUnit{NodeName: nodeName, VolumeType: localtype.VolumeTypeLVM, VgName: "share"})
+
+	// the annotated pvc is accounted when allocating the others
+	if _, err := PlanVolumes(nil, []*corev1.PersistentVolumeClaim{annotated, newTierPVC("large", "open-local-lvm", "60Gi")}, nil, nil, nil, node, ctx); err == nil {
+		t.Errorf("PlanVolumes() should fail when vg share is not enough for all pvcs")
+	}
+	plan, err := PlanVolumes(nil, []*corev1.PersistentVolumeClaim{annotated, newTierPVC("small", "open-local-lvm", "30Gi")}, nil, nil, nil, node, ctx)
+	if err != nil || len(plan) != 2 {
+		t.Fatalf("PlanVolumes() = %+v, %v, expected 2 units", plan, err)
+	}
+	if requested := ctx.ClusterNodeCache.GetNodeCache(nodeName).VGs["share"].Requested; requested != 0 {
+		t.Errorf("requested of vg share after planning is %d, expected 0", requested)
+	}
+}
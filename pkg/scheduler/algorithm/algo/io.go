@@ -0,0 +1,130 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algo
+
+import (
+	"fmt"
+
+	nodelocalstorage "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	"github.com/alibaba/open-local/pkg/scheduler/errors"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	clientgocache "k8s.io/client-go/tools/cache"
+)
+
+// SetIORequests sets the io limits of pvcs to the units
+func SetIORequests(units []cache.AllocatedUnit, ctx *algorithm.SchedulingContext) {
+	for i := range units {
+		namespace, name, err := clientgocache.SplitMetaNamespaceKey(units[i].PVCName)
+		if err != nil {
+			continue
+		}
+		pvc, err := ctx.CoreV1Informers.PersistentVolumeClaims().Lister().PersistentVolumeClaims(namespace).Get(name)
+		if err != nil {
+			log.Warningf("failed to get pvc %s for io limits: %s", units[i].PVCName, err.Error())
+			continue
+		}
+		units[i].IO = algorithm.GetPVCIORequest(pvc, ctx)
+	}
+}
+
+// GetIORequested returns the io limits of the allocated volumes on node, keyed by VG or device name.
+// The pvcs of units are excluded, for they are being scheduled again.
+func GetIORequested(nodeName string, units []cache.AllocatedUnit, ctx *algorithm.SchedulingContext) map[cache.ResourceName]cache.IORequest {
+	scheduling := make(map[string]bool, len(units))
+	for _, unit := range units {
+		scheduling[unit.PVCName] = true
+	}
+	requested := make(map[cache.ResourceName]cache.IORequest)
+	for pvcName, unit := range ctx.ClusterNodeCache.BindingInfo {
+		if unit == nil || unit.NodeName != nodeName || scheduling[pvcName] {
+			continue
+		}
+		if name := unit.IOResourceName(); name != "" {
+			requested[name] = requested[name].Add(unit.IO)
+		}
+	}
+	return requested
+}
+
+// ProcessIOCapacity checks whether the io budget of VGs and devices on node is enough for the
+// io limits of units, which are set by SetIORequests
+func ProcessIOCapacity(units []cache.AllocatedUnit, node *corev1.Node, ctx *algorithm.SchedulingContext) (fits bool, err error) {
+	nc := ctx.ClusterNodeCache.GetNodeCache(node.Name)
+	if nc == nil {
+		return false, fmt.Errorf("node %s not found from cache", node.Name)
+	}
+	if len(nc.IOCapacities) == 0 {
+		return true, nil
+	}
+	requested := GetIORequested(node.Name, units, ctx)
+	for _, unit := range units {
+		name := unit.IOResourceName()
+		capacity, ok := nc.IOCapacities[name]
+		if !ok || unit.IO.IsZero() {
+			continue
+		}
+		used := requested[name]
+		requested[name] = used.Add(unit.IO)
+		if item := requested[name].Exceeds(capacity); item != nil {
+			return false, errors.NewInsufficientIOError(unit.VolumeType, item.Name,
+				ioItem(unit.IO, item.Name), ioItem(used, item.Name), item.Capacity, string(name), node.Name)
+		}
+	}
+	log.Debugf("node %s is capable of io limits of %d pvcs", node.Name, len(units))
+	return true, nil
+}
+
+// ScoreIOCapacity scores the free io budget of VGs and devices after units are allocated,
+// units without io limits or budget get MaxScore
+func ScoreIOCapacity(units []cache.AllocatedUnit, node *corev1.Node, ctx *algorithm.SchedulingContext) (score int) {
+	nc := ctx.ClusterNodeCache.GetNodeCache(node.Name)
+	if nc == nil || len(units) == 0 {
+		return MinScore
+	}
+	requested := GetIORequested(node.Name, units, ctx)
+	for _, unit := range units {
+		if name := unit.IOResourceName(); name != "" {
+			requested[name] = requested[name].Add(unit.IO)
+		}
+	}
+	scoref := 0.0
+	count := 0
+	for _, unit := range units {
+		name := unit.IOResourceName()
+		capacity, ok := nc.IOCapacities[name]
+		if !ok || unit.IO.IsZero() {
+			scoref += 1
+		} else if utilization := requested[name].Utilization(capacity); utilization < 1 {
+			scoref += 1 - utilization
+		}
+		count++
+	}
+	return int(scoref / float64(count) * float64(MaxScore))
+}
+
+// ioItem returns the io limit of request by name
+func ioItem(request cache.IORequest, name string) uint64 {
+	for _, item := range request.Items(nodelocalstorage.IOCapacity{}) {
+		if item.Name == name {
+			return item.Requested
+		}
+	}
+	return 0
+}
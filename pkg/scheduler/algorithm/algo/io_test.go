@@ -0,0 +1,102 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algo
+
+import (
+	"testing"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	nodelocalstorage "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	"github.com/alibaba/open-local/pkg/scheduler/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestProcessIOCapacity(t *testing.T) {
+	nodeName := "testnode"
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+	ctx := &algorithm.SchedulingContext{ClusterNodeCache: cache.NewClusterNodeCache()}
+	nc := cache.NewNodeCache(nodeName)
+	nc.IOCapacities["hdd"] = nodelocalstorage.IOCapacity{WriteIOPS: 1000}
+	ctx.ClusterNodeCache.SetNodeCache(nc)
+	// four throttled databases are already on the vg
+	for _, name := range []string{"db-1", "db-2", "db-3", "db-4"} {
+		ctx.ClusterNodeCache.BindingInfo["default/"+name] = &cache.AllocatedUnit{
+			NodeName:   nodeName,
+			VolumeType: localtype.VolumeTypeLVM,
+			VgName:     "hdd",
+			PVCName:    "default/" + name,
+			IO:         cache.IORequest{ReadIOPS: 1000, WriteIOPS: 200},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		units []cache.AllocatedUnit
+		fits  bool
+		score int
+	}{
+		{
+			name:  "fits the rest of io budget",
+			units: []cache.AllocatedUnit{{NodeName: nodeName, VolumeType: localtype.VolumeTypeLVM, VgName: "hdd", PVCName: "default/db-5", IO: cache.IORequest{WriteIOPS: 200}}},
+			fits:  true,
+			score: MinScore,
+		},
+		{
+			name:  "exceeds io budget",
+			units: []cache.AllocatedUnit{{NodeName: nodeName, VolumeType: localtype.VolumeTypeLVM, VgName: "hdd", PVCName: "default/db-5", IO: cache.IORequest{WriteIOPS: 201}}},
+			fits:  false,
+			score: MinScore,
+		},
+		{
+			name:  "rescheduled pvc is not counted twice",
+			units: []cache.AllocatedUnit{{NodeName: nodeName, VolumeType: localtype.VolumeTypeLVM, VgName: "hdd", PVCName: "default/db-4", IO: cache.IORequest{WriteIOPS: 100}}},
+			fits:  true,
+			score: 3,
+		},
+		{
+			name:  "vg without io budget",
+			units: []cache.AllocatedUnit{{NodeName: nodeName, VolumeType: localtype.VolumeTypeLVM, VgName: "ssd", PVCName: "default/db-5", IO: cache.IORequest{WriteIOPS: 10000}}},
+			fits:  true,
+			score: MaxScore,
+		},
+		{
+			name:  "pvc without io limits",
+			units: []cache.AllocatedUnit{{NodeName: nodeName, VolumeType: localtype.VolumeTypeLVM, VgName: "hdd", PVCName: "default/db-5"}},
+			fits:  true,
+			score: MaxScore,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fits, err := ProcessIOCapacity(test.units, node, ctx)
+			if fits != test.fits {
+				t.Errorf("ProcessIOCapacity() = %t, expected %t, err: %v", fits, test.fits, err)
+			}
+			if !test.fits {
+				if _, ok := err.(errors.PredicateError); !ok {
+					t.Errorf("ProcessIOCapacity() error %v is not a predicate error", err)
+				}
+			}
+			if score := ScoreIOCapacity(test.units, node, ctx); score != test.score {
+				t.Errorf("ScoreIOCapacity() = %d, expected %d", score, test.score)
+			}
+		})
+	}
+}
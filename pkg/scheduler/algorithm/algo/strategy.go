@@ -0,0 +1,300 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algo
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	"github.com/alibaba/open-local/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Strategy decides which VG, thin pool or mount point of a node a volume is allocated from,
+// and how the allocation is scored
+type Strategy interface {
+	// Name returns the name the strategy is registered with
+	Name() localtype.StrategyType
+	// Sort orders the candidates in the order they are tried for the request
+	Sort(request StrategyRequest, candidates []Candidate)
+	// Score scores a resource whose usage ratio is ratio after allocation, the result is in [0, 1]
+	Score(ratio float64) float64
+}
+
+// StrategyRequest is the volume to be allocated
+type StrategyRequest struct {
+	Size      int64
+	MediaType localtype.MediaType
+}
+
+// Candidate is a VG, thin pool or mount point the volume can be allocated from
+type Candidate struct {
+	cache.SharedResource
+	MediaType localtype.MediaType
+	// Volumes is the number of volumes allocated from the resource
+	Volumes int
+}
+
+func (c Candidate) free() int64 {
+	return c.Capacity - c.Requested
+}
+
+var (
+	strategiesLock sync.RWMutex
+	strategies     = make(map[localtype.StrategyType]Strategy)
+	// strategyAliases keeps the names of --scheduler-strategy before strategies are pluggable
+	strategyAliases = map[localtype.StrategyType]localtype.StrategyType{
+		localtype.StrategyBinpack: localtype.StrategyBestFit,
+		localtype.StrategySpread:  localtype.StrategyWorstFit,
+	}
+)
+
+func init() {
+	for _, s := range []Strategy{
+		sortStrategy{name: localtype.StrategyBestFit, less: bestFit},
+		sortStrategy{name: localtype.StrategyWorstFit, less: worstFit, spread: true},
+		sortStrategy{name: localtype.StrategyMediaAware, less: mediaAware},
+		sortStrategy{name: localtype.StrategyLeastFragmentation, less: leastFragmentation},
+		sortStrategy{name: localtype.StrategyRoundRobin, less: roundRobin, spread: true},
+	} {
+		if err := RegisterStrategy(s); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterStrategy makes the strategy available to storage classes and --scheduler-strategy by its name
+func RegisterStrategy(strategy Strategy) error {
+	strategiesLock.Lock()
+	defer strategiesLock.Unlock()
+	if _, ok := strategies[strategy.Name()]; ok {
+		return fmt.Errorf("scheduler strategy %s is already registered", strategy.Name())
+	}
+	if alias, ok := strategyAliases[strategy.Name()]; ok {
+		return fmt.Errorf("scheduler strategy %s is an alias of %s", strategy.Name(), alias)
+	}
+	strategies[strategy.Name()] = strategy
+	return nil
+}
+
+// GetStrategy returns the strategy registered with name, binpack and spread are aliases of best-fit and worst-fit
+func GetStrategy(name localtype.StrategyType) (Strategy, error) {
+	strategiesLock.RLock()
+	defer strategiesLock.RUnlock()
+	if alias, ok := strategyAliases[name]; ok {
+		name = alias
+	}
+	if strategy, ok := strategies[name]; ok {
+		return strategy, nil
+	}
+	names := make([]string, 0, len(strategies)+len(strategyAliases))
+	for n := range strategies {
+		names = append(names, string(n))
+	}
+	for n := range strategyAliases {
+		names = append(names, string(n))
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("unknown scheduler strategy %q, valid values are %v", name, names)
+}
+
+// ParseStrategy returns the name of the strategy registered with name s, aliases are resolved
+// to the names they refer to. It is the only parser of --scheduler-strategy and the strategy of plugin args
+func ParseStrategy(s string) (localtype.StrategyType, error) {
+	strategy, err := GetStrategy(localtype.StrategyType(s))
+	if err != nil {
+		return "", err
+	}
+	return strategy.Name(), nil
+}
+
+// DefaultStrategy returns the default strategy of ctx, or the strategy of --scheduler-strategy if not set
+func DefaultStrategy(ctx *algorithm.SchedulingContext) Strategy {
+	name := localtype.SchedulerStrategy
+	if ctx != nil && ctx.SchedulerStrategy != "" {
+		name = ctx.SchedulerStrategy
+	}
+	strategy, err := GetStrategy(name)
+	if err != nil {
+		log.Errorf("%s, using %s", err.Error(), localtype.StrategyBestFit)
+		strategy, _ = GetStrategy(localtype.StrategyBestFit)
+	}
+	return strategy
+}
+
+// GetStrategyFromPVC returns the strategy set in the storage class of pvc, or the default strategy if not set
+func GetStrategyFromPVC(pvc *corev1.PersistentVolumeClaim, ctx *algorithm.SchedulingContext) Strategy {
+	name := utils.GetSchedulerStrategyFromPVC(pvc, ctx.StorageV1Informers)
+	if name == "" {
+		return DefaultStrategy(ctx)
+	}
+	strategy, err := GetStrategy(localtype.StrategyType(name))
+	if err != nil {
+		log.Warningf("pvc %s/%s: %s, using the default strategy", pvc.Namespace, pvc.Name, err.Error())
+		return DefaultStrategy(ctx)
+	}
+	return strategy
+}
+
+// pvcStrategies returns the strategies of pvcs keyed by pvc name
+func pvcStrategies(pvcs []*corev1.PersistentVolumeClaim, ctx *algorithm.SchedulingContext) map[string]Strategy {
+	result := make(map[string]Strategy, len(pvcs))
+	for _, pvc := range pvcs {
+		result[utils.PVCName(pvc)] = GetStrategyFromPVC(pvc, ctx)
+	}
+	return result
+}
+
+// strategyOf returns the strategy of the pvc in strategies, or defaultStrategy for inline volumes
+func strategyOf(strategies map[string]Strategy, defaultStrategy Strategy, pvcName string) Strategy {
+	if strategy, ok := strategies[pvcName]; ok {
+		return strategy
+	}
+	return defaultStrategy
+}
+
+// resourceVolumes returns the number of local pvs on the node, keyed by VG name or mount point name
+func resourceVolumes(nodeCache *cache.NodeCache) map[cache.ResourceName]int {
+	volumes := make(map[cache.ResourceName]int)
+	if nodeCache == nil {
+		return volumes
+	}
+	for _, pv := range nodeCache.LocalPVs {
+		if vgName := utils.GetVGNameFromCsiPV(&pv); vgName != "" {
+			volumes[cache.ResourceName(vgName)]++
+		} else if mpName := utils.GetMountPointFromCsiPV(&pv); mpName != "" {
+			volumes[cache.ResourceName(mpName)]++
+		}
+	}
+	return volumes
+}
+
+// selectCandidate returns the first candidate in the order of strategy which has enough free size for request
+func selectCandidate(strategy Strategy, request StrategyRequest, candidates []Candidate) (*Candidate, bool) {
+	strategy.Sort(request, candidates)
+	for i := range candidates {
+		if candidates[i].free() >= request.Size {
+			return &candidates[i], true
+		}
+	}
+	return nil, false
+}
+
+// maxFreeCandidate returns the candidate of the largest free size, which is reported when no candidate fits
+func maxFreeCandidate(candidates []Candidate) Candidate {
+	var max Candidate
+	for i, c := range candidates {
+		if i == 0 || c.free() > max.free() {
+			max = c
+		}
+	}
+	return max
+}
+
+// sortStrategy tries the candidates in the order of less, candidates of the same order are sorted by name
+type sortStrategy struct {
+	name localtype.StrategyType
+	less func(request StrategyRequest, a, b Candidate) bool
+	// spread strategies score resources of low usage higher
+	spread bool
+}
+
+func (s sortStrategy) Name() localtype.StrategyType {
+	return s.name
+}
+
+func (s sortStrategy) Sort(request StrategyRequest, candidates []Candidate) {
+	sort.Slice(candidates, func(i, j int) bool {
+		if s.less(request, candidates[i], candidates[j]) {
+			return true
+		}
+		if s.less(request, candidates[j], candidates[i]) {
+			return false
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+}
+
+func (s sortStrategy) Score(ratio float64) float64 {
+	if s.spread {
+		return 1.0 - ratio
+	}
+	return ratio
+}
+
+// bestFit prefers the resource of the smallest free size, leaving large free space for large volumes
+func bestFit(_ StrategyRequest, a, b Candidate) bool {
+	return a.free() < b.free()
+}
+
+// worstFit prefers the resource of the largest free size, spreading volumes over resources
+func worstFit(_ StrategyRequest, a, b Candidate) bool {
+	return a.free() > b.free()
+}
+
+// mediaAware prefers the resources of the media type requested by the storage class, volumes requesting
+// no media type prefer hdd to keep ssd for the volumes requesting it, then it falls back to best fit
+func mediaAware(request StrategyRequest, a, b Candidate) bool {
+	rank := func(c Candidate) int {
+		if request.MediaType != "" {
+			if c.MediaType == request.MediaType {
+				return 0
+			}
+			return 1
+		}
+		switch c.MediaType {
+		case localtype.MediaTypeHDD:
+			return 0
+		case localtype.MediaTypeSSD:
+			return 2
+		}
+		return 1
+	}
+	if rank(a) != rank(b) {
+		return rank(a) < rank(b)
+	}
+	return bestFit(request, a, b)
+}
+
+// leastFragmentation prefers the resources already in use, the most used first, so that
+// the untouched resources are kept whole for large volumes, then it falls back to best fit
+func leastFragmentation(request StrategyRequest, a, b Candidate) bool {
+	usage := func(c Candidate) float64 {
+		if c.Capacity <= 0 {
+			return 0
+		}
+		return float64(c.Requested) / float64(c.Capacity)
+	}
+	if usage(a) != usage(b) {
+		return usage(a) > usage(b)
+	}
+	return bestFit(request, a, b)
+}
+
+// roundRobin prefers the resource holding the fewest volumes, so that volumes rotate over resources,
+// then it falls back to worst fit
+func roundRobin(request StrategyRequest, a, b Candidate) bool {
+	if a.Volumes != b.Volumes {
+		return a.Volumes < b.Volumes
+	}
+	return worstFit(request, a, b)
+}
@@ -0,0 +1,180 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algo
+
+import (
+	"testing"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBuiltinStrategies(t *testing.T) {
+	// free size: hdd-used 60Gi, ssd-empty 100Gi, ssd-used 20Gi, unknown 200Gi
+	newCandidates := func() []Candidate {
+		return []Candidate{
+			{SharedResource: cache.SharedResource{Name: "hdd-used", Capacity: 100 << 30, Requested: 40 << 30}, MediaType: localtype.MediaTypeHDD, Volumes: 2},
+			{SharedResource: cache.SharedResource{Name: "ssd-empty", Capacity: 100 << 30}, MediaType: localtype.MediaTypeSSD},
+			{SharedResource: cache.SharedResource{Name: "ssd-used", Capacity: 100 << 30, Requested: 80 << 30}, MediaType: localtype.MediaTypeSSD, Volumes: 1},
+			{SharedResource: cache.SharedResource{Name: "unknown", Capacity: 200 << 30}},
+		}
+	}
+	tests := []struct {
+		strategy localtype.StrategyType
+		request  StrategyRequest
+		expected []string
+	}{
+		{localtype.StrategyBinpack, StrategyRequest{}, []string{"ssd-used", "hdd-used", "ssd-empty", "unknown"}},
+		{localtype.StrategyBestFit, StrategyRequest{}, []string{"ssd-used", "hdd-used", "ssd-empty", "unknown"}},
+		{localtype.StrategySpread, StrategyRequest{}, []string{"unknown", "ssd-empty", "hdd-used", "ssd-used"}},
+		{localtype.StrategyWorstFit, StrategyRequest{}, []string{"unknown", "ssd-empty", "hdd-used", "ssd-used"}},
+		{localtype.StrategyMediaAware, StrategyRequest{MediaType: localtype.MediaTypeSSD}, []string{"ssd-used", "ssd-empty", "hdd-used", "unknown"}},
+		{localtype.StrategyMediaAware, StrategyRequest{}, []string{"hdd-used", "unknown", "ssd-used", "ssd-empty"}},
+		{localtype.StrategyLeastFragmentation, StrategyRequest{}, []string{"ssd-used", "hdd-used", "ssd-empty", "unknown"}},
+		{localtype.StrategyRoundRobin, StrategyRequest{}, []string{"unknown", "ssd-empty", "ssd-used", "hdd-used"}},
+	}
+	for _, test := range tests {
+		t.Run(string(test.strategy), func(t *testing.T) {
+			strategy, err := GetStrategy(test.strategy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			candidates := newCandidates()
+			strategy.Sort(test.request, candidates)
+			for i, c := range candidates {
+				if c.Name != test.expected[i] {
+					t.Fatalf("candidate %d is %s, expected order %v", i, c.Name, test.expected)
+				}
+			}
+		})
+	}
+
+	if _, err := GetStrategy("first-fit"); err == nil {
+		t.Errorf("expected error of unknown strategy")
+	}
+	if err := RegisterStrategy(sortStrategy{name: localtype.StrategyBestFit, less: bestFit}); err == nil {
+		t.Errorf("expected error of registering best-fit again")
+	}
+	if err := RegisterStrategy(sortStrategy{name: localtype.StrategyBinpack, less: bestFit}); err == nil {
+		t.Errorf("expected error of registering alias binpack")
+	}
+}
+
+func TestParseStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected localtype.StrategyType
+		err      bool
+	}{
+		{name: "binpack", expected: localtype.StrategyBestFit},
+		{name: "spread", expected: localtype.StrategyWorstFit},
+		{name: "best-fit", expected: localtype.StrategyBestFit},
+		{name: "round-robin", expected: localtype.StrategyRoundRobin},
+		{name: "first-fit", err: true},
+	}
+	for _, test := range tests {
+		strategy, err := ParseStrategy(test.name)
+		if test.err {
+			if err == nil {
+				t.Errorf("ParseStrategy(%q) = %s, expected error", test.name, strategy)
+			}
+			continue
+		}
+		if err != nil || strategy != test.expected {
+			t.Errorf("ParseStrategy(%q) = %s, %v, expected %s", test.name, strategy, err, test.expected)
+		}
+	}
+	if binpack, err := GetStrategy(localtype.StrategyBinpack); err != nil || binpack.Name() != localtype.StrategyBestFit {
+		t.Errorf("GetStrategy(binpack) = %v, %v, expected the strategy of best-fit", binpack, err)
+	}
+}
+
+func TestStrategyFromStorageClass(t *testing.T) {
+	nodeName := "testnode"
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+	factory := informers.NewSharedInformerFactory(k8sfake.NewSimpleClientset(), 0)
+	for _, sc := range []*storagev1.StorageClass{
+		{ObjectMeta: metav1.ObjectMeta{Name: "lvm"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "lvm-db"}, Parameters: map[string]string{localtype.VolumeSchedulerStrategy: string(localtype.StrategyBestFit)}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "lvm-log"}, Parameters: map[string]string{localtype.VolumeSchedulerStrategy: string(localtype.StrategyWorstFit)}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "lvm-unknown"}, Parameters: map[string]string{localtype.VolumeSchedulerStrategy: "first-fit"}},
+	} {
+		factory.Storage().V1().StorageClasses().Informer().GetIndexer().Add(sc)
+	}
+	ctx := &algorithm.SchedulingContext{
+		ClusterNodeCache:   cache.NewClusterNodeCache(),
+		CoreV1Informers:    factory.Core().V1(),
+		StorageV1Informers: factory.Storage().V1(),
+	}
+	nc := cache.NewNodeCache(nodeName)
+	nc.VGs["small"] = cache.SharedResource{Name: "small", Capacity: 100 << 30}
+	nc.VGs["large"] = cache.SharedResource{Name: "large", Capacity: 1000 << 30}
+	ctx.ClusterNodeCache.SetNodeCache(nc)
+
+	defaultStrategy := localtype.SchedulerStrategy
+	defer func() { localtype.SchedulerStrategy = defaultStrategy }()
+	localtype.SchedulerStrategy = localtype.StrategySpread
+
+	tests := []struct {
+		name string
+		pvc  *corev1.PersistentVolumeClaim
+		vg   string
+	}{
+		{"best fit of storage class", newTierPVC("db", "lvm-db", "10Gi"), "small"},
+		{"worst fit of storage class", newTierPVC("log", "lvm-log", "10Gi"), "large"},
+		{"default strategy", newTierPVC("data", "lvm", "10Gi"), "large"},
+		{"unknown strategy falls back to default", newTierPVC("data", "lvm-unknown", "10Gi"), "large"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fits, units, err := ProcessLVMPVCPriority(nil, []*corev1.PersistentVolumeClaim{test.pvc}, node, ctx)
+			if !fits || err != nil {
+				t.Fatalf("ProcessLVMPVCPriority() = %t, err: %v", fits, err)
+			}
+			if units[0].VgName != test.vg {
+				t.Errorf("pvc is allocated from vg %s, expected %s", units[0].VgName, test.vg)
+			}
+		})
+	}
+
+	// the strategy of scheduling context overrides --scheduler-strategy
+	ctx.SchedulerStrategy = localtype.StrategyBestFit
+	fits, units, err := ProcessLVMPVCPriority(nil, []*corev1.PersistentVolumeClaim{newTierPVC("data", "lvm", "10Gi")}, node, ctx)
+	if !fits || err != nil {
+		t.Fatalf("ProcessLVMPVCPriority() = %t, err: %v", fits, err)
+	}
+	if units[0].VgName != "small" {
+		t.Errorf("pvc is allocated from vg %s with strategy of context, expected small", units[0].VgName)
+	}
+	ctx.SchedulerStrategy = ""
+
+	// pvcs of a pod are scored by their own strategies
+	units = []cache.AllocatedUnit{
+		{VgName: "small", Allocated: 30 << 30, PVCName: "default/db"},
+		{VgName: "small", Allocated: 30 << 30, PVCName: "default/log"},
+	}
+	strategies := pvcStrategies([]*corev1.PersistentVolumeClaim{newTierPVC("db", "lvm-db", "30Gi"), newTierPVC("log", "lvm-log", "30Gi")}, ctx)
+	if score := ScoreLVM(units, nc.VGs, nc.ThinPools, strategies, DefaultStrategy(ctx)); score != MaxScore/2 {
+		t.Errorf("ScoreLVM() = %d, expected %d", score, MaxScore/2)
+	}
+}
@@ -17,17 +17,137 @@ limitations under the License.
 package bind
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/algo"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	"github.com/alibaba/open-local/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	schedulerapi "k8s.io/kube-scheduler/extender/v1"
 )
 
+// Bind allocates local storage for the unbound pvcs of pod on the node, writes the allocation of
+// every local pvc of the pod as pvc annotations, and then binds the pod to the node. The allocation
+// is reserved for the pod and used when provisioning the pvcs.
 type Bind struct {
-	Func func(podName string, podNamespace string, podUID types.UID, node string) error
+	Ctx        *algorithm.SchedulingContext
+	KubeClient kubernetes.Interface
+}
+
+func NewBind(ctx *algorithm.SchedulingContext, kubeClient kubernetes.Interface) *Bind {
+	if ctx == nil {
+		panic("scheduling context must not be nil")
+	}
+	return &Bind{Ctx: ctx, KubeClient: kubeClient}
 }
 
 func (b Bind) Handler(args schedulerapi.ExtenderBindingArgs) *schedulerapi.ExtenderBindingResult {
-	_ = b.Func(args.PodName, args.PodNamespace, args.PodUID, args.Node)
-	return &schedulerapi.ExtenderBindingResult{
-		Error: "Bind not implemented",
+	if err := b.bind(args.PodName, args.PodNamespace, args.PodUID, args.Node); err != nil {
+		log.Errorf("failed to bind pod %s/%s to node %s: %s", args.PodNamespace, args.PodName, args.Node, err.Error())
+		return &schedulerapi.ExtenderBindingResult{Error: err.Error()}
+	}
+	log.Infof("successfully bind pod %s/%s to node %s", args.PodNamespace, args.PodName, args.Node)
+	return &schedulerapi.ExtenderBindingResult{}
+}
+
+func (b Bind) bind(podName string, podNamespace string, podUID types.UID, nodeName string) error {
+	pod, err := b.Ctx.CoreV1Informers.Pods().Lister().Pods(podNamespace).Get(podName)
+	if err != nil {
+		return err
+	}
+	if pod.UID != podUID {
+		return fmt.Errorf("pod %s/%s is recreated with uid %s, expected %s", podNamespace, podName, pod.UID, podUID)
+	}
+
+	if !utils.NeedSkip(schedulerapi.ExtenderArgs{Pod: pod}) {
+		units, err := b.allocate(pod, nodeName)
+		if err != nil {
+			return err
+		}
+		if err := algo.AnnotateAllocation(b.KubeClient, units); err != nil {
+			b.unreserve(pod)
+			return err
+		}
+	}
+
+	binding := &corev1.Binding{
+		ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: podNamespace, UID: podUID},
+		Target:     corev1.ObjectReference{Kind: "Node", Name: nodeName},
+	}
+	if err := b.KubeClient.CoreV1().Pods(podNamespace).Bind(context.Background(), binding, metav1.CreateOptions{}); err != nil {
+		b.unreserve(pod)
+		return err
+	}
+	return nil
+}
+
+// allocate reserves local storage on node for the unbound local pvcs of pod, the pvcs already
+// allocated on the node are kept as they are. It returns the allocation of all the local pvcs of pod.
+func (b Bind) allocate(pod *corev1.Pod, nodeName string) ([]cache.AllocatedUnit, error) {
+	node, err := b.Ctx.CoreV1Informers.Nodes().Lister().Get(nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	b.Ctx.CtxLock.Lock()
+	defer b.Ctx.CtxLock.Unlock()
+
+	containReadonlySnapshot := false
+	err, lvmPVCs, mpPVCs, devicePVCs, quotaPVCs := algorithm.GetPodPvcs(pod, b.Ctx, true, containReadonlySnapshot)
+	if err != nil {
+		return nil, err
+	}
+	var units []cache.AllocatedUnit
+	filter := func(pvcs []*corev1.PersistentVolumeClaim) (pending []*corev1.PersistentVolumeClaim, err error) {
+		for _, pvc := range pvcs {
+			unit, ok := b.Ctx.ClusterNodeCache.BindingInfo[utils.PVCName(pvc)]
+			if !ok {
+				pending = append(pending, pvc)
+				continue
+			}
+			if unit.NodeName != nodeName {
+				return nil, fmt.Errorf("pvc %s is allocated on node %s", utils.PVCName(pvc), unit.NodeName)
+			}
+			units = append(units, *unit)
+		}
+		return pending, nil
+	}
+	if lvmPVCs, err = filter(lvmPVCs); err != nil {
+		return nil, err
+	}
+	if mpPVCs, err = filter(mpPVCs); err != nil {
+		return nil, err
+	}
+	if devicePVCs, err = filter(devicePVCs); err != nil {
+		return nil, err
+	}
+	if quotaPVCs, err = filter(quotaPVCs); err != nil {
+		return nil, err
+	}
+	if len(lvmPVCs)+len(mpPVCs)+len(devicePVCs)+len(quotaPVCs) == 0 {
+		return units, nil
+	}
+
+	allocatedUnits, err := algo.PlanVolumes(pod, lvmPVCs, mpPVCs, devicePVCs, quotaPVCs, node, b.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate local storage on node %s: %s", nodeName, err.Error())
+	}
+	if err := b.Ctx.ClusterNodeCache.Reserve(string(pod.UID), utils.PodName(pod), allocatedUnits, b.Ctx.ReservationTTL); err != nil {
+		return nil, fmt.Errorf("failed to assume local storage on node %s: %s", nodeName, err.Error())
+	}
+	return append(units, allocatedUnits...), nil
+}
+
+func (b Bind) unreserve(pod *corev1.Pod) {
+	b.Ctx.CtxLock.Lock()
+	defer b.Ctx.CtxLock.Unlock()
+	if err := b.Ctx.ClusterNodeCache.Unreserve(string(pod.UID)); err != nil {
+		log.Errorf("failed to unreserve local storage of pod %s: %s", utils.PodName(pod), err.Error())
 	}
 }
@@ -0,0 +1,157 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bind
+
+import (
+	"context"
+	"testing"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	schedulerapi "k8s.io/kube-scheduler/extender/v1"
+)
+
+func TestBind(t *testing.T) {
+	nodeName := "testnode"
+	storageClass := "open-local-lvm"
+	newPVC := func(name, size string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: &storageClass,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				},
+			},
+		}
+	}
+	newPod := func(name, pvcName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name)},
+			Spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{{
+					Name:         "data",
+					VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvcName}},
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		pvc    *corev1.PersistentVolumeClaim
+		bound  bool
+		vgName string
+	}{
+		{
+			name:   "pvc is allocated before binding",
+			pvc:    newPVC("pvc-small", "10Gi"),
+			bound:  true,
+			vgName: "share",
+		},
+		{
+			name:  "pod is not bound if storage is not enough",
+			pvc:   newPVC("pvc-large", "200Gi"),
+			bound: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pod := newPod("pod", test.pvc.Name)
+			kubeClient := k8sfake.NewSimpleClientset(test.pvc.DeepCopy(), pod.DeepCopy())
+			var binding *corev1.Binding
+			kubeClient.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if action.GetSubresource() != "binding" {
+					return false, nil, nil
+				}
+				binding = action.(k8stesting.CreateAction).GetObject().(*corev1.Binding)
+				return true, binding, nil
+			})
+			factory := informers.NewSharedInformerFactory(k8sfake.NewSimpleClientset(), 0)
+			factory.Core().V1().Nodes().Informer().GetIndexer().Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}})
+			factory.Core().V1().Pods().Informer().GetIndexer().Add(pod)
+			factory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(test.pvc)
+			factory.Storage().V1().StorageClasses().Informer().GetIndexer().Add(&storagev1.StorageClass{
+				ObjectMeta:  metav1.ObjectMeta{Name: storageClass},
+				Provisioner: localtype.ProvisionerName,
+				Parameters:  map[string]string{localtype.VolumeTypeKey: string(localtype.VolumeTypeLVM)},
+			})
+			ctx := &algorithm.SchedulingContext{
+				ClusterNodeCache:       cache.NewClusterNodeCache(),
+				CoreV1Informers:        factory.Core().V1(),
+				StorageV1Informers:     factory.Storage().V1(),
+				NodeAntiAffinityWeight: localtype.NewNodeAntiAffinityWeight(),
+			}
+			nc := cache.NewNodeCache(nodeName)
+			nc.VGs["share"] = cache.SharedResource{Name: "share", Capacity: 100 << 30}
+			ctx.ClusterNodeCache.SetNodeCache(nc)
+
+			result := NewBind(ctx, kubeClient).Handler(schedulerapi.ExtenderBindingArgs{
+				PodName:      pod.Name,
+				PodNamespace: pod.Namespace,
+				PodUID:       pod.UID,
+				Node:         nodeName,
+			})
+			if !test.bound {
+				if result.Error == "" || binding != nil {
+					t.Fatalf("pod is bound with result %+v, expected error", result)
+				}
+				return
+			}
+			if result.Error != "" {
+				t.Fatalf("failed to bind pod: %s", result.Error)
+			}
+			if binding == nil || binding.Target.Name != nodeName {
+				t.Fatalf("binding of pod is %+v, expected bound to %s", binding, nodeName)
+			}
+			pvc, err := kubeClient.CoreV1().PersistentVolumeClaims("default").Get(context.Background(), test.pvc.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pvc.Annotations[localtype.AnnAllocatedNode] != nodeName || pvc.Annotations[localtype.AnnAllocatedVG] != test.vgName {
+				t.Errorf("annotations of pvc are %v, expected allocated in vg %s", pvc.Annotations, test.vgName)
+			}
+			// the pvc is annotated before the pod is bound
+			patched := false
+			for _, action := range kubeClient.Actions() {
+				switch {
+				case action.GetVerb() == "patch" && action.GetResource().Resource == "persistentvolumeclaims":
+					patched = true
+				case action.GetVerb() == "create" && action.GetSubresource() == "binding" && !patched:
+					t.Errorf("pod is bound before the allocation is annotated")
+				}
+			}
+			unit := ctx.ClusterNodeCache.BindingInfo["default/"+test.pvc.Name]
+			if unit == nil || unit.NodeName != nodeName || unit.VgName != test.vgName {
+				t.Errorf("binding info of pvc is %+v, expected allocated in vg %s", unit, test.vgName)
+			}
+			if _, ok := ctx.ClusterNodeCache.Reservations[string(pod.UID)]; !ok {
+				t.Errorf("allocation of pvc is not reserved for pod")
+			}
+		})
+	}
+}
@@ -33,6 +33,8 @@ type ClusterInfo struct {
 	BindingInfo BindingMap `json:"bindingInfo,omitempty"`
 	// PvcMapping records requested pod and pvc mapping
 	PvcMapping *PodPvcMapping `json:"pvcMapping"`
+	// Reservations records the units assumed for pods, whose pvs are not created yet
+	Reservations ReservationMap `json:"reservations,omitempty"`
 }
 
 // ClusterNodeCache maintains mapping of allocated local PVs and Nodes
@@ -47,12 +49,48 @@ func NewClusterNodeCache() *ClusterNodeCache {
 	pvcInfo := NewPodPvcMapping()
 	return &ClusterNodeCache{
 		ClusterInfo: ClusterInfo{
-			Nodes:       nodes,
-			BindingInfo: info,
-			PvcMapping:  pvcInfo,
+			Nodes:        nodes,
+			BindingInfo:  info,
+			PvcMapping:   pvcInfo,
+			Reservations: make(ReservationMap),
 		}}
 }
 
+// Clone returns a deep copy of the cluster cache, which can be changed to simulate scheduling
+func (c *ClusterNodeCache) Clone() *ClusterNodeCache {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	clone := NewClusterNodeCache()
+	for name, nodeCache := range c.Nodes {
+		clone.Nodes[name] = nodeCache.Clone()
+	}
+	// units of reservations are shared with binding info, which is kept in the clone
+	units := make(map[*AllocatedUnit]*AllocatedUnit, len(c.BindingInfo))
+	cloneUnit := func(unit *AllocatedUnit) *AllocatedUnit {
+		if u, ok := units[unit]; ok {
+			return u
+		}
+		u := *unit
+		units[unit] = &u
+		return &u
+	}
+	for pvcName, unit := range c.BindingInfo {
+		clone.BindingInfo[pvcName] = cloneUnit(unit)
+	}
+	clone.PvcMapping = c.PvcMapping.Clone()
+	for podUID, r := range c.Reservations {
+		reservation := *r
+		reservation.Units = make([]*AllocatedUnit, 0, len(r.Units))
+		for _, unit := range r.Units {
+			reservation.Units = append(reservation.Units, cloneUnit(unit))
+		}
+		reservation.Committed = append([]string(nil), r.Committed...)
+		clone.Reservations[podUID] = &reservation
+	}
+	return clone
+}
+
 func (c *ClusterNodeCache) AddNodeCache(nodeLocal *nodelocalstorage.NodeLocalStorage) *NodeCache {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -128,6 +166,8 @@ func (c *ClusterNodeCache) Assume(units []AllocatedUnit) (err error) {
 			_, err = c.assumeDeviceAllocatedUnit(u, nodeCache)
 		case pkg.VolumeTypeMountPoint:
 			_, err = c.assumeMountPointAllocatedUnit(u, nodeCache)
+		case pkg.VolumeTypeQuota:
+			_, err = c.assumeQuotaAllocatedUnit(u, nodeCache)
 		default:
 			err = fmt.Errorf("invalid volumeType %s", volumeType)
 		}
@@ -135,6 +175,60 @@ func (c *ClusterNodeCache) Assume(units []AllocatedUnit) (err error) {
 	return err
 }
 
+// Unassume reverts the allocated units assumed into cache,
+// e.g. when the pod is rejected after its volumes are reserved
+func (c *ClusterNodeCache) Unassume(units []AllocatedUnit) (err error) {
+	for _, u := range units {
+		nodeCache := c.GetNodeCache(u.NodeName)
+		if nodeCache == nil {
+			err = fmt.Errorf("node %s not found from cache when unassume", u.NodeName)
+			continue
+		}
+		switch u.VolumeType {
+		case pkg.VolumeTypeLVM:
+			resources := nodeCache.VGs
+			if u.Thin {
+				resources = nodeCache.ThinPools
+			}
+			c.unassumeSharedResource(resources, u.VgName, u.Requested)
+		case pkg.VolumeTypeQuota:
+			c.unassumeSharedResource(nodeCache.Quotas, u.MountPoint, u.Requested)
+		case pkg.VolumeTypeMountPoint:
+			c.unassumeExclusiveResource(nodeCache.MountPoints, u.MountPoint)
+		case pkg.VolumeTypeDevice:
+			c.unassumeExclusiveResource(nodeCache.Devices, u.Device)
+		default:
+			err = fmt.Errorf("invalid volumeType %s", u.VolumeType)
+			continue
+		}
+		nodeCache.AllocatedNum -= 1
+		log.Debugf("unassume node cache successfully: node = %s, pvc = %s", nodeCache.NodeName, u.PVCName)
+		c.SetNodeCache(nodeCache)
+	}
+	return err
+}
+
+func (c *ClusterNodeCache) unassumeSharedResource(resources map[ResourceName]SharedResource, name string, requested int64) {
+	resource, ok := resources[ResourceName(name)]
+	if !ok {
+		return
+	}
+	resource.Requested -= requested
+	if resource.Requested < 0 {
+		resource.Requested = 0
+	}
+	resources[ResourceName(name)] = resource
+}
+
+func (c *ClusterNodeCache) unassumeExclusiveResource(resources map[ResourceName]ExclusiveResource, name string) {
+	resource, ok := resources[ResourceName(name)]
+	if !ok {
+		return
+	}
+	resource.IsAllocated = false
+	resources[ResourceName(name)] = resource
+}
+
 func (c *ClusterNodeCache) assumeMountPointAllocatedUnit(unit AllocatedUnit, nodeCache *NodeCache) (*NodeCache, error) {
 	nodeCache.AllocatedNum += 1
 
@@ -158,10 +252,14 @@ func (c *ClusterNodeCache) assumeMountPointAllocatedUnit(unit AllocatedUnit, nod
 }
 
 func (c *ClusterNodeCache) assumeLVMAllocatedUnit(unit AllocatedUnit, nodeCache *NodeCache) (*NodeCache, error) {
-	vg, ok := nodeCache.VGs[ResourceName(unit.VgName)]
+	resources := nodeCache.VGs
+	if unit.Thin {
+		resources = nodeCache.ThinPools
+	}
+	vg, ok := resources[ResourceName(unit.VgName)]
 	if ok {
 		if vg.Requested+unit.Requested > vg.Capacity {
-			return nil, fmt.Errorf("VG %s resource is not enough, requested = %d, actual left = %d", vg.Name, unit.Requested, vg.Capacity-vg.Requested)
+			return nil, fmt.Errorf("VG %s resource is not enough(thin: %t), requested = %d, actual left = %d", vg.Name, unit.Thin, unit.Requested, vg.Capacity-vg.Requested)
 		}
 	} else {
 		// vg is not found
@@ -169,7 +267,7 @@ func (c *ClusterNodeCache) assumeLVMAllocatedUnit(unit AllocatedUnit, nodeCache
 	}
 	nodeCache.AllocatedNum += 1
 
-	nodeCache.VGs[ResourceName(vg.Name)] = SharedResource{
+	resources[ResourceName(vg.Name)] = SharedResource{
 		Name:      vg.Name,
 		Capacity:  vg.Capacity,
 		Requested: vg.Requested + unit.Requested,
@@ -179,6 +277,30 @@ func (c *ClusterNodeCache) assumeLVMAllocatedUnit(unit AllocatedUnit, nodeCache
 	return nodeCache, nil
 }
 
+func (c *ClusterNodeCache) assumeQuotaAllocatedUnit(unit AllocatedUnit, nodeCache *NodeCache) (*NodeCache, error) {
+	quota, ok := nodeCache.Quotas[ResourceName(unit.MountPoint)]
+	if ok {
+		if quota.Requested+unit.Requested > quota.Capacity {
+			return nil, fmt.Errorf("mount point %s quota is not enough, requested = %d, actual left = %d", quota.Name, unit.Requested, quota.Capacity-quota.Requested)
+		}
+	} else {
+		return nil, fmt.Errorf("mount point %s/%s is not found in cache, please retry later", nodeCache.NodeName, unit.MountPoint)
+	}
+	if mp, ok := nodeCache.MountPoints[ResourceName(unit.MountPoint)]; ok && mp.IsAllocated {
+		return nil, fmt.Errorf("mount point %s was already allocated exclusively", mp.Name)
+	}
+	nodeCache.AllocatedNum += 1
+
+	nodeCache.Quotas[ResourceName(quota.Name)] = SharedResource{
+		Name:      quota.Name,
+		Capacity:  quota.Capacity,
+		Requested: quota.Requested + unit.Requested,
+	}
+	log.Debugf("assume node cache successfully: node = %s, quota mount point = %s", nodeCache.NodeName, quota.Name)
+	c.SetNodeCache(nodeCache)
+	return nodeCache, nil
+}
+
 func (c *ClusterNodeCache) assumeDeviceAllocatedUnit(unit AllocatedUnit, nodeCache *NodeCache) (*NodeCache, error) {
 	nodeCache.AllocatedNum += 1
 
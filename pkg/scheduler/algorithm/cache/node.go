@@ -32,16 +32,65 @@ func NewNodeCache(nodeName string) *NodeCache {
 	return &NodeCache{
 		rwLock: sync.RWMutex{},
 		NodeInfo: NodeInfo{NodeName: nodeName,
-			VGs:          make(map[ResourceName]SharedResource),
-			MountPoints:  make(map[ResourceName]ExclusiveResource),
-			Devices:      make(map[ResourceName]ExclusiveResource),
-			AllocatedNum: 0,
+			VGs:              make(map[ResourceName]SharedResource),
+			MountPoints:      make(map[ResourceName]ExclusiveResource),
+			Devices:          make(map[ResourceName]ExclusiveResource),
+			Quotas:           make(map[ResourceName]SharedResource),
+			ThinPools:        make(map[ResourceName]SharedResource),
+			IOCapacities:     make(map[ResourceName]nodelocalstorage.IOCapacity),
+			PerformanceTiers: make(map[ResourceName]string),
+			MediaTypes:       make(map[ResourceName]localtype.MediaType),
+			FaultyResources:  make(map[ResourceName]bool),
+			AllocatedNum:     0,
 			// TODO(yuzhi.wx) using pv name may conflict, use pv uid later
 			LocalPVs:            make(map[string]corev1.PersistentVolume),
 			PodInlineVolumeInfo: make(map[string][]InlineVolumeInfo)},
 	}
 }
 
+// Clone returns a deep copy of the node cache, which can be changed to simulate scheduling
+func (nc *NodeCache) Clone() *NodeCache {
+	nc.rwLock.RLock()
+	defer nc.rwLock.RUnlock()
+
+	clone := NewNodeCache(nc.NodeName)
+	for k, v := range nc.VGs {
+		clone.VGs[k] = v
+	}
+	for k, v := range nc.MountPoints {
+		clone.MountPoints[k] = v
+	}
+	for k, v := range nc.Devices {
+		clone.Devices[k] = v
+	}
+	for k, v := range nc.Quotas {
+		clone.Quotas[k] = v
+	}
+	for k, v := range nc.ThinPools {
+		clone.ThinPools[k] = v
+	}
+	for k, v := range nc.IOCapacities {
+		clone.IOCapacities[k] = v
+	}
+	for k, v := range nc.PerformanceTiers {
+		clone.PerformanceTiers[k] = v
+	}
+	for k, v := range nc.MediaTypes {
+		clone.MediaTypes[k] = v
+	}
+	for k, v := range nc.FaultyResources {
+		clone.FaultyResources[k] = v
+	}
+	clone.AllocatedNum = nc.AllocatedNum
+	for k, v := range nc.LocalPVs {
+		clone.LocalPVs[k] = *v.DeepCopy()
+	}
+	for k, v := range nc.PodInlineVolumeInfo {
+		clone.PodInlineVolumeInfo[k] = append([]InlineVolumeInfo(nil), v...)
+	}
+	return clone
+}
+
 func NewNodeCacheFromStorage(nodeLocal *nodelocalstorage.NodeLocalStorage) *NodeCache {
 	newNodeCache := NewNodeCache(nodeLocal.Name) // create a new node cache
 
@@ -59,6 +108,9 @@ func NewNodeCacheFromStorage(nodeLocal *nodelocalstorage.NodeLocalStorage) *Node
 		vgResource := SharedResource{vgName, int64(vgInfoMap[vgName].Allocatable), 0}
 		newNodeCache.VGs[ResourceName(vgName)] = vgResource
 		log.Debugf("vgResource: %#v", vgResource)
+		if vgInfoMap[vgName].ThinPool != nil {
+			newNodeCache.ThinPools[ResourceName(vgName)] = SharedResource{vgName, thinPoolCapacity(nodeLocal, vgInfoMap[vgName]), 0}
+		}
 	}
 
 	// Devices
@@ -106,8 +158,13 @@ func NewNodeCacheFromStorage(nodeLocal *nodelocalstorage.NodeLocalStorage) *Node
 			localtype.MediaType(deviceInfoMap[tmpMP.Device].MediaType),
 			false}
 		newNodeCache.MountPoints[ResourceName(mp)] = diskResource
+		newNodeCache.Quotas[ResourceName(mp)] = SharedResource{mp, int64(tmpMP.Total), 0}
 		log.Debugf("diskResource: %#v", diskResource)
 	}
+	newNodeCache.IOCapacities = ioCapacities(nodeLocal)
+	newNodeCache.PerformanceTiers = performanceTiers(nodeLocal)
+	newNodeCache.MediaTypes = vgMediaTypes(nodeLocal)
+	newNodeCache.FaultyResources = faultyResources(nodeLocal)
 	return newNodeCache
 }
 
@@ -139,6 +196,10 @@ func (nc *NodeCache) UpdateNodeInfo(nodeLocal *nodelocalstorage.NodeLocalStorage
 		vgResource := SharedResource{vg, int64(vgMapInfo[vg].Allocatable), vgRequested}
 		cacheNode.VGs[ResourceName(vg)] = vgResource
 		log.Debugf("vgResource: %#v", vgResource)
+		if vgMapInfo[vg].ThinPool != nil {
+			thinRequested := utils.GetThinPoolRequested(nc.LocalPVs, vg)
+			cacheNode.ThinPools[ResourceName(vg)] = SharedResource{vg, thinPoolCapacity(nodeLocal, vgMapInfo[vg]), thinRequested}
+		}
 	}
 	for _, vg := range unchangedVGs {
 		// update the size if the updatedName got extended
@@ -147,9 +208,25 @@ func (nc *NodeCache) UpdateNodeInfo(nodeLocal *nodelocalstorage.NodeLocalStorage
 		cacheNode.VGs[ResourceName(vg)] = v
 		log.Debugf("updating existing volume group %q(total:%d,allocatable:%d,used:%d) on node cache %s",
 			vg, vgMapInfo[vg].Total, vgMapInfo[vg].Allocatable, vgMapInfo[vg].Total-vgMapInfo[vg].Available, cacheNode.NodeName)
+		// thin pool may be created or extended after vg
+		tp, exist := cacheNode.ThinPools[ResourceName(vg)]
+		if vgMapInfo[vg].ThinPool != nil {
+			if !exist {
+				tp = SharedResource{vg, 0, utils.GetThinPoolRequested(nc.LocalPVs, vg)}
+			}
+			tp.Capacity = thinPoolCapacity(nodeLocal, vgMapInfo[vg])
+			cacheNode.ThinPools[ResourceName(vg)] = tp
+		} else if exist {
+			if tp.Requested > 0 {
+				log.Errorf("thin pool of vg %q is used by PV.", vg)
+			} else {
+				delete(cacheNode.ThinPools, ResourceName(vg))
+			}
+		}
 	}
 	for _, vg := range removedVGs {
 		delete(cacheNode.VGs, ResourceName(vg))
+		delete(cacheNode.ThinPools, ResourceName(vg))
 		log.Debugf("deleted vg %s from node cache %s", vg, nodeLocal.Name)
 	}
 
@@ -240,6 +317,8 @@ func (nc *NodeCache) UpdateNodeInfo(nodeLocal *nodelocalstorage.NodeLocalStorage
 			localtype.MediaType(deviceMapInfo[mpMapInfo[mp].Device].MediaType),
 			allocated}
 		cacheNode.MountPoints[ResourceName(mp)] = diskResource
+		quotaRequested := utils.GetQuotaRequested(nc.LocalPVs, mp)
+		cacheNode.Quotas[ResourceName(mp)] = SharedResource{mp, int64(mpMapInfo[mp].Total), quotaRequested}
 		log.Debugf("diskResource: %#v", diskResource)
 	}
 	for _, mp := range unchangedMPs {
@@ -248,21 +327,109 @@ func (nc *NodeCache) UpdateNodeInfo(nodeLocal *nodelocalstorage.NodeLocalStorage
 		exMP.Capacity = int64(mpMapInfo[mp].Total)
 		exMP.MediaType = localtype.MediaType(deviceMapInfo[exMP.Device].MediaType)
 		cacheNode.MountPoints[ResourceName(mp)] = exMP
+		quota := cacheNode.Quotas[ResourceName(mp)]
+		quota.Name = mp
+		quota.Capacity = int64(mpMapInfo[mp].Total)
+		cacheNode.Quotas[ResourceName(mp)] = quota
 		log.Debugf("updating existing mount point %q(total:%d) on node cache %s",
 			exMP.Name, exMP.Capacity, cacheNode.NodeName)
 	}
 	for _, mp := range removedMPs {
 		if cacheNode.MountPoints[ResourceName(mp)].IsAllocated {
 			log.Errorf("mount point %q is used by PV.", mp)
+		} else if cacheNode.Quotas[ResourceName(mp)].Requested > 0 {
+			log.Errorf("mount point %q is used by quota PV.", mp)
 		} else {
 			delete(cacheNode.MountPoints, ResourceName(mp))
+			delete(cacheNode.Quotas, ResourceName(mp))
 			log.Debugf("mount point %q has been deleted from cache", mp)
 		}
 	}
+	cacheNode.IOCapacities = ioCapacities(nodeLocal)
+	cacheNode.PerformanceTiers = performanceTiers(nodeLocal)
+	cacheNode.MediaTypes = vgMediaTypes(nodeLocal)
+	cacheNode.FaultyResources = faultyResources(nodeLocal)
 
 	return cacheNode
 }
 
+// ioCapacities returns the io budget of the filtered VGs and devices
+func ioCapacities(nodeLocal *nodelocalstorage.NodeLocalStorage) map[ResourceName]nodelocalstorage.IOCapacity {
+	capacities := make(map[ResourceName]nodelocalstorage.IOCapacity)
+	for _, vg := range nodeLocal.Status.NodeStorageInfo.VolumeGroups {
+		if vg.IOCapacity != nil && utils.ContainsString(nodeLocal.Status.FilteredStorageInfo.VolumeGroups, vg.Name) {
+			capacities[ResourceName(vg.Name)] = *vg.IOCapacity
+		}
+	}
+	for _, device := range nodeLocal.Status.NodeStorageInfo.DeviceInfos {
+		if device.IOCapacity != nil && utils.ContainsString(nodeLocal.Status.FilteredStorageInfo.Devices, device.Name) {
+			capacities[ResourceName(device.Name)] = *device.IOCapacity
+		}
+	}
+	return capacities
+}
+
+// performanceTiers returns the tier of the benchmarked VGs and devices which are filtered
+func performanceTiers(nodeLocal *nodelocalstorage.NodeLocalStorage) map[ResourceName]string {
+	tiers := make(map[ResourceName]string)
+	for _, profile := range nodeLocal.Status.PerformanceProfiles {
+		if profile.Tier == "" {
+			continue
+		}
+		if utils.ContainsString(nodeLocal.Status.FilteredStorageInfo.VolumeGroups, profile.Name) ||
+			utils.ContainsString(nodeLocal.Status.FilteredStorageInfo.Devices, profile.Name) {
+			tiers[ResourceName(profile.Name)] = profile.Tier
+		}
+	}
+	return tiers
+}
+
+// vgMediaTypes returns the media type of the filtered VGs whose physical volumes are all of the same media
+func vgMediaTypes(nodeLocal *nodelocalstorage.NodeLocalStorage) map[ResourceName]localtype.MediaType {
+	deviceMediaTypes := make(map[string]string, len(nodeLocal.Status.NodeStorageInfo.DeviceInfos))
+	for _, device := range nodeLocal.Status.NodeStorageInfo.DeviceInfos {
+		deviceMediaTypes[device.Name] = device.MediaType
+	}
+	mediaTypes := make(map[ResourceName]localtype.MediaType)
+	for _, vg := range nodeLocal.Status.NodeStorageInfo.VolumeGroups {
+		if !utils.ContainsString(nodeLocal.Status.FilteredStorageInfo.VolumeGroups, vg.Name) || len(vg.PhysicalVolumes) == 0 {
+			continue
+		}
+		mediaType := deviceMediaTypes[vg.PhysicalVolumes[0]]
+		for _, pv := range vg.PhysicalVolumes[1:] {
+			if deviceMediaTypes[pv] != mediaType {
+				mediaType = ""
+				break
+			}
+		}
+		if mediaType != "" {
+			mediaTypes[ResourceName(vg.Name)] = localtype.MediaType(mediaType)
+		}
+	}
+	return mediaTypes
+}
+
+// faultyResources returns the VGs, devices and mount points whose condition is StorageFault
+func faultyResources(nodeLocal *nodelocalstorage.NodeLocalStorage) map[ResourceName]bool {
+	faulty := make(map[ResourceName]bool)
+	for _, vg := range nodeLocal.Status.NodeStorageInfo.VolumeGroups {
+		if vg.Condition == nodelocalstorage.StorageFault {
+			faulty[ResourceName(vg.Name)] = true
+		}
+	}
+	for _, device := range nodeLocal.Status.NodeStorageInfo.DeviceInfos {
+		if device.Condition == nodelocalstorage.StorageFault {
+			faulty[ResourceName(device.Name)] = true
+		}
+	}
+	for _, mp := range nodeLocal.Status.NodeStorageInfo.MountPoints {
+		if mp.Condition == nodelocalstorage.StorageFault {
+			faulty[ResourceName(mp.Name)] = true
+		}
+	}
+	return faulty
+}
+
 // AddLVM add lvm PV to cache
 // note: this function does not handle pv update event
 func (nc *NodeCache) AddLVM(pv *corev1.PersistentVolume) error {
@@ -282,7 +449,8 @@ func (nc *NodeCache) AddLVM(pv *corev1.PersistentVolume) error {
 				log.Debugf("pv %s(uid=%s) was already existed", pv.Name, pv.UID)
 			}
 		}
-		if vg, ok := nc.VGs[ResourceName(vgName)]; ok {
+		resources := nc.lvmResources(pv)
+		if vg, ok := resources[ResourceName(vgName)]; ok {
 			// TODO(huizhi.szh): when informer resync the cache, this function may be called again, this will be a bug,
 			// because it will do it one more time.
 			oldRequest := vg.Requested
@@ -290,7 +458,7 @@ func (nc *NodeCache) AddLVM(pv *corev1.PersistentVolume) error {
 			vg.Requested = oldRequest + s.Value()
 			// Added to node cache
 			nc.AllocatedNum += 1
-			nc.VGs[ResourceName(vgName)] = vg
+			resources[ResourceName(vgName)] = vg
 			log.Debugf("[AddLVM]added pv %s: VG info: old size => %d, new size => %d for vg %s ",
 				pv.Name, oldRequest, vg.Requested, vgName)
 		} else {
@@ -325,13 +493,14 @@ func (nc *NodeCache) UpdateLVM(old, pv *corev1.PersistentVolume) error {
 				return nil
 			}
 		}
-		if vg, ok := nc.VGs[ResourceName(vgName)]; ok {
+		resources := nc.lvmResources(pv)
+		if vg, ok := resources[ResourceName(vgName)]; ok {
 			// because it is already in cache, we only recalculate vg requested size and PV object
 			oldRequest := vg.Requested
 			newPVsize := pv.Spec.Capacity[corev1.ResourceStorage]
 			oldPVsize := old.Spec.Capacity[corev1.ResourceStorage]
 			vg.Requested = oldRequest - oldPVsize.Value() + newPVsize.Value()
-			nc.VGs[ResourceName(vgName)] = vg
+			resources[ResourceName(vgName)] = vg
 			log.Debugf("[UpdateLVM]updated pv %s: VG info: old size => %d, new size => %d for vg %s ",
 				pv.Name, oldRequest, vg.Requested, vgName)
 		} else {
@@ -357,12 +526,13 @@ func (nc *NodeCache) RemoveLVM(pv *corev1.PersistentVolume) error {
 	if len(vgName) == 0 {
 		log.Debugf("pv %s is not a valid open-local pv(lvm with name)", pv.Name)
 	}
-	if vg, ok := nc.VGs[ResourceName(vgName)]; ok {
+	resources := nc.lvmResources(pv)
+	if vg, ok := resources[ResourceName(vgName)]; ok {
 		oldUsed := vg.Requested
 		s := pv.Spec.Capacity[corev1.ResourceStorage]
 		vg.Requested = oldUsed - s.Value()
 		nc.AllocatedNum -= 1
-		nc.VGs[ResourceName(vgName)] = vg
+		resources[ResourceName(vgName)] = vg
 		log.Debugf("[RemoveLVM]removed pv %s: VG info: old size => %d, new size => %d for vg %s ", pv.Name, oldUsed, vg.Requested, vgName)
 	} else {
 		nc.AllocatedNum -= 1
@@ -372,6 +542,19 @@ func (nc *NodeCache) RemoveLVM(pv *corev1.PersistentVolume) error {
 	return nil
 }
 
+// lvmResources returns the thin pools for thin PV, otherwise the VGs
+func (nc *NodeCache) lvmResources(pv *corev1.PersistentVolume) map[ResourceName]SharedResource {
+	if utils.IsThinCsiPV(pv) {
+		return nc.ThinPools
+	}
+	return nc.VGs
+}
+
+// thinPoolCapacity is the thin pool size multiplied by the overcommit ratio of the VG
+func thinPoolCapacity(nodeLocal *nodelocalstorage.NodeLocalStorage, vg nodelocalstorage.VolumeGroup) int64 {
+	return int64(float64(vg.ThinPool.Total) * utils.GetThinPoolOvercommitRatio(nodeLocal, vg.Name))
+}
+
 func (nc *NodeCache) AddLocalMountPoint(pv *corev1.PersistentVolume) error {
 	if !nc.isNodeLocal(pv) {
 		return nil
@@ -423,6 +606,99 @@ func (nc *NodeCache) RemoveLocalMountPoint(pv *corev1.PersistentVolume) error {
 	return nil
 }
 
+// AddQuota add quota PV to cache
+// note: this function does not handle pv update event
+func (nc *NodeCache) AddQuota(pv *corev1.PersistentVolume) error {
+	if !nc.isNodeLocal(pv) {
+		return nil
+	}
+	nc.rwLock.Lock()
+	defer nc.rwLock.Unlock()
+	mpName := utils.GetMountPointFromCsiPV(pv)
+	if len(mpName) == 0 {
+		log.Debugf("pv %s is not a valid open-local pv(quota with mount point)", pv.Name)
+		return nil
+	}
+	if existing, ok := nc.LocalPVs[pv.Name]; ok && existing.UID == pv.UID {
+		log.Debugf("pv %s(uid=%s) was already existed", pv.Name, pv.UID)
+		nc.LocalPVs[pv.Name] = *pv
+		return nil
+	}
+	if quota, ok := nc.Quotas[ResourceName(mpName)]; ok {
+		oldRequest := quota.Requested
+		s := pv.Spec.Capacity[corev1.ResourceStorage]
+		quota.Requested = oldRequest + s.Value()
+		nc.Quotas[ResourceName(mpName)] = quota
+		log.Debugf("[AddQuota]added pv %s: quota info: old size => %d, new size => %d for mount point %s ",
+			pv.Name, oldRequest, quota.Requested, mpName)
+	} else {
+		log.Debugf("[AddQuota]mount point %s not found in NodeCache", mpName)
+	}
+	nc.AllocatedNum += 1
+	nc.LocalPVs[pv.Name] = *pv
+
+	return nil
+}
+
+// UpdateQuota updates quota PV to cache
+// note: this function does not handle pv add event
+func (nc *NodeCache) UpdateQuota(old, pv *corev1.PersistentVolume) error {
+	if !nc.isNodeLocal(pv) {
+		return nil
+	}
+	nc.rwLock.Lock()
+	defer nc.rwLock.Unlock()
+	mpName := utils.GetMountPointFromCsiPV(pv)
+	if len(mpName) == 0 {
+		log.Debugf("pv %s is not a valid open-local pv(quota with mount point)", pv.Name)
+		return nil
+	}
+	if existing, ok := nc.LocalPVs[pv.Name]; ok && existing.UID == pv.UID {
+		// expansion is already accounted by the scheduler extender
+		log.Debugf("pv %s(uid=%s) was already existed", pv.Name, pv.UID)
+		nc.LocalPVs[pv.Name] = *pv
+		return nil
+	}
+	if quota, ok := nc.Quotas[ResourceName(mpName)]; ok {
+		oldRequest := quota.Requested
+		newPVsize := pv.Spec.Capacity[corev1.ResourceStorage]
+		oldPVsize := old.Spec.Capacity[corev1.ResourceStorage]
+		quota.Requested = oldRequest - oldPVsize.Value() + newPVsize.Value()
+		nc.Quotas[ResourceName(mpName)] = quota
+		log.Debugf("[UpdateQuota]updated pv %s: quota info: old size => %d, new size => %d for mount point %s ",
+			pv.Name, oldRequest, quota.Requested, mpName)
+	} else {
+		log.Debugf("[UpdateQuota]mount point %s not found in NodeCache", mpName)
+	}
+	nc.LocalPVs[pv.Name] = *pv
+
+	return nil
+}
+
+func (nc *NodeCache) RemoveQuota(pv *corev1.PersistentVolume) error {
+	if !nc.isNodeLocal(pv) {
+		return nil
+	}
+	nc.rwLock.Lock()
+	defer nc.rwLock.Unlock()
+	mpName := utils.GetMountPointFromCsiPV(pv)
+	if len(mpName) == 0 {
+		log.Debugf("pv %s is not a valid open-local pv(quota with mount point)", pv.Name)
+	}
+	if quota, ok := nc.Quotas[ResourceName(mpName)]; ok {
+		oldUsed := quota.Requested
+		s := pv.Spec.Capacity[corev1.ResourceStorage]
+		quota.Requested = oldUsed - s.Value()
+		nc.Quotas[ResourceName(mpName)] = quota
+		log.Debugf("[RemoveQuota]removed pv %s: quota info: old size => %d, new size => %d for mount point %s ", pv.Name, oldUsed, quota.Requested, mpName)
+	} else {
+		log.Debugf("[RemoveQuota]pv %s was not in the node cache, skipped updating", pv.Name)
+	}
+	nc.AllocatedNum -= 1
+	delete(nc.LocalPVs, pv.Name)
+	return nil
+}
+
 func (nc *NodeCache) AddLocalDevice(pv *corev1.PersistentVolume) error {
 	if !nc.isNodeLocal(pv) {
 		return nil
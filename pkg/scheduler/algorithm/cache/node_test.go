@@ -0,0 +1,374 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/alibaba/open-local/pkg"
+	nodelocalstorage "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
+	"github.com/alibaba/open-local/test/framework"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestNodeCache_Quota(t *testing.T) {
+	nodeName := "testnode"
+	mpName := "/mnt/open-local/disk-1"
+	var capacity int64 = 100 << 30
+
+	nc := NewNodeCache(nodeName)
+	nc.MountPoints[ResourceName(mpName)] = ExclusiveResource{mpName, "/dev/vdb", capacity, pkg.MediaTypeSSD, false}
+	nc.Quotas[ResourceName(mpName)] = SharedResource{mpName, capacity, 0}
+
+	pv := framework.MakePV("quota-pv-1", nodeName, pkg.VolumeTypeQuota)
+	pv.UID = types.UID("quota-pv-1")
+	pv.Spec.CSI.VolumeAttributes[string(pkg.VolumeTypeMountPoint)] = mpName
+	size := pv.Spec.Capacity["storage"]
+
+	if err := nc.AddQuota(pv); err != nil {
+		t.Fatalf("AddQuota() error = %v", err)
+	}
+	if nc.Quotas[ResourceName(mpName)].Requested != size.Value() {
+		t.Errorf("requested after add is %d, expected %d", nc.Quotas[ResourceName(mpName)].Requested, size.Value())
+	}
+	// informer resync must not account the same pv twice
+	if err := nc.AddQuota(pv); err != nil {
+		t.Fatalf("AddQuota() error = %v", err)
+	}
+	if nc.Quotas[ResourceName(mpName)].Requested != size.Value() {
+		t.Errorf("requested after resync is %d, expected %d", nc.Quotas[ResourceName(mpName)].Requested, size.Value())
+	}
+	if nc.MountPoints[ResourceName(mpName)].IsAllocated {
+		t.Errorf("mount point %s should not be allocated exclusively by quota pv", mpName)
+	}
+
+	if err := nc.RemoveQuota(pv); err != nil {
+		t.Fatalf("RemoveQuota() error = %v", err)
+	}
+	if nc.Quotas[ResourceName(mpName)].Requested != 0 {
+		t.Errorf("requested after remove is %d, expected 0", nc.Quotas[ResourceName(mpName)].Requested)
+	}
+}
+
+func TestClusterNodeCache_AssumeQuota(t *testing.T) {
+	nodeName := "testnode"
+	mpName := "/mnt/open-local/disk-1"
+	var capacity int64 = 10 << 30
+
+	c := NewClusterNodeCache()
+	nc := NewNodeCache(nodeName)
+	nc.MountPoints[ResourceName(mpName)] = ExclusiveResource{mpName, "/dev/vdb", capacity, pkg.MediaTypeSSD, false}
+	nc.Quotas[ResourceName(mpName)] = SharedResource{mpName, capacity, 0}
+	c.SetNodeCache(nc)
+
+	unit := AllocatedUnit{
+		NodeName:   nodeName,
+		VolumeType: pkg.VolumeTypeQuota,
+		Requested:  6 << 30,
+		Allocated:  6 << 30,
+		MountPoint: mpName,
+		PVCName:    "default/quota-pvc-1",
+	}
+	if err := c.Assume([]AllocatedUnit{unit}); err != nil {
+		t.Fatalf("Assume() error = %v", err)
+	}
+	if c.GetNodeCache(nodeName).Quotas[ResourceName(mpName)].Requested != 6<<30 {
+		t.Errorf("requested after assume is %d, expected %d", c.GetNodeCache(nodeName).Quotas[ResourceName(mpName)].Requested, int64(6<<30))
+	}
+	unit.PVCName = "default/quota-pvc-2"
+	if err := c.Assume([]AllocatedUnit{unit}); err == nil {
+		t.Errorf("Assume() should fail when quota of mount point %s is not enough", mpName)
+	}
+}
+
+func TestClusterNodeCache_Unassume(t *testing.T) {
+	nodeName := "testnode"
+	vgName := "share"
+	device := "/dev/vdc"
+	var capacity int64 = 10 << 30
+
+	c := NewClusterNodeCache()
+	nc := NewNodeCache(nodeName)
+	nc.VGs[ResourceName(vgName)] = SharedResource{vgName, capacity, 0}
+	nc.Devices[ResourceName(device)] = ExclusiveResource{device, device, capacity, pkg.MediaTypeHDD, false}
+	c.SetNodeCache(nc)
+
+	units := []AllocatedUnit{
+		{
+			NodeName:   nodeName,
+			VolumeType: pkg.VolumeTypeLVM,
+			Requested:  6 << 30,
+			Allocated:  6 << 30,
+			VgName:     vgName,
+			PVCName:    "default/lvm-pvc",
+		},
+		{
+			NodeName:   nodeName,
+			VolumeType: pkg.VolumeTypeDevice,
+			Requested:  capacity,
+			Allocated:  capacity,
+			Device:     device,
+			PVCName:    "default/device-pvc",
+		},
+	}
+	if err := c.Assume(units); err != nil {
+		t.Fatalf("Assume() error = %v", err)
+	}
+	if err := c.Unassume(units); err != nil {
+		t.Fatalf("Unassume() error = %v", err)
+	}
+	nc = c.GetNodeCache(nodeName)
+	if nc.VGs[ResourceName(vgName)].Requested != 0 {
+		t.Errorf("requested after unassume is %d, expected 0", nc.VGs[ResourceName(vgName)].Requested)
+	}
+	if nc.Devices[ResourceName(device)].IsAllocated {
+		t.Errorf("device %s is still allocated after unassume", device)
+	}
+	if nc.AllocatedNum != 0 {
+		t.Errorf("allocated number after unassume is %d, expected 0", nc.AllocatedNum)
+	}
+	// vg has enough space for the volume again
+	if err := c.Assume(units[:1]); err != nil {
+		t.Errorf("Assume() after unassume error = %v", err)
+	}
+}
+
+func TestNodeCache_ThinPool(t *testing.T) {
+	nodeName := "testnode"
+	vgName := framework.DefaultVGName
+	var poolSize uint64 = 100 << 30
+
+	nodeLocal := &nodelocalstorage.NodeLocalStorage{
+		ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+		Spec: nodelocalstorage.NodeLocalStorageSpec{
+			ResourceToBeInited: nodelocalstorage.ResourceToBeInited{
+				VGs: []nodelocalstorage.VGToBeInited{
+					{Name: vgName, ThinPool: &nodelocalstorage.ThinPoolToBeInited{Name: "thinpool", OvercommitRatio: "2.5"}},
+				},
+			},
+		},
+		Status: nodelocalstorage.NodeLocalStorageStatus{
+			NodeStorageInfo: nodelocalstorage.NodeStorageInfo{
+				VolumeGroups: []nodelocalstorage.VolumeGroup{
+					{Name: vgName, Total: 200 << 30, Allocatable: 100 << 30, ThinPool: &nodelocalstorage.ThinPool{Name: "thinpool", Total: poolSize}},
+				},
+			},
+			FilteredStorageInfo: nodelocalstorage.FilteredStorageInfo{VolumeGroups: []string{vgName}},
+		},
+	}
+	nc := NewNodeCacheFromStorage(nodeLocal)
+	if nc.ThinPools[ResourceName(vgName)].Capacity != 250<<30 {
+		t.Fatalf("thin pool capacity is %d, expected %d", nc.ThinPools[ResourceName(vgName)].Capacity, int64(250<<30))
+	}
+
+	pv := framework.MakePV("thin-pv-1", nodeName, pkg.VolumeTypeLVM)
+	pv.Spec.CSI.VolumeAttributes[pkg.VolumeLVMType] = pkg.LVMTypeThin
+	size := pv.Spec.Capacity["storage"]
+	if err := nc.AddLVM(pv); err != nil {
+		t.Fatalf("AddLVM() error = %v", err)
+	}
+	if nc.ThinPools[ResourceName(vgName)].Requested != size.Value() || nc.VGs[ResourceName(vgName)].Requested != 0 {
+		t.Errorf("thin pv is accounted to thin pool %d and vg %d, expected %d and 0",
+			nc.ThinPools[ResourceName(vgName)].Requested, nc.VGs[ResourceName(vgName)].Requested, size.Value())
+	}
+
+	c := NewClusterNodeCache()
+	c.SetNodeCache(nc)
+	unit := AllocatedUnit{
+		NodeName:   nodeName,
+		VolumeType: pkg.VolumeTypeLVM,
+		Requested:  200 << 30,
+		Allocated:  200 << 30,
+		VgName:     vgName,
+		Thin:       true,
+		PVCName:    "default/thin-pvc-1",
+	}
+	// overcommitted beyond the vg allocatable
+	if err := c.Assume([]AllocatedUnit{unit}); err != nil {
+		t.Fatalf("Assume() error = %v", err)
+	}
+	if c.GetNodeCache(nodeName).ThinPools[ResourceName(vgName)].Requested != size.Value()+200<<30 {
+		t.Errorf("thin pool requested after assume is %d", c.GetNodeCache(nodeName).ThinPools[ResourceName(vgName)].Requested)
+	}
+	unit.PVCName = "default/thin-pvc-2"
+	if err := c.Assume([]AllocatedUnit{unit}); err == nil {
+		t.Errorf("Assume() should fail when thin pool of vg %s is not enough", vgName)
+	}
+
+	if err := nc.RemoveLVM(pv); err != nil {
+		t.Fatalf("RemoveLVM() error = %v", err)
+	}
+	if nc.ThinPools[ResourceName(vgName)].Requested != 200<<30 {
+		t.Errorf("thin pool requested after remove is %d, expected %d", nc.ThinPools[ResourceName(vgName)].Requested, int64(200<<30))
+	}
+}
+
+func TestNodeCache_IOCapacity(t *testing.T) {
+	nodeName := "testnode"
+	vgName := framework.DefaultVGName
+	capacity := nodelocalstorage.IOCapacity{ReadIOPS: 1000, WriteBPS: 100 << 20}
+
+	nodeLocal := &nodelocalstorage.NodeLocalStorage{
+		ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+		Status: nodelocalstorage.NodeLocalStorageStatus{
+			NodeStorageInfo: nodelocalstorage.NodeStorageInfo{
+				VolumeGroups: []nodelocalstorage.VolumeGroup{
+					{Name: vgName, Total: 200 << 30, Allocatable: 100 << 30, IOCapacity: &capacity},
+					{Name: "excluded", Total: 200 << 30, Allocatable: 100 << 30, IOCapacity: &capacity},
+				},
+				DeviceInfos: []nodelocalstorage.DeviceInfo{
+					{Name: "/dev/vdb", Total: 100 << 30},
+				},
+			},
+			FilteredStorageInfo: nodelocalstorage.FilteredStorageInfo{VolumeGroups: []string{vgName}, Devices: []string{"/dev/vdb"}},
+		},
+	}
+	nc := NewNodeCacheFromStorage(nodeLocal)
+	if len(nc.IOCapacities) != 1 || nc.IOCapacities[ResourceName(vgName)] != capacity {
+		t.Fatalf("io capacities are %+v, expected only %s", nc.IOCapacities, vgName)
+	}
+
+	// io budget of device is declared later
+	nodeLocal.Status.NodeStorageInfo.DeviceInfos[0].IOCapacity = &capacity
+	nc.UpdateNodeInfo(nodeLocal)
+	if len(nc.IOCapacities) != 2 || nc.IOCapacities["/dev/vdb"] != capacity {
+		t.Errorf("io capacities after update are %+v", nc.IOCapacities)
+	}
+}
+
+func TestNodeCache_FaultyResources(t *testing.T) {
+	nodeName := "testnode"
+	vgName := framework.DefaultVGName
+
+	nodeLocal := &nodelocalstorage.NodeLocalStorage{
+		ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+		Status: nodelocalstorage.NodeLocalStorageStatus{
+			NodeStorageInfo: nodelocalstorage.NodeStorageInfo{
+				VolumeGroups: []nodelocalstorage.VolumeGroup{
+					{Name: vgName, Total: 200 << 30, Allocatable: 100 << 30, Condition: nodelocalstorage.StorageFault},
+				},
+				DeviceInfos: []nodelocalstorage.DeviceInfo{
+					{Name: "/dev/vdb", Total: 100 << 30, Condition: nodelocalstorage.StorageReady},
+				},
+			},
+			FilteredStorageInfo: nodelocalstorage.FilteredStorageInfo{VolumeGroups: []string{vgName}, Devices: []string{"/dev/vdb"}},
+		},
+	}
+	nc := NewNodeCacheFromStorage(nodeLocal)
+	if len(nc.FaultyResources) != 1 || !nc.FaultyResources[ResourceName(vgName)] {
+		t.Fatalf("faulty resources are %+v, expected only %s", nc.FaultyResources, vgName)
+	}
+
+	// disk of vg is replaced, and device fails
+	nodeLocal.Status.NodeStorageInfo.VolumeGroups[0].Condition = nodelocalstorage.StorageReady
+	nodeLocal.Status.NodeStorageInfo.DeviceInfos[0].Condition = nodelocalstorage.StorageFault
+	nc.UpdateNodeInfo(nodeLocal)
+	if len(nc.FaultyResources) != 1 || !nc.FaultyResources["/dev/vdb"] {
+		t.Errorf("faulty resources after update are %+v", nc.FaultyResources)
+	}
+}
+
+func TestNodeCache_MediaTypes(t *testing.T) {
+	nodeLocal := &nodelocalstorage.NodeLocalStorage{
+		ObjectMeta: metav1.ObjectMeta{Name: "testnode"},
+		Status: nodelocalstorage.NodeLocalStorageStatus{
+			NodeStorageInfo: nodelocalstorage.NodeStorageInfo{
+				VolumeGroups: []nodelocalstorage.VolumeGroup{
+					{Name: "ssd", PhysicalVolumes: []string{"/dev/vdb", "/dev/vdc"}, Allocatable: 100 << 30},
+					{Name: "mixed", PhysicalVolumes: []string{"/dev/vdc", "/dev/vdd"}, Allocatable: 100 << 30},
+					{Name: "unfiltered", PhysicalVolumes: []string{"/dev/vdd"}, Allocatable: 100 << 30},
+				},
+				DeviceInfos: []nodelocalstorage.DeviceInfo{
+					{Name: "/dev/vdb", MediaType: string(pkg.MediaTypeSSD)},
+					{Name: "/dev/vdc", MediaType: string(pkg.MediaTypeSSD)},
+					{Name: "/dev/vdd", MediaType: string(pkg.MediaTypeHDD)},
+				},
+			},
+			FilteredStorageInfo: nodelocalstorage.FilteredStorageInfo{VolumeGroups: []string{"ssd", "mixed"}},
+		},
+	}
+	nc := NewNodeCacheFromStorage(nodeLocal)
+	if len(nc.MediaTypes) != 1 || nc.MediaTypes["ssd"] != pkg.MediaTypeSSD {
+		t.Fatalf("media types are %+v, expected only ssd", nc.MediaTypes)
+	}
+
+	// the hdd of vg mixed is replaced by ssd
+	nodeLocal.Status.NodeStorageInfo.DeviceInfos[2].MediaType = string(pkg.MediaTypeSSD)
+	nc.UpdateNodeInfo(nodeLocal)
+	if len(nc.MediaTypes) != 2 || nc.MediaTypes["mixed"] != pkg.MediaTypeSSD {
+		t.Errorf("media types after update are %+v", nc.MediaTypes)
+	}
+}
+
+func TestNodeCache_Clone(t *testing.T) {
+	nc := NewNodeCache("testnode")
+	nc.VGs["share"] = SharedResource{Name: "share", Capacity: 100 << 30, Requested: 10 << 30}
+	nc.Devices["/dev/vdb"] = ExclusiveResource{Name: "/dev/vdb", Capacity: 100 << 30, IsAllocated: true}
+	nc.PodInlineVolumeInfo["pod-1"] = []InlineVolumeInfo{{VgName: "share", VolumeSize: 10 << 30}}
+
+	clone := nc.Clone()
+	vg := clone.VGs["share"]
+	vg.Requested = 0
+	clone.VGs["share"] = vg
+	delete(clone.Devices, "/dev/vdb")
+	clone.PodInlineVolumeInfo["pod-1"][0].VolumeSize = 0
+
+	if nc.VGs["share"].Requested != 10<<30 {
+		t.Errorf("requested of vg share is changed to %d by clone", nc.VGs["share"].Requested)
+	}
+	if _, ok := nc.Devices["/dev/vdb"]; !ok {
+		t.Errorf("device /dev/vdb is removed by clone")
+	}
+	if nc.PodInlineVolumeInfo["pod-1"][0].VolumeSize != 10<<30 {
+		t.Errorf("inline volume of pod-1 is changed by clone")
+	}
+}
+
+func TestClusterNodeCache_Clone(t *testing.T) {
+	nodeName := "testnode"
+	vgName := "share"
+	var capacity int64 = 10 << 30
+
+	c := NewClusterNodeCache()
+	nc := NewNodeCache(nodeName)
+	nc.VGs[ResourceName(vgName)] = SharedResource{vgName, capacity, 0}
+	c.SetNodeCache(nc)
+	unit := AllocatedUnit{NodeName: nodeName, VolumeType: pkg.VolumeTypeLVM, Requested: 6 << 30, Allocated: 6 << 30, VgName: vgName, PVCName: "default/lvm-pvc"}
+	if err := c.Reserve("uid", "default/pod", []AllocatedUnit{unit}, pkg.DefaultReservationTTL); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+
+	clone := c.Clone()
+	if err := clone.Unreserve("uid"); err != nil {
+		t.Fatalf("Unreserve() of clone error = %v", err)
+	}
+	if vg := clone.GetNodeCache(nodeName).VGs[ResourceName(vgName)]; vg.Requested != 0 {
+		t.Errorf("vg of clone is requested %d after unreserve, expected 0", vg.Requested)
+	}
+	if clone.BindingInfo.IsPVCExists(unit.PVCName) {
+		t.Errorf("binding info of clone is not released by unreserve")
+	}
+
+	// the origin is not changed
+	if vg := c.GetNodeCache(nodeName).VGs[ResourceName(vgName)]; vg.Requested != unit.Requested {
+		t.Errorf("vg is requested %d, expected %d", vg.Requested, unit.Requested)
+	}
+	if !c.BindingInfo.IsPVCExists(unit.PVCName) || len(c.Reservations) != 1 {
+		t.Errorf("reservation of origin is released by clone")
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Reservation records the units assumed into cache for a pod, the units are released
+// when the binding fails, the pod is deleted or the reservation expires before pvs are created
+type Reservation struct {
+	PodUID  string           `json:"podUID"`
+	PodName string           `json:"podName"`
+	Units   []*AllocatedUnit `json:"units"`
+	// Committed records the pvcs whose pvs are created, the reservation is removed when all are committed
+	Committed  []string  `json:"committed,omitempty"`
+	Expiration time.Time `json:"expiration"`
+}
+
+// IsPartiallyCommitted checks whether some pvcs of the reservation are provisioned while the others are not
+func (r *Reservation) IsPartiallyCommitted() bool {
+	return len(r.Committed) > 0 && len(r.Units) > 0
+}
+
+// ReservationMap is the reservations keyed by uid of pod
+type ReservationMap map[string]*Reservation
+
+// Reserve assumes the units into cache as the reservation of pod, and records them in binding info,
+// the assumed units are reverted if any of them fails
+func (c *ClusterNodeCache) Reserve(podUID, podName string, units []AllocatedUnit, ttl time.Duration) error {
+	for i := range units {
+		if err := c.Assume(units[i : i+1]); err != nil {
+			if err := c.Unassume(units[:i]); err != nil {
+				log.Errorf("failed to unassume units of pod %s: %s", podName, err.Error())
+			}
+			return err
+		}
+	}
+	r, ok := c.Reservations[podUID]
+	if !ok {
+		r = &Reservation{PodUID: podUID, PodName: podName}
+		c.Reservations[podUID] = r
+	}
+	for _, unit := range units {
+		newUnit := unit
+		c.BindingInfo[newUnit.PVCName] = &newUnit
+		r.Units = append(r.Units, &newUnit)
+	}
+	r.Expiration = time.Now().Add(ttl)
+	log.Debugf("reserved %d units for pod %s until %s", len(units), podName, r.Expiration)
+	return nil
+}
+
+// Unreserve releases the units reserved for pod, which are not consumed by pvs yet
+func (c *ClusterNodeCache) Unreserve(podUID string) error {
+	r, ok := c.Reservations[podUID]
+	if !ok {
+		return nil
+	}
+	delete(c.Reservations, podUID)
+	units := make([]AllocatedUnit, 0, len(r.Units))
+	for _, unit := range r.Units {
+		if c.BindingInfo[unit.PVCName] == unit {
+			delete(c.BindingInfo, unit.PVCName)
+		}
+		units = append(units, *unit)
+	}
+	log.Debugf("unreserved %d units of pod %s", len(units), r.PodName)
+	return c.Unassume(units)
+}
+
+// ConsumeReservation removes the unit of pvc from reservations when its pv is added into cache,
+// the assumed unit is reverted for the pv is accounted instead, so it must be called before adding the pv
+func (c *ClusterNodeCache) ConsumeReservation(pvcName string) {
+	for podUID, r := range c.Reservations {
+		for i, unit := range r.Units {
+			if unit.PVCName != pvcName {
+				continue
+			}
+			if err := c.Unassume([]AllocatedUnit{*unit}); err != nil {
+				log.Errorf("failed to unassume unit of pvc %s: %s", pvcName, err.Error())
+			}
+			r.Units = append(r.Units[:i], r.Units[i+1:]...)
+			r.Committed = append(r.Committed, pvcName)
+			if len(r.Units) == 0 {
+				delete(c.Reservations, podUID)
+			}
+			log.Debugf("reservation of pvc %s for pod %s is consumed", pvcName, r.PodName)
+			return
+		}
+	}
+}
+
+// CleanExpiredReservations releases the reservations expired before now, and returns them
+func (c *ClusterNodeCache) CleanExpiredReservations(now time.Time) (expired []*Reservation) {
+	for podUID, r := range c.Reservations {
+		if r.Expiration.After(now) {
+			continue
+		}
+		if err := c.Unreserve(podUID); err != nil {
+			log.Errorf("failed to release expired reservation of pod %s: %s", r.PodName, err.Error())
+		}
+		expired = append(expired, r)
+	}
+	return expired
+}
@@ -0,0 +1,117 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/test/framework"
+)
+
+func TestClusterNodeCache_Reservation(t *testing.T) {
+	nodeName := "testnode"
+	vgName := framework.DefaultVGName
+	var capacity int64 = 100 << 30
+
+	c := NewClusterNodeCache()
+	nc := NewNodeCache(nodeName)
+	nc.VGs[ResourceName(vgName)] = SharedResource{vgName, capacity, 0}
+	c.SetNodeCache(nc)
+	requested := func() int64 {
+		return c.GetNodeCache(nodeName).VGs[ResourceName(vgName)].Requested
+	}
+	newUnit := func(pvcName string, size int64) AllocatedUnit {
+		return AllocatedUnit{
+			NodeName:   nodeName,
+			VolumeType: pkg.VolumeTypeLVM,
+			Requested:  size,
+			Allocated:  size,
+			VgName:     vgName,
+			PVCName:    pvcName,
+		}
+	}
+
+	// units are reverted if any of them fails
+	if err := c.Reserve("uid-0", "default/pod-0", []AllocatedUnit{newUnit("default/pvc-0", 60<<30), newUnit("default/pvc-00", 60<<30)}, time.Minute); err == nil {
+		t.Fatalf("Reserve() should fail when vg %s is not enough", vgName)
+	}
+	if requested() != 0 || c.BindingInfo.IsPVCExists("default/pvc-0") {
+		t.Fatalf("requested after failed reserve is %d, expected 0", requested())
+	}
+
+	if err := c.Reserve("uid-1", "default/pod-1", []AllocatedUnit{newUnit("default/pvc-1", 20<<30)}, time.Minute); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if requested() != 20<<30 || !c.BindingInfo.IsPVCExists("default/pvc-1") {
+		t.Fatalf("requested after reserve is %d, expected %d", requested(), int64(20<<30))
+	}
+
+	// pv of pvc-1 is created
+	pv := framework.MakePV("pv-1", nodeName, pkg.VolumeTypeLVM)
+	c.ConsumeReservation("default/pvc-1")
+	if err := c.GetNodeCache(nodeName).AddLVM(pv); err != nil {
+		t.Fatalf("AddLVM() error = %v", err)
+	}
+	if requested() != 20<<30 {
+		t.Errorf("requested after pv is created is %d, expected %d", requested(), int64(20<<30))
+	}
+	if _, ok := c.Reservations["uid-1"]; ok {
+		t.Errorf("reservation of pod-1 is not removed after consumed")
+	}
+
+	if err := c.Reserve("uid-2", "default/pod-2", []AllocatedUnit{newUnit("default/pvc-2", 30<<30)}, time.Minute); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if err := c.Reserve("uid-3", "default/pod-3", []AllocatedUnit{newUnit("default/pvc-3", 40<<30)}, -time.Minute); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if expired := c.CleanExpiredReservations(time.Now()); len(expired) != 1 || expired[0].PodName != "default/pod-3" || expired[0].IsPartiallyCommitted() {
+		t.Errorf("CleanExpiredReservations() = %v, expected reservation of default/pod-3", expired)
+	}
+	if requested() != 50<<30 || c.BindingInfo.IsPVCExists("default/pvc-3") {
+		t.Errorf("requested after expired reservation is cleaned is %d, expected %d", requested(), int64(50<<30))
+	}
+
+	// pod-2 is deleted
+	if err := c.Unreserve("uid-2"); err != nil {
+		t.Fatalf("Unreserve() error = %v", err)
+	}
+	if requested() != 20<<30 || c.BindingInfo.IsPVCExists("default/pvc-2") {
+		t.Errorf("requested after unreserve is %d, expected %d", requested(), int64(20<<30))
+	}
+	if err := c.Unreserve("uid-2"); err != nil {
+		t.Errorf("Unreserve() twice error = %v", err)
+	}
+	if len(c.Reservations) != 0 {
+		t.Errorf("reservations = %v, expected empty", c.Reservations)
+	}
+
+	// only pvc-5 of pod-5 is provisioned before the reservation expires
+	if err := c.Reserve("uid-5", "default/pod-5", []AllocatedUnit{newUnit("default/pvc-5", 10<<30), newUnit("default/pvc-6", 10<<30)}, -time.Minute); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	c.ConsumeReservation("default/pvc-5")
+	expired := c.CleanExpiredReservations(time.Now())
+	if len(expired) != 1 || !expired[0].IsPartiallyCommitted() || len(expired[0].Committed) != 1 || expired[0].Committed[0] != "default/pvc-5" {
+		t.Errorf("CleanExpiredReservations() = %+v, expected reservation of default/pod-5 partially committed", expired)
+	}
+	if requested() != 20<<30 || c.BindingInfo.IsPVCExists("default/pvc-6") {
+		t.Errorf("requested after partially committed reservation is cleaned is %d, expected %d", requested(), int64(20<<30))
+	}
+}
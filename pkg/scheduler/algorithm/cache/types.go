@@ -20,6 +20,7 @@ import (
 	"sync"
 
 	localtype "github.com/alibaba/open-local/pkg"
+	nodelocalstorage "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
 	"github.com/alibaba/open-local/pkg/utils"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
@@ -40,7 +41,23 @@ type NodeInfo struct {
 	VGs         map[ResourceName]SharedResource
 	MountPoints map[ResourceName]ExclusiveResource
 	// Devices only contains the whitelist raw devices
-	Devices             map[ResourceName]ExclusiveResource
+	Devices map[ResourceName]ExclusiveResource
+	// Quotas records the project quota usage of each mount point,
+	// keyed by mount point name
+	Quotas map[ResourceName]SharedResource
+	// ThinPools records the thin volume usage of the thin pool in each VG,
+	// keyed by VG name, the capacity is pool size multiplied by overcommit ratio
+	ThinPools map[ResourceName]SharedResource
+	// IOCapacities records the io budget of VGs and devices, keyed by VG name or device name,
+	// VGs and devices without io budget are not limited
+	IOCapacities map[ResourceName]nodelocalstorage.IOCapacity
+	// PerformanceTiers records the benchmarked tier of VGs and devices, keyed by VG name or device name
+	PerformanceTiers map[ResourceName]string
+	// MediaTypes records the media type of VGs whose physical volumes are of the same media, keyed by VG name
+	MediaTypes map[ResourceName]localtype.MediaType
+	// FaultyResources records the VGs, devices and mount points on faulty disks,
+	// keyed by VG name, device name or mount point name
+	FaultyResources     map[ResourceName]bool
 	AllocatedNum        int64
 	LocalPVs            map[string]corev1.PersistentVolume
 	PodInlineVolumeInfo map[string][]InlineVolumeInfo
@@ -80,9 +97,83 @@ type AllocatedUnit struct {
 	Requested  int64 // requested size from pvc
 	Allocated  int64 // actual allocated size for the pvc
 	VgName     string
+	Thin       bool // Thin means the volume is allocated from the thin pool of VgName
 	Device     string
 	MountPoint string
 	PVCName    string
+	IO         IORequest // io limits of the pvc
+}
+
+// IORequest is the io limits of volumes, zero means unlimited
+type IORequest struct {
+	ReadIOPS  uint64 `json:"readIOPS,omitempty"`
+	WriteIOPS uint64 `json:"writeIOPS,omitempty"`
+	ReadBPS   uint64 `json:"readBPS,omitempty"`
+	WriteBPS  uint64 `json:"writeBPS,omitempty"`
+}
+
+// IsZero checks whether no limit is requested
+func (r IORequest) IsZero() bool {
+	return r == IORequest{}
+}
+
+// Add returns the sum of io requests
+func (r IORequest) Add(o IORequest) IORequest {
+	return IORequest{
+		ReadIOPS:  r.ReadIOPS + o.ReadIOPS,
+		WriteIOPS: r.WriteIOPS + o.WriteIOPS,
+		ReadBPS:   r.ReadBPS + o.ReadBPS,
+		WriteBPS:  r.WriteBPS + o.WriteBPS,
+	}
+}
+
+// IOItem is an io limit of request and the budget of it
+type IOItem struct {
+	Name      string
+	Requested uint64
+	Capacity  uint64
+}
+
+// Items pairs the io limits of request with the budgets in capacity
+func (r IORequest) Items(capacity nodelocalstorage.IOCapacity) []IOItem {
+	return []IOItem{
+		{"readIOPS", r.ReadIOPS, capacity.ReadIOPS},
+		{"writeIOPS", r.WriteIOPS, capacity.WriteIOPS},
+		{"readBPS", r.ReadBPS, capacity.ReadBPS},
+		{"writeBPS", r.WriteBPS, capacity.WriteBPS},
+	}
+}
+
+// Exceeds returns the first io limit which exceeds its budget, budgets of zero are unlimited
+func (r IORequest) Exceeds(capacity nodelocalstorage.IOCapacity) *IOItem {
+	for _, item := range r.Items(capacity) {
+		if item.Capacity > 0 && item.Requested > item.Capacity {
+			return &item
+		}
+	}
+	return nil
+}
+
+// Utilization returns the max ratio of the io limits to their budgets, budgets of zero are ignored
+func (r IORequest) Utilization(capacity nodelocalstorage.IOCapacity) float64 {
+	utilization := 0.0
+	for _, item := range r.Items(capacity) {
+		if item.Capacity > 0 && float64(item.Requested)/float64(item.Capacity) > utilization {
+			utilization = float64(item.Requested) / float64(item.Capacity)
+		}
+	}
+	return utilization
+}
+
+// IOResourceName returns the VG or device which the io of unit is from
+func (u AllocatedUnit) IOResourceName() ResourceName {
+	switch u.VolumeType {
+	case localtype.VolumeTypeLVM:
+		return ResourceName(u.VgName)
+	case localtype.VolumeTypeDevice:
+		return ResourceName(u.Device)
+	}
+	return ""
 }
 
 // pvc and binding info mapping
@@ -121,6 +212,21 @@ func NewPodPvcMapping() *PodPvcMapping {
 	}
 }
 
+// Clone returns a deep copy of the mapping
+func (p *PodPvcMapping) Clone() *PodPvcMapping {
+	clone := NewPodPvcMapping()
+	for podName, info := range p.PodPvcInfo {
+		clone.PodPvcInfo[podName] = NewPvcStatusInfo()
+		for pvcName, selected := range info {
+			clone.PodPvcInfo[podName][pvcName] = selected
+		}
+	}
+	for pvcName, podName := range p.PvcPod {
+		clone.PvcPod[pvcName] = podName
+	}
+	return clone
+}
+
 func NewPvcStatusInfo() PvcStatusInfo {
 	return make(PvcStatusInfo)
 }
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/alibaba/open-local/pkg"
+	nodelocalstorage "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
 	"github.com/alibaba/open-local/pkg/utils"
 	"github.com/alibaba/open-local/test/framework"
 	corev1 "k8s.io/api/core/v1"
@@ -282,3 +283,44 @@ func TestNewPvcStatusInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestIORequest(t *testing.T) {
+	capacity := nodelocalstorage.IOCapacity{ReadIOPS: 1000, WriteIOPS: 500}
+	tests := []struct {
+		name        string
+		request     IORequest
+		exceeds     string
+		utilization float64
+	}{
+		{
+			name:        "fits",
+			request:     IORequest{ReadIOPS: 500, WriteIOPS: 100, ReadBPS: 1 << 30},
+			utilization: 0.5,
+		},
+		{
+			name:        "full",
+			request:     IORequest{ReadIOPS: 1000, WriteIOPS: 500},
+			utilization: 1,
+		},
+		{
+			name:        "exceeds write iops",
+			request:     IORequest{ReadIOPS: 100, WriteIOPS: 100}.Add(IORequest{WriteIOPS: 500}),
+			exceeds:     "writeIOPS",
+			utilization: 1.2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exceeds := ""
+			if item := test.request.Exceeds(capacity); item != nil {
+				exceeds = item.Name
+			}
+			if exceeds != test.exceeds {
+				t.Errorf("Exceeds() = %q, expected %q", exceeds, test.exceeds)
+			}
+			if utilization := test.request.Utilization(capacity); utilization != test.utilization {
+				t.Errorf("Utilization() = %v, expected %v", utilization, test.utilization)
+			}
+		})
+	}
+}
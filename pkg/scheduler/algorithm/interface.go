@@ -18,6 +18,7 @@ package algorithm
 
 import (
 	"sync"
+	"time"
 
 	"github.com/alibaba/open-local/pkg"
 
@@ -53,6 +54,10 @@ type SchedulingContext struct {
 	SnapshotInformers      volumesnapshotinformers.Interface
 	LocalStorageInformer   nodelocalstorageinformer.Interface
 	NodeAntiAffinityWeight *pkg.NodeAntiAffinityWeight
+	// ReservationTTL is the time the units are reserved for a pod before its pvs are created
+	ReservationTTL time.Duration
+	// SchedulerStrategy is the default strategy of volumes, --scheduler-strategy is used if it is empty
+	SchedulerStrategy pkg.StrategyType
 }
 
 func NewSchedulingContext(coreV1Informers corev1informers.Interface,
@@ -69,6 +74,7 @@ func NewSchedulingContext(coreV1Informers corev1informers.Interface,
 		LocalStorageInformer:   localStorageInformer,
 		SnapshotInformers:      snapshotInformer,
 		NodeAntiAffinityWeight: weights,
+		ReservationTTL:         pkg.DefaultReservationTTL,
 	}
 
 }
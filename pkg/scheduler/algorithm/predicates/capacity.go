@@ -22,6 +22,7 @@ import (
 
 	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
 	"github.com/alibaba/open-local/pkg/scheduler/algorithm/algo"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
 	"github.com/alibaba/open-local/pkg/utils"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
@@ -29,7 +30,7 @@ import (
 )
 
 // CapacityPredicate checks if local storage on a node matches the persistent volume claims, follow rules are applied:
-// 1. pvc contains vg or mount point or device claim
+// 1. pvc contains vg or mount point or device or quota claim
 // 2. node free size must larger or equal to pvcs
 // 3. for pvc of type mount point/device:
 //	 a. must contains more mount points than pvc count
@@ -38,7 +39,7 @@ func CapacityPredicate(ctx *algorithm.SchedulingContext, pod *corev1.Pod, node *
 	defer trace.LogIfLong(50 * time.Millisecond)
 
 	containReadonlySnapshot := false
-	err, lvmPVCs, mpPVCs, devicePVCs := algorithm.GetPodPvcs(pod, ctx, true, containReadonlySnapshot)
+	err, lvmPVCs, mpPVCs, devicePVCs, quotaPVCs := algorithm.GetPodPvcs(pod, ctx, true, containReadonlySnapshot)
 	if err != nil {
 		return false, err
 	}
@@ -55,10 +56,12 @@ func CapacityPredicate(ctx *algorithm.SchedulingContext, pod *corev1.Pod, node *
 	}
 
 	var fits bool
+	var units, allocatedUnits []cache.AllocatedUnit
 	if len(lvmPVCs) > 0 {
 		trace.Step("Computing AllocateLVMVolume")
 
-		fits, _, err = algo.AllocateLVMVolume(pod, lvmPVCs, node, ctx)
+		fits, units, err = algo.AllocateLVMVolume(pod, lvmPVCs, node, ctx)
+		allocatedUnits = append(allocatedUnits, units...)
 		if err != nil {
 			log.Error(err)
 			return false, err
@@ -82,7 +85,8 @@ func CapacityPredicate(ctx *algorithm.SchedulingContext, pod *corev1.Pod, node *
 	if len(devicePVCs) > 0 {
 		trace.Step("Computing AllocateDeviceVolume")
 
-		fits, _, err = algo.AllocateDeviceVolume(pod, devicePVCs, node, ctx)
+		fits, units, err = algo.AllocateDeviceVolume(pod, devicePVCs, node, ctx)
+		allocatedUnits = append(allocatedUnits, units...)
 		if err != nil {
 			log.Error(err)
 			return false, err
@@ -91,8 +95,30 @@ func CapacityPredicate(ctx *algorithm.SchedulingContext, pod *corev1.Pod, node *
 		}
 	}
 
+	if len(quotaPVCs) > 0 {
+		trace.Step("Computing AllocateQuotaVolume")
+
+		fits, _, err = algo.AllocateQuotaVolume(pod, quotaPVCs, node, ctx)
+		if err != nil {
+			log.Error(err)
+			return false, err
+		} else if !fits {
+			return false, nil
+		}
+	}
+
+	if len(allocatedUnits) > 0 {
+		trace.Step("Computing ProcessIOCapacity")
+		if fits, err = processIOCapacity(allocatedUnits, node, ctx); err != nil {
+			log.Error(err)
+			return false, err
+		} else if !fits {
+			return false, nil
+		}
+	}
+
 	containReadonlySnapshot = true
-	err, lvmPVCs, _, _ = algorithm.GetPodPvcs(pod, ctx, true, containReadonlySnapshot)
+	err, lvmPVCs, _, _, _ = algorithm.GetPodPvcs(pod, ctx, true, containReadonlySnapshot)
 	if err != nil {
 		return false, err
 	}
@@ -108,8 +134,14 @@ func CapacityPredicate(ctx *algorithm.SchedulingContext, pod *corev1.Pod, node *
 			return false, nil
 		}
 	}
+	// clone pvcs must be on the node of source pvc
+	if utils.ContainsClonePVC(lvmPVCs) {
+		if fits, err := algo.ProcessClonePVC(lvmPVCs, node, ctx); err != nil || !fits {
+			return false, err
+		}
+	}
 
-	if len(lvmPVCs) <= 0 && len(mpPVCs) <= 0 && len(devicePVCs) <= 0 && !containInlineVolume {
+	if len(lvmPVCs) <= 0 && len(mpPVCs) <= 0 && len(devicePVCs) <= 0 && len(quotaPVCs) <= 0 && !containInlineVolume {
 		log.Infof("no open-local volume request on pod %s, skipped", pod.Name)
 		return true, nil
 	}
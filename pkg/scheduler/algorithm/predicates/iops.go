@@ -15,3 +15,20 @@ limitations under the License.
 */
 
 package predicates
+
+import (
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/algo"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// processIOCapacity checks whether the sum of io limits of the allocated volumes and
+// the pvcs of pod exceeds the io budget of VGs and devices on node
+func processIOCapacity(units []cache.AllocatedUnit, node *corev1.Node, ctx *algorithm.SchedulingContext) (bool, error) {
+	algo.SetIORequests(units, ctx)
+	// binding info is updated by event handlers
+	ctx.CtxLock.RLock()
+	defer ctx.CtxLock.RUnlock()
+	return algo.ProcessIOCapacity(units, node, ctx)
+}
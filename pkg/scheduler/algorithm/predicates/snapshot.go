@@ -33,7 +33,7 @@ func SnapshotPredicate(ctx *algorithm.SchedulingContext, pod *corev1.Pod, node *
 	defer trace.LogIfLong(50 * time.Millisecond)
 
 	containReadonlySnapshot := true
-	err, lvmPVCs, _, _ := algorithm.GetPodPvcs(pod, ctx, true, containReadonlySnapshot)
+	err, lvmPVCs, _, _, _ := algorithm.GetPodPvcs(pod, ctx, true, containReadonlySnapshot)
 
 	if err != nil {
 		return false, err
@@ -17,23 +17,318 @@ limitations under the License.
 package preemptions
 
 import (
-	v1 "k8s.io/api/core/v1"
+	"fmt"
+	"sort"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/predicates"
+	"github.com/alibaba/open-local/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	schedulerapi "k8s.io/kube-scheduler/extender/v1"
 )
 
+// Preemption checks whether evicting the victims chosen by scheduler frees enough local storage
+// for the preemptor, and adds the minimal lower priority pods to victims if not.
+// Evicting a pod frees the storage of its ephemeral inline volumes, and of its pvcs
+// which are being deleted or owned by the pod, other pvcs still hold the storage.
 type Preemption struct {
-	Func func(
-		pod v1.Pod,
-		nodeNameToVictims map[string]*schedulerapi.Victims,
-		nodeNameToMetaVictims map[string]*schedulerapi.MetaVictims,
-	) map[string]*schedulerapi.MetaVictims
+	Name           string
+	Ctx            *algorithm.SchedulingContext
+	PredicateFuncs []predicates.PredicateFunc
+}
+
+func NewPreemption(ctx *algorithm.SchedulingContext) *Preemption {
+	if ctx == nil {
+		panic("scheduling context must not be nil")
+	}
+	return &Preemption{"open-local-preemption", ctx, predicates.DefaultPredicateFuncs}
 }
 
-func (b Preemption) Handler(
+func (p Preemption) Handler(
 	args schedulerapi.ExtenderPreemptionArgs,
 ) *schedulerapi.ExtenderPreemptionResult {
-	nodeNameToMetaVictims := b.Func(*args.Pod, args.NodeNameToVictims, args.NodeNameToMetaVictims)
-	return &schedulerapi.ExtenderPreemptionResult{
-		NodeNameToMetaVictims: nodeNameToMetaVictims,
+	pod := args.Pod
+	nodeNameToVictims := p.getVictims(args)
+
+	result := make(map[string]*schedulerapi.MetaVictims, len(nodeNameToVictims))
+	if utils.NeedSkip(schedulerapi.ExtenderArgs{Pod: pod}) {
+		log.Infof("preemption: skip pod %s/%s preemption", pod.Namespace, pod.Name)
+		for nodeName, victims := range nodeNameToVictims {
+			result[nodeName] = toMetaVictims(victims)
+		}
+		return &schedulerapi.ExtenderPreemptionResult{NodeNameToMetaVictims: result}
+	}
+
+	for nodeName, victims := range nodeNameToVictims {
+		pods, err := p.preemptOnNode(pod, nodeName, victims.Pods)
+		if err != nil {
+			log.Infof("pod %s/%s can not preempt on node %s: %s", pod.Namespace, pod.Name, nodeName, err.Error())
+			continue
+		}
+		log.Infof("pod %s/%s preempts %d pods on node %s", pod.Namespace, pod.Name, len(pods), nodeName)
+		result[nodeName] = toMetaVictims(&schedulerapi.Victims{Pods: pods, NumPDBViolations: victims.NumPDBViolations})
+	}
+	return &schedulerapi.ExtenderPreemptionResult{NodeNameToMetaVictims: result}
+}
+
+// getVictims returns the victims chosen by scheduler, meta victims are looked up from pod informer
+func (p Preemption) getVictims(args schedulerapi.ExtenderPreemptionArgs) map[string]*schedulerapi.Victims {
+	if args.NodeNameToMetaVictims == nil {
+		return args.NodeNameToVictims
+	}
+	nodeNameToVictims := make(map[string]*schedulerapi.Victims, len(args.NodeNameToMetaVictims))
+	for nodeName, metaVictims := range args.NodeNameToMetaVictims {
+		victims := &schedulerapi.Victims{NumPDBViolations: metaVictims.NumPDBViolations}
+		pods, err := p.getPodsOnNode(nodeName)
+		if err != nil {
+			log.Errorf("failed to list pods on node %s: %s", nodeName, err.Error())
+		}
+		podsByUID := make(map[string]*corev1.Pod, len(pods))
+		for _, pod := range pods {
+			podsByUID[string(pod.UID)] = pod
+		}
+		for _, metaPod := range metaVictims.Pods {
+			if pod, ok := podsByUID[metaPod.UID]; ok {
+				victims.Pods = append(victims.Pods, pod)
+			} else {
+				log.Warningf("victim %s is not found on node %s", metaPod.UID, nodeName)
+			}
+		}
+		nodeNameToVictims[nodeName] = victims
+	}
+	return nodeNameToVictims
+}
+
+func (p Preemption) getPodsOnNode(nodeName string) ([]*corev1.Pod, error) {
+	pods, err := p.Ctx.CoreV1Informers.Pods().Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var podsOnNode []*corev1.Pod
+	for _, pod := range pods {
+		if pod.Spec.NodeName == nodeName {
+			podsOnNode = append(podsOnNode, pod)
+		}
+	}
+	return podsOnNode, nil
+}
+
+// preemptOnNode returns the victims and the minimal lower priority pods, whose eviction
+// frees enough local storage on node for pod
+func (p Preemption) preemptOnNode(pod *corev1.Pod, nodeName string, victims []*corev1.Pod) ([]*corev1.Pod, error) {
+	node, err := p.Ctx.CoreV1Informers.Nodes().Lister().Get(nodeName)
+	if err != nil {
+		return nil, err
+	}
+	// the cache is copied under lock as Clone does, it is changed by informers during simulation
+	p.Ctx.CtxLock.RLock()
+	nodeCache := p.Ctx.ClusterNodeCache.GetNodeCache(nodeName)
+	if nodeCache == nil {
+		p.Ctx.CtxLock.RUnlock()
+		log.Infof("node %s is not found from cache, victims of pod %s/%s are not changed", nodeName, pod.Namespace, pod.Name)
+		return victims, nil
+	}
+	nodeCache = nodeCache.Clone()
+	bindingInfo := make(cache.BindingMap, len(p.Ctx.ClusterNodeCache.BindingInfo))
+	for pvcName, unit := range p.Ctx.ClusterNodeCache.BindingInfo {
+		u := *unit
+		bindingInfo[pvcName] = &u
+	}
+	pvcMapping := p.Ctx.ClusterNodeCache.PvcMapping.Clone()
+	p.Ctx.CtxLock.RUnlock()
+
+	fits, err := p.simulate(pod, node, nodeCache, bindingInfo, pvcMapping, victims)
+	if err != nil {
+		return nil, err
+	} else if fits {
+		return victims, nil
+	}
+
+	// the victims do not free enough storage, evict lower priority pods which free the most storage first
+	candidates, err := p.getCandidates(pod, nodeName, nodeCache, victims)
+	if err != nil {
+		return nil, err
+	}
+	var added []*corev1.Pod
+	for _, candidate := range candidates {
+		added = append(added, candidate)
+		if fits, err = p.simulate(pod, node, nodeCache, bindingInfo, pvcMapping, concat(victims, added)); err != nil {
+			return nil, err
+		} else if fits {
+			break
+		}
+	}
+	if !fits {
+		return nil, fmt.Errorf("not enough local storage after evicting all lower priority pods")
+	}
+
+	// reprieve the added pods which are not necessary, the last one is always necessary
+	for i := len(added) - 2; i >= 0; i-- {
+		reprieved := concat(added[:i], added[i+1:])
+		if fits, err = p.simulate(pod, node, nodeCache, bindingInfo, pvcMapping, concat(victims, reprieved)); err != nil {
+			return nil, err
+		} else if fits {
+			added = reprieved
+		}
+	}
+	return concat(victims, added), nil
+}
+
+func concat(a, b []*corev1.Pod) []*corev1.Pod {
+	return append(append(make([]*corev1.Pod, 0, len(a)+len(b)), a...), b...)
+}
+
+type candidate struct {
+	pod   *corev1.Pod
+	freed int64
+}
+
+// getCandidates returns the pods on node with lower priority than pod, which free local storage if evicted,
+// sorted by priority ascending and freed storage descending
+func (p Preemption) getCandidates(pod *corev1.Pod, nodeName string, nodeCache *cache.NodeCache, victims []*corev1.Pod) ([]*corev1.Pod, error) {
+	pods, err := p.getPodsOnNode(nodeName)
+	if err != nil {
+		return nil, err
+	}
+	isVictim := make(map[string]bool, len(victims))
+	for _, victim := range victims {
+		isVictim[string(victim.UID)] = true
+	}
+	var candidates []candidate
+	for _, other := range pods {
+		if isVictim[string(other.UID)] || other.UID == pod.UID || other.DeletionTimestamp != nil ||
+			other.Status.Phase == corev1.PodSucceeded || other.Status.Phase == corev1.PodFailed ||
+			podPriority(other) >= podPriority(pod) {
+			continue
+		}
+		pvs, inlineVolumes := p.getFreeableVolumes(other, nodeCache)
+		var freed int64
+		for _, pv := range pvs {
+			size := pv.Spec.Capacity[corev1.ResourceStorage]
+			freed += size.Value()
+		}
+		for _, volume := range inlineVolumes {
+			freed += volume.VolumeSize
+		}
+		if len(pvs) > 0 || len(inlineVolumes) > 0 {
+			candidates = append(candidates, candidate{pod: other, freed: freed})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		pi, pj := podPriority(candidates[i].pod), podPriority(candidates[j].pod)
+		if pi != pj {
+			return pi < pj
+		}
+		return candidates[i].freed > candidates[j].freed
+	})
+	result := make([]*corev1.Pod, 0, len(candidates))
+	for _, c := range candidates {
+		result = append(result, c.pod)
+	}
+	return result, nil
+}
+
+// getFreeableVolumes returns the local pvs and inline volumes on node which are freed if pod is evicted
+func (p Preemption) getFreeableVolumes(pod *corev1.Pod, nodeCache *cache.NodeCache) (pvs []*corev1.PersistentVolume, inlineVolumes []cache.InlineVolumeInfo) {
+	for _, volume := range pod.Spec.Volumes {
+		var claimName string
+		if volume.PersistentVolumeClaim != nil {
+			claimName = volume.PersistentVolumeClaim.ClaimName
+		} else if volume.Ephemeral != nil {
+			claimName = fmt.Sprintf("%s-%s", pod.Name, volume.Name)
+		} else {
+			continue
+		}
+		pvc, err := p.Ctx.CoreV1Informers.PersistentVolumeClaims().Lister().PersistentVolumeClaims(pod.Namespace).Get(claimName)
+		if err != nil {
+			log.Debugf("failed to get pvc %s/%s of pod %s: %s", pod.Namespace, claimName, pod.Name, err.Error())
+			continue
+		}
+		if pvc.DeletionTimestamp == nil && !metav1.IsControlledBy(pvc, pod) {
+			continue
+		}
+		if pv, ok := nodeCache.LocalPVs[pvc.Spec.VolumeName]; ok {
+			pvs = append(pvs, pv.DeepCopy())
+		}
+	}
+	return pvs, nodeCache.PodInlineVolumeInfo[string(pod.UID)]
+}
+
+// simulate checks whether pod fits node after evicting victims, nodeCache and bindingInfo are not changed
+func (p Preemption) simulate(pod *corev1.Pod, node *corev1.Node, nodeCache *cache.NodeCache, bindingInfo cache.BindingMap, pvcMapping *cache.PodPvcMapping, victims []*corev1.Pod) (bool, error) {
+	nodeCache = nodeCache.Clone()
+	clusterNodeCache := cache.NewClusterNodeCache()
+	clusterNodeCache.SetNodeCache(nodeCache)
+	clusterNodeCache.PvcMapping = pvcMapping
+	for pvcName, unit := range bindingInfo {
+		clusterNodeCache.BindingInfo[pvcName] = unit
+	}
+	ctx := &algorithm.SchedulingContext{
+		ClusterNodeCache:       clusterNodeCache,
+		CoreV1Informers:        p.Ctx.CoreV1Informers,
+		StorageV1Informers:     p.Ctx.StorageV1Informers,
+		SnapshotInformers:      p.Ctx.SnapshotInformers,
+		LocalStorageInformer:   p.Ctx.LocalStorageInformer,
+		NodeAntiAffinityWeight: p.Ctx.NodeAntiAffinityWeight,
+		ReservationTTL:         p.Ctx.ReservationTTL,
+	}
+
+	for _, victim := range victims {
+		pvs, _ := p.getFreeableVolumes(victim, nodeCache)
+		for _, pv := range pvs {
+			if err := p.removePV(nodeCache, pv); err != nil {
+				return false, err
+			}
+			if pv.Spec.ClaimRef != nil {
+				delete(clusterNodeCache.BindingInfo, fmt.Sprintf("%s/%s", pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name))
+			}
+		}
+		if err := nodeCache.DeletePodInlineVolumeInfo(victim); err != nil {
+			return false, err
+		}
+	}
+
+	fits, failReasons, err := predicates.Predicates(ctx, p.PredicateFuncs, pod, node)
+	log.Debugf("pod %s/%s fits node %s after evicting %d pods: %t, failReasons: %s", pod.Namespace, pod.Name, node.Name, len(victims), fits, failReasons)
+	return fits, err
+}
+
+func (p Preemption) removePV(nodeCache *cache.NodeCache, pv *corev1.PersistentVolume) error {
+	containReadonlySnapshot := false
+	isOpenLocalPV, pvType := utils.IsOpenLocalPV(pv, p.Ctx.StorageV1Informers, p.Ctx.CoreV1Informers, containReadonlySnapshot)
+	if !isOpenLocalPV {
+		return nil
+	}
+	switch pvType {
+	case localtype.VolumeTypeLVM:
+		return nodeCache.RemoveLVM(pv)
+	case localtype.VolumeTypeMountPoint:
+		return nodeCache.RemoveLocalMountPoint(pv)
+	case localtype.VolumeTypeDevice:
+		return nodeCache.RemoveLocalDevice(pv)
+	case localtype.VolumeTypeQuota:
+		return nodeCache.RemoveQuota(pv)
+	}
+	return nil
+}
+
+// podPriority returns the priority of pod, which is 0 if not set
+func podPriority(pod *corev1.Pod) int32 {
+	if pod.Spec.Priority != nil {
+		return *pod.Spec.Priority
+	}
+	return 0
+}
+
+func toMetaVictims(victims *schedulerapi.Victims) *schedulerapi.MetaVictims {
+	metaVictims := &schedulerapi.MetaVictims{NumPDBViolations: victims.NumPDBViolations}
+	for _, pod := range victims.Pods {
+		metaVictims.Pods = append(metaVictims.Pods, &schedulerapi.MetaPod{UID: string(pod.UID)})
 	}
+	return metaVictims
 }
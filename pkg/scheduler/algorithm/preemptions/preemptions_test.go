@@ -0,0 +1,249 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preemptions
+
+import (
+	"testing"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	schedulerapi "k8s.io/kube-scheduler/extender/v1"
+)
+
+const (
+	testNode         = "testnode"
+	testStorageClass = "open-local-lvm"
+)
+
+type testPod struct {
+	name     string
+	priority int32
+	// size of the pvc of pod in Gi
+	size int64
+	// owned means the pvc is controlled by the pod, and deleted with it
+	owned bool
+}
+
+func newPreemption(pods ...testPod) *Preemption {
+	factory := informers.NewSharedInformerFactory(k8sfake.NewSimpleClientset(), 0)
+	factory.Core().V1().Nodes().Informer().GetIndexer().Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: testNode}})
+	factory.Storage().V1().StorageClasses().Informer().GetIndexer().Add(&storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: testStorageClass},
+		Provisioner: localtype.ProvisionerName,
+		Parameters:  map[string]string{localtype.VolumeTypeKey: string(localtype.VolumeTypeLVM)},
+	})
+	ctx := &algorithm.SchedulingContext{
+		ClusterNodeCache:       cache.NewClusterNodeCache(),
+		CoreV1Informers:        factory.Core().V1(),
+		StorageV1Informers:     factory.Storage().V1(),
+		NodeAntiAffinityWeight: localtype.NewNodeAntiAffinityWeight(),
+	}
+	nc := cache.NewNodeCache(testNode)
+	nc.VGs["share"] = cache.SharedResource{Name: "share", Capacity: 100 << 30}
+	ctx.ClusterNodeCache.SetNodeCache(nc)
+
+	for _, p := range pods {
+		pod := newPod(p.name, p.priority)
+		pod.Spec.NodeName = testNode
+		pvc := newPVC(p.name, p.size)
+		pvc.Spec.VolumeName = "pv-" + p.name
+		if p.owned {
+			controller := true
+			pvc.OwnerReferences = []metav1.OwnerReference{{Kind: "Pod", Name: pod.Name, UID: pod.UID, Controller: &controller}}
+		}
+		pv := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: pvc.Spec.VolumeName},
+			Spec: corev1.PersistentVolumeSpec{
+				Capacity: corev1.ResourceList{corev1.ResourceStorage: *resource.NewQuantity(p.size<<30, resource.BinarySI)},
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{
+						Driver: localtype.ProvisionerName,
+						VolumeAttributes: map[string]string{
+							localtype.VolumeTypeKey: string(localtype.VolumeTypeLVM),
+							"vgName":                "share",
+						},
+					},
+				},
+				ClaimRef:         &corev1.ObjectReference{Namespace: pvc.Namespace, Name: pvc.Name},
+				StorageClassName: testStorageClass,
+				NodeAffinity: &corev1.VolumeNodeAffinity{
+					Required: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+							MatchExpressions: []corev1.NodeSelectorRequirement{{
+								Key:      localtype.KubernetesNodeIdentityKey,
+								Operator: corev1.NodeSelectorOpIn,
+								Values:   []string{testNode},
+							}},
+						}},
+					},
+				},
+			},
+		}
+		factory.Core().V1().Pods().Informer().GetIndexer().Add(pod)
+		factory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(pvc)
+		if err := nc.AddLVM(pv); err != nil {
+			panic(err)
+		}
+	}
+	factory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(newPVC("preemptor", 50))
+	return NewPreemption(ctx)
+}
+
+func newPod(name string, priority int32) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name)},
+		Spec: corev1.PodSpec{
+			Priority: &priority,
+			Volumes: []corev1.Volume{
+				{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "pvc-" + name},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newPVC(podName string, size int64) *corev1.PersistentVolumeClaim {
+	storageClass := testStorageClass
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc-" + podName, Namespace: "default"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: *resource.NewQuantity(size<<30, resource.BinarySI)},
+			},
+		},
+	}
+}
+
+func TestPreemption_Handler(t *testing.T) {
+	tests := []struct {
+		name              string
+		pods              []testPod
+		preemptorPriority int32
+		victims           []string
+		// expected victims, nil means the node is not suitable
+		expected []string
+	}{
+		{
+			name:              "victims free enough storage",
+			pods:              []testPod{{name: "low", size: 60, owned: true}, {name: "other", size: 30}},
+			preemptorPriority: 100,
+			victims:           []string{"low"},
+			expected:          []string{"low"},
+		},
+		{
+			name:              "pvc of victim is not deleted",
+			pods:              []testPod{{name: "low", size: 60, owned: true}, {name: "other", size: 30}},
+			preemptorPriority: 100,
+			victims:           []string{"other"},
+			expected:          []string{"other", "low"},
+		},
+		{
+			name:              "no lower priority pod frees storage",
+			pods:              []testPod{{name: "low", size: 60, owned: true}, {name: "other", size: 30}},
+			preemptorPriority: 0,
+			victims:           []string{"other"},
+		},
+		{
+			name:              "unnecessary victims are reprieved",
+			pods:              []testPod{{name: "lowest", size: 10, owned: true}, {name: "low", priority: 5, size: 60, owned: true}, {name: "other", size: 20}},
+			preemptorPriority: 100,
+			victims:           []string{},
+			expected:          []string{"low"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := newPreemption(test.pods...)
+			metaVictims := &schedulerapi.MetaVictims{NumPDBViolations: 1}
+			for _, victim := range test.victims {
+				metaVictims.Pods = append(metaVictims.Pods, &schedulerapi.MetaPod{UID: victim})
+			}
+			result := p.Handler(schedulerapi.ExtenderPreemptionArgs{
+				Pod:                   newPod("preemptor", test.preemptorPriority),
+				NodeNameToMetaVictims: map[string]*schedulerapi.MetaVictims{testNode: metaVictims},
+			})
+
+			victims, ok := result.NodeNameToMetaVictims[testNode]
+			if test.expected == nil {
+				if ok {
+					t.Fatalf("victims on node are %+v, expected node is removed", victims.Pods)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("node is removed, expected victims %v", test.expected)
+			}
+			var uids []string
+			for _, pod := range victims.Pods {
+				uids = append(uids, pod.UID)
+			}
+			if len(uids) != len(test.expected) {
+				t.Fatalf("victims are %v, expected %v", uids, test.expected)
+			}
+			for i := range uids {
+				if uids[i] != test.expected[i] {
+					t.Fatalf("victims are %v, expected %v", uids, test.expected)
+				}
+			}
+			if victims.NumPDBViolations != 1 {
+				t.Errorf("NumPDBViolations is %d, expected 1", victims.NumPDBViolations)
+			}
+		})
+	}
+}
+
+func TestPreemption_HandlerSkip(t *testing.T) {
+	p := newPreemption()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "preemptor", Namespace: "default"}}
+	victim := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "victim", Namespace: "default", UID: "victim"}}
+	result := p.Handler(schedulerapi.ExtenderPreemptionArgs{
+		Pod:               pod,
+		NodeNameToVictims: map[string]*schedulerapi.Victims{testNode: {Pods: []*corev1.Pod{victim}}},
+	})
+	victims, ok := result.NodeNameToMetaVictims[testNode]
+	if !ok || len(victims.Pods) != 1 || victims.Pods[0].UID != "victim" {
+		t.Errorf("victims of pod without local volumes are %+v, expected passed through", victims)
+	}
+}
+
+func TestPreemption_HandlerNodeNotInCache(t *testing.T) {
+	p := newPreemption(testPod{name: "low", size: 60, owned: true})
+	p.Ctx.CoreV1Informers.Nodes().Informer().GetIndexer().Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "othernode"}})
+	victim := newPod("victim", 0)
+	result := p.Handler(schedulerapi.ExtenderPreemptionArgs{
+		Pod:               newPod("preemptor", 100),
+		NodeNameToVictims: map[string]*schedulerapi.Victims{"othernode": {Pods: []*corev1.Pod{victim}, NumPDBViolations: 1}},
+	})
+	victims, ok := result.NodeNameToMetaVictims["othernode"]
+	if !ok || len(victims.Pods) != 1 || victims.Pods[0].UID != "victim" || victims.NumPDBViolations != 1 {
+		t.Errorf("victims on node without local storage are %+v, expected passed through", victims)
+	}
+}
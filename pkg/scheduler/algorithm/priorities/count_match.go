@@ -32,7 +32,7 @@ func CountMatch(ctx *algorithm.SchedulingContext, pod *corev1.Pod, node *corev1.
 	trace := utiltrace.New(fmt.Sprintf("Scheduling[CountMatch] %s/%s", pod.Namespace, pod.Name))
 	defer trace.LogIfLong(50 * time.Millisecond)
 	containReadonlySnapshot := false
-	err, _, mpPVCs, devicePVCs := algorithm.GetPodPvcs(pod, ctx, true, containReadonlySnapshot)
+	err, _, mpPVCs, devicePVCs, _ := algorithm.GetPodPvcs(pod, ctx, true, containReadonlySnapshot)
 	if err != nil {
 		return MinScore, err
 	}
@@ -64,6 +64,10 @@ func freeMountPoints(nc *cache.NodeCache) (int, error) {
 
 	freeMPs := make([]cache.ExclusiveResource, 0)
 	for _, mp := range nc.MountPoints {
+		// mount point shared by quota volumes is not free
+		if quota, ok := nc.Quotas[cache.ResourceName(mp.Name)]; ok && quota.Requested > 0 {
+			continue
+		}
 		if !mp.IsAllocated {
 			freeMPs = append(freeMPs, mp)
 		}
@@ -0,0 +1,59 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorities
+
+import (
+	"fmt"
+	"time"
+
+	utiltrace "k8s.io/utils/trace"
+
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/algo"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IOCapacityMatch prefers the nodes with more free io budget of VGs and devices for the io limits of pvcs
+func IOCapacityMatch(ctx *algorithm.SchedulingContext, pod *corev1.Pod, node *corev1.Node) (int, error) {
+	trace := utiltrace.New(fmt.Sprintf("Scheduling[IOCapacityMatch] %s/%s", pod.Namespace, pod.Name))
+	defer trace.LogIfLong(50 * time.Millisecond)
+	containReadonlySnapshot := false
+	err, lvmPVCs, _, devicePVCs, _ := algorithm.GetPodPvcs(pod, ctx, true, containReadonlySnapshot)
+	if err != nil {
+		return MinScore, err
+	}
+	if len(lvmPVCs) <= 0 && len(devicePVCs) <= 0 {
+		return MinScore, nil
+	}
+
+	trace.Step("Computing ScoreLVMVolume")
+	_, units, err := algo.ScoreLVMVolume(pod, lvmPVCs, node, ctx)
+	if err != nil {
+		return MinScore, err
+	}
+	trace.Step("Computing ScoreDeviceVolume")
+	_, deviceUnits, err := algo.ScoreDeviceVolume(pod, devicePVCs, node, ctx)
+	if err != nil {
+		return MinScore, err
+	}
+	units = append(units, deviceUnits...)
+
+	algo.SetIORequests(units, ctx)
+	ctx.CtxLock.RLock()
+	defer ctx.CtxLock.RUnlock()
+	return algo.ScoreIOCapacity(units, node, ctx), nil
+}
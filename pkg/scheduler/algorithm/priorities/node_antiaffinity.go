@@ -18,6 +18,8 @@ package priorities
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/alibaba/open-local/pkg"
@@ -27,12 +29,57 @@ import (
 	utiltrace "k8s.io/utils/trace"
 )
 
+// DefaultNodeAntiAffinityWeight is the weight of volume type without weight specified
+const DefaultNodeAntiAffinityWeight int = 5
+
+// ParseNodeAntiAffinityWeight parses weights of volume types, example format: 'MountPoint=5,LVM=3'
+func ParseNodeAntiAffinityWeight(s string) (weights *pkg.NodeAntiAffinityWeight, err error) {
+	weights = pkg.NewNodeAntiAffinityWeight()
+	if len(s) > 0 {
+		// example format
+		sec := strings.Split(s, ",")
+		for _, e := range sec {
+			var vt pkg.VolumeType
+			var weight int
+			if strings.Contains(e, "=") { // e is "<volumeType>=<weight>"
+				ex := strings.SplitN(e, "=", 2)
+				t := ex[0]
+				vt, err = pkg.VolumeTypeFromString(t)
+				if err != nil {
+					return nil, err
+				}
+				tmp, err := strconv.ParseInt(ex[1], 10, 32)
+				weight = int(tmp)
+				if err != nil {
+					return nil, err
+				}
+			} else { // e is "volumeType"
+				vt, err = pkg.VolumeTypeFromString(e)
+				if err != nil {
+					return
+				}
+				weight = DefaultNodeAntiAffinityWeight
+			}
+
+			//
+			if weight < MinScore || weight > MaxScore {
+				err = fmt.Errorf("weight is out-of-range [%d, %d], current value is %d", MinScore, MaxScore, weight)
+				log.Errorf(err.Error())
+				return nil, err
+			}
+			log.Infof("node anti-affinity for %v added, weight is %d", vt, weight)
+			weights.Put(vt, weight)
+		}
+	}
+	return
+}
+
 // NodeAntiAffinity picks the node whose amount of device/mount point best fulfill the amount of pvc requests
 func NodeAntiAffinity(ctx *algorithm.SchedulingContext, pod *corev1.Pod, node *corev1.Node) (int, error) {
 	trace := utiltrace.New(fmt.Sprintf("Scheduling[NodeAntiAffinity] %s/%s", pod.Namespace, pod.Name))
 	defer trace.LogIfLong(50 * time.Millisecond)
 	containReadonlySnapshot := false
-	err, _, mpPVCs, devicePVCs := algorithm.GetPodPvcs(pod, ctx, true, containReadonlySnapshot)
+	err, _, mpPVCs, devicePVCs, _ := algorithm.GetPodPvcs(pod, ctx, true, containReadonlySnapshot)
 	if err != nil {
 		return MinScore, err
 	}
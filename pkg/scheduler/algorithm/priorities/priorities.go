@@ -31,6 +31,7 @@ var (
 		CapacityMatch,
 		CountMatch,
 		NodeAntiAffinity,
+		IOCapacityMatch,
 	}
 )
 
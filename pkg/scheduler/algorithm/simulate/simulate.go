@@ -0,0 +1,314 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/algo"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/predicates"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/priorities"
+	"github.com/alibaba/open-local/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	clientgocache "k8s.io/client-go/tools/cache"
+	schedulerapi "k8s.io/kube-scheduler/extender/v1"
+)
+
+// Args is the pods and pvcs to be scheduled in simulation
+type Args struct {
+	Pods                   []corev1.Pod                   `json:"pods"`
+	PersistentVolumeClaims []corev1.PersistentVolumeClaim `json:"persistentVolumeClaims,omitempty"`
+	// NodeNames are the nodes the pods can be scheduled to, all nodes if empty
+	NodeNames []string `json:"nodeNames,omitempty"`
+}
+
+// Result is the scheduling result of each pod in the order of Args.Pods
+type Result struct {
+	Pods  []PodResult `json:"pods"`
+	Error string      `json:"error,omitempty"`
+}
+
+// PodResult is the node pod would be scheduled to and the storage allocated for its pvcs
+type PodResult struct {
+	Pod    string        `json:"pod"`
+	Node   string        `json:"node,omitempty"`
+	Score  int64         `json:"score,omitempty"`
+	Claims []ClaimResult `json:"claims,omitempty"`
+	// FailedNodes records why pod does not fit each node
+	FailedNodes map[string]string `json:"failedNodes,omitempty"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// ClaimResult is the storage allocated for a pvc
+type ClaimResult struct {
+	PersistentVolumeClaim string               `json:"persistentVolumeClaim"`
+	VolumeType            localtype.VolumeType `json:"volumeType"`
+	Requested             int64                `json:"requested"`
+	Allocated             int64                `json:"allocated"`
+	VgName                string               `json:"vgName,omitempty"`
+	Thin                  bool                 `json:"thin,omitempty"`
+	Device                string               `json:"device,omitempty"`
+	MountPoint            string               `json:"mountPoint,omitempty"`
+}
+
+// Simulate schedules pods one by one with the predicates and priorities of extender against a copy of
+// the cluster cache, the storage allocated for a pod is reserved in the copy for the pods after it.
+// The cluster cache and the informers are not changed.
+type Simulate struct {
+	Ctx             *algorithm.SchedulingContext
+	PredicateFuncs  []predicates.PredicateFunc
+	PrioritizeFuncs []priorities.PrioritizeFunc
+}
+
+func NewSimulate(ctx *algorithm.SchedulingContext) *Simulate {
+	if ctx == nil {
+		panic("scheduling context must not be nil")
+	}
+	return &Simulate{
+		Ctx:             ctx,
+		PredicateFuncs:  predicates.DefaultPredicateFuncs,
+		PrioritizeFuncs: priorities.DefaultPrioritizeFuncs,
+	}
+}
+
+func (s Simulate) Handler(args Args) *Result {
+	nodes, err := s.getNodes(args.NodeNames)
+	if err != nil {
+		return &Result{Error: err.Error()}
+	}
+	claims, err := newClaimIndexer(s.Ctx.CoreV1Informers.PersistentVolumeClaims().Lister(), args.PersistentVolumeClaims)
+	if err != nil {
+		return &Result{Error: err.Error()}
+	}
+
+	s.Ctx.CtxLock.RLock()
+	clusterNodeCache := s.Ctx.ClusterNodeCache.Clone()
+	s.Ctx.CtxLock.RUnlock()
+	ctx := &algorithm.SchedulingContext{
+		ClusterNodeCache:       clusterNodeCache,
+		CoreV1Informers:        claimInformers{Interface: s.Ctx.CoreV1Informers, indexer: claims},
+		StorageV1Informers:     s.Ctx.StorageV1Informers,
+		SnapshotInformers:      s.Ctx.SnapshotInformers,
+		LocalStorageInformer:   s.Ctx.LocalStorageInformer,
+		NodeAntiAffinityWeight: s.Ctx.NodeAntiAffinityWeight,
+		ReservationTTL:         s.Ctx.ReservationTTL,
+	}
+
+	result := &Result{Pods: make([]PodResult, 0, len(args.Pods))}
+	for i := range args.Pods {
+		pod := args.Pods[i].DeepCopy()
+		if pod.Namespace == "" {
+			pod.Namespace = corev1.NamespaceDefault
+		}
+		if pod.UID == "" {
+			pod.UID = types.UID("simulated-" + utils.PodName(pod))
+		}
+		podResult := s.schedule(ctx, claims, pod, nodes)
+		log.Infof("simulated pod %s: node %q, error %q", podResult.Pod, podResult.Node, podResult.Error)
+		result.Pods = append(result.Pods, podResult)
+	}
+	return result
+}
+
+// getNodes returns the nodes of nodeNames sorted by name, or all the nodes if nodeNames is empty
+func (s Simulate) getNodes(nodeNames []string) ([]*corev1.Node, error) {
+	var nodes []*corev1.Node
+	if len(nodeNames) == 0 {
+		all, err := s.Ctx.CoreV1Informers.Nodes().Lister().List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		nodes = all
+	} else {
+		for _, name := range nodeNames {
+			node, err := s.Ctx.CoreV1Informers.Nodes().Lister().Get(name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get node %s: %s", name, err.Error())
+			}
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	return nodes, nil
+}
+
+// schedule picks the node of the highest score among the nodes pod fits, and reserves the storage of pod on it
+func (s Simulate) schedule(ctx *algorithm.SchedulingContext, claims clientgocache.Indexer, pod *corev1.Pod, nodes []*corev1.Node) PodResult {
+	result := PodResult{Pod: utils.PodName(pod), FailedNodes: make(map[string]string)}
+
+	fitNodes := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		fits, failReasons, err := predicates.Predicates(ctx, s.PredicateFuncs, pod, node)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		if fits {
+			fitNodes = append(fitNodes, node.Name)
+		} else {
+			result.FailedNodes[node.Name] = strings.Join(failReasons, ",")
+		}
+	}
+	if len(fitNodes) == 0 {
+		result.Error = fmt.Sprintf("0/%d nodes are available", len(nodes))
+		return result
+	}
+
+	prioritize := priorities.Prioritize{Name: "open-local-simulate", Ctx: ctx, PrioritizeFuncs: s.PrioritizeFuncs}
+	hostPriorityList, err := prioritize.Handler(schedulerapi.ExtenderArgs{Pod: pod, NodeNames: &fitNodes})
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	best := (*hostPriorityList)[0]
+	for _, host := range *hostPriorityList {
+		if host.Score > best.Score {
+			best = host
+		}
+	}
+	result.Node, result.Score = best.Host, best.Score
+
+	var node *corev1.Node
+	for _, n := range nodes {
+		if n.Name == best.Host {
+			node = n
+		}
+	}
+	units, err := s.reserve(ctx, pod, node)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to allocate local storage on node %s: %s", node.Name, err.Error())
+		return result
+	}
+	for _, unit := range units {
+		result.Claims = append(result.Claims, ClaimResult{
+			PersistentVolumeClaim: unit.PVCName,
+			VolumeType:            unit.VolumeType,
+			Requested:             unit.Requested,
+			Allocated:             unit.Allocated,
+			VgName:                unit.VgName,
+			Thin:                  unit.Thin,
+			Device:                unit.Device,
+			MountPoint:            unit.MountPoint,
+		})
+		if err := bindClaim(claims, unit.PVCName, node.Name); err != nil {
+			result.Error = err.Error()
+			return result
+		}
+	}
+	return result
+}
+
+// reserve allocates the storage of pod on node in the cache of ctx, including its inline volumes
+func (s Simulate) reserve(ctx *algorithm.SchedulingContext, pod *corev1.Pod, node *corev1.Node) ([]cache.AllocatedUnit, error) {
+	containReadonlySnapshot := false
+	err, lvmPVCs, mpPVCs, devicePVCs, quotaPVCs := algorithm.GetPodPvcs(pod, ctx, true, containReadonlySnapshot)
+	if err != nil {
+		return nil, err
+	}
+	units, err := algo.PlanVolumes(pod, lvmPVCs, mpPVCs, devicePVCs, quotaPVCs, node, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.ClusterNodeCache.Reserve(string(pod.UID), utils.PodName(pod), units, ctx.ReservationTTL); err != nil {
+		return nil, err
+	}
+
+	if contain, _ := utils.ContainInlineVolumes(pod); contain {
+		nodeCache := ctx.ClusterNodeCache.GetNodeCache(node.Name)
+		if nodeCache == nil {
+			return nil, fmt.Errorf("node %s not found from cache", node.Name)
+		}
+		scheduled := pod.DeepCopy()
+		scheduled.Spec.NodeName = node.Name
+		scheduled.Status.Phase = corev1.PodRunning
+		if err := nodeCache.AddPodInlineVolumeInfo(scheduled); err != nil {
+			return nil, err
+		}
+	}
+	return units, nil
+}
+
+// newClaimIndexer returns the indexer of the pvcs in cluster, overridden by the pvcs in simulation
+func newClaimIndexer(lister corev1listers.PersistentVolumeClaimLister, simulated []corev1.PersistentVolumeClaim) (clientgocache.Indexer, error) {
+	indexer := clientgocache.NewIndexer(clientgocache.MetaNamespaceKeyFunc, clientgocache.Indexers{clientgocache.NamespaceIndex: clientgocache.MetaNamespaceIndexFunc})
+	pvcs, err := lister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, pvc := range pvcs {
+		if err := indexer.Add(pvc); err != nil {
+			return nil, err
+		}
+	}
+	for i := range simulated {
+		pvc := simulated[i].DeepCopy()
+		if pvc.Namespace == "" {
+			pvc.Namespace = corev1.NamespaceDefault
+		}
+		pvc.Status.Phase = corev1.ClaimPending
+		if err := indexer.Update(pvc); err != nil {
+			return nil, err
+		}
+	}
+	return indexer, nil
+}
+
+// bindClaim marks the pvc bound on node in indexer, so that it is skipped by the pods after
+func bindClaim(indexer clientgocache.Indexer, pvcName, nodeName string) error {
+	obj, exists, err := indexer.GetByKey(pvcName)
+	if err != nil || !exists {
+		return fmt.Errorf("failed to get pvc %s: %v", pvcName, err)
+	}
+	pvc := obj.(*corev1.PersistentVolumeClaim).DeepCopy()
+	if pvc.Annotations == nil {
+		pvc.Annotations = make(map[string]string)
+	}
+	pvc.Annotations[localtype.AnnoSelectedNode] = nodeName
+	pvc.Status.Phase = corev1.ClaimBound
+	return indexer.Update(pvc)
+}
+
+// claimInformers serves the pvcs from indexer instead of the informer of cluster
+type claimInformers struct {
+	corev1informers.Interface
+	indexer clientgocache.Indexer
+}
+
+func (i claimInformers) PersistentVolumeClaims() corev1informers.PersistentVolumeClaimInformer {
+	return claimInformer{PersistentVolumeClaimInformer: i.Interface.PersistentVolumeClaims(), indexer: i.indexer}
+}
+
+type claimInformer struct {
+	corev1informers.PersistentVolumeClaimInformer
+	indexer clientgocache.Indexer
+}
+
+func (i claimInformer) Lister() corev1listers.PersistentVolumeClaimLister {
+	return corev1listers.NewPersistentVolumeClaimLister(i.indexer)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulate
+
+import (
+	"testing"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func newPVC(name, size string) corev1.PersistentVolumeClaim {
+	storageClass := "open-local-lvm"
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+			},
+		},
+	}
+}
+
+func newPod(name string, pvcNames ...string) corev1.Pod {
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	for _, pvcName := range pvcNames {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name:         pvcName,
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvcName}},
+		})
+	}
+	return pod
+}
+
+func TestSimulate(t *testing.T) {
+	factory := informers.NewSharedInformerFactory(k8sfake.NewSimpleClientset(), 0)
+	factory.Storage().V1().StorageClasses().Informer().GetIndexer().Add(&storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "open-local-lvm"},
+		Provisioner: localtype.ProvisionerName,
+		Parameters:  map[string]string{localtype.VolumeTypeKey: string(localtype.VolumeTypeLVM)},
+	})
+	ctx := &algorithm.SchedulingContext{
+		ClusterNodeCache:       cache.NewClusterNodeCache(),
+		CoreV1Informers:        factory.Core().V1(),
+		StorageV1Informers:     factory.Storage().V1(),
+		NodeAntiAffinityWeight: localtype.NewNodeAntiAffinityWeight(),
+		ReservationTTL:         localtype.DefaultReservationTTL,
+	}
+	for _, name := range []string{"node-1", "node-2"} {
+		factory.Core().V1().Nodes().Informer().GetIndexer().Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		nc := cache.NewNodeCache(name)
+		nc.VGs["share"] = cache.SharedResource{Name: "share", Capacity: 100 << 30}
+		ctx.ClusterNodeCache.SetNodeCache(nc)
+	}
+
+	args := Args{
+		Pods: []corev1.Pod{
+			newPod("db-0", "data-db-0"),
+			newPod("db-1", "data-db-1"),
+			newPod("db-2", "data-db-2"),
+			newPod("web"),
+		},
+		PersistentVolumeClaims: []corev1.PersistentVolumeClaim{
+			newPVC("data-db-0", "80Gi"),
+			newPVC("data-db-1", "80Gi"),
+			newPVC("data-db-2", "80Gi"),
+		},
+	}
+	result := NewSimulate(ctx).Handler(args)
+	if result.Error != "" || len(result.Pods) != len(args.Pods) {
+		t.Fatalf("Handler() = %+v", result)
+	}
+
+	// the storage of db-0 is reserved, so db-1 goes to the other node and db-2 fits no node
+	nodes := map[string]bool{}
+	for _, pod := range result.Pods[:2] {
+		if pod.Error != "" || len(pod.Claims) != 1 || pod.Claims[0].VgName != "share" {
+			t.Fatalf("pod %s is not scheduled: %+v", pod.Pod, pod)
+		}
+		nodes[pod.Node] = true
+	}
+	if len(nodes) != 2 {
+		t.Errorf("db-0 and db-1 are scheduled to the same node: %+v", result.Pods[:2])
+	}
+	if db2 := result.Pods[2]; db2.Error == "" || db2.Node != "" || len(db2.FailedNodes) != 2 {
+		t.Errorf("db-2 should fit no node: %+v", db2)
+	}
+	if web := result.Pods[3]; web.Error != "" || web.Node == "" || len(web.Claims) != 0 {
+		t.Errorf("web without local storage should be scheduled: %+v", web)
+	}
+
+	// the cache and the informers are not changed
+	for _, name := range []string{"node-1", "node-2"} {
+		if vg := ctx.ClusterNodeCache.GetNodeCache(name).VGs["share"]; vg.Requested != 0 {
+			t.Errorf("vg of %s is requested %d after simulation", name, vg.Requested)
+		}
+	}
+	if len(ctx.ClusterNodeCache.BindingInfo) != 0 || len(ctx.ClusterNodeCache.Reservations) != 0 {
+		t.Errorf("binding info or reservations of cache are changed by simulation")
+	}
+	if pvcs, _ := factory.Core().V1().PersistentVolumeClaims().Lister().List(labels.Everything()); len(pvcs) != 0 {
+		t.Errorf("pvcs of simulation are added into informer")
+	}
+
+	// nodes are limited by NodeNames
+	result = NewSimulate(ctx).Handler(Args{Pods: args.Pods[:2], PersistentVolumeClaims: args.PersistentVolumeClaims, NodeNames: []string{"node-2"}})
+	if result.Pods[0].Node != "node-2" || result.Pods[1].Error == "" {
+		t.Errorf("pods should be scheduled to node-2 only: %+v", result.Pods)
+	}
+	if result = NewSimulate(ctx).Handler(Args{Pods: args.Pods[:1], NodeNames: []string{"node-3"}}); result.Error == "" {
+		t.Errorf("expected error of unknown node")
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/alibaba/open-local/pkg"
 	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
 	"github.com/alibaba/open-local/pkg/utils"
+	"github.com/alibaba/open-local/pkg/utils/cgroup"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -35,7 +36,8 @@ func GetPodPvcs(pod *corev1.Pod, ctx *SchedulingContext, skipBound bool, contain
 	err error,
 	lvmPVCs []*corev1.PersistentVolumeClaim,
 	mpPVCs []*corev1.PersistentVolumeClaim,
-	devicePVCs []*corev1.PersistentVolumeClaim) {
+	devicePVCs []*corev1.PersistentVolumeClaim,
+	quotaPVCs []*corev1.PersistentVolumeClaim) {
 
 	ns := pod.Namespace
 	for _, v := range pod.Spec.Volumes {
@@ -44,7 +46,7 @@ func GetPodPvcs(pod *corev1.Pod, ctx *SchedulingContext, skipBound bool, contain
 			pvc, err := ctx.CoreV1Informers.PersistentVolumeClaims().Lister().PersistentVolumeClaims(ns).Get(name)
 			if err != nil {
 				log.Errorf("failed to get pvc by name %s/%s: %s", ns, name, err.Error())
-				return err, lvmPVCs, mpPVCs, devicePVCs
+				return err, lvmPVCs, mpPVCs, devicePVCs, quotaPVCs
 			}
 			if pvc.Status.Phase == corev1.ClaimBound && skipBound {
 				log.Infof("skip scheduling bound pvc %s/%s", pvc.Namespace, pvc.Name)
@@ -57,11 +59,12 @@ func GetPodPvcs(pod *corev1.Pod, ctx *SchedulingContext, skipBound bool, contain
 			_, err = ctx.StorageV1Informers.StorageClasses().Lister().Get(*scName)
 			if err != nil {
 				log.Errorf("failed to get storage class by name %s: %s", *scName, err.Error())
-				return err, lvmPVCs, mpPVCs, devicePVCs
+				return err, lvmPVCs, mpPVCs, devicePVCs, quotaPVCs
 			}
 			var isLocalPV bool
 			var pvType pkg.VolumeType
-			if isLocalPV, pvType = utils.IsLocalPVC(pvc, ctx.StorageV1Informers, containReadonlySnapshot); isLocalPV {
+			// pvc restored from backup is provisioned like a new volume
+			if isLocalPV, pvType = utils.IsLocalPVC(pvc, ctx.StorageV1Informers, containReadonlySnapshot || IsRestorePVC(pvc, ctx)); isLocalPV {
 				switch pvType {
 				case pkg.VolumeTypeLVM:
 					log.Infof("got pvc %s/%s as lvm pvc", pvc.Namespace, pvc.Name)
@@ -72,6 +75,9 @@ func GetPodPvcs(pod *corev1.Pod, ctx *SchedulingContext, skipBound bool, contain
 				case pkg.VolumeTypeDevice:
 					log.Infof("got pvc %s/%s as device pvc", pvc.Namespace, pvc.Name)
 					devicePVCs = append(devicePVCs, pvc)
+				case pkg.VolumeTypeQuota:
+					log.Infof("got pvc %s/%s as quota pvc", pvc.Namespace, pvc.Name)
+					quotaPVCs = append(quotaPVCs, pvc)
 				default:
 					log.Infof("not a open-local pvc %s/%s, should handled by other provisioner", pvc.Namespace, pvc.Name)
 				}
@@ -81,27 +87,57 @@ func GetPodPvcs(pod *corev1.Pod, ctx *SchedulingContext, skipBound bool, contain
 	return
 }
 
+// IsRestorePVC checks whether pvc is restored from a backed up snapshot, which can be
+// provisioned on any node instead of the node of snapshot
+func IsRestorePVC(pvc *corev1.PersistentVolumeClaim, ctx *SchedulingContext) bool {
+	if !utils.IsSnapshotPVC(pvc) || ctx.SnapshotInformers == nil {
+		return false
+	}
+	snapshot, err := ctx.SnapshotInformers.VolumeSnapshots().Lister().VolumeSnapshots(pvc.Namespace).Get(pvc.Spec.DataSource.Name)
+	if err != nil || snapshot.Status == nil || snapshot.Status.BoundVolumeSnapshotContentName == nil {
+		return false
+	}
+	content, err := ctx.SnapshotInformers.VolumeSnapshotContents().Lister().Get(*snapshot.Status.BoundVolumeSnapshotContentName)
+	if err != nil {
+		return false
+	}
+	if _, ok := content.Annotations[pkg.AnnBackupLocation]; !ok || content.Spec.VolumeSnapshotClassName == nil {
+		return false
+	}
+	// readonly snapshot is always mounted on the node of snapshot
+	class, err := ctx.SnapshotInformers.VolumeSnapshotClasses().Lister().Get(*content.Spec.VolumeSnapshotClassName)
+	return err == nil && class.Parameters[pkg.ParamSnapshotReadonly] != "true"
+}
+
 func GetPodUnboundPvcs(pvc *corev1.PersistentVolumeClaim, ctx *SchedulingContext, containReadonlySnapshot bool) (
 	err error,
 	lvmPVCs []*corev1.PersistentVolumeClaim,
 	mpPVCs []*corev1.PersistentVolumeClaim,
-	devicePVCs []*corev1.PersistentVolumeClaim) {
-	pvcName := utils.PVCName(pvc)
+	devicePVCs []*corev1.PersistentVolumeClaim,
+	quotaPVCs []*corev1.PersistentVolumeClaim) {
+	pod, err := GetPodOfPVC(utils.PVCName(pvc), ctx)
+	if err != nil {
+		return err, lvmPVCs, mpPVCs, devicePVCs, quotaPVCs
+	}
+	return GetPodPvcs(pod, ctx, true, containReadonlySnapshot)
+}
+
+// GetPodOfPVC returns the pod associated with pvc in PvcPod mapping
+func GetPodOfPVC(pvcName string, ctx *SchedulingContext) (*corev1.Pod, error) {
 	podName := ctx.ClusterNodeCache.PvcMapping.PvcPod[pvcName]
 	if podName == "" {
-		return fmt.Errorf("pod associated with pvc %s is not yet in PvcPod mapping", pvcName), lvmPVCs, mpPVCs, devicePVCs
+		return nil, fmt.Errorf("pod associated with pvc %s is not yet in PvcPod mapping", pvcName)
 	}
-	var pod *corev1.Pod
-	pod, err = ctx.CoreV1Informers.Pods().Lister().Pods(strings.Split(podName, "/")[0]).Get(strings.Split(podName, "/")[1])
+	pod, err := ctx.CoreV1Informers.Pods().Lister().Pods(strings.Split(podName, "/")[0]).Get(strings.Split(podName, "/")[1])
 	if err != nil {
 		log.Errorf("failed to get pod by name %s: %s", podName, err.Error())
-		return
+		return nil, err
 	}
-	return GetPodPvcs(pod, ctx, true, containReadonlySnapshot)
+	return pod, nil
 }
 
 func GetAllPodPvcs(pod *corev1.Pod, ctx *SchedulingContext, containReadonlySnapshot bool) ([]*corev1.PersistentVolumeClaim, error) {
-	err, pvc1, pvc2, pvc3 := GetPodPvcs(pod, ctx, false, containReadonlySnapshot)
+	err, pvc1, pvc2, pvc3, pvc4 := GetPodPvcs(pod, ctx, false, containReadonlySnapshot)
 	if err != nil {
 		log.Errorf("failed to get pod pvcs: %s", err.Error())
 		return nil, err
@@ -110,6 +146,7 @@ func GetAllPodPvcs(pod *corev1.Pod, ctx *SchedulingContext, containReadonlySnaps
 	pvcs = append(pvcs, pvc1...)
 	pvcs = append(pvcs, pvc2...)
 	pvcs = append(pvcs, pvc3...)
+	pvcs = append(pvcs, pvc4...)
 	return pvcs, err
 }
 
@@ -147,6 +184,16 @@ func ConvertAUFromPV(pv *corev1.PersistentVolume, scInformer storagev1informers.
 	vgName := utils.GetVGNameFromCsiPV(pv)
 	device := utils.GetDeviceNameFromCsiPV(pv)
 	mountPoint := utils.GetMountPointFromCsiPV(pv)
+	var annotations map[string]string
+	if claimRef := pv.Spec.ClaimRef; claimRef != nil {
+		if pvc, err := coreInformer.PersistentVolumeClaims().Lister().PersistentVolumeClaims(claimRef.Namespace).Get(claimRef.Name); err == nil {
+			annotations = pvc.Annotations
+		}
+	}
+	var attributes map[string]string
+	if pv.Spec.CSI != nil {
+		attributes = pv.Spec.CSI.VolumeAttributes
+	}
 	return &cache.AllocatedUnit{
 		NodeName: nodeName,
 		// currently we do not care abort the volume type of au
@@ -157,5 +204,33 @@ func ConvertAUFromPV(pv *corev1.PersistentVolume, scInformer storagev1informers.
 		Device:     device,
 		MountPoint: mountPoint,
 		PVCName:    utils.PVCName(pv),
+		IO:         GetIORequest(attributes, annotations),
 	}, nil
 }
+
+// GetPVCIORequest returns the io limits of pvc, which are set in the parameters of
+// storage class and can be overridden by the annotations of pvc
+func GetPVCIORequest(pvc *corev1.PersistentVolumeClaim, ctx *SchedulingContext) cache.IORequest {
+	var parameters map[string]string
+	if sc := utils.GetStorageClassFromPVC(pvc, ctx.StorageV1Informers); sc != nil {
+		parameters = sc.Parameters
+	}
+	return GetIORequest(parameters, pvc.Annotations)
+}
+
+// GetIORequest returns the io limits in parameters overridden by annotations, invalid values are ignored
+func GetIORequest(parameters, annotations map[string]string) cache.IORequest {
+	limits := cgroup.Limits{}
+	if err := cgroup.ParseLimits(parameters, cgroup.VolumeLimitKeys, &limits); err != nil {
+		log.Warningf("ignore io limits in parameters: %s", err.Error())
+	}
+	if err := cgroup.ParseLimits(annotations, cgroup.AnnotationLimitKeys, &limits); err != nil {
+		log.Warningf("ignore io limits in annotations: %s", err.Error())
+	}
+	return cache.IORequest{
+		ReadIOPS:  limits.ReadIOPS,
+		WriteIOPS: limits.WriteIOPS,
+		ReadBPS:   limits.ReadBPS,
+		WriteBPS:  limits.WriteBPS,
+	}
+}
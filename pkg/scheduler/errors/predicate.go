@@ -79,6 +79,52 @@ func NewNoAvailableVGError(nodeName string) *NoAvailableVGError {
 	}
 }
 
+// NoPerformanceTierError means there is no vg or device of performance tier on `nodeName`
+type NoPerformanceTierError struct {
+	resource pkg.VolumeType
+	tier     string
+	nodeName string
+}
+
+func (e *NoPerformanceTierError) GetReason() string {
+	return fmt.Sprintf("no %s storage of performance tier %s on node %s. you can run command \"kubectl get nls %s -o jsonpath={.status.performanceProfiles}\" to get more details", e.resource, e.tier, e.nodeName, e.nodeName)
+}
+
+func (e *NoPerformanceTierError) Error() string {
+	return fmt.Sprintf("no %s storage of performance tier %s on node %s", e.resource, e.tier, e.nodeName)
+}
+
+func NewNoPerformanceTierError(resource pkg.VolumeType, tier string, nodeName string) *NoPerformanceTierError {
+	return &NoPerformanceTierError{
+		resource: resource,
+		tier:     tier,
+		nodeName: nodeName,
+	}
+}
+
+// StorageFaultError means the vg, device or mount point on `nodeName` is on faulty disks
+type StorageFaultError struct {
+	resource pkg.VolumeType
+	name     string
+	nodeName string
+}
+
+func (e *StorageFaultError) GetReason() string {
+	return fmt.Sprintf("%s storage %s on node %s is on faulty disks. you can run command \"kubectl get nls %s -o jsonpath={.status.nodeStorageInfo.state}\" to get more details", e.resource, e.name, e.nodeName, e.nodeName)
+}
+
+func (e *StorageFaultError) Error() string {
+	return fmt.Sprintf("%s storage %s on node %s is on faulty disks", e.resource, e.name, e.nodeName)
+}
+
+func NewStorageFaultError(resource pkg.VolumeType, name string, nodeName string) *StorageFaultError {
+	return &StorageFaultError{
+		resource: resource,
+		name:     name,
+		nodeName: nodeName,
+	}
+}
+
 type InsufficientLVMError struct {
 	requested int64
 	used      int64
@@ -201,3 +247,35 @@ func NewInsufficientExclusiveResourceError(resource pkg.VolumeType, requested, m
 		maxSize:       max,
 	}
 }
+
+// InsufficientIOError means the io budget of vg or device is not enough for the io limits of pvcs
+type InsufficientIOError struct {
+	item      string
+	requested uint64
+	used      uint64
+	capacity  uint64
+	name      string
+	nodeName  string
+	resource  pkg.VolumeType
+}
+
+func (e *InsufficientIOError) GetReason() string {
+	return fmt.Sprintf("Insufficient %s io capacity on node %s, %s is %s, pvc requested %s %d, used %d, capacity %d",
+		e.resource, e.nodeName, e.resource, e.name, e.item, e.requested, e.used, e.capacity)
+}
+
+func (e *InsufficientIOError) Error() string {
+	return e.GetReason()
+}
+
+func NewInsufficientIOError(resource pkg.VolumeType, item string, requested, used, capacity uint64, name string, nodeName string) *InsufficientIOError {
+	return &InsufficientIOError{
+		resource:  resource,
+		item:      item,
+		requested: requested,
+		used:      used,
+		capacity:  capacity,
+		name:      name,
+		nodeName:  nodeName,
+	}
+}
@@ -0,0 +1,346 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	clientset "github.com/alibaba/open-local/pkg/generated/clientset/versioned"
+	informers "github.com/alibaba/open-local/pkg/generated/informers/externalversions"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/algo"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/predicates"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/priorities"
+	"github.com/alibaba/open-local/pkg/scheduler/server"
+	"github.com/alibaba/open-local/pkg/utils"
+	volumesnapshot "github.com/kubernetes-csi/external-snapshotter/client/v4/clientset/versioned"
+	volumesnapshotinformers "github.com/kubernetes-csi/external-snapshotter/client/v4/informers/externalversions"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	schedulerapi "k8s.io/kube-scheduler/extender/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+)
+
+const (
+	// Name is the name of the plugin used in plugin registry and scheduler configuration
+	Name = "Open-Local"
+
+	reservationStateKey = Name + "/reservation"
+)
+
+// Args is the arguments of the plugin in KubeSchedulerConfiguration,
+// which are the same as the flags of scheduler extender
+type Args struct {
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	Master     string `json:"master,omitempty"`
+	// Port for receiving the callback of provisioner, set to 0 to disable http server
+	Port                    int32  `json:"port,omitempty"`
+	EnabledNodeAntiAffinity string `json:"enabledNodeAntiAffinity,omitempty"`
+	Strategy                string `json:"strategy,omitempty"`
+	// ReservationTTL is the time local storage is reserved for a pod before its pvs are created
+	ReservationTTL metav1.Duration `json:"reservationTTL,omitempty"`
+}
+
+// OpenLocal runs the predicates and priorities of open-local scheduler extender in the scheduling
+// framework, and reserves local storage in Reserve, so that the volumes are provisioned in the
+// reserved VG, mount point or device when provisioner calls back to the scheduler
+type OpenLocal struct {
+	ctx             *algorithm.SchedulingContext
+	kubeClient      kubernetes.Interface
+	predicateFuncs  []predicates.PredicateFunc
+	prioritizeFuncs []priorities.PrioritizeFunc
+	hasSynced       func() bool
+}
+
+var _ framework.FilterPlugin = &OpenLocal{}
+var _ framework.ScorePlugin = &OpenLocal{}
+var _ framework.ReservePlugin = &OpenLocal{}
+var _ framework.PreBindPlugin = &OpenLocal{}
+
+var (
+	sharedOnce   sync.Once
+	sharedPlugin *OpenLocal
+	sharedErr    error
+)
+
+// New initializes the plugin, it is registered by the out-of-tree scheduler, e.g.
+// app.NewSchedulerCommand(app.WithPlugin(plugin.Name, plugin.New)).
+// The cache is shared by all scheduler profiles, and the args of the first profile are used.
+func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	sharedOnce.Do(func() {
+		sharedPlugin, sharedErr = newOpenLocal(obj, handle)
+	})
+	return sharedPlugin, sharedErr
+}
+
+func newOpenLocal(obj runtime.Object, handle framework.Handle) (*OpenLocal, error) {
+	args := &Args{
+		Port:           localtype.DefaultPort,
+		Strategy:       string(localtype.StrategyBinpack),
+		ReservationTTL: metav1.Duration{Duration: localtype.DefaultReservationTTL},
+	}
+	if err := frameworkruntime.DecodeInto(obj, args); err != nil {
+		return nil, fmt.Errorf("error decoding args of %s: %s", Name, err.Error())
+	}
+	weights, err := priorities.ParseNodeAntiAffinityWeight(args.EnabledNodeAntiAffinity)
+	if err != nil {
+		return nil, err
+	}
+	strategy, err := algo.ParseStrategy(args.Strategy)
+	if err != nil {
+		return nil, err
+	}
+	localtype.SchedulerStrategy = strategy
+
+	cfg, err := clientcmd.BuildConfigFromFlags(args.Master, args.Kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("error building kubeconfig: %s", err.Error())
+	}
+	localClient, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error building open-local clientset: %s", err.Error())
+	}
+	snapClient, err := volumesnapshot.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error building snapshot clientset: %s", err.Error())
+	}
+	localStorageInformerFactory := informers.NewSharedInformerFactory(localClient, 0)
+	snapshotInformerFactory := volumesnapshotinformers.NewSharedInformerFactory(snapClient, 0)
+
+	// the informers of kubernetes resources are shared with scheduler, and started by scheduler
+	extenderServer := server.NewExtenderServer(handle.ClientSet(), localClient, snapClient, handle.SharedInformerFactory(),
+		localStorageInformerFactory, snapshotInformerFactory, args.Port, weights, args.ReservationTTL.Duration)
+	localStorageInformerFactory.Start(wait.NeverStop)
+	snapshotInformerFactory.Start(wait.NeverStop)
+	// the http server is still required for the callback of provisioner
+	go extenderServer.Start(wait.NeverStop)
+	log.Infof("started %s scheduler plugin", Name)
+
+	return &OpenLocal{
+		ctx:             extenderServer.Ctx,
+		kubeClient:      handle.ClientSet(),
+		predicateFuncs:  predicates.DefaultPredicateFuncs,
+		prioritizeFuncs: priorities.DefaultPrioritizeFuncs,
+		hasSynced:       extenderServer.HasSynced,
+	}, nil
+}
+
+// Name returns name of the plugin
+func (pl *OpenLocal) Name() string {
+	return Name
+}
+
+// Filter filters out nodes without enough local storage for the pod
+func (pl *OpenLocal) Filter(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	if utils.NeedSkip(schedulerapi.ExtenderArgs{Pod: pod}) {
+		return nil
+	}
+	if !pl.hasSynced() {
+		return framework.NewStatus(framework.Error, "open-local cache is not synced yet")
+	}
+	node := nodeInfo.Node()
+	if node == nil {
+		return framework.NewStatus(framework.Error, "node not found")
+	}
+	pl.ctx.CtxLock.RLock()
+	defer pl.ctx.CtxLock.RUnlock()
+
+	fits, failReasons, err := predicates.Predicates(pl.ctx, pl.predicateFuncs, pod, node)
+	if err != nil {
+		log.Errorf("failed to filter node %s for pod %s/%s: %s", node.Name, pod.Namespace, pod.Name, err.Error())
+		return framework.NewStatus(framework.Error, err.Error())
+	}
+	if !fits {
+		log.Infof("node %s is not suitable for pod %s/%s, reason: %s", node.Name, pod.Namespace, pod.Name, failReasons)
+		return framework.NewStatus(framework.Unschedulable, failReasons...)
+	}
+	return nil
+}
+
+// Score sums the scores of priorities, and scales it to the range of framework
+func (pl *OpenLocal) Score(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeName string) (int64, *framework.Status) {
+	if len(pl.ctx.NodeAntiAffinityWeight.Items(false)) == 0 && utils.NeedSkip(schedulerapi.ExtenderArgs{Pod: pod}) {
+		return framework.MinNodeScore, nil
+	}
+	node, err := pl.ctx.CoreV1Informers.Nodes().Lister().Get(nodeName)
+	if err != nil {
+		return framework.MinNodeScore, framework.NewStatus(framework.Error, fmt.Sprintf("failed to get node %s: %s", nodeName, err.Error()))
+	}
+	pl.ctx.CtxLock.RLock()
+	defer pl.ctx.CtxLock.RUnlock()
+
+	score := 0
+	for _, pri := range pl.prioritizeFuncs {
+		s, err := pri(pl.ctx, pod, node)
+		if err != nil {
+			log.Errorf("error when prioritize pod %s, for node %s: %s", pod.Name, node.Name, err.Error())
+			continue
+		}
+		score += s
+	}
+	return scaleScore(score, len(pl.prioritizeFuncs)), nil
+}
+
+// scaleScore scales the sum of priority scores in [MinScore, MaxScore] to [MinNodeScore, MaxNodeScore]
+func scaleScore(score, count int) int64 {
+	if count == 0 || score <= priorities.MinScore {
+		return framework.MinNodeScore
+	}
+	scaled := int64(score) * framework.MaxNodeScore / int64(priorities.MaxScore*count)
+	if scaled > framework.MaxNodeScore {
+		return framework.MaxNodeScore
+	}
+	return scaled
+}
+
+// ScoreExtensions of the plugin is nil, for scores are scaled in Score
+func (pl *OpenLocal) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+// reservation is the pvcs of pod reserved in cache in the scheduling cycle
+type reservation struct {
+	pvcs []string
+}
+
+func (r *reservation) Clone() framework.StateData {
+	return r
+}
+
+// Reserve allocates local storage on node for the unbound pvcs of pod, and assumes them into cache,
+// the provisioner will get the allocated units from binding info when it calls back to the scheduler
+func (pl *OpenLocal) Reserve(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeName string) *framework.Status {
+	if utils.NeedSkip(schedulerapi.ExtenderArgs{Pod: pod}) {
+		return nil
+	}
+	node, err := pl.ctx.CoreV1Informers.Nodes().Lister().Get(nodeName)
+	if err != nil {
+		return framework.NewStatus(framework.Error, fmt.Sprintf("failed to get node %s: %s", nodeName, err.Error()))
+	}
+	pl.ctx.CtxLock.Lock()
+	defer pl.ctx.CtxLock.Unlock()
+
+	containReadonlySnapshot := false
+	err, lvmPVCs, mpPVCs, devicePVCs, quotaPVCs := algorithm.GetPodPvcs(pod, pl.ctx, true, containReadonlySnapshot)
+	if err != nil {
+		return framework.NewStatus(framework.Error, fmt.Sprintf("failed to get pvcs of pod %s/%s: %s", pod.Namespace, pod.Name, err.Error()))
+	}
+	// pvcs already allocated, e.g. by the callback of provisioner, are not reserved again
+	lvmPVCs = pl.unallocatedPVCs(lvmPVCs)
+	mpPVCs = pl.unallocatedPVCs(mpPVCs)
+	devicePVCs = pl.unallocatedPVCs(devicePVCs)
+	quotaPVCs = pl.unallocatedPVCs(quotaPVCs)
+	if len(lvmPVCs)+len(mpPVCs)+len(devicePVCs)+len(quotaPVCs) == 0 {
+		state.Write(reservationStateKey, &reservation{})
+		return nil
+	}
+
+	units, err := algo.AllocateVolumes(pod, lvmPVCs, mpPVCs, devicePVCs, quotaPVCs, node, pl.ctx)
+	if err != nil {
+		// the storage may be taken by other pods since filtered, the pod is scheduled again
+		return framework.NewStatus(framework.Unschedulable, fmt.Sprintf("failed to allocate local storage for pod %s/%s: %s", pod.Namespace, pod.Name, err.Error()))
+	}
+	if err := pl.ctx.ClusterNodeCache.Reserve(string(pod.UID), utils.PodName(pod), units, pl.ctx.ReservationTTL); err != nil {
+		return framework.NewStatus(framework.Error, fmt.Sprintf("failed to assume local storage for pod %s/%s: %s", pod.Namespace, pod.Name, err.Error()))
+	}
+	r := &reservation{}
+	for _, unit := range units {
+		r.pvcs = append(r.pvcs, unit.PVCName)
+	}
+	state.Write(reservationStateKey, r)
+	log.Infof("reserved local storage on node %s for pod %s/%s", nodeName, pod.Namespace, pod.Name)
+	return nil
+}
+
+func (pl *OpenLocal) unallocatedPVCs(pvcs []*corev1.PersistentVolumeClaim) (unallocated []*corev1.PersistentVolumeClaim) {
+	for _, pvc := range pvcs {
+		if !pl.ctx.ClusterNodeCache.BindingInfo.IsPVCExists(utils.PVCName(pvc)) {
+			unallocated = append(unallocated, pvc)
+		}
+	}
+	return unallocated
+}
+
+// Unreserve releases the local storage reserved for pod, the units consumed by pvs are kept
+func (pl *OpenLocal) Unreserve(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeName string) {
+	pl.ctx.CtxLock.Lock()
+	defer pl.ctx.CtxLock.Unlock()
+
+	if _, ok := pl.ctx.ClusterNodeCache.Reservations[string(pod.UID)]; !ok {
+		return
+	}
+	if err := pl.ctx.ClusterNodeCache.Unreserve(string(pod.UID)); err != nil {
+		log.Errorf("failed to unreserve local storage for pod %s/%s: %s", pod.Namespace, pod.Name, err.Error())
+		return
+	}
+	log.Infof("unreserved local storage on node %s for pod %s/%s", nodeName, pod.Namespace, pod.Name)
+}
+
+// PreBind checks that the reservation is still held, and records it in annotations of pvcs
+// before the volumes are provisioned
+func (pl *OpenLocal) PreBind(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeName string) *framework.Status {
+	if utils.NeedSkip(schedulerapi.ExtenderArgs{Pod: pod}) {
+		return nil
+	}
+	r, err := getReservation(state)
+	if err != nil {
+		// Reserve always writes the state for pods with local pvcs
+		log.Errorf("failed to check reservation of pod %s/%s on node %s: %s", pod.Namespace, pod.Name, nodeName, err.Error())
+		return nil
+	}
+	pl.ctx.CtxLock.RLock()
+	var lost []string
+	var units []cache.AllocatedUnit
+	for _, pvcName := range r.pvcs {
+		if allocated, ok := pl.ctx.ClusterNodeCache.BindingInfo[pvcName]; !ok || allocated.NodeName != nodeName {
+			lost = append(lost, pvcName)
+		} else {
+			units = append(units, *allocated)
+		}
+	}
+	pl.ctx.CtxLock.RUnlock()
+	if len(lost) > 0 {
+		return framework.NewStatus(framework.Error, fmt.Sprintf("reservation of pvc %s on node %s is lost", strings.Join(lost, ","), nodeName))
+	}
+	if err := algo.AnnotateAllocation(pl.kubeClient, units); err != nil {
+		return framework.NewStatus(framework.Error, err.Error())
+	}
+	return nil
+}
+
+func getReservation(state *framework.CycleState) (*reservation, error) {
+	data, err := state.Read(reservationStateKey)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := data.(*reservation)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to reservation error", data)
+	}
+	return r, nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/predicates"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/priorities"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+func newTestPod(name, pvcName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name)},
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{
+				{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvcName},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTestPlugin(nodeName string, pvcs ...*corev1.PersistentVolumeClaim) *OpenLocal {
+	factory := informers.NewSharedInformerFactory(k8sfake.NewSimpleClientset(), 0)
+	factory.Core().V1().Nodes().Informer().GetIndexer().Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}})
+	factory.Storage().V1().StorageClasses().Informer().GetIndexer().Add(&storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "open-local-lvm"},
+		Provisioner: localtype.ProvisionerName,
+		Parameters:  map[string]string{localtype.VolumeTypeKey: string(localtype.VolumeTypeLVM)},
+	})
+	kubeClient := k8sfake.NewSimpleClientset()
+	for _, pvc := range pvcs {
+		factory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(pvc)
+		kubeClient.Tracker().Add(pvc.DeepCopy())
+	}
+	ctx := &algorithm.SchedulingContext{
+		ClusterNodeCache:       cache.NewClusterNodeCache(),
+		CoreV1Informers:        factory.Core().V1(),
+		StorageV1Informers:     factory.Storage().V1(),
+		NodeAntiAffinityWeight: localtype.NewNodeAntiAffinityWeight(),
+	}
+	nc := cache.NewNodeCache(nodeName)
+	nc.VGs["share"] = cache.SharedResource{Name: "share", Capacity: 100 << 30}
+	ctx.ClusterNodeCache.SetNodeCache(nc)
+
+	return &OpenLocal{
+		ctx:             ctx,
+		kubeClient:      kubeClient,
+		predicateFuncs:  predicates.DefaultPredicateFuncs,
+		prioritizeFuncs: priorities.DefaultPrioritizeFuncs,
+		hasSynced:       func() bool { return true },
+	}
+}
+
+func TestReserve(t *testing.T) {
+	nodeName := "testnode"
+	storageClass := "open-local-lvm"
+	newPVC := func(name string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: &storageClass,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("60Gi")},
+				},
+			},
+		}
+	}
+	pl := newTestPlugin(nodeName, newPVC("pvc-1"), newPVC("pvc-2"))
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}})
+	ctx := context.Background()
+	pod1, pod2 := newTestPod("pod-1", "pvc-1"), newTestPod("pod-2", "pvc-2")
+
+	state1 := framework.NewCycleState()
+	if status := pl.Filter(ctx, state1, pod1, nodeInfo); !status.IsSuccess() {
+		t.Fatalf("Filter() of pod-1 = %v, expected success", status)
+	}
+	if score, status := pl.Score(ctx, state1, pod1, nodeName); !status.IsSuccess() || score < framework.MinNodeScore || score > framework.MaxNodeScore {
+		t.Fatalf("Score() of pod-1 = %d, %v, expected success in range", score, status)
+	}
+	if status := pl.Reserve(ctx, state1, pod1, nodeName); !status.IsSuccess() {
+		t.Fatalf("Reserve() of pod-1 = %v, expected success", status)
+	}
+	unit := pl.ctx.ClusterNodeCache.BindingInfo["default/pvc-1"]
+	if unit == nil || unit.NodeName != nodeName || unit.VgName != "share" {
+		t.Fatalf("binding info of pvc-1 = %+v, expected reserved in vg share", unit)
+	}
+	if _, ok := pl.ctx.ClusterNodeCache.Reservations["pod-1"]; !ok {
+		t.Fatalf("reservation of pod-1 is not found")
+	}
+	if status := pl.PreBind(ctx, state1, pod1, nodeName); !status.IsSuccess() {
+		t.Errorf("PreBind() of pod-1 = %v, expected success", status)
+	}
+	// the allocation is annotated before the volume is provisioned
+	pvc1, err := pl.kubeClient.CoreV1().PersistentVolumeClaims("default").Get(ctx, "pvc-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pvc1.Annotations[localtype.AnnAllocatedNode] != nodeName || pvc1.Annotations[localtype.AnnAllocatedVG] != "share" {
+		t.Errorf("annotations of pvc-1 after PreBind() = %v, expected allocated in vg share", pvc1.Annotations)
+	}
+
+	// the vg is not enough for pod-2 when pvc-1 is reserved
+	state2 := framework.NewCycleState()
+	if status := pl.Filter(ctx, state2, pod2, nodeInfo); status.Code() != framework.Unschedulable {
+		t.Fatalf("Filter() of pod-2 = %v, expected unschedulable", status)
+	}
+	if status := pl.Reserve(ctx, state2, pod2, nodeName); status.Code() != framework.Unschedulable {
+		t.Fatalf("Reserve() of pod-2 = %v, expected unschedulable", status)
+	}
+
+	pl.Unreserve(ctx, state1, pod1, nodeName)
+	if pl.ctx.ClusterNodeCache.BindingInfo.IsPVCExists("default/pvc-1") {
+		t.Errorf("binding info of pvc-1 is not removed after unreserve")
+	}
+	if requested := pl.ctx.ClusterNodeCache.GetNodeCache(nodeName).VGs["share"].Requested; requested != 0 {
+		t.Errorf("requested of vg share after unreserve is %d, expected 0", requested)
+	}
+	// unreserve is idempotent
+	pl.Unreserve(ctx, state1, pod1, nodeName)
+	if status := pl.PreBind(ctx, state1, pod1, nodeName); status.IsSuccess() {
+		t.Errorf("PreBind() of pod-1 after unreserve = %v, expected error", status)
+	}
+
+	if status := pl.Filter(ctx, state2, pod2, nodeInfo); !status.IsSuccess() {
+		t.Errorf("Filter() of pod-2 after unreserve = %v, expected success", status)
+	}
+}
+
+func TestScaleScore(t *testing.T) {
+	tests := []struct {
+		score, count int
+		expected     int64
+	}{
+		{score: 0, count: 4, expected: framework.MinNodeScore},
+		{score: 20, count: 4, expected: 50},
+		{score: 40, count: 4, expected: framework.MaxNodeScore},
+		{score: 50, count: 4, expected: framework.MaxNodeScore},
+		{score: 10, count: 0, expected: framework.MinNodeScore},
+	}
+	for _, test := range tests {
+		if scaled := scaleScore(test.score, test.count); scaled != test.expected {
+			t.Errorf("scaleScore(%d, %d) = %d, expected %d", test.score, test.count, scaled, test.expected)
+		}
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package server
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -25,15 +26,18 @@ import (
 	"github.com/julienschmidt/httprouter"
 
 	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/simulate"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 const schedulingPVCPrefix = "/apis/scheduling/:namespace/persistentvolumeclaims/:name"
 const schedulingExpandPVCPrefix = "/apis/expand/:namespace/persistentvolumeclaims/:name"
+const simulatePath = "/apis/simulate"
 
-func AddSchedulingApis(router *httprouter.Router, ctx *algorithm.SchedulingContext) {
-	router.POST(schedulingPVCPrefix, DebugLogging(SchedulingPVCWrap(ctx), schedulingPVCPrefix))
+func AddSchedulingApis(router *httprouter.Router, ctx *algorithm.SchedulingContext, kubeClient kubernetes.Interface) {
+	router.POST(schedulingPVCPrefix, DebugLogging(SchedulingPVCWrap(ctx, kubeClient), schedulingPVCPrefix))
 	router.POST(schedulingExpandPVCPrefix, DebugLogging(SchedulingExpandWrap(ctx), schedulingExpandPVCPrefix))
 }
 
@@ -59,7 +63,7 @@ func SchedulingExpandWrap(ctx *algorithm.SchedulingContext) httprouter.Handle {
 }
 
 // SchedulingPVCWrap handles the request during volume provisioning
-func SchedulingPVCWrap(ctx *algorithm.SchedulingContext) httprouter.Handle {
+func SchedulingPVCWrap(ctx *algorithm.SchedulingContext, kubeClient kubernetes.Interface) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		var pvc *corev1.PersistentVolumeClaim
 		var err error
@@ -79,7 +83,7 @@ func SchedulingPVCWrap(ctx *algorithm.SchedulingContext) httprouter.Handle {
 			}
 		}
 
-		info, err := apis.SchedulingPVC(ctx, pvc, node)
+		info, err := apis.SchedulingPVC(ctx, kubeClient, pvc, node)
 		if err != nil {
 			log.Errorf("failed to scheduling pvc %s/%s: %s", pvc.Namespace, pvc.Name, err.Error())
 			utils.HttpResponse(w, http.StatusInternalServerError, []byte(err.Error()))
@@ -89,6 +93,23 @@ func SchedulingPVCWrap(ctx *algorithm.SchedulingContext) httprouter.Handle {
 	}
 }
 
+func AddSimulate(router *httprouter.Router, s simulate.Simulate) {
+	router.POST(simulatePath, DebugLogging(SimulateRoute(s), simulatePath))
+}
+
+// SimulateRoute schedules the pods and pvcs in request body against a copy of cache without changing the cluster
+func SimulateRoute(s simulate.Simulate) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		checkBody(w, r)
+		var args simulate.Args
+		if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+			utils.HttpResponse(w, http.StatusBadRequest, []byte(err.Error()))
+			return
+		}
+		utils.HttpJSON(w, http.StatusOK, s.Handler(args))
+	}
+}
+
 func AddGetNodeCache(router *httprouter.Router, ctx *algorithm.SchedulingContext) {
 	router.POST(cachePath, DebugLogging(apis.CacheRoute(ctx), cachePath))
 }
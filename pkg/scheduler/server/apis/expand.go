@@ -76,7 +76,11 @@ func ExpandPVC(ctx *algorithm.SchedulingContext, pvc *corev1.PersistentVolumeCla
 		if vg == "" {
 			return fmt.Errorf("vgName is empty for pv %s", pv.Name)
 		}
-		if vgCache, ok := nc.VGs[cache.ResourceName(vg)]; ok {
+		vgs := nc.VGs
+		if utils.IsThinCsiPV(pv) {
+			vgs = nc.ThinPools
+		}
+		if vgCache, ok := vgs[cache.ResourceName(vg)]; ok {
 			newRequested := vgCache.Requested + int64(newSize-oldSize)
 			log.Infof("matching pvc %s/%s on vg %s(left=%d bytes), ", pvc.Namespace, pvc.Name, vg, vgCache.Capacity-vgCache.Requested)
 			if newRequested > vgCache.Capacity {
@@ -84,7 +88,7 @@ func ExpandPVC(ctx *algorithm.SchedulingContext, pvc *corev1.PersistentVolumeCla
 				return err
 			}
 			vgCache.Requested += newSize - oldSize
-			nc.VGs[cache.ResourceName(vg)] = vgCache
+			vgs[cache.ResourceName(vg)] = vgCache
 			return nil
 		} else {
 			err := fmt.Errorf("vg cache is not found for VG %s", vg)
@@ -95,7 +99,24 @@ func ExpandPVC(ctx *algorithm.SchedulingContext, pvc *corev1.PersistentVolumeCla
 	case pkg.VolumeTypeDevice:
 		return fmt.Errorf("expansion on Device volume is not supported")
 	case pkg.VolumeTypeQuota:
-		return fmt.Errorf("expansion on Quota volume is not supported")
+		mp := utils.GetMountPointFromCsiPV(pv)
+		if mp == "" {
+			return fmt.Errorf("mount point is empty for pv %s", pv.Name)
+		}
+		if quotaCache, ok := nc.Quotas[cache.ResourceName(mp)]; ok {
+			newRequested := quotaCache.Requested + int64(newSize-oldSize)
+			log.Infof("matching pvc %s/%s on mount point %s(left=%d bytes), ", pvc.Namespace, pvc.Name, mp, quotaCache.Capacity-quotaCache.Requested)
+			if newRequested > quotaCache.Capacity {
+				err := fmt.Errorf("failed to extend pvc, mount point %s is not enough, requested total %d, capacity %d", mp, newRequested, quotaCache.Capacity)
+				return err
+			}
+			quotaCache.Requested += newSize - oldSize
+			nc.Quotas[cache.ResourceName(mp)] = quotaCache
+			return nil
+		} else {
+			err := fmt.Errorf("quota cache is not found for mount point %s", mp)
+			return err
+		}
 	}
 	return fmt.Errorf("unhandled error during volume expansion")
 
@@ -30,11 +30,14 @@ import (
 	"github.com/alibaba/open-local/pkg/scheduler/algorithm/algo"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 // Scheduling is the interface for provisioner to request storage info
-// reports error if failed to reserve storage for pvc
-func SchedulingPVC(ctx *algorithm.SchedulingContext, pvc *corev1.PersistentVolumeClaim, node *corev1.Node) (*scheduler.BindingInfo, error) {
+// reports error if failed to reserve storage for pvc. The allocation is recorded in annotations
+// of the pvcs before it is returned, so that the volumes are created as allocated even if the
+// scheduler restarts before provisioner retries.
+func SchedulingPVC(ctx *algorithm.SchedulingContext, kubeClient kubernetes.Interface, pvc *corev1.PersistentVolumeClaim, node *corev1.Node) (*scheduler.BindingInfo, error) {
 	if node == nil {
 		log.Infof("scheduling pvc %s/%s without node", pvc.Namespace, pvc.Name)
 	} else {
@@ -65,61 +68,48 @@ func SchedulingPVC(ctx *algorithm.SchedulingContext, pvc *corev1.PersistentVolum
 		return nil, fmt.Errorf(msg)
 	}
 	containReadonlySnapshot := false
-	err, lvmPVCs, mpPVCs, devicePVCs := algorithm.GetPodUnboundPvcs(pvc, ctx, containReadonlySnapshot)
+	err, lvmPVCs, mpPVCs, devicePVCs, quotaPVCs := algorithm.GetPodUnboundPvcs(pvc, ctx, containReadonlySnapshot)
 	if err != nil {
 		log.Errorf("failed to get pod unbound pvcs: %s", err.Error())
 		return nil, err
 	}
 
-	if len(lvmPVCs)+len(mpPVCs)+len(devicePVCs) == 0 {
+	if len(lvmPVCs)+len(mpPVCs)+len(devicePVCs)+len(quotaPVCs) == 0 {
 		msg := "unexpected schedulering request for all pvcs are bounded"
 		log.Info(msg)
 		return nil, fmt.Errorf(msg)
 	}
-	var allocatedUnits []cache.AllocatedUnit
-	trace.Step("Computing ScoreLVMVolume")
-	if _, lvmUnits, err := algo.ScoreLVMVolume(nil /*do we need pod here*/, lvmPVCs, node, ctx); err != nil {
-		err = fmt.Errorf("failed to allocate local storage for pvc %s/%s: %s", pvc.Namespace, pvc.Name, err.Error())
+	// all the unbound pvcs of the pod are planned and reserved together, so that none of them
+	// is provisioned if the others do not fit the node
+	trace.Step("Computing PlanVolumes")
+	allocatedUnits, err := algo.PlanVolumes(nil /*do we need pod here*/, lvmPVCs, mpPVCs, devicePVCs, quotaPVCs, node, ctx)
+	if err != nil || len(allocatedUnits) <= 0 {
+		err = fmt.Errorf("failed to allocate local storage for pvc %s/%s: %v", pvc.Namespace, pvc.Name, err)
 		log.Errorf(err.Error())
 		return nil, err
-	} else {
-		allocatedUnits = append(allocatedUnits, lvmUnits...)
-	}
-	trace.Step("Computing ScoreMountPointVolume")
-	if _, mpUnits, err := algo.ScoreMountPointVolume(nil /*do we need pod here*/, mpPVCs, node, ctx); err != nil {
-		err = fmt.Errorf("failed to allocate local storage for pvc %s/%s: %s", pvc.Namespace, pvc.Name, err.Error())
-		log.Errorf(err.Error())
-		return nil, err
-	} else {
-		allocatedUnits = append(allocatedUnits, mpUnits...)
-	}
-	trace.Step("Computing ScoreDeviceVolume")
-	if _, deviceUnits, err := algo.ScoreDeviceVolume(nil /*do we need pod here*/, devicePVCs, node, ctx); err != nil {
-		err = fmt.Errorf("failed to allocate local storage for pvc %s/%s: %s", pvc.Namespace, pvc.Name, err.Error())
-		log.Errorf(err.Error())
-		return nil, err
-	} else {
-		allocatedUnits = append(allocatedUnits, deviceUnits...)
 	}
-
-	if (allocatedUnits == nil || len(allocatedUnits) <= 0) || len(allocatedUnits) != (len(lvmPVCs)+len(mpPVCs)+len(devicePVCs)) {
-		log.Errorf("unexpected allocated unit number: %d", len(allocatedUnits))
+	trace.Step("Computing Reserve")
+	pod, err := algorithm.GetPodOfPVC(pvcName, ctx)
+	if err != nil {
 		return nil, err
 	}
-	trace.Step("Computing Assume")
-
-	err = ctx.ClusterNodeCache.Assume(allocatedUnits)
-
+	err = ctx.ClusterNodeCache.Reserve(string(pod.UID), utils.PodName(pod), allocatedUnits, ctx.ReservationTTL)
 	if err != nil {
 		err = fmt.Errorf("failed to assume local storage for pvc %s/%s: %s", pvc.Namespace, pvc.Name, err.Error())
 		log.Error(err.Error())
 		return nil, err
 	}
+	trace.Step("Computing AnnotateAllocation")
+	if err := algo.AnnotateAllocation(kubeClient, allocatedUnits); err != nil {
+		if err := ctx.ClusterNodeCache.Unreserve(string(pod.UID)); err != nil {
+			log.Errorf("failed to unreserve local storage of pod %s: %s", utils.PodName(pod), err.Error())
+		}
+		log.Error(err.Error())
+		return nil, err
+	}
 	var targetAllocateUnits []cache.AllocatedUnit
 	log.Debugf("allocatedUnits of pvc %s: %+v", pvcName, allocatedUnits)
 	for _, unit := range allocatedUnits {
-		newUnit := unit
-		ctx.ClusterNodeCache.BindingInfo[newUnit.PVCName] = &newUnit
 		if unit.PVCName == utils.PVCName(pvc) {
 			targetAllocateUnits = append(targetAllocateUnits, unit)
 		}
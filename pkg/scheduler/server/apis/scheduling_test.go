@@ -0,0 +1,108 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis
+
+import (
+	"context"
+	"testing"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSchedulingPVC(t *testing.T) {
+	nodeName := "testnode"
+	storageClass := "open-local-lvm"
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "pvc-1",
+			Namespace:   "default",
+			Annotations: map[string]string{localtype.AnnoSelectedNode: nodeName},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "default", UID: "uid-pod-1"},
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{{
+				Name:         "data",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.Name}},
+			}},
+		},
+	}
+	newContext := func() *algorithm.SchedulingContext {
+		factory := informers.NewSharedInformerFactory(k8sfake.NewSimpleClientset(), 0)
+		factory.Core().V1().Pods().Informer().GetIndexer().Add(pod)
+		factory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(pvc)
+		factory.Storage().V1().StorageClasses().Informer().GetIndexer().Add(&storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: storageClass},
+			Provisioner: localtype.ProvisionerName,
+			Parameters:  map[string]string{localtype.VolumeTypeKey: string(localtype.VolumeTypeLVM)},
+		})
+		ctx := &algorithm.SchedulingContext{
+			ClusterNodeCache:       cache.NewClusterNodeCache(),
+			CoreV1Informers:        factory.Core().V1(),
+			StorageV1Informers:     factory.Storage().V1(),
+			NodeAntiAffinityWeight: localtype.NewNodeAntiAffinityWeight(),
+		}
+		nc := cache.NewNodeCache(nodeName)
+		nc.VGs["share"] = cache.SharedResource{Name: "share", Capacity: 100 << 30}
+		ctx.ClusterNodeCache.SetNodeCache(nc)
+		ctx.ClusterNodeCache.PvcMapping.PutPod("default/pod-1", []*corev1.PersistentVolumeClaim{pvc})
+		return ctx
+	}
+
+	// the allocation is annotated before it is returned to provisioner
+	ctx := newContext()
+	kubeClient := k8sfake.NewSimpleClientset(pvc.DeepCopy())
+	info, err := SchedulingPVC(ctx, kubeClient, pvc, node)
+	if err != nil || info.VgName != "share" || info.Node != nodeName {
+		t.Fatalf("SchedulingPVC() = %+v, %v, expected allocated in vg share", info, err)
+	}
+	annotated, err := kubeClient.CoreV1().PersistentVolumeClaims("default").Get(context.Background(), pvc.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if annotated.Annotations[localtype.AnnAllocatedNode] != nodeName || annotated.Annotations[localtype.AnnAllocatedVG] != "share" {
+		t.Errorf("annotations of pvc are %v, expected allocated in vg share", annotated.Annotations)
+	}
+
+	// the reservation is released if the allocation can not be recorded
+	ctx = newContext()
+	if _, err := SchedulingPVC(ctx, k8sfake.NewSimpleClientset(), pvc, node); err == nil {
+		t.Fatalf("SchedulingPVC() should fail when pvc can not be annotated")
+	}
+	if ctx.ClusterNodeCache.BindingInfo.IsPVCExists("default/pvc-1") {
+		t.Errorf("binding info of pvc-1 is not removed after annotating failed")
+	}
+	if requested := ctx.ClusterNodeCache.GetNodeCache(nodeName).VGs["share"].Requested; requested != 0 {
+		t.Errorf("requested of vg share after annotating failed is %d, expected 0", requested)
+	}
+}
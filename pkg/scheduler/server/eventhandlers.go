@@ -130,6 +130,11 @@ func (e *ExtenderServer) onPVAdd(obj interface{}) {
 		e.Ctx.ClusterNodeCache.SetNodeCache(nc)
 		log.Debugf("created new node cache %q when adding pv %q", node, pv.Name)
 	}
+	pvcKey, pvcKeyErr := algorithm.ExtractPVCKey(pv)
+	if pvcKeyErr == nil {
+		// the reserved unit is replaced by pv
+		e.Ctx.ClusterNodeCache.ConsumeReservation(pvcKey)
+	}
 	// handle according to types
 	switch pkg.VolumeType(pvType) {
 	case pkg.VolumeTypeLVM:
@@ -153,13 +158,19 @@ func (e *ExtenderServer) onPVAdd(obj interface{}) {
 			log.Errorf("failed to add local pv %s (type: %s) on node %s: %s", pv.Name, pvType, nc.NodeName, err.Error())
 			return
 		}
+	case pkg.VolumeTypeQuota:
+		trace.Step("Computing AddQuota")
+		err := nc.AddQuota(pv)
+		if err != nil {
+			log.Errorf("failed to add local pv %s (type: %s) on node %s: %s", pv.Name, pvType, nc.NodeName, err.Error())
+			return
+		}
 	default:
 		log.Debugf("not a open-local pv %s, type %s, not add to cache", pv.Name, pvType)
 		return
 	}
-	pvcKey, err := algorithm.ExtractPVCKey(pv)
-	if err != nil {
-		log.Errorf("failed to extract pvc name from pv %s: %s", pv.Name, err.Error())
+	if pvcKeyErr != nil {
+		log.Errorf("failed to extract pvc name from pv %s: %s", pv.Name, pvcKeyErr.Error())
 		return
 	}
 	au, err := algorithm.ConvertAUFromPV(pv, e.Ctx.StorageV1Informers, e.Ctx.CoreV1Informers)
@@ -229,6 +240,12 @@ func (e *ExtenderServer) onPVDelete(obj interface{}) {
 			log.Errorf("failed to remove local pv %s (type: %s) on node %s: %s", pv.Name, pvType, nc.NodeName, err.Error())
 			return
 		}
+	case pkg.VolumeTypeQuota:
+		err := nc.RemoveQuota(pv)
+		if err != nil {
+			log.Errorf("failed to remove local pv %s (type: %s) on node %s: %s", pv.Name, pvType, nc.NodeName, err.Error())
+			return
+		}
 	default:
 		log.Infof("not a open-local pv %s, volumeType %s, skipped", pv.Name, pvType)
 		return
@@ -257,6 +274,12 @@ func (e *ExtenderServer) onPVUpdate(oldObj, newObj interface{}) {
 		log.Infof("pv %s is in %s status, skipped", pv.Status.Phase, pv.Name)
 		return
 	}
+	if old.Status.Phase == corev1.VolumePending {
+		// a new pv is created pending and skipped in onPVAdd, it is accounted when it leaves pending,
+		// which consumes the reservation of its pvc
+		e.onPVAdd(pv)
+		return
+	}
 	node := e.Ctx.ClusterNodeCache.GetNodeNameFromPV(pv)
 	if node == "" {
 		log.Infof("pv %s is not a local pv, skipped", pv.Name)
@@ -294,6 +317,12 @@ func (e *ExtenderServer) onPVUpdate(oldObj, newObj interface{}) {
 			log.Errorf("failed to update local pv %s (type: %s) on node %s: %s", pv.Name, pvType, nc.NodeName, err.Error())
 			return
 		}
+	case pkg.VolumeTypeQuota:
+		err := nc.UpdateQuota(old, pv)
+		if err != nil {
+			log.Errorf("failed to update local pv %s (type: %s) on node %s: %s", pv.Name, pvType, nc.NodeName, err.Error())
+			return
+		}
 	default:
 		log.Infof("not a open-local pv %s, volumeType %s, skipped", pv.Name, pvType)
 		return
@@ -358,6 +387,13 @@ func (e *ExtenderServer) onPodDelete(obj interface{}) {
 	}
 	podName := utils.PodName(pod)
 
+	e.Ctx.CtxLock.Lock()
+	defer e.Ctx.CtxLock.Unlock()
+	// release the local storage reserved for pod, whose pvs are not created
+	if err := e.Ctx.ClusterNodeCache.Unreserve(string(pod.UID)); err != nil {
+		log.Errorf("failed to release reservation of pod %s: %s", podName, err.Error())
+	}
+
 	containReadonlySnapshot := true
 	pvcs, err := algorithm.GetAllPodPvcs(pod, e.Ctx, containReadonlySnapshot)
 	if err != nil {
@@ -369,8 +405,6 @@ func (e *ExtenderServer) onPodDelete(obj interface{}) {
 		log.Infof("no open-local pvc found for %s", podName)
 		return
 	}
-	e.Ctx.CtxLock.Lock()
-	defer e.Ctx.CtxLock.Unlock()
 	e.Ctx.ClusterNodeCache.PvcMapping.DeletePod(podName, pvcs)
 
 	nc := e.Ctx.ClusterNodeCache.GetNodeCache(nodeName)
@@ -513,4 +547,8 @@ func (e *ExtenderServer) onPvcUpdate(_, newObj interface{}) {
 	e.Ctx.CtxLock.Lock()
 	defer e.Ctx.CtxLock.Unlock()
 	e.Ctx.ClusterNodeCache.PvcMapping.PutPvc(pvc)
+	// io limits may be updated by the annotations of pvc
+	if unit, ok := e.Ctx.ClusterNodeCache.BindingInfo[utils.PVCName(pvc)]; ok && unit != nil {
+		unit.IO = algorithm.GetPVCIORequest(pvc, e.Ctx)
+	}
 }
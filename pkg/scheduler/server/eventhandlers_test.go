@@ -0,0 +1,133 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	nodelocalstorage "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// newTestExtender returns an extender whose cache has node-1 with vg share of 100Gi
+func newTestExtender(t *testing.T) *ExtenderServer {
+	e, err := NewReplayServer(&Snapshot{
+		Nodes: []corev1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}},
+		NodeLocalStorages: []nodelocalstorage.NodeLocalStorage{{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status: nodelocalstorage.NodeLocalStorageStatus{
+				NodeStorageInfo:     nodelocalstorage.NodeStorageInfo{VolumeGroups: []nodelocalstorage.VolumeGroup{{Name: "share", Total: 100 << 30, Available: 100 << 30, Allocatable: 100 << 30}}},
+				FilteredStorageInfo: nodelocalstorage.FilteredStorageInfo{VolumeGroups: []string{"share"}},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e.ExtenderServer
+}
+
+func newTestLVMUnit(pvcName string, size int64) cache.AllocatedUnit {
+	return cache.AllocatedUnit{NodeName: "node-1", VolumeType: localtype.VolumeTypeLVM, Requested: size, Allocated: size, VgName: "share", PVCName: "default/" + pvcName}
+}
+
+// newTestLVMPV returns the pv of pvc on vg share of node-1, which is created pending
+func newTestLVMPV(pvcName string, size int64) *corev1.PersistentVolume {
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "local-" + pvcName, UID: types.UID("uid-local-" + pvcName)},
+		Spec: corev1.PersistentVolumeSpec{
+			Capacity: corev1.ResourceList{corev1.ResourceStorage: *resource.NewQuantity(size, resource.BinarySI)},
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{
+					Driver:           localtype.ProvisionerName,
+					VolumeHandle:     "local-" + pvcName,
+					VolumeAttributes: map[string]string{localtype.VGName: "share", localtype.VolumeTypeKey: string(localtype.VolumeTypeLVM)},
+				},
+			},
+			ClaimRef:                      &corev1.ObjectReference{Namespace: "default", Name: pvcName},
+			PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+			NodeAffinity: &corev1.VolumeNodeAffinity{
+				Required: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      localtype.KubernetesNodeIdentityKey,
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{"node-1"},
+						}},
+					}},
+				},
+			},
+		},
+		Status: corev1.PersistentVolumeStatus{Phase: corev1.VolumePending},
+	}
+}
+
+func TestPVUpdateConsumesReservation(t *testing.T) {
+	e := newTestExtender(t)
+	size := int64(10 << 30)
+	requested := func() int64 {
+		return e.Ctx.ClusterNodeCache.GetNodeCache("node-1").VGs["share"].Requested
+	}
+	if err := e.Ctx.ClusterNodeCache.Reserve("uid-pod", "default/pod", []cache.AllocatedUnit{newTestLVMUnit("data", size)}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	pv := newTestLVMPV("data", size)
+	e.onPVAdd(pv)
+	if _, ok := e.Ctx.ClusterNodeCache.Reservations["uid-pod"]; !ok {
+		t.Fatalf("reservation is consumed by pending pv")
+	}
+	if requested() != size {
+		t.Fatalf("requested of vg share with pending pv is %d, expected %d", requested(), size)
+	}
+
+	bound := pv.DeepCopy()
+	bound.Status.Phase = corev1.VolumeBound
+	e.onPVUpdate(pv, bound)
+	if _, ok := e.Ctx.ClusterNodeCache.Reservations["uid-pod"]; ok {
+		t.Errorf("reservation is not consumed when pv is bound")
+	}
+	if requested() != size {
+		t.Errorf("requested of vg share with bound pv is %d, expected %d", requested(), size)
+	}
+	if _, ok := e.Ctx.ClusterNodeCache.GetNodeCache("node-1").LocalPVs[pv.Name]; !ok {
+		t.Errorf("pv %s is not added into node cache", pv.Name)
+	}
+
+	// the pv is accounted only once when it is updated again
+	e.onPVUpdate(bound, bound.DeepCopy())
+	if expired := e.Ctx.ClusterNodeCache.CleanExpiredReservations(time.Now().Add(time.Hour)); len(expired) != 0 {
+		t.Errorf("expired reservations are %v, expected none", expired)
+	}
+	if requested() != size {
+		t.Errorf("requested of vg share after cleaning reservations is %d, expected %d", requested(), size)
+	}
+}
+
+// provisionTestPV drives the events of a pv which is created pending and then bound
+func provisionTestPV(e *ExtenderServer, pv *corev1.PersistentVolume) {
+	e.onPVAdd(pv)
+	bound := pv.DeepCopy()
+	bound.Status.Phase = corev1.VolumeBound
+	e.onPVUpdate(pv, bound)
+}
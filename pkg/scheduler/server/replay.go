@@ -0,0 +1,403 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	nodelocalstorage "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
+	localfake "github.com/alibaba/open-local/pkg/generated/clientset/versioned/fake"
+	informers "github.com/alibaba/open-local/pkg/generated/informers/externalversions"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/algo"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/predicates"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/priorities"
+	"github.com/alibaba/open-local/pkg/utils"
+	"github.com/julienschmidt/httprouter"
+	volumesnapshotv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1beta1"
+	volumesnapshotfake "github.com/kubernetes-csi/external-snapshotter/client/v4/clientset/versioned/fake"
+	volumesnapshotinformers "github.com/kubernetes-csi/external-snapshotter/client/v4/informers/externalversions"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kubeinformers "k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clientgocache "k8s.io/client-go/tools/cache"
+	schedulerapi "k8s.io/kube-scheduler/extender/v1"
+)
+
+const dumpPath = "/apis/dump"
+
+const (
+	VerbPredicates = "predicates"
+	VerbPriorities = "priorities"
+)
+
+// Snapshot is the objects in the informers of scheduling context and the options of extender,
+// it is dumped from a running extender to replay the scheduling offline
+type Snapshot struct {
+	SchedulerStrategy       localtype.StrategyType              `json:"schedulerStrategy,omitempty"`
+	NodeAntiAffinityWeights map[localtype.VolumeType]int        `json:"nodeAntiAffinityWeights,omitempty"`
+	Nodes                   []corev1.Node                       `json:"nodes,omitempty"`
+	NodeLocalStorages       []nodelocalstorage.NodeLocalStorage `json:"nodeLocalStorages,omitempty"`
+	StorageClasses          []storagev1.StorageClass            `json:"storageClasses,omitempty"`
+	PersistentVolumes       []corev1.PersistentVolume           `json:"persistentVolumes,omitempty"`
+	PersistentVolumeClaims  []corev1.PersistentVolumeClaim      `json:"persistentVolumeClaims,omitempty"`
+	// Pods are the pods with volumes only
+	Pods                   []corev1.Pod                                  `json:"pods,omitempty"`
+	VolumeSnapshots        []volumesnapshotv1beta1.VolumeSnapshot        `json:"volumeSnapshots,omitempty"`
+	VolumeSnapshotContents []volumesnapshotv1beta1.VolumeSnapshotContent `json:"volumeSnapshotContents,omitempty"`
+	VolumeSnapshotClasses  []volumesnapshotv1beta1.VolumeSnapshotClass   `json:"volumeSnapshotClasses,omitempty"`
+	// BindingInfo and Reservations are the units assumed in the cache of extender, the reserved units
+	// are not in the informers until their pvs are created
+	BindingInfo  map[string]cache.AllocatedUnit `json:"bindingInfo,omitempty"`
+	Reservations []cache.Reservation            `json:"reservations,omitempty"`
+}
+
+// Call is a filter or prioritize request of kube-scheduler and its result
+type Call struct {
+	Verb             string                             `json:"verb"`
+	Args             schedulerapi.ExtenderArgs          `json:"args"`
+	FilterResult     *schedulerapi.ExtenderFilterResult `json:"filterResult,omitempty"`
+	HostPriorityList *schedulerapi.HostPriorityList     `json:"hostPriorityList,omitempty"`
+}
+
+// Recording is a snapshot and the calls replayed against it in order, with the expected results
+type Recording struct {
+	Snapshot Snapshot `json:"snapshot"`
+	Calls    []Call   `json:"calls"`
+}
+
+func ReadRecording(r io.Reader) (*Recording, error) {
+	recording := &Recording{}
+	if err := json.NewDecoder(r).Decode(recording); err != nil {
+		return nil, err
+	}
+	return recording, nil
+}
+
+func AddDump(router *httprouter.Router, ctx *algorithm.SchedulingContext) {
+	router.GET(dumpPath, DebugLogging(DumpRoute(ctx), dumpPath))
+}
+
+// DumpRoute responds the snapshot of scheduling context
+func DumpRoute(ctx *algorithm.SchedulingContext) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		snapshot, err := DumpSnapshot(ctx)
+		if err != nil {
+			utils.HttpResponse(w, http.StatusInternalServerError, []byte(err.Error()))
+			return
+		}
+		utils.HttpJSON(w, http.StatusOK, snapshot)
+	}
+}
+
+// DumpSnapshot lists the objects from the informers of ctx, sorted by namespace and name.
+// The env, command and args of containers are removed as they are not used in scheduling
+// and may contain credentials.
+func DumpSnapshot(ctx *algorithm.SchedulingContext) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		SchedulerStrategy:       ctx.SchedulerStrategy,
+		NodeAntiAffinityWeights: ctx.NodeAntiAffinityWeight.Items(true),
+	}
+	if snapshot.SchedulerStrategy == "" {
+		snapshot.SchedulerStrategy = localtype.SchedulerStrategy
+	}
+
+	nodes, err := ctx.CoreV1Informers.Nodes().Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		node = node.DeepCopy()
+		node.ManagedFields = nil
+		snapshot.Nodes = append(snapshot.Nodes, *node)
+	}
+	nlsList, err := ctx.LocalStorageInformer.NodeLocalStorages().Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, nls := range nlsList {
+		nls = nls.DeepCopy()
+		nls.ManagedFields = nil
+		snapshot.NodeLocalStorages = append(snapshot.NodeLocalStorages, *nls)
+	}
+	scs, err := ctx.StorageV1Informers.StorageClasses().Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, sc := range scs {
+		sc = sc.DeepCopy()
+		sc.ManagedFields = nil
+		snapshot.StorageClasses = append(snapshot.StorageClasses, *sc)
+	}
+	pvs, err := ctx.CoreV1Informers.PersistentVolumes().Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, pv := range pvs {
+		pv = pv.DeepCopy()
+		pv.ManagedFields = nil
+		snapshot.PersistentVolumes = append(snapshot.PersistentVolumes, *pv)
+	}
+	pvcs, err := ctx.CoreV1Informers.PersistentVolumeClaims().Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, pvc := range pvcs {
+		pvc = pvc.DeepCopy()
+		pvc.ManagedFields = nil
+		snapshot.PersistentVolumeClaims = append(snapshot.PersistentVolumeClaims, *pvc)
+	}
+	pods, err := ctx.CoreV1Informers.Pods().Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods {
+		if len(pod.Spec.Volumes) == 0 {
+			continue
+		}
+		snapshot.Pods = append(snapshot.Pods, *sanitizePod(pod))
+	}
+	snaps, err := ctx.SnapshotInformers.VolumeSnapshots().Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, snap := range snaps {
+		snap = snap.DeepCopy()
+		snap.ManagedFields = nil
+		snapshot.VolumeSnapshots = append(snapshot.VolumeSnapshots, *snap)
+	}
+	contents, err := ctx.SnapshotInformers.VolumeSnapshotContents().Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, content := range contents {
+		content = content.DeepCopy()
+		content.ManagedFields = nil
+		snapshot.VolumeSnapshotContents = append(snapshot.VolumeSnapshotContents, *content)
+	}
+	classes, err := ctx.SnapshotInformers.VolumeSnapshotClasses().Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, class := range classes {
+		class = class.DeepCopy()
+		class.ManagedFields = nil
+		snapshot.VolumeSnapshotClasses = append(snapshot.VolumeSnapshotClasses, *class)
+	}
+	dumpReservations(ctx, snapshot)
+
+	sortSnapshot(snapshot)
+	return snapshot, nil
+}
+
+// dumpReservations copies the binding info and reservations of the cluster cache into snapshot
+func dumpReservations(ctx *algorithm.SchedulingContext, snapshot *Snapshot) {
+	ctx.CtxLock.RLock()
+	defer ctx.CtxLock.RUnlock()
+	for pvcName, unit := range ctx.ClusterNodeCache.BindingInfo {
+		if unit == nil {
+			continue
+		}
+		if snapshot.BindingInfo == nil {
+			snapshot.BindingInfo = make(map[string]cache.AllocatedUnit)
+		}
+		snapshot.BindingInfo[pvcName] = *unit
+	}
+	for _, r := range ctx.ClusterNodeCache.Reservations {
+		reservation := *r
+		reservation.Units = make([]*cache.AllocatedUnit, 0, len(r.Units))
+		for _, unit := range r.Units {
+			u := *unit
+			reservation.Units = append(reservation.Units, &u)
+		}
+		reservation.Committed = append([]string(nil), r.Committed...)
+		snapshot.Reservations = append(snapshot.Reservations, reservation)
+	}
+}
+
+func sanitizePod(pod *corev1.Pod) *corev1.Pod {
+	pod = pod.DeepCopy()
+	pod.ManagedFields = nil
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range containers {
+			containers[i].Env = nil
+			containers[i].EnvFrom = nil
+			containers[i].Command = nil
+			containers[i].Args = nil
+		}
+	}
+	return pod
+}
+
+func sortSnapshot(s *Snapshot) {
+	sort.Slice(s.Nodes, func(i, j int) bool { return s.Nodes[i].Name < s.Nodes[j].Name })
+	sort.Slice(s.NodeLocalStorages, func(i, j int) bool { return s.NodeLocalStorages[i].Name < s.NodeLocalStorages[j].Name })
+	sort.Slice(s.StorageClasses, func(i, j int) bool { return s.StorageClasses[i].Name < s.StorageClasses[j].Name })
+	sort.Slice(s.PersistentVolumes, func(i, j int) bool { return s.PersistentVolumes[i].Name < s.PersistentVolumes[j].Name })
+	sort.Slice(s.PersistentVolumeClaims, func(i, j int) bool {
+		return utils.PVCName(&s.PersistentVolumeClaims[i]) < utils.PVCName(&s.PersistentVolumeClaims[j])
+	})
+	sort.Slice(s.Pods, func(i, j int) bool { return utils.PodName(&s.Pods[i]) < utils.PodName(&s.Pods[j]) })
+	sort.Slice(s.VolumeSnapshots, func(i, j int) bool {
+		a, b := s.VolumeSnapshots[i], s.VolumeSnapshots[j]
+		return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+	})
+	sort.Slice(s.VolumeSnapshotContents, func(i, j int) bool { return s.VolumeSnapshotContents[i].Name < s.VolumeSnapshotContents[j].Name })
+	sort.Slice(s.VolumeSnapshotClasses, func(i, j int) bool { return s.VolumeSnapshotClasses[i].Name < s.VolumeSnapshotClasses[j].Name })
+	sort.Slice(s.Reservations, func(i, j int) bool { return s.Reservations[i].PodUID < s.Reservations[j].PodUID })
+}
+
+// ReplayServer is an extender built from a snapshot, which replays the calls with the scheduler strategy of the snapshot
+type ReplayServer struct {
+	*ExtenderServer
+}
+
+// NewReplayServer returns an extender whose cache is built from snapshot without apiserver.
+// The objects are added to the informers, which are not started, and passed to the event handlers
+// in the order of node local storages, pvcs, pvs and pods, then the reservations are assumed again,
+// so that the cache is the same on every replay.
+func NewReplayServer(snapshot *Snapshot) (*ReplayServer, error) {
+	kubeClient := k8sfake.NewSimpleClientset()
+	localClient := localfake.NewSimpleClientset()
+	snapClient := volumesnapshotfake.NewSimpleClientset()
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, 0)
+	localStorageInformerFactory := informers.NewSharedInformerFactory(localClient, 0)
+	volumesnapshotInformerFactory := volumesnapshotinformers.NewSharedInformerFactory(snapClient, 0)
+
+	weights := localtype.NewNodeAntiAffinityWeight()
+	for volumeType, weight := range snapshot.NodeAntiAffinityWeights {
+		weights.Put(volumeType, weight)
+	}
+	e := NewExtenderServer(kubeClient, localClient, snapClient,
+		kubeInformerFactory, localStorageInformerFactory, volumesnapshotInformerFactory,
+		0, weights, localtype.DefaultReservationTTL)
+	// the strategy of snapshot is the default strategy of the scheduling context, instead of the
+	// global --scheduler-strategy shared with the other extenders in process
+	if snapshot.SchedulerStrategy != "" {
+		strategy, err := algo.ParseStrategy(string(snapshot.SchedulerStrategy))
+		if err != nil {
+			return nil, err
+		}
+		e.Ctx.SchedulerStrategy = strategy
+	}
+
+	corev1Informers := kubeInformerFactory.Core().V1()
+	snapshotInformers := volumesnapshotInformerFactory.Snapshot().V1beta1()
+	var err error
+	add := func(indexer clientgocache.Indexer, obj interface{}) {
+		if err == nil {
+			err = indexer.Add(obj)
+		}
+	}
+	for i := range snapshot.Nodes {
+		add(corev1Informers.Nodes().Informer().GetIndexer(), &snapshot.Nodes[i])
+	}
+	for i := range snapshot.NodeLocalStorages {
+		add(localStorageInformerFactory.Csi().V1alpha1().NodeLocalStorages().Informer().GetIndexer(), &snapshot.NodeLocalStorages[i])
+	}
+	for i := range snapshot.StorageClasses {
+		add(kubeInformerFactory.Storage().V1().StorageClasses().Informer().GetIndexer(), &snapshot.StorageClasses[i])
+	}
+	for i := range snapshot.PersistentVolumeClaims {
+		add(corev1Informers.PersistentVolumeClaims().Informer().GetIndexer(), &snapshot.PersistentVolumeClaims[i])
+	}
+	for i := range snapshot.PersistentVolumes {
+		add(corev1Informers.PersistentVolumes().Informer().GetIndexer(), &snapshot.PersistentVolumes[i])
+	}
+	for i := range snapshot.Pods {
+		add(corev1Informers.Pods().Informer().GetIndexer(), &snapshot.Pods[i])
+	}
+	for i := range snapshot.VolumeSnapshots {
+		add(snapshotInformers.VolumeSnapshots().Informer().GetIndexer(), &snapshot.VolumeSnapshots[i])
+	}
+	for i := range snapshot.VolumeSnapshotContents {
+		add(snapshotInformers.VolumeSnapshotContents().Informer().GetIndexer(), &snapshot.VolumeSnapshotContents[i])
+	}
+	for i := range snapshot.VolumeSnapshotClasses {
+		add(snapshotInformers.VolumeSnapshotClasses().Informer().GetIndexer(), &snapshot.VolumeSnapshotClasses[i])
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range snapshot.NodeLocalStorages {
+		e.onNodeLocalStorageAdd(&snapshot.NodeLocalStorages[i])
+	}
+	for i := range snapshot.PersistentVolumeClaims {
+		e.onPvcAdd(&snapshot.PersistentVolumeClaims[i])
+	}
+	for i := range snapshot.PersistentVolumes {
+		e.onPVAdd(&snapshot.PersistentVolumes[i])
+	}
+	for i := range snapshot.Pods {
+		e.onPodAdd(&snapshot.Pods[i])
+	}
+	if err := restoreReservations(e.Ctx.ClusterNodeCache, snapshot); err != nil {
+		return nil, err
+	}
+	return &ReplayServer{ExtenderServer: e}, nil
+}
+
+// restoreReservations assumes the reserved units of snapshot into the cache built from the objects,
+// and restores the binding info of pvcs which are not bound yet
+func restoreReservations(clusterNodeCache *cache.ClusterNodeCache, snapshot *Snapshot) error {
+	for _, r := range snapshot.Reservations {
+		units := make([]cache.AllocatedUnit, 0, len(r.Units))
+		for _, unit := range r.Units {
+			units = append(units, *unit)
+		}
+		if err := clusterNodeCache.Reserve(r.PodUID, r.PodName, units, localtype.DefaultReservationTTL); err != nil {
+			return fmt.Errorf("failed to restore reservation of pod %s: %s", r.PodName, err.Error())
+		}
+		reservation := clusterNodeCache.Reservations[r.PodUID]
+		reservation.Committed = append([]string(nil), r.Committed...)
+		reservation.Expiration = r.Expiration
+	}
+	for pvcName, unit := range snapshot.BindingInfo {
+		// the unit may be shared with a reservation, which is updated in place
+		if existing, ok := clusterNodeCache.BindingInfo[pvcName]; ok && existing != nil {
+			*existing = unit
+			continue
+		}
+		u := unit
+		clusterNodeCache.BindingInfo[pvcName] = &u
+	}
+	return nil
+}
+
+// Replay sends the request of call to the predicates or priorities of extender, and returns call with the actual result
+func (e *ReplayServer) Replay(call Call) (Call, error) {
+	result := Call{Verb: call.Verb, Args: call.Args}
+	var err error
+	switch call.Verb {
+	case VerbPredicates:
+		result.FilterResult, err = predicates.NewPredicate(e.Ctx).Handler(call.Args)
+	case VerbPriorities:
+		result.HostPriorityList, err = priorities.NewPrioritize(e.Ctx).Handler(call.Args)
+	default:
+		err = fmt.Errorf("unknown verb %q, must be %s or %s", call.Verb, VerbPredicates, VerbPriorities)
+	}
+	return result, err
+}
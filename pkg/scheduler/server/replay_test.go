@@ -0,0 +1,178 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	nodelocalstorage "github.com/alibaba/open-local/pkg/apis/storage/v1alpha1"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var update = flag.Bool("update", false, "update the expected results of the recordings in testdata/replay")
+
+// TestReplay replays the calls of each recording in testdata/replay, a recording is added for each
+// scheduling incident with the snapshot dumped from /apis/dump
+func TestReplay(t *testing.T) {
+	defaultStrategy := localtype.SchedulerStrategy
+	files, err := filepath.Glob("testdata/replay/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			f, err := os.Open(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			recording, err := ReadRecording(f)
+			f.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			e, err := NewReplayServer(&recording.Snapshot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, call := range recording.Calls {
+				actual, err := e.Replay(call)
+				if err != nil {
+					t.Fatalf("call %d %s: %s", i, call.Verb, err.Error())
+				}
+				if *update {
+					recording.Calls[i] = actual
+					continue
+				}
+				if !reflect.DeepEqual(actual.FilterResult, call.FilterResult) || !reflect.DeepEqual(actual.HostPriorityList, call.HostPriorityList) {
+					t.Errorf("call %d %s: result is %s, expected %s", i, call.Verb, resultJSON(actual), resultJSON(call))
+				}
+			}
+			if localtype.SchedulerStrategy != defaultStrategy {
+				t.Errorf("default strategy is changed to %s by replay", localtype.SchedulerStrategy)
+			}
+			if *update {
+				data, err := json.MarshalIndent(recording, "", "  ")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := ioutil.WriteFile(file, append(data, '\n'), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func resultJSON(call Call) string {
+	var data []byte
+	if call.FilterResult != nil {
+		data, _ = json.Marshal(call.FilterResult)
+	} else {
+		data, _ = json.Marshal(call.HostPriorityList)
+	}
+	return string(data)
+}
+
+func TestDumpSnapshot(t *testing.T) {
+	newPod := func(name string, volumes ...corev1.Volume) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:    "app",
+					Command: []string{"run"},
+					Env:     []corev1.EnvVar{{Name: "PASSWORD", Value: "secret"}},
+				}},
+				Volumes: volumes,
+			},
+		}
+	}
+	unit := cache.AllocatedUnit{NodeName: "node-1", VolumeType: localtype.VolumeTypeLVM, Requested: 10 << 30, Allocated: 10 << 30, VgName: "share", PVCName: "default/data"}
+	snapshot := &Snapshot{
+		SchedulerStrategy:       localtype.StrategyWorstFit,
+		NodeAntiAffinityWeights: map[localtype.VolumeType]int{localtype.VolumeTypeLVM: 3},
+		Nodes: []corev1.Node{
+			{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		},
+		NodeLocalStorages: []nodelocalstorage.NodeLocalStorage{{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status: nodelocalstorage.NodeLocalStorageStatus{
+				NodeStorageInfo:     nodelocalstorage.NodeStorageInfo{VolumeGroups: []nodelocalstorage.VolumeGroup{{Name: "share", Total: 100 << 30, Available: 100 << 30, Allocatable: 100 << 30}}},
+				FilteredStorageInfo: nodelocalstorage.FilteredStorageInfo{VolumeGroups: []string{"share"}},
+			},
+		}},
+		Pods: []corev1.Pod{
+			newPod("with-volume", corev1.Volume{
+				Name:         "data",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}},
+			}),
+			newPod("without-volume"),
+		},
+		BindingInfo:  map[string]cache.AllocatedUnit{"default/data": unit},
+		Reservations: []cache.Reservation{{PodUID: "uid-with-volume", PodName: "default/with-volume", Units: []*cache.AllocatedUnit{&unit}}},
+	}
+	defaultStrategy := localtype.SchedulerStrategy
+	e, err := NewReplayServer(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if localtype.SchedulerStrategy != defaultStrategy {
+		t.Errorf("default strategy is changed to %s by replay server", localtype.SchedulerStrategy)
+	}
+	if requested := e.Ctx.ClusterNodeCache.GetNodeCache("node-1").VGs["share"].Requested; requested != unit.Requested {
+		t.Errorf("requested of vg share is %d, expected the reserved %d", requested, unit.Requested)
+	}
+
+	dumped, err := DumpSnapshot(e.Ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dumped.SchedulerStrategy != snapshot.SchedulerStrategy {
+		t.Errorf("scheduler strategy is %s, expected %s", dumped.SchedulerStrategy, snapshot.SchedulerStrategy)
+	}
+	if !reflect.DeepEqual(dumped.NodeAntiAffinityWeights, snapshot.NodeAntiAffinityWeights) {
+		t.Errorf("node anti affinity weights are %v, expected %v", dumped.NodeAntiAffinityWeights, snapshot.NodeAntiAffinityWeights)
+	}
+	if len(dumped.Nodes) != 2 || dumped.Nodes[0].Name != "node-1" || dumped.Nodes[1].Name != "node-2" {
+		t.Errorf("nodes are %v, expected node-1 and node-2 in order", dumped.Nodes)
+	}
+	if len(dumped.Pods) != 1 || dumped.Pods[0].Name != "with-volume" {
+		t.Fatalf("pods are %v, expected with-volume only", dumped.Pods)
+	}
+	if container := dumped.Pods[0].Spec.Containers[0]; container.Env != nil || container.Command != nil {
+		t.Errorf("env and command of container are %v and %v, expected removed", container.Env, container.Command)
+	}
+	if snapshot.Pods[0].Spec.Containers[0].Env == nil {
+		t.Errorf("env of the pod in informer is removed")
+	}
+	if !reflect.DeepEqual(dumped.BindingInfo, snapshot.BindingInfo) {
+		t.Errorf("binding info is %v, expected %v", dumped.BindingInfo, snapshot.BindingInfo)
+	}
+	if len(dumped.Reservations) != 1 || len(dumped.Reservations[0].Units) != 1 || *dumped.Reservations[0].Units[0] != unit {
+		t.Errorf("reservations are %v, expected the reservation of with-volume", dumped.Reservations)
+	}
+}
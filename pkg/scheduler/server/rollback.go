@@ -0,0 +1,271 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgocache "k8s.io/client-go/tools/cache"
+)
+
+const (
+	recreatePVCTimeout = time.Minute
+	// rolledBackPVCsRetries is the number of attempts to update the configmap of rolled back pvcs on conflict
+	rolledBackPVCsRetries = 5
+)
+
+// scheduledPVCAnnotations are set when the pvc is scheduled and bound, they are removed from the recreated pvc
+var scheduledPVCAnnotations = []string{
+	pkg.AnnoSelectedNode,
+	pkg.AnnAllocatedNode,
+	pkg.AnnAllocatedVG,
+	pkg.AnnAllocatedDevice,
+	pkg.AnnAllocatedMountPoint,
+	"pv.kubernetes.io/bind-completed",
+	"pv.kubernetes.io/bound-by-controller",
+	"volume.beta.kubernetes.io/storage-provisioner",
+}
+
+// rolledBackPVC is a pvc deleted by rollback, which is kept in configmap until it is created again
+type rolledBackPVC struct {
+	// UID is the uid of the deleted pvc, the pvc is created again only after it is gone
+	UID types.UID                     `json:"uid"`
+	PVC *corev1.PersistentVolumeClaim `json:"pvc"`
+}
+
+// RollbackReservation deletes the pvs provisioned for a partially committed reservation by recreating
+// their pvcs, so that all the pvcs of the pod can be scheduled again together, instead of the pod being
+// stuck on a node where the other pvcs do not fit.
+// The pvcs are only recreated when the pod is not scheduled yet, and the pvs are deleted with them.
+func (e *ExtenderServer) RollbackReservation(r *cache.Reservation) {
+	namespace, name, err := clientgocache.SplitMetaNamespaceKey(r.PodName)
+	if err != nil {
+		log.Errorf("invalid pod name %s: %s", r.PodName, err.Error())
+		return
+	}
+	pod, err := e.kubeClient.CoreV1().Pods(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		log.Warningf("skip rolling back pvcs %v of pod %s: %s", r.Committed, r.PodName, err.Error())
+		return
+	}
+	if string(pod.UID) != r.PodUID || pod.Spec.NodeName != "" {
+		log.Warningf("skip rolling back pvcs %v of pod %s, for the pod is recreated or scheduled", r.Committed, r.PodName)
+		return
+	}
+	log.Infof("rolling back pvcs %v of pod %s, which are provisioned while %d pvcs are not", r.Committed, r.PodName, len(r.Units))
+	for _, pvcName := range r.Committed {
+		if err := e.rollbackPVC(pvcName); err != nil {
+			log.Errorf("failed to roll back pvc %s of pod %s: %s", pvcName, r.PodName, err.Error())
+			continue
+		}
+		log.Infof("successfully rolled back pvc %s of pod %s", pvcName, r.PodName)
+	}
+}
+
+// rollbackPVC deletes the pvc with its pv, and creates it again as pending. The pvc is saved in
+// configmap before it is deleted, so that creating it is retried by RecreateRolledBackPVCs if it
+// fails here or extender restarts in the meantime.
+func (e *ExtenderServer) rollbackPVC(pvcName string) error {
+	namespace, name, err := clientgocache.SplitMetaNamespaceKey(pvcName)
+	if err != nil {
+		return err
+	}
+	pvcClient := e.kubeClient.CoreV1().PersistentVolumeClaims(namespace)
+	pvc, err := pvcClient.Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if pvc.Spec.VolumeName != "" {
+		pv, err := e.kubeClient.CoreV1().PersistentVolumes().Get(context.Background(), pvc.Spec.VolumeName, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err == nil && pv.Spec.PersistentVolumeReclaimPolicy != corev1.PersistentVolumeReclaimDelete {
+			return fmt.Errorf("pv %s would not be deleted with reclaim policy %s", pv.Name, pv.Spec.PersistentVolumeReclaimPolicy)
+		}
+	}
+
+	rolledBack := rolledBackPVC{UID: pvc.UID, PVC: newPendingPVC(pvc)}
+	key := rolledBackPVCKey(pvc)
+	if err := e.saveRolledBackPVC(key, rolledBack); err != nil {
+		return err
+	}
+	uid := pvc.UID
+	err = pvcClient.Delete(context.Background(), name, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &uid}})
+	if err != nil && !apierrors.IsNotFound(err) {
+		if err := e.removeRolledBackPVC(key); err != nil {
+			log.Errorf("failed to remove rolled back pvc %s: %s", pvcName, err.Error())
+		}
+		return err
+	}
+	err = wait.PollImmediate(time.Second, recreatePVCTimeout, func() (bool, error) {
+		return e.recreatePVC(key, rolledBack) == nil, nil
+	})
+	if err != nil {
+		return fmt.Errorf("pvc is not created again in %s, will be retried: %s", recreatePVCTimeout, err.Error())
+	}
+	return nil
+}
+
+// RecreateRolledBackPVCs creates the pvcs deleted by rollback again, the pvcs failed to be created
+// are kept in configmap and retried in the next cycle
+func (e *ExtenderServer) RecreateRolledBackPVCs() {
+	pvcs, err := e.listRolledBackPVCs()
+	if err != nil {
+		log.Errorf("failed to list rolled back pvcs: %s", err.Error())
+		return
+	}
+	for key, rolledBack := range pvcs {
+		if err := e.recreatePVC(key, rolledBack); err != nil {
+			log.Warningf("failed to create rolled back pvc %s/%s, will be retried: %s", rolledBack.PVC.Namespace, rolledBack.PVC.Name, err.Error())
+		}
+	}
+}
+
+// recreatePVC creates the rolled back pvc once the deleted one is gone, and removes it from configmap
+func (e *ExtenderServer) recreatePVC(key string, rolledBack rolledBackPVC) error {
+	pvcClient := e.kubeClient.CoreV1().PersistentVolumeClaims(rolledBack.PVC.Namespace)
+	existing, err := pvcClient.Get(context.Background(), rolledBack.PVC.Name, metav1.GetOptions{})
+	switch {
+	case err == nil && existing.UID == rolledBack.UID:
+		return fmt.Errorf("pvc is not deleted yet")
+	case err == nil:
+		// created by a previous attempt or the workload controller
+		log.Infof("rolled back pvc %s/%s is already created", existing.Namespace, existing.Name)
+	case apierrors.IsNotFound(err):
+		if _, err := pvcClient.Create(context.Background(), rolledBack.PVC, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+	default:
+		return err
+	}
+	return e.removeRolledBackPVC(key)
+}
+
+// rolledBackPVCKey is the key of pvc in configmap, which does not allow "/" in keys
+func rolledBackPVCKey(pvc *corev1.PersistentVolumeClaim) string {
+	return pvc.Namespace + "." + pvc.Name
+}
+
+func (e *ExtenderServer) listRolledBackPVCs() (map[string]rolledBackPVC, error) {
+	cm, err := e.kubeClient.CoreV1().ConfigMaps(e.namespace).Get(context.Background(), pkg.RolledBackPVCsConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	pvcs := make(map[string]rolledBackPVC, len(cm.Data))
+	for key, data := range cm.Data {
+		rolledBack := rolledBackPVC{}
+		if err := json.Unmarshal([]byte(data), &rolledBack); err != nil || rolledBack.PVC == nil {
+			log.Errorf("ignore invalid rolled back pvc %s: %v", key, err)
+			continue
+		}
+		pvcs[key] = rolledBack
+	}
+	return pvcs, nil
+}
+
+func (e *ExtenderServer) saveRolledBackPVC(key string, rolledBack rolledBackPVC) error {
+	data, err := json.Marshal(rolledBack)
+	if err != nil {
+		return err
+	}
+	return e.updateRolledBackPVCs(func(cm *corev1.ConfigMap) bool {
+		cm.Data[key] = string(data)
+		return true
+	})
+}
+
+func (e *ExtenderServer) removeRolledBackPVC(key string) error {
+	return e.updateRolledBackPVCs(func(cm *corev1.ConfigMap) bool {
+		if _, ok := cm.Data[key]; !ok {
+			return false
+		}
+		delete(cm.Data, key)
+		return true
+	})
+}
+
+// updateRolledBackPVCs applies mutate to the latest configmap of rolled back pvcs and writes it back,
+// the configmap is created if not found
+func (e *ExtenderServer) updateRolledBackPVCs(mutate func(cm *corev1.ConfigMap) bool) error {
+	cmClient := e.kubeClient.CoreV1().ConfigMaps(e.namespace)
+	var err error
+	for i := 0; i < rolledBackPVCsRetries; i++ {
+		var cm *corev1.ConfigMap
+		exists := true
+		cm, err = cmClient.Get(context.Background(), pkg.RolledBackPVCsConfigMapName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			cm, exists = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: pkg.RolledBackPVCsConfigMapName, Namespace: e.namespace}}, false
+		} else if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		if !mutate(cm) {
+			return nil
+		}
+		if exists {
+			_, err = cmClient.Update(context.Background(), cm, metav1.UpdateOptions{})
+		} else {
+			_, err = cmClient.Create(context.Background(), cm, metav1.CreateOptions{})
+		}
+		if err == nil || !(apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)) {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update configmap %s/%s: %s", e.namespace, pkg.RolledBackPVCsConfigMapName, err.Error())
+	}
+	return nil
+}
+
+// newPendingPVC returns a copy of pvc to be created again, which is neither scheduled nor bound
+func newPendingPVC(pvc *corev1.PersistentVolumeClaim) *corev1.PersistentVolumeClaim {
+	recreated := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            pvc.Name,
+			Namespace:       pvc.Namespace,
+			Labels:          pvc.Labels,
+			Annotations:     make(map[string]string, len(pvc.Annotations)),
+			OwnerReferences: pvc.OwnerReferences,
+		},
+		Spec: *pvc.Spec.DeepCopy(),
+	}
+	recreated.Spec.VolumeName = ""
+	for key, value := range pvc.Annotations {
+		recreated.Annotations[key] = value
+	}
+	for _, key := range scheduledPVCAnnotations {
+		delete(recreated.Annotations, key)
+	}
+	return recreated
+}
@@ -0,0 +1,188 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alibaba/open-local/pkg"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/cache"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRollbackReservation(t *testing.T) {
+	newPVC := func(name, volumeName string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				UID:       types.UID("uid-" + name),
+				Labels:    map[string]string{"app": "test"},
+				Annotations: map[string]string{
+					pkg.AnnoSelectedNode:              "testnode",
+					pkg.AnnAllocatedNode:              "testnode",
+					"pv.kubernetes.io/bind-completed": "yes",
+					"custom":                          "value",
+				},
+			},
+			Spec:   corev1.PersistentVolumeClaimSpec{VolumeName: volumeName},
+			Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+		}
+	}
+	newPV := func(name string, policy corev1.PersistentVolumeReclaimPolicy) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.PersistentVolumeSpec{PersistentVolumeReclaimPolicy: policy},
+		}
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default", UID: "uid-pod"}}
+
+	tests := []struct {
+		name      string
+		nodeName  string
+		policy    corev1.PersistentVolumeReclaimPolicy
+		recreated bool
+	}{
+		{name: "pvc of unscheduled pod is recreated", policy: corev1.PersistentVolumeReclaimDelete, recreated: true},
+		{name: "pvc of scheduled pod is kept", nodeName: "testnode", policy: corev1.PersistentVolumeReclaimDelete},
+		{name: "pvc with retained pv is kept", policy: corev1.PersistentVolumeReclaimRetain},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := pod.DeepCopy()
+			p.Spec.NodeName = test.nodeName
+			kubeClient := k8sfake.NewSimpleClientset(p, newPVC("pvc-1", "pv-1"), newPV("pv-1", test.policy))
+			e := &ExtenderServer{kubeClient: kubeClient, namespace: pkg.DefaultNamespace}
+			e.RollbackReservation(&cache.Reservation{
+				PodUID:    string(pod.UID),
+				PodName:   "default/pod",
+				Units:     []*cache.AllocatedUnit{{PVCName: "default/pvc-2"}},
+				Committed: []string{"default/pvc-1"},
+			})
+
+			pvc, err := kubeClient.CoreV1().PersistentVolumeClaims("default").Get(context.Background(), "pvc-1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if recreated := pvc.UID != "uid-pvc-1"; recreated != test.recreated {
+				t.Fatalf("pvc recreated: %t, expected %t", recreated, test.recreated)
+			}
+			if !test.recreated {
+				return
+			}
+			if pvc.Spec.VolumeName != "" {
+				t.Errorf("volume name of recreated pvc is %s, expected empty", pvc.Spec.VolumeName)
+			}
+			for _, key := range []string{pkg.AnnoSelectedNode, pkg.AnnAllocatedNode, "pv.kubernetes.io/bind-completed"} {
+				if _, ok := pvc.Annotations[key]; ok {
+					t.Errorf("annotation %s is not removed from recreated pvc", key)
+				}
+			}
+			if pvc.Annotations["custom"] != "value" || pvc.Labels["app"] != "test" {
+				t.Errorf("metadata of recreated pvc is %+v, expected labels and annotations kept", pvc.ObjectMeta)
+			}
+			if pvcs, err := e.listRolledBackPVCs(); err != nil || len(pvcs) != 0 {
+				t.Errorf("rolled back pvcs are %v, %v, expected removed after recreated", pvcs, err)
+			}
+		})
+	}
+}
+
+func TestRecreateRolledBackPVCs(t *testing.T) {
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc-1", Namespace: "default", UID: "uid-pvc-1"},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pv-1"},
+	}
+	kubeClient := k8sfake.NewSimpleClientset()
+	createFails := true
+	kubeClient.PrependReactor("create", "persistentvolumeclaims", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if createFails {
+			return true, nil, fmt.Errorf("denied by webhook")
+		}
+		return false, nil, nil
+	})
+	e := &ExtenderServer{kubeClient: kubeClient, namespace: pkg.DefaultNamespace}
+	// the pvc is deleted by rollback while it is not created again, e.g. extender restarts
+	if err := e.saveRolledBackPVC(rolledBackPVCKey(pvc), rolledBackPVC{UID: pvc.UID, PVC: newPendingPVC(pvc)}); err != nil {
+		t.Fatal(err)
+	}
+
+	e.RecreateRolledBackPVCs()
+	if pvcs, err := e.listRolledBackPVCs(); err != nil || len(pvcs) != 1 {
+		t.Fatalf("rolled back pvcs are %v, %v, expected kept when creating fails", pvcs, err)
+	}
+
+	createFails = false
+	e.RecreateRolledBackPVCs()
+	recreated, err := kubeClient.CoreV1().PersistentVolumeClaims("default").Get(context.Background(), "pvc-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("pvc is not created again: %s", err.Error())
+	}
+	if recreated.Spec.VolumeName != "" {
+		t.Errorf("volume name of recreated pvc is %s, expected empty", recreated.Spec.VolumeName)
+	}
+	if pvcs, err := e.listRolledBackPVCs(); err != nil || len(pvcs) != 0 {
+		t.Errorf("rolled back pvcs are %v, %v, expected removed after recreated", pvcs, err)
+	}
+}
+
+func TestRollbackFromPVEvents(t *testing.T) {
+	e := newTestExtender(t)
+	size := int64(10 << 30)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default", UID: "uid-pod"}}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default", UID: "uid-data"},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "local-data"},
+	}
+	pv := newTestLVMPV("data", size)
+	for _, obj := range []runtime.Object{pod, pvc, pv} {
+		if err := e.kubeClient.(*k8sfake.Clientset).Tracker().Add(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the reservation expires before the pv of log is created
+	units := []cache.AllocatedUnit{newTestLVMUnit("data", size), newTestLVMUnit("log", size)}
+	if err := e.Ctx.ClusterNodeCache.Reserve(string(pod.UID), "default/pod", units, -time.Second); err != nil {
+		t.Fatal(err)
+	}
+	provisionTestPV(e, pv)
+	r := e.Ctx.ClusterNodeCache.Reservations[string(pod.UID)]
+	if r == nil || !r.IsPartiallyCommitted() {
+		t.Fatalf("reservation is %+v, expected partially committed by the pv of data", r)
+	}
+
+	e.CleanExpiredReservations()
+	err := wait.PollImmediate(10*time.Millisecond, time.Second, func() (bool, error) {
+		recreated, err := e.kubeClient.CoreV1().PersistentVolumeClaims("default").Get(context.Background(), "data", metav1.GetOptions{})
+		return err == nil && recreated.UID != pvc.UID, nil
+	})
+	if err != nil {
+		t.Errorf("pvc data is not recreated after the reservation expires: %s", err.Error())
+	}
+	if requested := e.Ctx.ClusterNodeCache.GetNodeCache("node-1").VGs["share"].Requested; requested != size {
+		t.Errorf("requested of vg share is %d, expected the pv of data %d", requested, size)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
 	"strconv"
 	"time"
 
@@ -29,8 +30,11 @@ import (
 	informers "github.com/alibaba/open-local/pkg/generated/informers/externalversions"
 	"github.com/alibaba/open-local/pkg/metrics"
 	"github.com/alibaba/open-local/pkg/scheduler/algorithm"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/bind"
 	"github.com/alibaba/open-local/pkg/scheduler/algorithm/predicates"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/preemptions"
 	"github.com/alibaba/open-local/pkg/scheduler/algorithm/priorities"
+	"github.com/alibaba/open-local/pkg/scheduler/algorithm/simulate"
 	"github.com/julienschmidt/httprouter"
 	volumesnapshot "github.com/kubernetes-csi/external-snapshotter/client/v4/clientset/versioned"
 	volumesnapshotinformers "github.com/kubernetes-csi/external-snapshotter/client/v4/informers/externalversions"
@@ -40,6 +44,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/wait"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	clientgocache "k8s.io/client-go/tools/cache"
@@ -51,13 +56,16 @@ func NewExtenderServer(kubeClient kubernetes.Interface,
 	kubeInformerFactory kubeinformers.SharedInformerFactory,
 	localStorageInformerFactory informers.SharedInformerFactory,
 	volumesnapshotInformerFactory volumesnapshotinformers.SharedInformerFactory,
-	port int32, weights *pkg.NodeAntiAffinityWeight) *ExtenderServer {
+	port int32, weights *pkg.NodeAntiAffinityWeight, reservationTTL time.Duration) *ExtenderServer {
 	corev1Informers := kubeInformerFactory.Core().V1()
 	storagev1Informers := kubeInformerFactory.Storage().V1()
 	localStorageInformers := localStorageInformerFactory.Csi().V1alpha1()
 	snapshotInformers := volumesnapshotInformerFactory.Snapshot().V1beta1()
 
 	Ctx := algorithm.NewSchedulingContext(corev1Informers, storagev1Informers, localStorageInformers, snapshotInformers, weights)
+	if reservationTTL > 0 {
+		Ctx.ReservationTTL = reservationTTL
+	}
 
 	informersSyncd := make([]clientgocache.InformerSynced, 0)
 
@@ -100,6 +108,10 @@ func NewExtenderServer(kubeClient kubernetes.Interface,
 	snapStorageClassInformer := snapshotInformers.VolumeSnapshotClasses().Informer()
 	informersSyncd = append(informersSyncd, snapStorageClassInformer.HasSynced)
 
+	namespace := os.Getenv(pkg.EnvNamespace)
+	if namespace == "" {
+		namespace = pkg.DefaultNamespace
+	}
 	e := &ExtenderServer{
 		kubeClient:         kubeClient,
 		localStorageClient: localclient,
@@ -107,6 +119,7 @@ func NewExtenderServer(kubeClient kubernetes.Interface,
 		port:               port,
 		Ctx:                Ctx,
 		informersSynced:    informersSyncd,
+		namespace:          namespace,
 	}
 	localInformer.AddEventHandler(clientgocache.ResourceEventHandlerFuncs{
 		AddFunc:    e.onNodeLocalStorageAdd,
@@ -141,6 +154,8 @@ type ExtenderServer struct {
 	port                   int32
 	informersSynced        []clientgocache.InformerSynced
 	currentWorkingRoutines int32
+	// namespace keeps the configmap of rolled back pvcs
+	namespace string
 }
 
 func (e *ExtenderServer) Start(stopCh <-chan struct{}) {
@@ -150,6 +165,8 @@ func (e *ExtenderServer) Start(stopCh <-chan struct{}) {
 	log.Infof("maxConcurrentWorkingRoutines was set to %d", MaxConcurrentWorkingRoutines)
 	log.Info("started open-local scheduler extender")
 	go e.TriggerPendingPodReschedule(stopCh)
+	go wait.Until(e.CleanExpiredReservations, pkg.CleanExpiredReservationCycle, stopCh)
+	go wait.Until(e.RecreateRolledBackPVCs, pkg.CleanExpiredReservationCycle, stopCh)
 	<-stopCh
 	log.Info("Shutting down open-local scheduler extender")
 }
@@ -177,7 +194,11 @@ func (e *ExtenderServer) InitRouter() {
 	AddGetNodeCache(router, e.Ctx)
 	AddPredicate(router, *predicates.NewPredicate(e.Ctx))
 	AddPrioritize(router, *priorities.NewPrioritize(e.Ctx))
-	AddSchedulingApis(router, e.Ctx)
+	AddPreemption(router, *preemptions.NewPreemption(e.Ctx))
+	AddBind(router, *bind.NewBind(e.Ctx, e.kubeClient))
+	AddSchedulingApis(router, e.Ctx, e.kubeClient)
+	AddSimulate(router, *simulate.NewSimulate(e.Ctx))
+	AddDump(router, e.Ctx)
 
 	go func() {
 		if e.port > 0 {
@@ -202,6 +223,30 @@ func (e *ExtenderServer) WaitForCacheSync(stopCh <-chan struct{}) {
 	log.Info("all informer caches are synced")
 }
 
+// HasSynced returns true if all informers of extender are synced
+func (e *ExtenderServer) HasSynced() bool {
+	for _, synced := range e.informersSynced {
+		if !synced() {
+			return false
+		}
+	}
+	return true
+}
+
+// CleanExpiredReservations releases the local storage reserved for pods whose pvs are not created in time
+// and rolls back the pvs of pods which are partially provisioned
+func (e *ExtenderServer) CleanExpiredReservations() {
+	e.Ctx.CtxLock.Lock()
+	expired := e.Ctx.ClusterNodeCache.CleanExpiredReservations(time.Now())
+	e.Ctx.CtxLock.Unlock()
+	for _, r := range expired {
+		log.Warningf("reservation of pod %s expired after %s, released", r.PodName, e.Ctx.ReservationTTL)
+		if r.IsPartiallyCommitted() {
+			go e.RollbackReservation(r)
+		}
+	}
+}
+
 func (e *ExtenderServer) TriggerPendingPodReschedule(stopCh <-chan struct{}) {
 	ticker := time.NewTicker(pkg.TriggerPendingPodCycle)
 	// set ResourceVersion to 0
@@ -32,8 +32,13 @@ const (
 	Mi          uint64 = 1024 * 1024
 	DefaultPort int32  = 23000
 
-	StrategyBinpack StrategyType = "binpack"
-	StrategySpread  StrategyType = "spread"
+	StrategyBinpack            StrategyType = "binpack"
+	StrategySpread             StrategyType = "spread"
+	StrategyBestFit            StrategyType = "best-fit"
+	StrategyWorstFit           StrategyType = "worst-fit"
+	StrategyMediaAware         StrategyType = "media-aware"
+	StrategyLeastFragmentation StrategyType = "least-fragmentation"
+	StrategyRoundRobin         StrategyType = "round-robin"
 
 	AgentName           string = "open-local-agent"
 	ProvisionerNameYoda string = "yodaplugin.csi.alibabacloud.com"
@@ -53,16 +58,35 @@ const (
 	VolumeTypeKey             = "volumeType"
 	VolumeFSTypeKey           = "fsType"
 	VolumeMediaType           = "mediaType"
+	VolumePerformanceTier     = "performanceTier"
+	VolumeSchedulerStrategy   = "schedulerStrategy"
 	VolumeFSTypeExt4          = "ext4"
 	VolumeFSTypeExt3          = "ext3"
 	VolumeFSTypeXFS           = "xfs"
 	VolumeIOPS                = "iops"
 	VolumeBPS                 = "bps"
-
-	BPSReadFile   = "blkio.throttle.read_bps_device"
-	BPSWriteFile  = "blkio.throttle.write_bps_device"
-	IOPSReadFile  = "blkio.throttle.read_iops_device"
-	IOPSWriteFile = "blkio.throttle.write_iops_device"
+	VolumeReadIOPS            = "readIOPS"
+	VolumeWriteIOPS           = "writeIOPS"
+	VolumeReadBPS             = "readBPS"
+	VolumeWriteBPS            = "writeBPS"
+	VolumeLVMType             = "lvmType"
+	LVMTypeThin               = "thin"
+
+	// AnnIOPS and the others are set on pvc to update the io limits of bound volume, which
+	// override the parameters of storageclass
+	AnnIOPS      = "csi.aliyun.com/iops"
+	AnnBPS       = "csi.aliyun.com/bps"
+	AnnReadIOPS  = "csi.aliyun.com/read-iops"
+	AnnWriteIOPS = "csi.aliyun.com/write-iops"
+	AnnReadBPS   = "csi.aliyun.com/read-bps"
+	AnnWriteBPS  = "csi.aliyun.com/write-bps"
+
+	// AnnAllocatedNode and the others are set on pvc by scheduler before the pvc is provisioned,
+	// they record the storage allocated for the pvc, which is used if the scheduler restarts
+	AnnAllocatedNode       = "csi.aliyun.com/allocated-node"
+	AnnAllocatedVG         = "csi.aliyun.com/allocated-vg"
+	AnnAllocatedDevice     = "csi.aliyun.com/allocated-device"
+	AnnAllocatedMountPoint = "csi.aliyun.com/allocated-mount-point"
 
 	PVCName      = "csi.storage.k8s.io/pvc/name"
 	PVCNameSpace = "csi.storage.k8s.io/pvc/namespace"
@@ -88,6 +112,14 @@ const (
 	EnvForceCreateVG                     = "Force_Create_VG"
 	PendingWithoutScheduledFieldSelector = "status.phase=Pending,spec.nodeName="
 	TriggerPendingPodCycle               = time.Second * 300
+	DefaultReservationTTL                = time.Minute * 10
+	CleanExpiredReservationCycle         = time.Second * 30
+	// RolledBackPVCsConfigMapName is the configmap of scheduler extender keeping the pvcs deleted by
+	// rollback until they are created again
+	RolledBackPVCsConfigMapName = "open-local-rolled-back-pvcs"
+	// EnvNamespace is the namespace of the pod of scheduler extender, DefaultNamespace is used if not set
+	EnvNamespace     = "NAMESPACE"
+	DefaultNamespace = "kube-system"
 
 	ParamSnapshotName            = "yoda.io/snapshot-name"
 	ParamSnapshotReadonly        = "csi.aliyun.com/readonly"
@@ -101,36 +133,28 @@ const (
 	DefaultSnapshotInitialSize   = 4 * 1024 * 1024 * 1024
 	DefaultSnapshotThreshold     = 0.5
 	DefaultSnapshotExpansionSize = 1 * 1024 * 1024 * 1024
-
-	Separator = "<:SEP:>"
-
-	// lv tags
-	Lvm2LVNameTag        = "LVM2_LV_NAME"
-	Lvm2LVSizeTag        = "LVM2_LV_SIZE"
-	Lvm2LVKernelMajorTag = "LVM2_LV_KERNEL_MAJOR"
-	Lvm2LVKernelMinorTag = "LVM2_LV_KERNEL_MINOR"
-	Lvm2LVAttrTag        = "LVM2_LV_ATTR"
-	Lvm2LVUuidTag        = "LVM2_LV_UUID"
-	Lvm2CopyPercentTag   = "LVM2_COPY_PERCENT"
-	Lvm2LVTagsTag        = "LVM2_LV_TAGS"
-
-	// vg tags
-	Lvm2VGNameTag  = "LVM2_VG_NAME"
-	Lvm2VGSizeTag  = "LVM2_VG_SIZE"
-	Lvm2VGFreeTag  = "LVM2_VG_FREE"
-	Lvm2VGUuidTag  = "LVM2_VG_UUID"
-	Lvm2VGTagsTag  = "LVM2_VG_TAGS"
-	Lvm2PVCountTag = "LVM2_PV_COUNT"
-
-	// pv tags
-	Lvm2PVUuidTag = "LVM2_PV_UUID"
-	Lvm2PVNameTag = "LVM2_PV_NAME"
-	Lvm2PVSizeTag = "LVM2_PV_SIZE"
-	Lvm2PVFreeTag = "LVM2_PV_FREE"
-	Lvm2PVTagsTag = "LVM2_PV_TAGS"
+	DefaultThinPoolPercent       = 100
+	DefaultThinPoolOvercommit    = 1.0
+
+	// ParamBackupEndpoint is set in volumesnapshotclass to upload snapshots to the S3 compatible store,
+	// whose credential is in the secret of csi.storage.k8s.io/snapshotter-secret-name
+	ParamBackupEndpoint = "csi.aliyun.com/backup-endpoint"
+	ParamBackupRegion   = "csi.aliyun.com/backup-region"
+	ParamBackupBucket   = "csi.aliyun.com/backup-bucket"
+	// AnnBackupLocation is set on volumesnapshotcontent when the snapshot is backed up
+	AnnBackupLocation               = "csi.aliyun.com/backup-location"
+	BackupAccessKeyID               = "accessKeyID"
+	BackupSecretAccessKey           = "secretAccessKey"
+	ParamSnapshotterSecretName      = "csi.storage.k8s.io/snapshotter-secret-name"
+	ParamSnapshotterSecretNamespace = "csi.storage.k8s.io/snapshotter-secret-namespace"
 
 	// EVENT
-	EventCreateVGFailed = "CreateVGFailed"
+	EventCreateVGFailed       = "CreateVGFailed"
+	EventCreateThinPoolFailed = "CreateThinPoolFailed"
+	EventBenchmarkFailed      = "BenchmarkFailed"
+	EventDiskFault            = "DiskFault"
+	EventDiskRecovered        = "DiskRecovered"
+	EventDiskDegraded         = "DiskDegraded"
 
 	NsenterCmd = "/bin/nsenter --mount=/proc/1/ns/mnt --ipc=/proc/1/ns/ipc --net=/proc/1/ns/net --uts=/proc/1/ns/uts "
 )
@@ -146,7 +170,8 @@ var (
 		VolumeTypeDevice,
 		VolumeTypeQuota,
 	}
-	SupportedFS                    = []string{VolumeFSTypeExt3, VolumeFSTypeExt4, VolumeFSTypeXFS}
+	SupportedFS = []string{VolumeFSTypeExt3, VolumeFSTypeExt4, VolumeFSTypeXFS}
+	// SchedulerStrategy is the strategy of volumes whose storage class does not set schedulerStrategy
 	SchedulerStrategy StrategyType = StrategyBinpack
 )
 
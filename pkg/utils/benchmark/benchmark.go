@@ -0,0 +1,227 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const (
+	// RandomBlockSize is the block size of iops and latency probes
+	RandomBlockSize int64 = 4 << 10
+	// SequentialBlockSize is the block size of throughput probes
+	SequentialBlockSize int64 = 1 << 20
+	// alignment of buffers required by direct io
+	alignment = 4096
+)
+
+// openFlags bypasses the page cache, and fails with EBUSY if the block device
+// is mounted or held by device mapper
+var openFlags = syscall.O_DIRECT | syscall.O_EXCL
+
+// Options bounds a benchmark
+type Options struct {
+	// Duration is the time of each probe
+	Duration time.Duration
+	// Size is the size of the region at the beginning of the device to probe
+	Size int64
+	// Concurrency is the number of outstanding ios
+	Concurrency int
+	// Write enables the write probes, which destroy the data in the region
+	Write bool
+}
+
+// Latency is the percentiles of io latency
+type Latency struct {
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+}
+
+// Result is the result of a benchmark, write fields are zero if write probes are disabled
+type Result struct {
+	ReadIOPS     uint64
+	WriteIOPS    uint64
+	ReadBPS      uint64
+	WriteBPS     uint64
+	ReadLatency  Latency
+	WriteLatency Latency
+}
+
+// Run probes random reads of RandomBlockSize and sequential reads of SequentialBlockSize
+// on path, followed by writes of the same patterns if enabled
+func Run(path string, opts Options) (*Result, error) {
+	if opts.Duration <= 0 || opts.Concurrency <= 0 {
+		return nil, fmt.Errorf("invalid benchmark options %+v", opts)
+	}
+	flags := os.O_RDONLY
+	if opts.Write {
+		flags = os.O_RDWR
+	}
+	f, err := os.OpenFile(path, flags|openFlags, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	size := opts.Size
+	end, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if end < size {
+		size = end
+	}
+	size -= size % SequentialBlockSize
+	if size <= 0 {
+		return nil, fmt.Errorf("%s is smaller than %d bytes", path, SequentialBlockSize)
+	}
+
+	result := &Result{}
+	p, err := run(f, size, RandomBlockSize, true, false, opts)
+	if err != nil {
+		return nil, fmt.Errorf("random read of %s failed: %s", path, err.Error())
+	}
+	result.ReadIOPS, result.ReadLatency = p.iops(), p.latency()
+	if p, err = run(f, size, SequentialBlockSize, false, false, opts); err != nil {
+		return nil, fmt.Errorf("sequential read of %s failed: %s", path, err.Error())
+	}
+	result.ReadBPS = p.bps()
+	if !opts.Write {
+		return result, nil
+	}
+	if p, err = run(f, size, RandomBlockSize, true, true, opts); err != nil {
+		return nil, fmt.Errorf("random write of %s failed: %s", path, err.Error())
+	}
+	result.WriteIOPS, result.WriteLatency = p.iops(), p.latency()
+	if p, err = run(f, size, SequentialBlockSize, false, true, opts); err != nil {
+		return nil, fmt.Errorf("sequential write of %s failed: %s", path, err.Error())
+	}
+	result.WriteBPS = p.bps()
+	return result, nil
+}
+
+type probe struct {
+	blockSize int64
+	elapsed   time.Duration
+	latencies []time.Duration
+}
+
+// run issues ios of blockSize to the first size bytes of f by opts.Concurrency workers until opts.Duration passes
+func run(f *os.File, size, blockSize int64, random, write bool, opts Options) (*probe, error) {
+	blocks := size / blockSize
+	next := int64(-1)
+	workers := make([]probe, opts.Concurrency)
+	errs := make(chan error, opts.Concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+	deadline := start.Add(opts.Duration)
+	for i := range workers {
+		wg.Add(1)
+		go func(w *probe, seed int64) {
+			defer wg.Done()
+			buf := alignedBuffer(blockSize)
+			rnd := rand.New(rand.NewSource(seed))
+			if write {
+				rnd.Read(buf)
+			}
+			for time.Now().Before(deadline) {
+				var block int64
+				if random {
+					block = rnd.Int63n(blocks)
+				} else {
+					block = atomic.AddInt64(&next, 1) % blocks
+				}
+				begin := time.Now()
+				var err error
+				if write {
+					_, err = f.WriteAt(buf, block*blockSize)
+				} else {
+					_, err = f.ReadAt(buf, block*blockSize)
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+				w.latencies = append(w.latencies, time.Since(begin))
+			}
+		}(&workers[i], start.UnixNano()+int64(i))
+	}
+	wg.Wait()
+	// flush the volatile cache of device, so that the writes are not faster than the media
+	if write {
+		if err := f.Sync(); err != nil {
+			return nil, err
+		}
+	}
+	elapsed := time.Since(start)
+	select {
+	case err := <-errs:
+		return nil, err
+	default:
+	}
+
+	p := &probe{blockSize: blockSize, elapsed: elapsed}
+	for _, w := range workers {
+		p.latencies = append(p.latencies, w.latencies...)
+	}
+	sort.Slice(p.latencies, func(i, j int) bool { return p.latencies[i] < p.latencies[j] })
+	return p, nil
+}
+
+func (p *probe) iops() uint64 {
+	return uint64(float64(len(p.latencies)) / p.elapsed.Seconds())
+}
+
+func (p *probe) bps() uint64 {
+	return uint64(float64(int64(len(p.latencies))*p.blockSize) / p.elapsed.Seconds())
+}
+
+// latency returns the percentiles of sorted latencies
+func (p *probe) latency() Latency {
+	return Latency{
+		P50: percentile(p.latencies, 0.50),
+		P90: percentile(p.latencies, 0.90),
+		P99: percentile(p.latencies, 0.99),
+	}
+}
+
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[int(float64(len(sorted)-1)*q)]
+}
+
+// alignedBuffer returns a buffer of size whose address is aligned for direct io
+func alignedBuffer(size int64) []byte {
+	buf := make([]byte, size+alignment)
+	offset := 0
+	if remainder := int(uintptr(unsafe.Pointer(&buf[0])) & (alignment - 1)); remainder != 0 {
+		offset = alignment - remainder
+	}
+	return buf[offset : offset+int(size)]
+}
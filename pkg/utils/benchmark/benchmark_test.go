@@ -0,0 +1,78 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	// direct io is not supported by tmpfs
+	openFlags = 0
+	f, err := ioutil.TempFile("", "benchmark")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if err := f.Truncate(4 * SequentialBlockSize); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{
+			name: "read only",
+			opts: Options{Duration: 50 * time.Millisecond, Size: 1 << 30, Concurrency: 2},
+		},
+		{
+			name: "read and write",
+			opts: Options{Duration: 50 * time.Millisecond, Size: 2 * SequentialBlockSize, Concurrency: 2, Write: true},
+		},
+		{
+			name:    "region smaller than a block",
+			opts:    Options{Duration: 50 * time.Millisecond, Size: RandomBlockSize, Concurrency: 2},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := Run(f.Name(), test.opts)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got result %+v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run() error: %s", err.Error())
+			}
+			if result.ReadIOPS == 0 || result.ReadBPS == 0 || result.ReadLatency.P99 < result.ReadLatency.P50 {
+				t.Errorf("unexpected read result %+v", result)
+			}
+			if test.opts.Write != (result.WriteIOPS > 0 && result.WriteBPS > 0) {
+				t.Errorf("unexpected write result %+v", result)
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	"golang.org/x/sys/unix"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Version is the version of cgroup hierarchy
+type Version int
+
+const (
+	V1 Version = 1
+	V2 Version = 2
+)
+
+// Driver is the cgroup driver of kubelet
+type Driver string
+
+const (
+	DriverSystemd  Driver = "systemd"
+	DriverCgroupfs Driver = "cgroupfs"
+)
+
+const (
+	// v1 blkio throttle files
+	readBPSFile   = "blkio.throttle.read_bps_device"
+	writeBPSFile  = "blkio.throttle.write_bps_device"
+	readIOPSFile  = "blkio.throttle.read_iops_device"
+	writeIOPSFile = "blkio.throttle.write_iops_device"
+	// v2 io controller file
+	ioMaxFile = "io.max"
+)
+
+// Limits is the io limits of a device, zero means unlimited
+type Limits struct {
+	ReadBPS   uint64
+	WriteBPS  uint64
+	ReadIOPS  uint64
+	WriteIOPS uint64
+}
+
+// IsZero checks whether no limit is set
+func (l Limits) IsZero() bool {
+	return l == Limits{}
+}
+
+// LimitKeys are the keys of io limits in parameters or annotations. IOPS and BPS
+// limit both read and write, and are overridden by the others.
+type LimitKeys struct {
+	IOPS      string
+	BPS       string
+	ReadIOPS  string
+	WriteIOPS string
+	ReadBPS   string
+	WriteBPS  string
+}
+
+var (
+	// VolumeLimitKeys are the keys of io limits in the parameters of storageclass
+	VolumeLimitKeys = LimitKeys{
+		IOPS:      localtype.VolumeIOPS,
+		BPS:       localtype.VolumeBPS,
+		ReadIOPS:  localtype.VolumeReadIOPS,
+		WriteIOPS: localtype.VolumeWriteIOPS,
+		ReadBPS:   localtype.VolumeReadBPS,
+		WriteBPS:  localtype.VolumeWriteBPS,
+	}
+	// AnnotationLimitKeys are the keys of io limits in the annotations of pvc
+	AnnotationLimitKeys = LimitKeys{
+		IOPS:      localtype.AnnIOPS,
+		BPS:       localtype.AnnBPS,
+		ReadIOPS:  localtype.AnnReadIOPS,
+		WriteIOPS: localtype.AnnWriteIOPS,
+		ReadBPS:   localtype.AnnReadBPS,
+		WriteBPS:  localtype.AnnWriteBPS,
+	}
+)
+
+// ParseLimits overrides limits with the values of keys, which are quantities like "100Mi"
+func ParseLimits(values map[string]string, keys LimitKeys, limits *Limits) error {
+	for _, item := range []struct {
+		key    string
+		fields []*uint64
+	}{
+		{keys.IOPS, []*uint64{&limits.ReadIOPS, &limits.WriteIOPS}},
+		{keys.BPS, []*uint64{&limits.ReadBPS, &limits.WriteBPS}},
+		{keys.ReadIOPS, []*uint64{&limits.ReadIOPS}},
+		{keys.WriteIOPS, []*uint64{&limits.WriteIOPS}},
+		{keys.ReadBPS, []*uint64{&limits.ReadBPS}},
+		{keys.WriteBPS, []*uint64{&limits.WriteBPS}},
+	} {
+		str, exist := values[item.key]
+		if item.key == "" || !exist || str == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(str)
+		if err != nil || quantity.Sign() < 0 {
+			return fmt.Errorf("invalid %s %q", item.key, str)
+		}
+		for _, field := range item.fields {
+			*field = uint64(quantity.Value())
+		}
+	}
+	return nil
+}
+
+// HasLimits checks whether any key of io limits is in values
+func HasLimits(values map[string]string, keys LimitKeys) bool {
+	for _, key := range []string{keys.IOPS, keys.BPS, keys.ReadIOPS, keys.WriteIOPS, keys.ReadBPS, keys.WriteBPS} {
+		if _, exist := values[key]; exist && key != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// String formats the limits which are set
+func (l Limits) String() string {
+	var fields []string
+	for _, item := range []struct {
+		name  string
+		value uint64
+	}{
+		{"readIOPS", l.ReadIOPS}, {"writeIOPS", l.WriteIOPS}, {"readBPS", l.ReadBPS}, {"writeBPS", l.WriteBPS},
+	} {
+		if item.value != 0 {
+			fields = append(fields, fmt.Sprintf("%s=%d", item.name, item.value))
+		}
+	}
+	if len(fields) == 0 {
+		return "unlimited"
+	}
+	return strings.Join(fields, " ")
+}
+
+// DeviceNumber returns the major and minor number of block device
+func DeviceNumber(device string) (uint32, uint32, error) {
+	stat := unix.Stat_t{}
+	if err := unix.Stat(device, &stat); err != nil {
+		return 0, 0, fmt.Errorf("stat %s: %s", device, err.Error())
+	}
+	if stat.Mode&unix.S_IFMT != unix.S_IFBLK {
+		return 0, 0, fmt.Errorf("%s is not block device", device)
+	}
+	return unix.Major(uint64(stat.Rdev)), unix.Minor(uint64(stat.Rdev)), nil
+}
+
+// Manager writes io limits to the cgroups of pods
+type Manager struct {
+	// Root is the mountpoint of cgroup filesystem, e.g. /sys/fs/cgroup
+	Root    string
+	Version Version
+	Driver  Driver
+}
+
+// Detect detects the cgroup version and the driver used by kubelet under root
+func Detect(root string) (*Manager, error) {
+	m := &Manager{Root: root, Version: V1}
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err == nil {
+		m.Version = V2
+	}
+	for _, driver := range []Driver{DriverSystemd, DriverCgroupfs} {
+		m.Driver = driver
+		if _, err := os.Stat(m.kubepods()); err == nil {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("no kubepods cgroup found under %s", root)
+}
+
+// kubepods returns the cgroup of all pods
+func (m *Manager) kubepods() string {
+	root := m.Root
+	if m.Version == V1 {
+		root = filepath.Join(root, "blkio")
+	}
+	if m.Driver == DriverSystemd {
+		return filepath.Join(root, "kubepods.slice")
+	}
+	return filepath.Join(root, "kubepods")
+}
+
+// podPaths returns the cgroups of pod in all qos classes
+func (m *Manager) podPaths(podUID string) []string {
+	kubepods := m.kubepods()
+	if m.Driver == DriverSystemd {
+		uid := strings.Replace(podUID, "-", "_", -1)
+		return []string{
+			filepath.Join(kubepods, fmt.Sprintf("kubepods-pod%s.slice", uid)),
+			filepath.Join(kubepods, "kubepods-burstable.slice", fmt.Sprintf("kubepods-burstable-pod%s.slice", uid)),
+			filepath.Join(kubepods, "kubepods-besteffort.slice", fmt.Sprintf("kubepods-besteffort-pod%s.slice", uid)),
+		}
+	}
+	return []string{
+		filepath.Join(kubepods, "pod"+podUID),
+		filepath.Join(kubepods, "burstable", "pod"+podUID),
+		filepath.Join(kubepods, "besteffort", "pod"+podUID),
+	}
+}
+
+// PodPath returns the cgroup of pod, the error is os.ErrNotExist if the pod has no cgroup
+func (m *Manager) PodPath(podUID string) (string, error) {
+	for _, path := range m.podPaths(podUID) {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("cgroup of pod %s: %w", podUID, os.ErrNotExist)
+}
+
+// SetLimits sets the io limits of device maj:min in the cgroup of pod
+func (m *Manager) SetLimits(podUID string, major, minor uint32, limits Limits) error {
+	path, err := m.PodPath(podUID)
+	if err != nil {
+		return err
+	}
+	device := fmt.Sprintf("%d:%d", major, minor)
+	if m.Version == V2 {
+		line := fmt.Sprintf("%s rbps=%s wbps=%s riops=%s wiops=%s", device,
+			maxValue(limits.ReadBPS), maxValue(limits.WriteBPS), maxValue(limits.ReadIOPS), maxValue(limits.WriteIOPS))
+		return writeFile(filepath.Join(path, ioMaxFile), line)
+	}
+	// writing 0 removes the limit in v1
+	for file, value := range map[string]uint64{
+		readBPSFile:   limits.ReadBPS,
+		writeBPSFile:  limits.WriteBPS,
+		readIOPSFile:  limits.ReadIOPS,
+		writeIOPSFile: limits.WriteIOPS,
+	} {
+		if err := writeFile(filepath.Join(path, file), fmt.Sprintf("%s %d", device, value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetLimits reads the io limits of device maj:min in the cgroup of pod
+func (m *Manager) GetLimits(podUID string, major, minor uint32) (Limits, error) {
+	limits := Limits{}
+	path, err := m.PodPath(podUID)
+	if err != nil {
+		return limits, err
+	}
+	device := fmt.Sprintf("%d:%d", major, minor)
+	if m.Version == V2 {
+		values, err := readDeviceLine(filepath.Join(path, ioMaxFile), device)
+		if err != nil {
+			return limits, err
+		}
+		for _, field := range values {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 || kv[1] == "max" {
+				continue
+			}
+			value, err := strconv.ParseUint(kv[1], 10, 64)
+			if err != nil {
+				return limits, fmt.Errorf("invalid io.max of %s: %s", device, field)
+			}
+			switch kv[0] {
+			case "rbps":
+				limits.ReadBPS = value
+			case "wbps":
+				limits.WriteBPS = value
+			case "riops":
+				limits.ReadIOPS = value
+			case "wiops":
+				limits.WriteIOPS = value
+			}
+		}
+		return limits, nil
+	}
+	for file, value := range map[string]*uint64{
+		readBPSFile:   &limits.ReadBPS,
+		writeBPSFile:  &limits.WriteBPS,
+		readIOPSFile:  &limits.ReadIOPS,
+		writeIOPSFile: &limits.WriteIOPS,
+	} {
+		values, err := readDeviceLine(filepath.Join(path, file), device)
+		if err != nil {
+			return limits, err
+		}
+		if len(values) == 1 {
+			if *value, err = strconv.ParseUint(values[0], 10, 64); err != nil {
+				return limits, fmt.Errorf("invalid %s of %s: %s", file, device, values[0])
+			}
+		}
+	}
+	return limits, nil
+}
+
+// readDeviceLine returns the fields after device in the line of device
+func readDeviceLine(file, device string) ([]string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == device {
+			return fields[1:], nil
+		}
+	}
+	return nil, nil
+}
+
+func maxValue(value uint64) string {
+	if value == 0 {
+		return "max"
+	}
+	return strconv.FormatUint(value, 10)
+}
+
+func writeFile(file, content string) error {
+	f, err := os.OpenFile(file, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		return fmt.Errorf("write %q to %s: %s", content, file, err.Error())
+	}
+	return nil
+}
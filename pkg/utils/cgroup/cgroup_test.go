@@ -0,0 +1,141 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const podUID = "2a7bbb9c-c915-4006-84d7-0e3ac9d8d70f"
+
+// mkPod creates the cgroup of pod with empty control files
+func mkPod(t *testing.T, dir string, files ...string) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, file), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestManager(t *testing.T) {
+	tests := []struct {
+		name    string
+		markers []string
+		podPath string
+		files   []string
+		version Version
+		driver  Driver
+	}{
+		{
+			name:    "v1 systemd",
+			podPath: "blkio/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod2a7bbb9c_c915_4006_84d7_0e3ac9d8d70f.slice",
+			files:   []string{readBPSFile, writeBPSFile, readIOPSFile, writeIOPSFile},
+			version: V1,
+			driver:  DriverSystemd,
+		},
+		{
+			name:    "v1 cgroupfs",
+			podPath: "blkio/kubepods/pod" + podUID,
+			files:   []string{readBPSFile, writeBPSFile, readIOPSFile, writeIOPSFile},
+			version: V1,
+			driver:  DriverCgroupfs,
+		},
+		{
+			name:    "v2 systemd",
+			markers: []string{"cgroup.controllers"},
+			podPath: "kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod2a7bbb9c_c915_4006_84d7_0e3ac9d8d70f.slice",
+			files:   []string{ioMaxFile},
+			version: V2,
+			driver:  DriverSystemd,
+		},
+		{
+			name:    "v2 cgroupfs",
+			markers: []string{"cgroup.controllers"},
+			podPath: "kubepods/pod" + podUID,
+			files:   []string{ioMaxFile},
+			version: V2,
+			driver:  DriverCgroupfs,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := t.TempDir()
+			mkPod(t, root, test.markers...)
+			m, err := Detect(root)
+			if err == nil {
+				t.Fatalf("Detect() without kubepods = %+v, want error", m)
+			}
+			mkPod(t, filepath.Join(root, test.podPath), test.files...)
+
+			if m, err = Detect(root); err != nil {
+				t.Fatal(err)
+			}
+			if m.Version != test.version || m.Driver != test.driver {
+				t.Errorf("Detect() = %s v%d, want %s v%d", m.Driver, m.Version, test.driver, test.version)
+			}
+			if path, err := m.PodPath(podUID); err != nil || path != filepath.Join(root, test.podPath) {
+				t.Errorf("PodPath() = %s, %v, want %s", path, err, test.podPath)
+			}
+			if _, err := m.PodPath("unknown"); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("PodPath() of unknown pod error = %v, want %v", err, os.ErrNotExist)
+			}
+
+			limits := Limits{ReadBPS: 100 << 20, WriteBPS: 50 << 20, WriteIOPS: 1000}
+			if err := m.SetLimits(podUID, 253, 3, limits); err != nil {
+				t.Fatal(err)
+			}
+			got, err := m.GetLimits(podUID, 253, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != limits {
+				t.Errorf("GetLimits() = %+v, want %+v", got, limits)
+			}
+			if got, _ := m.GetLimits(podUID, 253, 4); !got.IsZero() {
+				t.Errorf("GetLimits() of unlimited device = %+v, want zero", got)
+			}
+		})
+	}
+}
+
+func TestSetLimitsV2(t *testing.T) {
+	root := t.TempDir()
+	mkPod(t, root, "cgroup.controllers")
+	mkPod(t, filepath.Join(root, "kubepods.slice", "kubepods-pod2a7bbb9c_c915_4006_84d7_0e3ac9d8d70f.slice"), ioMaxFile)
+	m, err := Detect(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetLimits(podUID, 8, 16, Limits{ReadIOPS: 500}); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := m.PodPath(podUID)
+	data, err := ioutil.ReadFile(filepath.Join(path, ioMaxFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "8:16 rbps=max wbps=max riops=500 wiops=max"; string(data) != expect {
+		t.Errorf("io.max = %q, want %q", data, expect)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"os/exec"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
@@ -171,6 +172,9 @@ func GetMountPointFromCsiPV(pv *corev1.PersistentVolume) string {
 func GetVGRequested(localPVs map[string]corev1.PersistentVolume, vgName string) (requested int64) {
 	requested = 0
 	for _, pv := range localPVs {
+		if IsThinCsiPV(&pv) {
+			continue
+		}
 		vgNameFromPV := GetVGNameFromCsiPV(&pv)
 		if vgNameFromPV == vgName {
 			v := pv.Spec.Capacity[corev1.ResourceStorage]
@@ -184,6 +188,63 @@ func GetVGRequested(localPVs map[string]corev1.PersistentVolume, vgName string)
 	return requested
 }
 
+// GetThinPoolRequested sums up the size of thin PVs in the thin pool of VG
+func GetThinPoolRequested(localPVs map[string]corev1.PersistentVolume, vgName string) (requested int64) {
+	requested = 0
+	for _, pv := range localPVs {
+		if !IsThinCsiPV(&pv) {
+			continue
+		}
+		if GetVGNameFromCsiPV(&pv) == vgName {
+			v := pv.Spec.Capacity[corev1.ResourceStorage]
+			requested += v.Value()
+		}
+	}
+	log.Debugf("requested size of thin pool in VG %s is %d", vgName, requested)
+	return requested
+}
+
+// IsThinCsiPV returns true if the open-local lvm PV is allocated from thin pool
+func IsThinCsiPV(pv *corev1.PersistentVolume) bool {
+	if pv.Spec.CSI == nil {
+		return false
+	}
+	return pv.Spec.CSI.VolumeAttributes[localtype.VolumeLVMType] == localtype.LVMTypeThin
+}
+
+// GetThinPoolOvercommitRatio returns the overcommit ratio of thin pool in VG,
+// which is configured in ResourceToBeInited of NodeLocalStorage
+func GetThinPoolOvercommitRatio(nodeLocal *nodelocalstorage.NodeLocalStorage, vgName string) float64 {
+	for _, vg := range nodeLocal.Spec.ResourceToBeInited.VGs {
+		if vg.Name != vgName || vg.ThinPool == nil || vg.ThinPool.OvercommitRatio == "" {
+			continue
+		}
+		ratio, err := strconv.ParseFloat(vg.ThinPool.OvercommitRatio, 64)
+		if err != nil || ratio <= 0 {
+			log.Errorf("invalid overcommit ratio %q of thin pool in vg %s on node %s", vg.ThinPool.OvercommitRatio, vgName, nodeLocal.Name)
+			break
+		}
+		return ratio
+	}
+	return localtype.DefaultThinPoolOvercommit
+}
+
+// GetQuotaRequested sums up the size of quota PVs on the mount point
+func GetQuotaRequested(localPVs map[string]corev1.PersistentVolume, mpName string) (requested int64) {
+	requested = 0
+	for _, pv := range localPVs {
+		if pv.Spec.CSI == nil || pv.Spec.CSI.VolumeAttributes[localtype.VolumeTypeKey] != string(localtype.VolumeTypeQuota) {
+			continue
+		}
+		if GetMountPointFromCsiPV(&pv) == mpName {
+			v := pv.Spec.Capacity[corev1.ResourceStorage]
+			requested += v.Value()
+		}
+	}
+	log.Debugf("requested quota size of mount point %s is %d", mpName, requested)
+	return requested
+}
+
 //CheckDiskOptions excludes mp which is readyonly or with unsupported fs type
 func CheckMountPointOptions(mp *nodelocalstorage.MountPoint) bool {
 	if mp == nil {
@@ -359,6 +420,15 @@ func GetVGNameFromPVC(pvc *corev1.PersistentVolumeClaim, p storagev1informers.In
 	return vgName
 }
 
+// IsThinPVC returns true if the pvc is provisioned from thin pool
+func IsThinPVC(pvc *corev1.PersistentVolumeClaim, p storagev1informers.Interface) bool {
+	sc := GetStorageClassFromPVC(pvc, p)
+	if sc == nil {
+		return false
+	}
+	return sc.Parameters[localtype.VolumeLVMType] == localtype.LVMTypeThin
+}
+
 func GetMediaTypeFromPVC(pvc *corev1.PersistentVolumeClaim, p storagev1informers.Interface) localtype.MediaType {
 	sc := GetStorageClassFromPVC(pvc, p)
 	if sc == nil {
@@ -372,6 +442,24 @@ func GetMediaTypeFromPVC(pvc *corev1.PersistentVolumeClaim, p storagev1informers
 	return localtype.MediaType(mediaType)
 }
 
+// GetPerformanceTierFromPVC returns the performance tier required by the storage class of pvc
+func GetPerformanceTierFromPVC(pvc *corev1.PersistentVolumeClaim, p storagev1informers.Interface) string {
+	sc := GetStorageClassFromPVC(pvc, p)
+	if sc == nil {
+		return ""
+	}
+	return sc.Parameters[localtype.VolumePerformanceTier]
+}
+
+// GetSchedulerStrategyFromPVC returns the scheduler strategy set in the storage class of pvc
+func GetSchedulerStrategyFromPVC(pvc *corev1.PersistentVolumeClaim, p storagev1informers.Interface) string {
+	sc := GetStorageClassFromPVC(pvc, p)
+	if sc == nil {
+		return ""
+	}
+	return sc.Parameters[localtype.VolumeSchedulerStrategy]
+}
+
 func IsLocalPVC(claim *corev1.PersistentVolumeClaim, p storagev1informers.Interface, containReadonlySnapshot bool) (bool, localtype.VolumeType) {
 	sc := GetStorageClassFromPVC(claim, p)
 	if sc == nil {
@@ -427,6 +515,20 @@ func IsSnapshotPVC(claim *corev1.PersistentVolumeClaim) bool {
 	return false
 }
 
+// IsClonePVC checks if the data source of pvc is another pvc
+func IsClonePVC(claim *corev1.PersistentVolumeClaim) bool {
+	return claim.Spec.DataSource != nil && claim.Spec.DataSource.Kind == "PersistentVolumeClaim"
+}
+
+func ContainsClonePVC(claims []*corev1.PersistentVolumeClaim) bool {
+	for _, claim := range claims {
+		if IsClonePVC(claim) {
+			return true
+		}
+	}
+	return false
+}
+
 func ContainsSnapshotPVC(claims []*corev1.PersistentVolumeClaim) (contain bool) {
 	contain = false
 	for _, claim := range claims {
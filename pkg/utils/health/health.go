@@ -0,0 +1,217 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	localtype "github.com/alibaba/open-local/pkg"
+)
+
+var (
+	// ErrNoHealthInfo means the disk does not report SMART or NVMe health information, e.g. virtual disks
+	ErrNoHealthInfo = errors.New("no health information")
+)
+
+// smartctl exit status bits, see smartctl(8)
+const (
+	smartctlCommandLineError = 1 << 0
+	smartctlDeviceOpenFailed = 1 << 1
+)
+
+// ATA attributes whose non-zero raw value means that sectors of the disk are failing
+var ataDegradationAttributes = map[int]bool{
+	5:   true, // Reallocated_Sector_Ct
+	187: true, // Reported_Uncorrect
+	197: true, // Current_Pending_Sector
+	198: true, // Offline_Uncorrectable
+}
+
+// NVMe critical warning bits, see NVMe base specification of SMART / Health Information log page
+var nvmeCriticalWarnings = []string{
+	"available spare is below threshold",
+	"temperature is above or below threshold",
+	"reliability is degraded due to media or internal errors",
+	"media is placed in read only mode",
+	"volatile memory backup device has failed",
+	"persistent memory region has become read-only",
+}
+
+// CommandRunner runs cmd on host and returns its stdout, which may be returned
+// along with an error, for smartctl exits with non-zero status when the disk is failing
+type CommandRunner func(cmd string, args ...string) ([]byte, error)
+
+// HostRunner runs cmd in the mount namespace of host
+func HostRunner(cmd string, args ...string) ([]byte, error) {
+	cmdline := strings.Join(append([]string{localtype.NsenterCmd + cmd}, args...), " ")
+	stdout := new(bytes.Buffer)
+	c := exec.Command("sh", "-c", cmdline)
+	c.Stdout = stdout
+	err := c.Run()
+	return stdout.Bytes(), err
+}
+
+// Report is the health of a disk
+type Report struct {
+	// Device is the disk, e.g. /dev/sdb
+	Device string
+	// Faults are the reasons why the disk is failing, the disk is healthy if empty
+	Faults []string
+	// Warnings are the signs of degradation, which do not fail the disk
+	Warnings []string
+}
+
+// Healthy returns true if the disk has no fault
+func (r *Report) Healthy() bool {
+	return len(r.Faults) == 0
+}
+
+// Check reads the NVMe health log of nvme disks, or the SMART status and attributes of other disks
+func Check(device string, run CommandRunner) (*Report, error) {
+	if strings.HasPrefix(filepath.Base(device), "nvme") {
+		out, err := run("nvme", "smart-log", "--output-format=json", device)
+		if err != nil {
+			return nil, fmt.Errorf("nvme smart-log %s failed: %v", device, err)
+		}
+		return ParseNVMeSmartLog(device, out)
+	}
+	out, err := run("smartctl", "--json", "--health", "--attributes", device)
+	report, parseErr := ParseSmartctl(device, out)
+	// smartctl exits with non-zero status if the disk is failing, so the error is only
+	// reported if the output is not usable
+	if parseErr != nil && err != nil && !errors.Is(parseErr, ErrNoHealthInfo) {
+		return nil, fmt.Errorf("smartctl %s failed: %v, %s", device, err, parseErr.Error())
+	}
+	return report, parseErr
+}
+
+type smartctlOutput struct {
+	Smartctl struct {
+		ExitStatus int `json:"exit_status"`
+		Messages   []struct {
+			String string `json:"string"`
+		} `json:"messages"`
+	} `json:"smartctl"`
+	SmartStatus *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	ATASmartAttributes *struct {
+		Table []struct {
+			ID         int    `json:"id"`
+			Name       string `json:"name"`
+			Value      int    `json:"value"`
+			Thresh     int    `json:"thresh"`
+			WhenFailed string `json:"when_failed"`
+			Raw        struct {
+				Value uint64 `json:"value"`
+			} `json:"raw"`
+		} `json:"table"`
+	} `json:"ata_smart_attributes"`
+	NVMeSmartHealthInformationLog *nvmeSmartLog `json:"nvme_smart_health_information_log"`
+}
+
+// nvmeSmartLog is the health log in the output of both smartctl and nvme-cli
+type nvmeSmartLog struct {
+	CriticalWarning uint64 `json:"critical_warning"`
+	// smartctl
+	AvailableSpare          *uint64 `json:"available_spare"`
+	AvailableSpareThreshold *uint64 `json:"available_spare_threshold"`
+	PercentageUsed          *uint64 `json:"percentage_used"`
+	// nvme-cli
+	AvailSpare  *uint64 `json:"avail_spare"`
+	SpareThresh *uint64 `json:"spare_thresh"`
+	PercentUsed *uint64 `json:"percent_used"`
+
+	MediaErrors uint64 `json:"media_errors"`
+}
+
+// ParseSmartctl parses the json output of "smartctl --json --health --attributes"
+func ParseSmartctl(device string, out []byte) (*Report, error) {
+	result := smartctlOutput{}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, fmt.Errorf("invalid output of smartctl: %s", err.Error())
+	}
+	if result.Smartctl.ExitStatus&(smartctlCommandLineError|smartctlDeviceOpenFailed) != 0 {
+		var messages []string
+		for _, message := range result.Smartctl.Messages {
+			messages = append(messages, message.String)
+		}
+		return nil, fmt.Errorf("smartctl %s failed with status %d: %s", device, result.Smartctl.ExitStatus, strings.Join(messages, "; "))
+	}
+	if result.SmartStatus == nil && result.ATASmartAttributes == nil && result.NVMeSmartHealthInformationLog == nil {
+		return nil, ErrNoHealthInfo
+	}
+
+	report := &Report{Device: device}
+	if result.SmartStatus != nil && !result.SmartStatus.Passed {
+		report.Faults = append(report.Faults, "SMART overall-health self-assessment failed")
+	}
+	if result.ATASmartAttributes != nil {
+		for _, attr := range result.ATASmartAttributes.Table {
+			switch {
+			case attr.WhenFailed == "now":
+				report.Faults = append(report.Faults, fmt.Sprintf("SMART attribute %s is failing now, value %d, threshold %d", attr.Name, attr.Value, attr.Thresh))
+			case attr.WhenFailed == "past":
+				report.Warnings = append(report.Warnings, fmt.Sprintf("SMART attribute %s failed in the past", attr.Name))
+			case ataDegradationAttributes[attr.ID] && attr.Raw.Value > 0:
+				report.Warnings = append(report.Warnings, fmt.Sprintf("SMART attribute %s is %d", attr.Name, attr.Raw.Value))
+			}
+		}
+	}
+	if result.NVMeSmartHealthInformationLog != nil {
+		result.NVMeSmartHealthInformationLog.check(report)
+	}
+	return report, nil
+}
+
+// ParseNVMeSmartLog parses the json output of "nvme smart-log --output-format=json"
+func ParseNVMeSmartLog(device string, out []byte) (*Report, error) {
+	log := nvmeSmartLog{}
+	if err := json.Unmarshal(out, &log); err != nil {
+		return nil, fmt.Errorf("invalid output of nvme smart-log: %s", err.Error())
+	}
+	report := &Report{Device: device}
+	log.check(report)
+	return report, nil
+}
+
+func (l *nvmeSmartLog) check(report *Report) {
+	for bit, warning := range nvmeCriticalWarnings {
+		if l.CriticalWarning&(1<<uint(bit)) != 0 {
+			report.Faults = append(report.Faults, "NVMe critical warning: "+warning)
+		}
+	}
+	spare, threshold, used := l.AvailableSpare, l.AvailableSpareThreshold, l.PercentageUsed
+	if spare == nil {
+		spare, threshold, used = l.AvailSpare, l.SpareThresh, l.PercentUsed
+	}
+	if spare != nil && threshold != nil && *spare < *threshold && l.CriticalWarning&1 == 0 {
+		report.Faults = append(report.Faults, fmt.Sprintf("NVMe available spare %d%% is below threshold %d%%", *spare, *threshold))
+	}
+	if used != nil && *used >= 100 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("NVMe percentage used is %d%%", *used))
+	}
+	if l.MediaErrors > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("NVMe media errors are %d", l.MediaErrors))
+	}
+}
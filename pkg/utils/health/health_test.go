@@ -0,0 +1,136 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func fixtureRunner(t *testing.T, fixture string, runErr error) CommandRunner {
+	return func(cmd string, args ...string) ([]byte, error) {
+		out, err := ioutil.ReadFile(filepath.Join("testdata", fixture))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out, runErr
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name         string
+		device       string
+		fixture      string
+		runErr       error
+		wantErr      error
+		wantHealthy  bool
+		wantFaults   int
+		wantWarnings int
+	}{
+		{
+			name:        "ata disk passed",
+			device:      "/dev/sdb",
+			fixture:     "smartctl_ata_passed.json",
+			wantHealthy: true,
+		},
+		{
+			name:         "ata disk with pending and reallocated sectors",
+			device:       "/dev/sdc",
+			fixture:      "smartctl_ata_degraded.json",
+			runErr:       fmt.Errorf("exit status 64"),
+			wantHealthy:  true,
+			wantWarnings: 3,
+		},
+		{
+			name:       "ata disk failed",
+			device:     "/dev/sdd",
+			fixture:    "smartctl_ata_failed.json",
+			runErr:     fmt.Errorf("exit status 24"),
+			wantFaults: 2,
+		},
+		{
+			name:        "nvme health log from smartctl",
+			device:      "/dev/dm-0",
+			fixture:     "smartctl_nvme.json",
+			wantHealthy: true,
+		},
+		{
+			name:    "virtual disk without smart",
+			device:  "/dev/vdb",
+			fixture: "smartctl_virtual.json",
+			runErr:  fmt.Errorf("exit status 4"),
+			wantErr: ErrNoHealthInfo,
+		},
+		{
+			name:    "smartctl failed to open device",
+			device:  "/dev/sdz",
+			fixture: "smartctl_open_failed.json",
+			runErr:  fmt.Errorf("exit status 2"),
+			wantErr: errors.New("any"),
+		},
+		{
+			name:        "nvme disk healthy",
+			device:      "/dev/nvme0n1",
+			fixture:     "nvme_smart_log_healthy.json",
+			wantHealthy: true,
+		},
+		{
+			name:         "nvme disk in read only mode",
+			device:       "/dev/nvme1n1",
+			fixture:      "nvme_smart_log_critical.json",
+			wantFaults:   2,
+			wantWarnings: 2,
+		},
+		{
+			name:    "nvme-cli is not installed",
+			device:  "/dev/nvme1n1",
+			fixture: "nvme_smart_log_healthy.json",
+			runErr:  fmt.Errorf("exit status 127"),
+			wantErr: errors.New("any"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report, err := Check(test.device, fixtureRunner(t, test.fixture, test.runErr))
+			if test.wantErr != nil {
+				if err == nil {
+					t.Fatalf("expected error, got report %+v", report)
+				}
+				if test.wantErr == ErrNoHealthInfo && !errors.Is(err, ErrNoHealthInfo) {
+					t.Errorf("expected %v, got %v", ErrNoHealthInfo, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Check() error: %s", err.Error())
+			}
+			if report.Device != test.device {
+				t.Errorf("unexpected device %s", report.Device)
+			}
+			if report.Healthy() != test.wantHealthy {
+				t.Errorf("Healthy() = %t, expected %t, faults: %v", report.Healthy(), test.wantHealthy, report.Faults)
+			}
+			if len(report.Faults) != test.wantFaults || len(report.Warnings) != test.wantWarnings {
+				t.Errorf("unexpected faults %v and warnings %v", report.Faults, report.Warnings)
+			}
+		})
+	}
+}
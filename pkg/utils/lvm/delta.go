@@ -0,0 +1,162 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lvm
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// lvTimeLayout is the format of lv_time reported by lvs
+const lvTimeLayout = "2006-01-02 15:04:05 -0700"
+
+// Extent is a range of a logical volume in bytes
+type Extent struct {
+	Offset uint64
+	Length uint64
+}
+
+// ListSnapshots returns the snapshots of origin in the volume group, the oldest first.
+// Usage of the snapshots is not looked up.
+func (vg *VolumeGroup) ListSnapshots(origin string) ([]*LogicalVolume, error) {
+	result := new(lvsOutput)
+	if err := run("lvs", result, "--options=lv_name,lv_size,vg_name,origin,pool_lv,lv_time", vg.name); err != nil {
+		log.Errorf("ListSnapshots error: %s", err.Error())
+		return nil, err
+	}
+	var snapshots []*LogicalVolume
+	var times []time.Time
+	for _, report := range result.Report {
+		for _, lv := range report.Lv {
+			if lv.VgName != vg.name || lv.LvOrigin != origin {
+				continue
+			}
+			// keep the order of lvs if lv_time is not recognized
+			t, _ := time.Parse(lvTimeLayout, lv.LvTime)
+			snapshots = append(snapshots, &LogicalVolume{lv.Name, lv.LvSize, vg, lv.LvOrigin, lv.PoolLv, 0})
+			times = append(times, t)
+		}
+	}
+	sort.Stable(byTime{snapshots, times})
+	return snapshots, nil
+}
+
+type byTime struct {
+	lvs   []*LogicalVolume
+	times []time.Time
+}
+
+func (s byTime) Len() int           { return len(s.lvs) }
+func (s byTime) Less(i, j int) bool { return s.times[i].Before(s.times[j]) }
+func (s byTime) Swap(i, j int) {
+	s.lvs[i], s.lvs[j] = s.lvs[j], s.lvs[i]
+	s.times[i], s.times[j] = s.times[j], s.times[i]
+}
+
+// thinDeltaOutput is the xml output of thin_delta
+type thinDeltaOutput struct {
+	DataBlockSize uint64 `xml:"data_block_size,attr"`
+	Diff          struct {
+		Ranges []struct {
+			XMLName xml.Name
+			Begin   uint64 `xml:"begin,attr"`
+			Length  uint64 `xml:"length,attr"`
+		} `xml:",any"`
+	} `xml:"diff"`
+}
+
+// ThinDelta returns the extents where thin volume to differs from thin volume from, e.g.
+// two snapshots of the same origin. Both volumes must be in the same thin pool. The
+// mappings are read from a metadata snapshot by thin_delta, so the pool stays online.
+func (vg *VolumeGroup) ThinDelta(from, to string) ([]Extent, error) {
+	result := new(lvsOutput)
+	if err := run("lvs", result, "--options=lv_name,vg_name,pool_lv,thin_id", vg.name); err != nil {
+		log.Errorf("ThinDelta error: %s", err.Error())
+		return nil, err
+	}
+	var pool, fromID, toID string
+	for _, report := range result.Report {
+		for _, lv := range report.Lv {
+			if lv.VgName != vg.name || lv.PoolLv == "" {
+				continue
+			}
+			switch lv.Name {
+			case from:
+				fromID = lv.ThinID
+			case to:
+				toID = lv.ThinID
+			default:
+				continue
+			}
+			if pool != "" && pool != lv.PoolLv {
+				return nil, fmt.Errorf("lvm: %s and %s are not in the same thin pool", from, to)
+			}
+			pool = lv.PoolLv
+		}
+	}
+	if fromID == "" || toID == "" {
+		return nil, fmt.Errorf("lvm: %s and %s must both be thin volumes", from, to)
+	}
+
+	tpool := dmName(vg.name, pool) + "-tpool"
+	if _, err := Run("dmsetup", "message", tpool, "0", "reserve_metadata_snap"); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if _, err := Run("dmsetup", "message", tpool, "0", "release_metadata_snap"); err != nil {
+			log.Errorf("release metadata snapshot of %s error: %s", tpool, err.Error())
+		}
+	}()
+	out, err := Run("thin_delta", "--metadata-snap", "--snap1", fromID, "--snap2", toID, "/dev/mapper/"+dmName(vg.name, pool)+"_tmeta")
+	if err != nil {
+		return nil, err
+	}
+	return parseThinDelta(out)
+}
+
+func parseThinDelta(out string) ([]Extent, error) {
+	delta := thinDeltaOutput{}
+	if err := xml.Unmarshal([]byte(out), &delta); err != nil {
+		return nil, fmt.Errorf("lvm: invalid thin_delta output: %s", err.Error())
+	}
+	// data_block_size is in 512 bytes sectors
+	blockSize := delta.DataBlockSize * 512
+	extents := []Extent{}
+	for _, r := range delta.Diff.Ranges {
+		if r.XMLName.Local == "same" {
+			continue
+		}
+		extent := Extent{Offset: r.Begin * blockSize, Length: r.Length * blockSize}
+		if n := len(extents); n > 0 && extents[n-1].Offset+extents[n-1].Length == extent.Offset {
+			extents[n-1].Length += extent.Length
+			continue
+		}
+		extents = append(extents, extent)
+	}
+	return extents, nil
+}
+
+// dmName returns the device mapper name of the logical volume, in which
+// dashes of vg and lv names are doubled
+func dmName(vg, lv string) string {
+	return strings.ReplaceAll(vg, "-", "--") + "-" + strings.ReplaceAll(lv, "-", "--")
+}
@@ -0,0 +1,94 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lvm
+
+import (
+	"reflect"
+	"testing"
+)
+
+const (
+	lvsThin = `{"report": [{"lv": [
+		{"lv_name":"thin-pool", "vg_name":"share", "pool_lv":"", "thin_id":""},
+		{"lv_name":"pv-1", "vg_name":"share", "pool_lv":"thin-pool", "thin_id":"1"},
+		{"lv_name":"snap-1", "vg_name":"share", "pool_lv":"thin-pool", "thin_id":"2"},
+		{"lv_name":"snap-2", "vg_name":"share", "pool_lv":"thin-pool", "thin_id":"3"},
+		{"lv_name":"pv-2", "vg_name":"share", "pool_lv":"", "thin_id":""}
+	]}]}`
+	thinDeltaSnap = `<superblock uuid="" time="3" transaction="4" data_block_size="128" nr_data_blocks="16384">
+  <diff left="2" right="3">
+    <same begin="0" length="2"/>
+    <different begin="2" length="1"/>
+    <right_only begin="3" length="2"/>
+    <same begin="5" length="10"/>
+    <left_only begin="15" length="1"/>
+  </diff>
+</superblock>`
+	lvsSnapshots = `{"report": [{"lv": [
+		{"lv_name":"pv-1", "vg_name":"share", "lv_size":"1073741824", "origin":"", "lv_time":"2021-09-10 10:00:00 +0800"},
+		{"lv_name":"snap-2", "vg_name":"share", "lv_size":"1073741824", "origin":"pv-1", "lv_time":"2021-09-10 12:00:00 +0800"},
+		{"lv_name":"snap-1", "vg_name":"share", "lv_size":"1073741824", "origin":"pv-1", "lv_time":"2021-09-10 11:00:00 +0800"},
+		{"lv_name":"snap-3", "vg_name":"share", "lv_size":"1073741824", "origin":"pv-2", "lv_time":"2021-09-10 09:00:00 +0800"}
+	]}]}`
+)
+
+func TestThinDelta(t *testing.T) {
+	fake := NewFakeExecutor()
+	defer SetExecutor(SetExecutor(fake))
+	fake.Record("lvs --reportformat=json --units=b --nosuffix --options=lv_name,vg_name,pool_lv,thin_id share", lvsThin)
+	fake.Record("dmsetup message share-thin--pool-tpool 0 reserve_metadata_snap", "")
+	fake.Record("dmsetup message share-thin--pool-tpool 0 release_metadata_snap", "")
+	fake.Record("thin_delta --metadata-snap --snap1 2 --snap2 3 /dev/mapper/share-thin--pool_tmeta", thinDeltaSnap)
+
+	vg := &VolumeGroup{name: "share"}
+	extents, err := vg.ThinDelta("snap-1", "snap-2")
+	if err != nil {
+		t.Fatalf("ThinDelta() error = %v", err)
+	}
+	// blocks of 64KiB, adjacent ranges are merged
+	want := []Extent{{Offset: 2 << 16, Length: 3 << 16}, {Offset: 15 << 16, Length: 1 << 16}}
+	if !reflect.DeepEqual(extents, want) {
+		t.Errorf("ThinDelta() = %v, want %v", extents, want)
+	}
+	commands := fake.Commands()
+	if commands[len(commands)-1] != "dmsetup message share-thin--pool-tpool 0 release_metadata_snap" {
+		t.Errorf("ThinDelta() should release the metadata snapshot, commands = %v", commands)
+	}
+
+	if _, err := vg.ThinDelta("snap-1", "pv-2"); err == nil {
+		t.Errorf("ThinDelta() of thick volume should fail")
+	}
+}
+
+func TestListSnapshots(t *testing.T) {
+	fake := NewFakeExecutor()
+	defer SetExecutor(SetExecutor(fake))
+	fake.Record("lvs --reportformat=json --units=b --nosuffix --options=lv_name,lv_size,vg_name,origin,pool_lv,lv_time share", lvsSnapshots)
+
+	vg := &VolumeGroup{name: "share"}
+	snapshots, err := vg.ListSnapshots("pv-1")
+	if err != nil {
+		t.Fatalf("ListSnapshots() error = %v", err)
+	}
+	var names []string
+	for _, lv := range snapshots {
+		names = append(names, lv.Name())
+	}
+	if !reflect.DeepEqual(names, []string{"snap-1", "snap-2"}) {
+		t.Errorf("ListSnapshots() = %v, want [snap-1 snap-2]", names)
+	}
+}
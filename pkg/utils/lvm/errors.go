@@ -0,0 +1,85 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lvm
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrLocked is returned when lvm fails to acquire the lock of a volume group
+const ErrLocked = simpleError("lvm: volume group is locked")
+
+// Error is returned by Executor when an lvm command fails. Error() is the
+// stderr of the command, and the error unwraps to one of ErrNoSpace,
+// ErrLocked, ErrLogicalVolumeNotFound, ErrVolumeGroupNotFound or
+// ErrPhysicalVolumeNotFound when the failure is recognized, so callers can
+// check it with errors.Is.
+type Error struct {
+	// Cmd is the command line which failed, without nsenter prefix
+	Cmd string
+	// Stderr is the stderr of the command with warnings removed
+	Stderr string
+	kind   error
+}
+
+// NewError classifies the stderr of the failed lvm command
+func NewError(cmd, stderr string) *Error {
+	e := &Error{Cmd: cmd, Stderr: stderr}
+	plain := errors.New(stderr)
+	switch {
+	case isInsufficientSpace(plain):
+		e.kind = ErrNoSpace
+	case isLocked(plain):
+		e.kind = ErrLocked
+	case IsLogicalVolumeNotFound(plain):
+		e.kind = ErrLogicalVolumeNotFound
+	case IsVolumeGroupNotFound(plain):
+		e.kind = ErrVolumeGroupNotFound
+	case IsPhysicalVolumeNotFound(plain):
+		e.kind = ErrPhysicalVolumeNotFound
+	}
+	return e
+}
+
+func (e *Error) Error() string {
+	return e.Stderr
+}
+
+// Unwrap returns the typed error of the failure, or nil if it's unknown
+func (e *Error) Unwrap() error {
+	return e.kind
+}
+
+// IsNoSpace returns true if the error is due to insufficient space
+func IsNoSpace(err error) bool {
+	return errors.Is(err, ErrNoSpace) || isInsufficientSpace(err)
+}
+
+// IsLocked returns true if the error is due to volume group lock
+func IsLocked(err error) bool {
+	return errors.Is(err, ErrLocked) || isLocked(err)
+}
+
+func isLocked(err error) bool {
+	for _, msg := range []string{"Can't get lock", "Failed to lock", "Giving up waiting for lock"} {
+		if strings.Contains(err.Error(), msg) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lvm
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	localtype "github.com/alibaba/open-local/pkg"
+	log "github.com/sirupsen/logrus"
+)
+
+// Executor executes lvm commands. All lvm commands issued by open-local go
+// through an Executor, so that callers can be tested against a FakeExecutor
+// without root privilege or real volume groups.
+type Executor interface {
+	// Execute runs the lvm command cmd with args and returns its stdout.
+	// When the command fails, the returned error is an *Error.
+	Execute(cmd string, args ...string) ([]byte, error)
+}
+
+// nsenterExecutor runs lvm commands in the mount namespace of the host
+type nsenterExecutor struct{}
+
+// NewExecutor returns the Executor which runs lvm commands on the host
+func NewExecutor() Executor {
+	return &nsenterExecutor{}
+}
+
+func (e *nsenterExecutor) Execute(cmd string, args ...string) ([]byte, error) {
+	cmdline := strings.Join(append([]string{fmt.Sprintf("%s %s", localtype.NsenterCmd, cmd)}, args...), " ")
+	c := exec.Command("sh", "-c", cmdline)
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	c.Stdout = stdout
+	c.Stderr = stderr
+	if err := c.Run(); err != nil {
+		log.Debugf("[debug run]: command %s", c.String())
+		log.Debugf("[debug run]: error %s", err.Error())
+		return nil, NewError(commandLine(cmd, args...), ignoreWarnings(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+var (
+	executorLock sync.RWMutex
+	executor     = NewExecutor()
+)
+
+// GetExecutor returns the Executor used by this package
+func GetExecutor() Executor {
+	executorLock.RLock()
+	defer executorLock.RUnlock()
+	return executor
+}
+
+// SetExecutor replaces the Executor used by this package and returns the
+// previous one, so that tests can restore it when they are done.
+func SetExecutor(e Executor) Executor {
+	executorLock.Lock()
+	defer executorLock.Unlock()
+	old := executor
+	executor = e
+	return old
+}
+
+// Run runs the lvm command and returns its stdout
+func Run(cmd string, args ...string) (string, error) {
+	out, err := GetExecutor().Execute(cmd, args...)
+	return string(out), err
+}
+
+// RunReport runs the lvm reporting command(lvs, vgs or pvs) and returns its
+// output in json format, sizes are reported in bytes without suffix.
+func RunReport(cmd string, args ...string) ([]byte, error) {
+	args = append([]string{"--reportformat=json", "--units=b", "--nosuffix"}, args...)
+	return GetExecutor().Execute(cmd, args...)
+}
+
+func commandLine(cmd string, args ...string) string {
+	return strings.Join(append([]string{cmd}, args...), " ")
+}
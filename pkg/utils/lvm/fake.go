@@ -0,0 +1,87 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lvm
+
+import (
+	"fmt"
+	"sync"
+)
+
+type fakeOutput struct {
+	stdout string
+	stderr string
+	failed bool
+}
+
+// FakeExecutor is an Executor which replays recorded outputs instead of
+// running lvm commands. Outputs are keyed by command line, which is the lvm
+// command and its args joined by space, e.g.
+// "lvs --reportformat=json --units=b --nosuffix --options=lv_name,vg_name share".
+// When several outputs are recorded for the same command line they are
+// replayed in order, and the last one is replayed for all further calls.
+type FakeExecutor struct {
+	lock     sync.Mutex
+	outputs  map[string][]fakeOutput
+	commands []string
+}
+
+// NewFakeExecutor returns an empty FakeExecutor
+func NewFakeExecutor() *FakeExecutor {
+	return &FakeExecutor{
+		outputs: make(map[string][]fakeOutput),
+	}
+}
+
+// Record records the stdout of a successful command
+func (f *FakeExecutor) Record(cmdline, stdout string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.outputs[cmdline] = append(f.outputs[cmdline], fakeOutput{stdout: stdout})
+}
+
+// RecordError records the stderr of a failed command
+func (f *FakeExecutor) RecordError(cmdline, stderr string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.outputs[cmdline] = append(f.outputs[cmdline], fakeOutput{stderr: stderr, failed: true})
+}
+
+// Commands returns the command lines executed so far
+func (f *FakeExecutor) Commands() []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return append([]string{}, f.commands...)
+}
+
+func (f *FakeExecutor) Execute(cmd string, args ...string) ([]byte, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	cmdline := commandLine(cmd, args...)
+	f.commands = append(f.commands, cmdline)
+	outputs, exist := f.outputs[cmdline]
+	if !exist || len(outputs) == 0 {
+		return nil, fmt.Errorf("lvm: no recorded output for command %q", cmdline)
+	}
+	output := outputs[0]
+	if len(outputs) > 1 {
+		f.outputs[cmdline] = outputs[1:]
+	}
+	if output.failed {
+		return nil, NewError(cmdline, ignoreWarnings(output.stderr))
+	}
+	return []byte(output.stdout), nil
+}
@@ -17,15 +17,12 @@ limitations under the License.
 package lvm
 
 import (
-	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"os/exec"
 	"regexp"
+	"strconv"
 	"strings"
 
-	localtype "github.com/alibaba/open-local/pkg"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -222,7 +219,7 @@ func (vg *VolumeGroup) CreateLogicalVolume(name string, sizeInBytes uint64, tags
 		log.Errorf("CreateLogicalVolume error: %s", err.Error())
 		return nil, err
 	}
-	return &LogicalVolume{name, sizeInBytes, vg, "", 0}, nil
+	return &LogicalVolume{name, sizeInBytes, vg, "", "", 0}, nil
 }
 
 // ValidateLogicalVolumeName validates a volume group name. A valid volume
@@ -247,6 +244,12 @@ type lvsOutput struct {
 			LvTags      string  `json:"lv_tags"`
 			LvOrigin    string  `json:"origin"`
 			LvSnapUsage float64 `json:"snap_percent,string"`
+			LvAttr      string  `json:"lv_attr"`
+			PoolLv      string  `json:"pool_lv"`
+			DataUsage   string  `json:"data_percent"`
+			MetaUsage   string  `json:"metadata_percent"`
+			ThinID      string  `json:"thin_id"`
+			LvTime      string  `json:"lv_time"`
 		} `json:"lv"`
 	} `json:"report"`
 }
@@ -270,7 +273,7 @@ func IsLogicalVolumeNotFound(err error) bool {
 func (vg *VolumeGroup) LookupLogicalVolume(name string) (*LogicalVolume, error) {
 	var err error
 	result := new(lvsOutput)
-	if err = run("lvs", result, "--options=lv_name,lv_size,vg_name,origin", vg.Name()); err != nil {
+	if err = run("lvs", result, "--options=lv_name,lv_size,vg_name,origin,pool_lv", vg.Name()); err != nil {
 		if IsLogicalVolumeNotFound(err) {
 			return nil, ErrLogicalVolumeNotFound
 		}
@@ -288,10 +291,12 @@ func (vg *VolumeGroup) LookupLogicalVolume(name string) (*LogicalVolume, error)
 			var usage float64 = 0
 			if lv.LvOrigin != "" {
 				tmpResult := new(lvsOutput)
-				_ = run("lvs", tmpResult, "--options=lv_name,lv_size,vg_name,origin,snap_percent", lv.VgName+"/"+lv.Name)
-				usage = tmpResult.Report[0].Lv[0].LvSnapUsage
+				if err := run("lvs", tmpResult, "--options=lv_name,lv_size,vg_name,origin,snap_percent", lv.VgName+"/"+lv.Name); err == nil &&
+					len(tmpResult.Report) > 0 && len(tmpResult.Report[0].Lv) > 0 {
+					usage = tmpResult.Report[0].Lv[0].LvSnapUsage
+				}
 			}
-			return &LogicalVolume{lv.Name, lv.LvSize, vg, lv.LvOrigin, usage / 100}, nil
+			return &LogicalVolume{lv.Name, lv.LvSize, vg, lv.LvOrigin, lv.PoolLv, usage / 100}, nil
 		}
 	}
 	return nil, ErrLogicalVolumeNotFound
@@ -315,6 +320,72 @@ func (vg *VolumeGroup) ListLogicalVolumeNames() ([]string, error) {
 	return names, nil
 }
 
+// lvsAttrOutput is separated from lvsOutput, for snap_percent is empty for
+// logical volumes other than snapshots
+type lvsAttrOutput struct {
+	Report []struct {
+		Lv []struct {
+			Name        string `json:"lv_name"`
+			VgName      string `json:"vg_name"`
+			LvOrigin    string `json:"origin"`
+			LvAttr      string `json:"lv_attr"`
+			LvSnapUsage string `json:"snap_percent"`
+		} `json:"lv"`
+	} `json:"report"`
+}
+
+// ListAbnormalLogicalVolumes returns the abnormal logical volumes in this volume group,
+// keyed by name, the values describe why they are abnormal.
+func (vg *VolumeGroup) ListAbnormalLogicalVolumes() (map[string]string, error) {
+	result := new(lvsAttrOutput)
+	if err := run("lvs", result, "--options=lv_name,vg_name,origin,lv_attr,snap_percent", vg.name); err != nil {
+		log.Errorf("ListAbnormalLogicalVolumes error: %s", err.Error())
+		return nil, err
+	}
+	abnormal := make(map[string]string)
+	for _, report := range result.Report {
+		for _, lv := range report.Lv {
+			if lv.VgName != vg.name {
+				continue
+			}
+			if msg := logicalVolumeCondition(lv.LvAttr, lv.LvOrigin, lv.LvSnapUsage); msg != "" {
+				abnormal[lv.Name] = msg
+			}
+		}
+	}
+	return abnormal, nil
+}
+
+// logicalVolumeCondition returns why the logical volume is abnormal according to the
+// state (5th) and health (9th) bits of lv_attr, see lvs(8), or empty if it is normal
+func logicalVolumeCondition(attr, origin, snapUsage string) string {
+	var msgs []string
+	if len(attr) > 4 {
+		switch attr[4] {
+		case 'I', 'S':
+			msgs = append(msgs, "snapshot is invalid")
+		case 'm', 'M':
+			msgs = append(msgs, "merge of snapshot failed")
+		}
+	}
+	if len(attr) > 8 {
+		switch attr[8] {
+		case 'p':
+			msgs = append(msgs, "physical volumes of logical volume are missing")
+		case 'r':
+			msgs = append(msgs, "devices of logical volume failed and need to be refreshed")
+		case 'F':
+			msgs = append(msgs, "logical volume failed")
+		}
+	}
+	if origin != "" && snapUsage != "" {
+		if usage, err := strconv.ParseFloat(snapUsage, 64); err == nil && usage >= 100 {
+			msgs = append(msgs, "snapshot is full")
+		}
+	}
+	return strings.Join(msgs, ", ")
+}
+
 func IsPhysicalVolumeNotFound(err error) bool {
 	return isPhysicalVolumeNotFound(err) ||
 		isNoPhysicalVolumeLabel(err)
@@ -395,6 +466,7 @@ type LogicalVolume struct {
 	sizeInBytes    uint64
 	vg             *VolumeGroup
 	originLvName   string
+	poolLvName     string
 	usageInPercent float64
 }
 
@@ -436,6 +508,11 @@ func (lv *LogicalVolume) IsSnapshot() bool {
 	return lv.originLvName != ""
 }
 
+// IsThin returns true if the logical volume is allocated from a thin pool
+func (lv *LogicalVolume) IsThin() bool {
+	return lv.poolLvName != ""
+}
+
 func (lv *LogicalVolume) Remove() error {
 	if err := run("lvremove", nil, "-f", lv.vg.name+"/"+lv.name); err != nil {
 		log.Errorf("lvremove error: %s", err.Error())
@@ -445,15 +522,13 @@ func (lv *LogicalVolume) Remove() error {
 }
 
 func (lv *LogicalVolume) Expand(size uint64) error {
-	args := []string{localtype.NsenterCmd, "lvextend", fmt.Sprintf("--size=+%db", size), lv.vg.name + "/" + lv.name}
-	cmd := strings.Join(args, " ")
-	log.Debugf("[Expand]cmd: %s", cmd)
-	out, err := exec.Command("sh", "-c", cmd).CombinedOutput()
-	if err != nil {
+	if err := run("lvextend", nil, fmt.Sprintf("--size=+%db", size), lv.vg.name+"/"+lv.name); err != nil {
+		if isInsufficientSpace(err) {
+			return ErrNoSpace
+		}
+		log.Errorf("lvextend error: %s", err.Error())
 		return err
 	}
-	log.Infof("[Expand]out: %s", string(out))
-
 	return nil
 }
 
@@ -692,37 +767,16 @@ func LookupPhysicalVolume(name string) (*PhysicalVolume, error) {
 // https://github.com/Jajcus/lvm2/blob/266d6564d7a72fcff5b25367b7a95424ccf8089e/lib/metadata/metadata.c#L983
 
 func run(cmd string, v interface{}, extraArgs ...string) error {
-	var args []string
-	cmdUseNsenter := fmt.Sprintf("%s %s", localtype.NsenterCmd, cmd)
-	args = append(args, cmdUseNsenter)
-	if v != nil {
-		args = append(args, "--reportformat=json")
-		args = append(args, "--units=b")
-		args = append(args, "--nosuffix")
-	}
-	args = append(args, extraArgs...)
-	c := exec.Command("sh", "-c", strings.Join(args[:], " "))
-	// log.Infof("Executing: %s", c.String())
-	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
-	c.Stdout = stdout
-	c.Stderr = stderr
-	if err := c.Run(); err != nil {
-		errstr := ignoreWarnings(stderr.String())
-		// log.Infof("stdout: " + stdout.String())
-		// log.Infof("stderr: " + errstr)
-		log.Debugf("[debug run]: command %s", c.String())
-		log.Debugf("[debug run]: error %s", err.Error())
-		return errors.New(errstr)
-	}
-	stdoutbuf := stdout.Bytes()
-	// stderrbuf := stderr.Bytes()
-	// errstr := ignoreWarnings(string(stderrbuf))
-	// log.Infof("stdout: " + string(stdoutbuf))
-	// log.Infof("stderr: " + errstr)
-	if v != nil {
-		if err := json.Unmarshal(stdoutbuf, v); err != nil {
-			return fmt.Errorf("unmarshal error: %s", err.Error())
-		}
+	if v == nil {
+		_, err := Run(cmd, extraArgs...)
+		return err
+	}
+	out, err := RunReport(cmd, extraArgs...)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(out, v); err != nil {
+		return fmt.Errorf("unmarshal error: %s", err.Error())
 	}
 	return nil
 }
@@ -0,0 +1,140 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lvm
+
+import (
+	"errors"
+	"testing"
+)
+
+const (
+	lvsShare = `{"report": [{"lv": [
+		{"lv_name":"pv-1", "vg_name":"share", "lv_size":"1073741824", "origin":""},
+		{"lv_name":"snap-1", "vg_name":"share", "lv_size":"4294967296", "origin":"pv-1"}
+	]}]}`
+	lvsSnap1 = `{"report": [{"lv": [
+		{"lv_name":"snap-1", "vg_name":"share", "lv_size":"4294967296", "origin":"pv-1", "snap_percent":"62.50"}
+	]}]}`
+)
+
+func TestLookupLogicalVolume(t *testing.T) {
+	fake := NewFakeExecutor()
+	defer SetExecutor(SetExecutor(fake))
+	fake.Record("lvs --reportformat=json --units=b --nosuffix --options=lv_name,lv_size,vg_name,origin,pool_lv share", lvsShare)
+	fake.Record("lvs --reportformat=json --units=b --nosuffix --options=lv_name,lv_size,vg_name,origin,snap_percent share/snap-1", lvsSnap1)
+
+	vg := &VolumeGroup{name: "share"}
+	lv, err := vg.LookupLogicalVolume("snap-1")
+	if err != nil {
+		t.Fatalf("LookupLogicalVolume() error = %v", err)
+	}
+	if !lv.IsSnapshot() || lv.OriginLVName() != "pv-1" {
+		t.Errorf("LookupLogicalVolume() origin = %q, want pv-1", lv.OriginLVName())
+	}
+	if lv.SizeInBytes() != 4294967296 || lv.Usage() != 0.625 {
+		t.Errorf("LookupLogicalVolume() size = %d, usage = %f", lv.SizeInBytes(), lv.Usage())
+	}
+	if _, err := vg.LookupLogicalVolume("pv-2"); err != ErrLogicalVolumeNotFound {
+		t.Errorf("LookupLogicalVolume() error = %v, want %v", err, ErrLogicalVolumeNotFound)
+	}
+}
+
+func TestExecutorTypedErrors(t *testing.T) {
+	fake := NewFakeExecutor()
+	defer SetExecutor(SetExecutor(fake))
+	fake.RecordError("lvextend --size=+1073741824b share/snap-1", `  Insufficient free space: 256 extents needed, but only 10 available`)
+	fake.RecordError("lvextend --size=+1073741824b share/snap-2", "  Volume group \"share\" has insufficient free space (10 extents): 256 required.")
+	fake.RecordError("vgs --reportformat=json --units=b --nosuffix --options=vg_name share", "  WARNING: something\n  Volume group \"share\" not found\n  Cannot process volume group share")
+	fake.RecordError("lvremove -f share/pv-1", "  Can't get lock for share.")
+
+	tests := []struct {
+		name string
+		run  func() error
+		want error
+	}{
+		{
+			name: "no space",
+			run: func() error {
+				return (&LogicalVolume{name: "snap-2", vg: &VolumeGroup{name: "share"}}).Expand(1073741824)
+			},
+			want: ErrNoSpace,
+		},
+		{
+			name: "vg not found",
+			run: func() error {
+				_, err := LookupVolumeGroup("share")
+				return err
+			},
+			want: ErrVolumeGroupNotFound,
+		},
+		{
+			name: "locked",
+			run: func() error {
+				return (&LogicalVolume{name: "pv-1", vg: &VolumeGroup{name: "share"}}).Remove()
+			},
+			want: ErrLocked,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// unrecognized failure is still an *Error with stderr
+	_, err := Run("lvextend", "--size=+1073741824b", "share/snap-1")
+	var lvmErr *Error
+	if !errors.As(err, &lvmErr) || errors.Unwrap(err) != nil {
+		t.Errorf("Run() error = %#v, want unclassified *Error", err)
+	}
+	// commands which are not recorded fail
+	if _, err := Run("pvs"); err == nil {
+		t.Errorf("Run() of unrecorded command should fail")
+	}
+}
+
+func TestListAbnormalLogicalVolumes(t *testing.T) {
+	fake := NewFakeExecutor()
+	defer SetExecutor(SetExecutor(fake))
+	fake.Record("lvs --reportformat=json --units=b --nosuffix --options=lv_name,vg_name,origin,lv_attr,snap_percent share", `{"report": [{"lv": [
+		{"lv_name":"pv-1", "vg_name":"share", "origin":"", "lv_attr":"owi-aos---", "snap_percent":""},
+		{"lv_name":"pv-2", "vg_name":"share", "origin":"", "lv_attr":"-wi-ao--p-", "snap_percent":""},
+		{"lv_name":"snap-1", "vg_name":"share", "origin":"pv-1", "lv_attr":"swi-a-s---", "snap_percent":"62.50"},
+		{"lv_name":"snap-2", "vg_name":"share", "origin":"pv-1", "lv_attr":"swi-a-s---", "snap_percent":"100.00"},
+		{"lv_name":"snap-3", "vg_name":"share", "origin":"pv-1", "lv_attr":"swi-I-s---", "snap_percent":"100.00"}
+	]}]}`)
+
+	abnormal, err := (&VolumeGroup{name: "share"}).ListAbnormalLogicalVolumes()
+	if err != nil {
+		t.Fatalf("ListAbnormalLogicalVolumes() error = %v", err)
+	}
+	want := map[string]string{
+		"pv-2":   "physical volumes of logical volume are missing",
+		"snap-2": "snapshot is full",
+		"snap-3": "snapshot is invalid, snapshot is full",
+	}
+	if len(abnormal) != len(want) {
+		t.Fatalf("ListAbnormalLogicalVolumes() = %v, want %v", abnormal, want)
+	}
+	for name, msg := range want {
+		if abnormal[name] != msg {
+			t.Errorf("ListAbnormalLogicalVolumes()[%s] = %q, want %q", name, abnormal[name], msg)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright © 2021 Alibaba Group Holding Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lvm
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const ErrThinPoolNotFound = simpleError("lvm: thin pool not found")
+
+// ThinPool is a thin pool logical volume, thin volumes are allocated
+// from it on demand rather than from the volume group.
+type ThinPool struct {
+	name            string
+	sizeInBytes     uint64
+	vg              *VolumeGroup
+	dataPercent     string
+	metadataPercent string
+}
+
+func (tp *ThinPool) Name() string {
+	return tp.name
+}
+
+// SizeInBytes returns the data size of the thin pool
+func (tp *ThinPool) SizeInBytes() uint64 {
+	return tp.sizeInBytes
+}
+
+// DataPercent returns the usage of data in percent as reported by lvs
+func (tp *ThinPool) DataPercent() string {
+	return tp.dataPercent
+}
+
+// MetadataPercent returns the usage of metadata in percent as reported by lvs
+func (tp *ThinPool) MetadataPercent() string {
+	return tp.metadataPercent
+}
+
+// LookupThinPool looks up the thin pool in the volume group. If there are
+// more than one thin pools, the first one is returned.
+func (vg *VolumeGroup) LookupThinPool() (*ThinPool, error) {
+	result := new(lvsOutput)
+	if err := run("lvs", result, "--options=lv_name,lv_size,vg_name,lv_attr,data_percent,metadata_percent", vg.name); err != nil {
+		if IsVolumeGroupNotFound(err) {
+			return nil, ErrVolumeGroupNotFound
+		}
+		log.Errorf("LookupThinPool error: %s", err.Error())
+		return nil, err
+	}
+	for _, report := range result.Report {
+		for _, lv := range report.Lv {
+			if lv.VgName != vg.name || !isThinPoolAttr(lv.LvAttr) {
+				continue
+			}
+			return &ThinPool{lv.Name, lv.LvSize, vg, lv.DataUsage, lv.MetaUsage}, nil
+		}
+	}
+	return nil, ErrThinPoolNotFound
+}
+
+// CreateThinPool creates a thin pool using the given percentage of the
+// free space of the volume group. lvm reserves the space for pool metadata
+// out of it.
+func (vg *VolumeGroup) CreateThinPool(name string, percent int32) (*ThinPool, error) {
+	if err := ValidateLogicalVolumeName(name); err != nil {
+		return nil, err
+	}
+	if percent <= 0 || percent > 100 {
+		return nil, fmt.Errorf("lvm: invalid percent %d of thin pool %s", percent, name)
+	}
+	args := []string{"--type=thin-pool", fmt.Sprintf("--extents=%d%%FREE", percent), "--name=" + name, vg.name}
+	if err := run("lvcreate", nil, args...); err != nil {
+		if isInsufficientSpace(err) {
+			return nil, ErrNoSpace
+		}
+		log.Errorf("CreateThinPool error: %s", err.Error())
+		return nil, err
+	}
+	return vg.LookupThinPool()
+}
+
+// isThinPoolAttr checks the volume type bit of lv_attr
+func isThinPoolAttr(attr string) bool {
+	return strings.HasPrefix(attr, "t")
+}